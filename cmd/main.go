@@ -5,13 +5,19 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/Lapp-coder/todo-app/internal/config"
 	"github.com/Lapp-coder/todo-app/internal/handler"
+	"github.com/Lapp-coder/todo-app/internal/logger"
+	"github.com/Lapp-coder/todo-app/internal/mail"
+	"github.com/Lapp-coder/todo-app/internal/oidc"
+	"github.com/Lapp-coder/todo-app/internal/ratelimit"
 	"github.com/Lapp-coder/todo-app/internal/repository"
-	"github.com/Lapp-coder/todo-app/internal/repository/postgres"
+	"github.com/Lapp-coder/todo-app/internal/rpc"
 	"github.com/Lapp-coder/todo-app/internal/server"
 	"github.com/Lapp-coder/todo-app/internal/service"
+	"github.com/Lapp-coder/todo-app/internal/tracing"
 	_ "github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 )
@@ -28,30 +34,83 @@ const configPath = "configs/"
 // @securityDefinitions.apiKey ApiKeyAuth
 // @in header
 // @name Authorization
+
+// @securityDefinitions.apiKey AdminAuth
+// @in header
+// @name Authorization
 func main() {
 	cfg, err := config.New(configPath)
 	if err != nil {
 		logrus.Fatalf("failed to initializate config file: %s", err.Error())
 	}
 
-	db, err := postgres.NewDB(cfg.PostgresDB)
+	if err = logger.Configure(cfg.Log); err != nil {
+		logrus.Fatalf("failed to configure logger: %s", err.Error())
+	}
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case migrateCommand:
+			if err = runMigrate(cfg, os.Args[2:]); err != nil {
+				logrus.Fatalf("failed to migrate: %s", err.Error())
+			}
+		case roleCommand:
+			if err = runRole(cfg, os.Args[2:]); err != nil {
+				logrus.Fatalf("failed to set role: %s", err.Error())
+			}
+		default:
+			logrus.Fatal(errUnknownCommand.Error())
+		}
+
+		return
+	}
+
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing)
+	if err != nil {
+		logrus.Fatalf("failed to initializate tracing: %s", err.Error())
+	}
+
+	if err = autoMigrate(cfg); err != nil {
+		logrus.Fatalf("failed to apply migrations: %s", err.Error())
+	}
+
+	repositories, db, err := repository.Open(cfg.Storage, cfg.PostgresDB)
 	if err != nil {
 		logrus.Fatalf("failed to initializate db: %s", err.Error())
 	}
 
-	repositories := repository.New(db)
-	services := service.New(repositories, cfg.Service)
-	handlers := handler.New(services)
+	statsCtx, stopStats := context.WithCancel(context.Background())
+	if cfg.Storage.StatsInterval > 0 {
+		go reportPoolStats(statsCtx, db, time.Duration(cfg.Storage.StatsInterval)*time.Second)
+	}
+
+	checks, err := healthChecks(db)
+	if err != nil {
+		logrus.Fatalf("failed to initializate health checks: %s", err.Error())
+	}
+
+	health := service.NewHealthService(checks)
+	services := service.New(repositories, cfg.Service, health, service.LogNotifier{}, mail.New(cfg.Mail), oidc.New(cfg.OIDC, nil))
+	// The REST and the gRPC APIs share the buckets, so neither is a way around the limits of the other.
+	limiter := ratelimit.FromConfig(cfg.RateLimit, ratelimit.NewMemoryStore())
+	handlers := handler.New(services, limiter)
 
 	cfg.Handler = handlers.InitRoutes()
 	srv := server.NewServer(cfg.Server)
+	grpcSrv := server.NewGRPCServer(cfg.GRPCServer, rpc.New(services, limiter).InitServer())
 
 	go func() {
-		if err = srv.Run(); err != nil {
+		if err := srv.Run(); err != nil {
 			logrus.Errorf("failed to starting server: %s", err.Error())
 		}
 	}()
 
+	go func() {
+		if err := grpcSrv.Run(); err != nil {
+			logrus.Errorf("failed to starting grpc server: %s", err.Error())
+		}
+	}()
+
 	logrus.Info("todo-app started")
 
 	quit := make(chan os.Signal, 1)
@@ -60,10 +119,22 @@ func main() {
 
 	logrus.Info("todo-app shutting down")
 
+	health.ShutDown()
+
 	if err = srv.Shutdown(context.Background()); err != nil {
 		logrus.Errorf("failed to shutting down server: %s", err.Error())
 	}
 
+	if err = grpcSrv.Shutdown(context.Background()); err != nil {
+		logrus.Errorf("failed to shutting down grpc server: %s", err.Error())
+	}
+
+	stopStats()
+
+	if err = shutdownTracing(context.Background()); err != nil {
+		logrus.Errorf("failed to flush traces: %s", err.Error())
+	}
+
 	if err = db.Close(); err != nil {
 		logrus.Errorf("failed to close connection to database: %s", err.Error())
 	}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/Lapp-coder/todo-app/internal/config"
+	"github.com/Lapp-coder/todo-app/internal/migrate"
+	"github.com/Lapp-coder/todo-app/internal/repository"
+	"github.com/Lapp-coder/todo-app/internal/repository/postgres"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	"github.com/Lapp-coder/todo-app/migrations"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	migrateCommand = "migrate"
+	migrateUp      = "up"
+	migrateDown    = "down"
+	migrateStatus  = "status"
+)
+
+var (
+	errUnknownCommand         = errors.New("unknown command, usage: todo-app migrate up|down [steps]|status, todo-app role <email> admin|user")
+	errInvalidSteps           = errors.New("invalid number of steps")
+	errMigrationsNotSupported = errors.New("migrations are only used by the postgres storage driver")
+)
+
+// runMigrate handles the migrate subcommand: up applies all pending migrations,
+// down rolls back the given number of migrations (one by default), status lists them.
+func runMigrate(cfg config.Config, args []string) error {
+	if len(args) == 0 {
+		return errUnknownCommand
+	}
+
+	steps := 1
+	if args[0] == migrateDown && len(args) > 1 {
+		var err error
+		if steps, err = strconv.Atoi(args[1]); err != nil || steps <= 0 {
+			return errInvalidSteps
+		}
+	}
+
+	if cfg.Storage.Driver != repository.DriverPostgres {
+		return errMigrationsNotSupported
+	}
+
+	db, err := postgres.NewDB(cfg.PostgresDB)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case migrateUp:
+		applied, err := migrator.Up(ctx)
+		logMigrations("applied", applied)
+		return err
+	case migrateDown:
+		reverted, err := migrator.Down(ctx, steps)
+		logMigrations("reverted", reverted)
+		return err
+	case migrateStatus:
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		logrus.Infof("schema version %d, dirty: %t", status.Version, status.Dirty)
+		logMigrations("applied", status.Applied)
+		logMigrations("pending", status.Pending)
+
+		return nil
+	default:
+		return errUnknownCommand
+	}
+}
+
+// autoMigrate applies the pending migrations on boot when auto_migrate is enabled.
+func autoMigrate(cfg config.Config) error {
+	if cfg.Storage.Driver != repository.DriverPostgres || !cfg.PostgresDB.AutoMigrate {
+		return nil
+	}
+
+	return runMigrate(cfg, []string{migrateUp})
+}
+
+func logMigrations(action string, list []migrate.Migration) {
+	for _, migration := range list {
+		logrus.Infof("%s migration %d_%s", action, migration.Version, migration.Name)
+	}
+}
+
+// healthChecks returns the readiness checks of the storage: the database answers and,
+// for postgres, the schema has all the embedded migrations applied.
+func healthChecks(db repository.Database) (map[string]service.HealthCheck, error) {
+	checks := map[string]service.HealthCheck{"database": db.PingContext}
+
+	cluster, ok := db.(*postgres.Cluster)
+	if !ok {
+		return checks, nil
+	}
+
+	migrator, err := migrate.New(cluster.Primary(), migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+
+	checks["migrations"] = migrator.Check
+
+	return checks, nil
+}
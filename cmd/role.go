@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Lapp-coder/todo-app/internal/config"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+const roleCommand = "role"
+
+var (
+	errRoleUsage         = errors.New("usage: todo-app role <email> admin|user")
+	errRoleNotPersistent = errors.New("the memory storage driver doesn't keep the roles")
+)
+
+// runRole handles the role subcommand, which sets the role of the user with the email. It's how
+// the first admin is appointed, the admin API only takes the sessions of the admins.
+func runRole(cfg config.Config, args []string) error {
+	if len(args) != 2 || (args[1] != model.RoleAdmin && args[1] != model.RoleUser) {
+		return errRoleUsage
+	}
+
+	if cfg.Storage.Driver == repository.DriverMemory {
+		return errRoleNotPersistent
+	}
+
+	repositories, db, err := repository.Open(cfg.Storage, cfg.PostgresDB)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	user, err := repositories.Authorization.GetUser(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	if err = repositories.Authorization.SetRole(ctx, user.ID, args[1]); err != nil {
+		return err
+	}
+
+	logrus.Infof("%s is now %s", user.Email, args[1])
+
+	return nil
+}
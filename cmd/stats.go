@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+// reportPoolStats logs the statistics of the connection pools every interval until ctx is done.
+func reportPoolStats(ctx context.Context, db repository.Database, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for name, stats := range db.PoolStats() {
+				logrus.WithFields(logrus.Fields{
+					"pool":            name,
+					"open":            stats.OpenConnections,
+					"in_use":          stats.InUse,
+					"idle":            stats.Idle,
+					"wait_count":      stats.WaitCount,
+					"wait_duration":   stats.WaitDuration,
+					"max_idle_closed": stats.MaxIdleClosed,
+					"lifetime_closed": stats.MaxLifetimeClosed,
+				}).Info("connection pool stats")
+			}
+		}
+	}
+}
@@ -21,3 +21,57 @@ type GetAllItemsResponse struct {
 type GetItemByIDResponse struct {
 	Item model.TodoItem `json:"item"`
 }
+
+type GetLockoutsResponse struct {
+	Lockouts []model.Lockout `json:"lockouts"`
+}
+
+type GetUsersResponse struct {
+	Users []model.UserSummary `json:"users"`
+}
+
+type CalendarTokenResponse struct {
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+type GraphQLResponse struct {
+	Data   interface{}   `json:"data"`
+	Errors []interface{} `json:"errors,omitempty"`
+}
+
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+type ReadinessResponse struct {
+	Status string            `json:"status"`
+	Error  string            `json:"error,omitempty"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+type VersionResponse struct {
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+type GetAccessTokensResponse struct {
+	Tokens []model.AccessToken `json:"tokens"`
+}
+
+type GetAccessTokenResponse struct {
+	Token model.AccessToken `json:"token"`
+}
+
+type GetWorkspacesResponse struct {
+	Workspaces []model.Workspace `json:"workspaces"`
+}
+
+type GetWorkspaceResponse struct {
+	Workspace model.Workspace `json:"workspace"`
+}
+
+type GetWorkspaceMembersResponse struct {
+	Members []model.WorkspaceMember `json:"members"`
+}
@@ -0,0 +1,12 @@
+package caldav
+
+import "errors"
+
+var (
+	ErrInvalidRequestBody  = errors.New("invalid request body")
+	ErrInvalidCalendarData = errors.New("invalid calendar data")
+	ErrNoTodoComponent     = errors.New("calendar data has no VTODO component")
+	ErrInvalidSummary      = errors.New("summary must be from 1 to 30 characters long")
+	ErrInvalidDescription  = errors.New("description must be at most 50 characters long")
+	ErrInvalidDueDate      = errors.New("invalid due date")
+)
@@ -0,0 +1,181 @@
+package caldav
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Lapp-coder/todo-app/internal/ical"
+	"github.com/Lapp-coder/todo-app/internal/model"
+)
+
+const (
+	ContentType = "text/calendar; charset=utf-8"
+
+	productID            = "-//Lapp-coder//todo-app//EN"
+	objectExtension      = ".ics"
+	maxTitleLength       = 30
+	maxDescriptionLength = 50
+)
+
+func DefaultUID(itemID int) string {
+	return fmt.Sprintf("item-%d@todo-app", itemID)
+}
+
+func DefaultName(itemID int) string {
+	return strconv.Itoa(itemID) + objectExtension
+}
+
+// ParseDefaultName returns the id of the item for names of objects that
+// weren't created by a CalDAV client.
+func ParseDefaultName(name string) (int, bool) {
+	if !strings.HasSuffix(name, objectExtension) {
+		return 0, false
+	}
+
+	id, err := strconv.Atoi(strings.TrimSuffix(name, objectExtension))
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+
+	return id, true
+}
+
+func EncodeItem(item model.TodoItem, uid string) ([]byte, error) {
+	calendar := ical.NewComponent("VCALENDAR")
+	calendar.Add("VERSION", "2.0")
+	calendar.Add("PRODID", productID)
+
+	// DTSTAMP is derived from the item instead of the current time so that
+	// the body of an object doesn't change while its ETag stays the same.
+	due, hasDue := model.ParseCompletionDate(item.CompletionDate)
+
+	todo := ical.NewComponent("VTODO")
+	todo.AddText("UID", uid)
+	if hasDue {
+		todo.Add("DTSTAMP", ical.FormatUTCDateTime(due))
+	} else {
+		todo.Add("DTSTAMP", ical.FormatUTCDateTime(time.Unix(0, 0)))
+	}
+	todo.AddText("SUMMARY", item.Title)
+	if item.Description != "" {
+		todo.AddText("DESCRIPTION", item.Description)
+	}
+	if hasDue {
+		todo.Add("DUE", ical.FormatDateTime(due))
+	}
+	if item.Done {
+		todo.Add("STATUS", "COMPLETED")
+	} else {
+		todo.Add("STATUS", "NEEDS-ACTION")
+	}
+	calendar.AddComponent(todo)
+
+	var buf bytes.Buffer
+	if err := ical.Encode(&buf, calendar); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// DecodeItem parses the first VTODO of the calendar data into an item and
+// returns it along with its UID. The completion date is left empty when the
+// VTODO has neither DUE nor DTSTART.
+func DecodeItem(r io.Reader) (model.TodoItem, string, error) {
+	calendar, err := ical.Decode(r)
+	if err != nil || calendar.Name != "VCALENDAR" {
+		return model.TodoItem{}, "", ErrInvalidCalendarData
+	}
+
+	var todo *ical.Component
+	for i := range calendar.Components {
+		if calendar.Components[i].Name == "VTODO" {
+			todo = &calendar.Components[i]
+			break
+		}
+	}
+
+	if todo == nil {
+		return model.TodoItem{}, "", ErrNoTodoComponent
+	}
+
+	var item model.TodoItem
+	if p, ok := todo.Get("SUMMARY"); ok {
+		item.Title = ical.UnescapeText(p.Value)
+	}
+
+	if length := utf8.RuneCountInString(item.Title); length == 0 || length > maxTitleLength {
+		return model.TodoItem{}, "", ErrInvalidSummary
+	}
+
+	if p, ok := todo.Get("DESCRIPTION"); ok {
+		item.Description = ical.UnescapeText(p.Value)
+	}
+
+	if utf8.RuneCountInString(item.Description) > maxDescriptionLength {
+		return model.TodoItem{}, "", ErrInvalidDescription
+	}
+
+	due, ok := todo.Get("DUE")
+	if !ok {
+		due, ok = todo.Get("DTSTART")
+	}
+
+	if ok {
+		t, err := due.DateTime()
+		if err != nil {
+			return model.TodoItem{}, "", ErrInvalidDueDate
+		}
+
+		item.CompletionDate = t.Format(model.CompletionDateLayout)
+	}
+
+	if p, ok := todo.Get("STATUS"); ok && strings.EqualFold(p.Value, "COMPLETED") {
+		item.Done = true
+	} else if _, ok = todo.Get("COMPLETED"); ok {
+		item.Done = true
+	}
+
+	var uid string
+	if p, ok := todo.Get("UID"); ok {
+		uid = ical.UnescapeText(p.Value)
+	}
+
+	return item, uid, nil
+}
+
+// ETag returns a strong entity tag of the object. The completion date is
+// normalized because it's returned by the database in a different format
+// than it's written.
+func ETag(item model.TodoItem, uid string) string {
+	completionDate := item.CompletionDate
+	if t, ok := model.ParseCompletionDate(completionDate); ok {
+		completionDate = t.Format(model.CompletionDateLayout)
+	}
+
+	hash := sha1.New()
+	fmt.Fprintf(hash, "%d\x00%s\x00%s\x00%s\x00%s\x00%t", item.ID, uid, item.Title, item.Description, completionDate, item.Done)
+
+	return `"` + hex.EncodeToString(hash.Sum(nil)) + `"`
+}
+
+// CTag returns a tag of the collection which changes whenever any of its objects changes.
+func CTag(etags []string) string {
+	sorted := append([]string(nil), etags...)
+	sort.Strings(sorted)
+
+	hash := sha1.New()
+	for _, etag := range sorted {
+		io.WriteString(hash, etag)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
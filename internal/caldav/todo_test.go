@@ -0,0 +1,101 @@
+package caldav
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeItem(t *testing.T) {
+	item := model.TodoItem{ID: 1, ListID: 1, Title: "test", Description: "testing", CompletionDate: "2021-11-21T10:00:00Z", Done: true}
+
+	got, err := EncodeItem(item, "uid")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Lapp-coder//todo-app//EN\r\n"+
+		"BEGIN:VTODO\r\nUID:uid\r\nDTSTAMP:20211121T100000Z\r\nSUMMARY:test\r\nDESCRIPTION:testing\r\n"+
+		"DUE:20211121T100000\r\nSTATUS:COMPLETED\r\nEND:VTODO\r\nEND:VCALENDAR\r\n", string(got))
+}
+
+func TestDecodeItem(t *testing.T) {
+	testCases := []struct {
+		name         string
+		input        string
+		expectedItem model.TodoItem
+		expectedUID  string
+		wantErr      bool
+	}{
+		{
+			name: "OK",
+			input: "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:uid\r\nSUMMARY:test\r\nDESCRIPTION:testing\\, testing\r\n" +
+				"DUE:20211121T100000\r\nSTATUS:COMPLETED\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+			expectedItem: model.TodoItem{Title: "test", Description: "testing, testing", CompletionDate: "2021-11-21 10:00:00", Done: true},
+			expectedUID:  "uid",
+			wantErr:      false,
+		},
+		{
+			name:         "OK_WithoutDue",
+			input:        "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:uid\r\nSUMMARY:test\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+			expectedItem: model.TodoItem{Title: "test"},
+			expectedUID:  "uid",
+			wantErr:      false,
+		},
+		{
+			name:    "Without VTODO",
+			input:   "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nSUMMARY:test\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+			wantErr: true,
+		},
+		{
+			name:    "Empty summary",
+			input:   "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:uid\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+			wantErr: true,
+		},
+		{
+			name:    "Long summary",
+			input:   "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nSUMMARY:" + strings.Repeat("a", 31) + "\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid due",
+			input:   "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nSUMMARY:test\r\nDUE:tomorrow\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, uid, err := DecodeItem(strings.NewReader(tc.input))
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedItem, got)
+				assert.Equal(t, tc.expectedUID, uid)
+			}
+		})
+	}
+}
+
+func TestETag(t *testing.T) {
+	stored := model.TodoItem{ID: 1, Title: "test", CompletionDate: "2021-11-21T10:00:00Z"}
+	written := model.TodoItem{ID: 1, Title: "test", CompletionDate: "2021-11-21 10:00:00"}
+	changed := model.TodoItem{ID: 1, Title: "test", CompletionDate: "2021-11-21 10:00:00", Done: true}
+
+	assert.Equal(t, ETag(stored, "uid"), ETag(written, "uid"))
+	assert.NotEqual(t, ETag(written, "uid"), ETag(changed, "uid"))
+	assert.NotEqual(t, ETag(written, "uid"), ETag(written, "other"))
+}
+
+func TestParseDefaultName(t *testing.T) {
+	id, ok := ParseDefaultName("12.ics")
+	assert.True(t, ok)
+	assert.Equal(t, 12, id)
+
+	_, ok = ParseDefaultName("client-uid.ics")
+	assert.False(t, ok)
+
+	_, ok = ParseDefaultName("12")
+	assert.False(t, ok)
+}
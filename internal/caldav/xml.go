@@ -0,0 +1,284 @@
+package caldav
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const (
+	nsDAV    = "DAV:"
+	nsCalDAV = "urn:ietf:params:xml:ns:caldav"
+	nsCS     = "http://calendarserver.org/ns/"
+)
+
+var prefixes = map[string]string{
+	nsDAV:    "D",
+	nsCalDAV: "C",
+	nsCS:     "CS",
+}
+
+var (
+	PropResourceType                  = xml.Name{Space: nsDAV, Local: "resourcetype"}
+	PropDisplayName                   = xml.Name{Space: nsDAV, Local: "displayname"}
+	PropGetETag                       = xml.Name{Space: nsDAV, Local: "getetag"}
+	PropGetContentType                = xml.Name{Space: nsDAV, Local: "getcontenttype"}
+	PropOwner                         = xml.Name{Space: nsDAV, Local: "owner"}
+	PropCurrentUserPrincipal          = xml.Name{Space: nsDAV, Local: "current-user-principal"}
+	PropCurrentUserPrivilegeSet       = xml.Name{Space: nsDAV, Local: "current-user-privilege-set"}
+	PropPrincipalURL                  = xml.Name{Space: nsDAV, Local: "principal-URL"}
+	PropSupportedReportSet            = xml.Name{Space: nsDAV, Local: "supported-report-set"}
+	PropCalendarHomeSet               = xml.Name{Space: nsCalDAV, Local: "calendar-home-set"}
+	PropCalendarDescription           = xml.Name{Space: nsCalDAV, Local: "calendar-description"}
+	PropSupportedCalendarComponentSet = xml.Name{Space: nsCalDAV, Local: "supported-calendar-component-set"}
+	PropCalendarData                  = xml.Name{Space: nsCalDAV, Local: "calendar-data"}
+	PropGetCTag                       = xml.Name{Space: nsCS, Local: "getctag"}
+
+	ReportCalendarMultiget = xml.Name{Space: nsCalDAV, Local: "calendar-multiget"}
+	ReportCalendarQuery    = xml.Name{Space: nsCalDAV, Local: "calendar-query"}
+)
+
+// Values of properties are kept as inner XML using the prefixes declared
+// on the multistatus element.
+const (
+	CollectionType          = "<D:collection/>"
+	CalendarType            = "<D:collection/><C:calendar/>"
+	PrincipalType           = "<D:principal/>"
+	AllPrivileges           = "<D:privilege><D:all/></D:privilege>"
+	TodoComponentSet        = `<C:comp name="VTODO"/>`
+	CalendarSupportedReport = "<D:supported-report><D:report><C:calendar-multiget/></D:report></D:supported-report>" +
+		"<D:supported-report><D:report><C:calendar-query/></D:report></D:supported-report>"
+)
+
+func Href(href string) string {
+	return "<D:href>" + escape(href) + "</D:href>"
+}
+
+func escape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+
+	return buf.String()
+}
+
+type Resource struct {
+	Href  string
+	Props map[xml.Name]string
+}
+
+func NewResource(href string) *Resource {
+	return &Resource{Href: href, Props: make(map[xml.Name]string)}
+}
+
+func (r *Resource) Set(name xml.Name, innerXML string) {
+	r.Props[name] = innerXML
+}
+
+func (r *Resource) SetText(name xml.Name, value string) {
+	r.Props[name] = escape(value)
+}
+
+type Propfind struct {
+	AllProp bool
+	Names   []xml.Name
+}
+
+type propfindXML struct {
+	XMLName  xml.Name  `xml:"DAV: propfind"`
+	AllProp  *struct{} `xml:"DAV: allprop"`
+	PropName *struct{} `xml:"DAV: propname"`
+	Prop     *propXML  `xml:"DAV: prop"`
+}
+
+type propXML struct {
+	Elements []elementXML `xml:",any"`
+}
+
+type elementXML struct {
+	XMLName xml.Name
+}
+
+func (p propXML) names() []xml.Name {
+	names := make([]xml.Name, 0, len(p.Elements))
+	for _, element := range p.Elements {
+		names = append(names, element.XMLName)
+	}
+
+	return names
+}
+
+// ParsePropfind parses the body of a PROPFIND request. An empty body is
+// treated as a request for all properties, as is propname.
+func ParsePropfind(r io.Reader) (Propfind, error) {
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return Propfind{}, err
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		return Propfind{AllProp: true}, nil
+	}
+
+	var req propfindXML
+	if err = xml.Unmarshal(body, &req); err != nil {
+		return Propfind{}, ErrInvalidRequestBody
+	}
+
+	if req.Prop == nil {
+		return Propfind{AllProp: true}, nil
+	}
+
+	return Propfind{Names: req.Prop.names()}, nil
+}
+
+type Report struct {
+	Name       xml.Name
+	AllProp    bool
+	Names      []xml.Name
+	Hrefs      []string
+	Components []string
+}
+
+type reportXML struct {
+	XMLName xml.Name
+	AllProp *struct{}  `xml:"DAV: allprop"`
+	Prop    *propXML   `xml:"DAV: prop"`
+	Hrefs   []string   `xml:"DAV: href"`
+	Filter  *filterXML `xml:"urn:ietf:params:xml:ns:caldav filter"`
+}
+
+type filterXML struct {
+	CompFilter compFilterXML `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+type compFilterXML struct {
+	Name        string          `xml:"name,attr"`
+	CompFilters []compFilterXML `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+func ParseReport(r io.Reader) (Report, error) {
+	var req reportXML
+	if err := xml.NewDecoder(r).Decode(&req); err != nil {
+		return Report{}, ErrInvalidRequestBody
+	}
+
+	report := Report{Name: req.XMLName, Hrefs: req.Hrefs}
+	if req.Prop == nil {
+		report.AllProp = true
+	} else {
+		report.Names = req.Prop.names()
+	}
+
+	if req.Filter != nil {
+		filters := []compFilterXML{req.Filter.CompFilter}
+		for len(filters) > 0 {
+			filter := filters[0]
+			filters = append(filters[1:], filter.CompFilters...)
+			report.Components = append(report.Components, strings.ToUpper(filter.Name))
+		}
+	}
+
+	return report, nil
+}
+
+type Multistatus struct {
+	XMLName   xml.Name      `xml:"D:multistatus"`
+	NSDAV     string        `xml:"xmlns:D,attr"`
+	NSCalDAV  string        `xml:"xmlns:C,attr"`
+	NSCS      string        `xml:"xmlns:CS,attr"`
+	Responses []responseXML `xml:"D:response"`
+}
+
+type responseXML struct {
+	Href      string        `xml:"D:href"`
+	Propstats []propstatXML `xml:"D:propstat,omitempty"`
+	Status    string        `xml:"D:status,omitempty"`
+}
+
+type propstatXML struct {
+	Prop   propValuesXML `xml:"D:prop"`
+	Status string        `xml:"D:status"`
+}
+
+type propValuesXML struct {
+	Values []propValueXML
+}
+
+type propValueXML struct {
+	XMLName xml.Name
+	Inner   string `xml:",innerxml"`
+}
+
+func NewMultistatus() *Multistatus {
+	return &Multistatus{NSDAV: nsDAV, NSCalDAV: nsCalDAV, NSCS: nsCS}
+}
+
+// AddResource adds the requested properties of the resource, all of them when
+// names is empty. Properties the resource doesn't have are reported as not found.
+func (m *Multistatus) AddResource(r *Resource, names []xml.Name) {
+	if len(names) == 0 {
+		for name := range r.Props {
+			names = append(names, name)
+		}
+
+		sort.Slice(names, func(i, j int) bool {
+			if names[i].Space != names[j].Space {
+				return names[i].Space < names[j].Space
+			}
+
+			return names[i].Local < names[j].Local
+		})
+	}
+
+	var found, notFound propValuesXML
+	for _, name := range names {
+		value, ok := r.Props[name]
+		if ok {
+			found.Values = append(found.Values, propValueXML{XMLName: elementName(name), Inner: value})
+		} else {
+			notFound.Values = append(notFound.Values, propValueXML{XMLName: elementName(name)})
+		}
+	}
+
+	response := responseXML{Href: r.Href}
+	if len(found.Values) > 0 {
+		response.Propstats = append(response.Propstats, propstatXML{Prop: found, Status: status(http.StatusOK)})
+	}
+
+	if len(notFound.Values) > 0 {
+		response.Propstats = append(response.Propstats, propstatXML{Prop: notFound, Status: status(http.StatusNotFound)})
+	}
+
+	m.Responses = append(m.Responses, response)
+}
+
+func (m *Multistatus) AddStatus(href string, code int) {
+	m.Responses = append(m.Responses, responseXML{Href: href, Status: status(code)})
+}
+
+func (m *Multistatus) Encode(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	return xml.NewEncoder(w).Encode(m)
+}
+
+// elementName returns the prefixed name for known namespaces, other
+// namespaces are declared on the element itself by the encoder.
+func elementName(name xml.Name) xml.Name {
+	if prefix, ok := prefixes[name.Space]; ok {
+		return xml.Name{Local: prefix + ":" + name.Local}
+	}
+
+	return name
+}
+
+func status(code int) string {
+	return fmt.Sprintf("HTTP/1.1 %d %s", code, http.StatusText(code))
+}
@@ -0,0 +1,128 @@
+package caldav
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePropfind(t *testing.T) {
+	testCases := []struct {
+		name             string
+		input            string
+		expectedPropfind Propfind
+		wantErr          bool
+	}{
+		{
+			name: "OK",
+			input: `<?xml version="1.0" encoding="utf-8"?><d:propfind xmlns:d="DAV:" xmlns:cs="http://calendarserver.org/ns/">` +
+				`<d:prop><d:displayname/><cs:getctag/></d:prop></d:propfind>`,
+			expectedPropfind: Propfind{Names: []xml.Name{PropDisplayName, PropGetCTag}},
+			wantErr:          false,
+		},
+		{
+			name:             "Empty body",
+			input:            "",
+			expectedPropfind: Propfind{AllProp: true},
+			wantErr:          false,
+		},
+		{
+			name:             "Allprop",
+			input:            `<propfind xmlns="DAV:"><allprop/></propfind>`,
+			expectedPropfind: Propfind{AllProp: true},
+			wantErr:          false,
+		},
+		{
+			name:    "Invalid body",
+			input:   `<propfind xmlns="DAV:">`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParsePropfind(strings.NewReader(tc.input))
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedPropfind, got)
+			}
+		})
+	}
+}
+
+func TestParseReport(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          string
+		expectedReport Report
+		wantErr        bool
+	}{
+		{
+			name: "Multiget",
+			input: `<c:calendar-multiget xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">` +
+				`<d:prop><d:getetag/><c:calendar-data/></d:prop><d:href>/caldav/calendars/1/1.ics</d:href></c:calendar-multiget>`,
+			expectedReport: Report{
+				Name:  ReportCalendarMultiget,
+				Names: []xml.Name{PropGetETag, PropCalendarData},
+				Hrefs: []string{"/caldav/calendars/1/1.ics"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Query",
+			input: `<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav"><d:prop><d:getetag/></d:prop>` +
+				`<c:filter><c:comp-filter name="VCALENDAR"><c:comp-filter name="VTODO"/></c:comp-filter></c:filter></c:calendar-query>`,
+			expectedReport: Report{
+				Name:       ReportCalendarQuery,
+				Names:      []xml.Name{PropGetETag},
+				Components: []string{"VCALENDAR", "VTODO"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Invalid body",
+			input:   "",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseReport(strings.NewReader(tc.input))
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedReport, got)
+			}
+		})
+	}
+}
+
+func TestMultistatus_Encode(t *testing.T) {
+	resource := NewResource("/caldav/calendars/1/")
+	resource.Set(PropResourceType, CalendarType)
+	resource.SetText(PropDisplayName, "work & home")
+
+	multistatus := NewMultistatus()
+	multistatus.AddResource(resource, []xml.Name{PropDisplayName, {Space: "urn:example", Local: "unknown"}})
+	multistatus.AddStatus("/caldav/calendars/1/2.ics", 404)
+
+	var buf bytes.Buffer
+	err := multistatus.Encode(&buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, xml.Header+
+		`<D:multistatus xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav" xmlns:CS="http://calendarserver.org/ns/">`+
+		`<D:response><D:href>/caldav/calendars/1/</D:href>`+
+		`<D:propstat><D:prop><D:displayname>work &amp; home</D:displayname></D:prop><D:status>HTTP/1.1 200 OK</D:status></D:propstat>`+
+		`<D:propstat><D:prop><unknown xmlns="urn:example"></unknown></D:prop><D:status>HTTP/1.1 404 Not Found</D:status></D:propstat>`+
+		`</D:response>`+
+		`<D:response><D:href>/caldav/calendars/1/2.ics</D:href><D:status>HTTP/1.1 404 Not Found</D:status></D:response>`+
+		`</D:multistatus>`, buf.String())
+}
@@ -7,12 +7,22 @@ import (
 	"github.com/spf13/viper"
 )
 
-const configName = "config"
+const (
+	configName     = "config"
+	postgresDriver = "postgres"
+)
 
 type Config struct {
 	Server
+	GRPCServer
 	Service
+	Storage
 	PostgresDB
+	Tracing
+	Log
+	RateLimit
+	Mail
+	OIDC
 }
 
 type Server struct {
@@ -24,19 +34,106 @@ type Server struct {
 	WriteTimeout   int `mapstructure:"write_timeout"`
 }
 
+type GRPCServer struct {
+	Host string `mapstructure:"host"`
+	Port string `mapstructure:"port"`
+}
+
 type Service struct {
 	TokenTTL   int `mapstructure:"token_ttl"`
 	SigningKey string
 	Salt       string
+	// AdminToken authenticates the lockout routes of the admin API next to the sessions of the admins,
+	// it's not accepted when it's empty.
+	AdminToken         string
+	LockoutThreshold   int `mapstructure:"lockout_threshold"`
+	LockoutDuration    int `mapstructure:"lockout_duration"`
+	MaxLockoutDuration int `mapstructure:"max_lockout_duration"`
+	// RequireVerification blocks the sign-in until the email of the account is verified.
+	RequireVerification bool `mapstructure:"require_verification"`
+	VerificationTTL     int  `mapstructure:"verification_ttl"`
+	PasswordResetTTL    int  `mapstructure:"password_reset_ttl"`
+	// TwoFactorChallengeTTL is the seconds the second step of the sign-in can take.
+	TwoFactorChallengeTTL int `mapstructure:"two_factor_challenge_ttl"`
+	// BaseURL is where the app is reached from the links in the emails.
+	BaseURL string `mapstructure:"base_url"`
 }
 
-type PostgresDB struct {
+type Log struct {
+	Level  string `mapstructure:"level"`
+	Format string `mapstructure:"format"`
+}
+
+type Mail struct {
 	Host     string `mapstructure:"host"`
 	Port     string `mapstructure:"port"`
 	Username string `mapstructure:"username"`
 	Password string
-	DBName   string `mapstructure:"db_name"`
-	SSLMode  string `mapstructure:"ssl_mode"`
+	From     string `mapstructure:"from"`
+}
+
+type RateLimit struct {
+	Enabled   bool            `mapstructure:"enabled"`
+	AuthIP    RateLimitPolicy `mapstructure:"auth_ip"`
+	AuthEmail RateLimitPolicy `mapstructure:"auth_email"`
+	APIUser   RateLimitPolicy `mapstructure:"api_user"`
+}
+
+type RateLimitPolicy struct {
+	Requests int `mapstructure:"requests"`
+	Period   int `mapstructure:"period"`
+	Burst    int `mapstructure:"burst"`
+}
+
+type Tracing struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	ServiceName string  `mapstructure:"service_name"`
+	Endpoint    string  `mapstructure:"endpoint"`
+	Insecure    bool    `mapstructure:"insecure"`
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
+type Storage struct {
+	Driver        string `mapstructure:"driver"`
+	SQLitePath    string `mapstructure:"sqlite_path"`
+	StatsInterval int    `mapstructure:"stats_interval"`
+}
+
+// OIDC is the identity provider the users can sign in with, its endpoints and keys are
+// read from the discovery document of the issuer.
+type OIDC struct {
+	Enabled      bool   `mapstructure:"enabled"`
+	Issuer       string `mapstructure:"issuer"`
+	ClientID     string `mapstructure:"client_id"`
+	ClientSecret string
+	RedirectURL  string   `mapstructure:"redirect_url"`
+	Scopes       []string `mapstructure:"scopes"`
+}
+
+type PostgresDB struct {
+	Host         string `mapstructure:"host"`
+	Port         string `mapstructure:"port"`
+	Username     string `mapstructure:"username"`
+	Password     string
+	DBName       string `mapstructure:"db_name"`
+	SSLMode      string `mapstructure:"ssl_mode"`
+	AutoMigrate  bool   `mapstructure:"auto_migrate"`
+	QueryTimeout int    `mapstructure:"query_timeout"`
+	// The replicas share the credentials and the database name with the primary.
+	Replicas            []PostgresReplica `mapstructure:"replicas"`
+	HealthCheckInterval int               `mapstructure:"health_check_interval"`
+	StickyWindow        int               `mapstructure:"sticky_window"`
+	MaxOpenConns        int               `mapstructure:"max_open_conns"`
+	MaxIdleConns        int               `mapstructure:"max_idle_conns"`
+	ConnMaxLifetime     int               `mapstructure:"conn_max_lifetime"`
+	ConnMaxIdleTime     int               `mapstructure:"conn_max_idle_time"`
+	ConnectRetries      int               `mapstructure:"connect_retries"`
+	ConnectBackoff      int               `mapstructure:"connect_backoff"`
+}
+
+type PostgresReplica struct {
+	Host string `mapstructure:"host"`
+	Port string `mapstructure:"port"`
 }
 
 func New(configPath string) (Config, error) {
@@ -46,6 +143,13 @@ func New(configPath string) (Config, error) {
 func parseConfig(configPath string) (Config, error) {
 	viper.AddConfigPath(configPath)
 	viper.SetConfigName(configName)
+	viper.SetDefault("storage.driver", postgresDriver)
+	viper.SetDefault("log.level", "info")
+	viper.SetDefault("log.format", "json")
+	viper.SetDefault("service.verification_ttl", 86400)
+	viper.SetDefault("service.password_reset_ttl", 3600)
+	viper.SetDefault("service.two_factor_challenge_ttl", 300)
+	viper.SetDefault("oidc.scopes", []string{"openid", "email", "profile"})
 
 	if err := viper.ReadInConfig(); err != nil {
 		return Config{}, err
@@ -68,20 +172,48 @@ func unmarshal(cfg *Config) error {
 		return err
 	}
 
+	if err := viper.UnmarshalKey("grpc_server", &cfg.GRPCServer); err != nil {
+		return err
+	}
+
 	if err := viper.UnmarshalKey("service", &cfg.Service); err != nil {
 		return err
 	}
 
+	if err := viper.UnmarshalKey("storage", &cfg.Storage); err != nil {
+		return err
+	}
+
 	if err := viper.UnmarshalKey("postgres_db", &cfg.PostgresDB); err != nil {
 		return err
 	}
 
+	if err := viper.UnmarshalKey("tracing", &cfg.Tracing); err != nil {
+		return err
+	}
+
+	if err := viper.UnmarshalKey("log", &cfg.Log); err != nil {
+		return err
+	}
+
+	if err := viper.UnmarshalKey("rate_limit", &cfg.RateLimit); err != nil {
+		return err
+	}
+
+	if err := viper.UnmarshalKey("mail", &cfg.Mail); err != nil {
+		return err
+	}
+
+	if err := viper.UnmarshalKey("oidc", &cfg.OIDC); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func loadEnv(cfg *Config) error {
 	postgresPassword := os.Getenv("POSTGRES_PASSWORD")
-	if postgresPassword == "" {
+	if postgresPassword == "" && cfg.Storage.Driver == postgresDriver {
 		return errPostgresPasswordIsEmpty
 	}
 
@@ -98,6 +230,9 @@ func loadEnv(cfg *Config) error {
 	cfg.PostgresDB.Password = postgresPassword
 	cfg.Service.SigningKey = signingKey
 	cfg.Salt = salt
+	cfg.AdminToken = os.Getenv("ADMIN_TOKEN")
+	cfg.Mail.Password = os.Getenv("SMTP_PASSWORD")
+	cfg.OIDC.ClientSecret = os.Getenv("OIDC_CLIENT_SECRET")
 
 	return nil
 }
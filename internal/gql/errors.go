@@ -0,0 +1,10 @@
+package gql
+
+import "errors"
+
+var (
+	errInvalidInput      = errors.New("invalid input")
+	errEmptyUpdate       = errors.New("update has no fields")
+	errInvalidID         = errors.New("invalid id")
+	errFailedToGetUserID = errors.New("failed to get user id")
+)
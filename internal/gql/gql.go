@@ -0,0 +1,54 @@
+package gql
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	"github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaString string
+
+type userIDKey struct{}
+
+type itemLoaderKey struct{}
+
+type Schema struct {
+	schema  *graphql.Schema
+	service *service.Service
+}
+
+func NewSchema(service *service.Service) *Schema {
+	return &Schema{
+		schema:  graphql.MustParseSchema(schemaString, &resolver{service: service}),
+		service: service,
+	}
+}
+
+// Exec executes the query on behalf of the user. Every call gets its own item loader,
+// so the items are never shared between requests.
+func (s *Schema) Exec(ctx context.Context, userID int, query, operationName string, variables map[string]interface{}) *graphql.Response {
+	loader := newItemLoader(func(listIDs []int) ([]model.TodoItem, error) {
+		return s.service.TodoItem.GetAllByLists(ctx, userID, listIDs)
+	})
+
+	ctx = context.WithValue(ctx, userIDKey{}, userID)
+	ctx = context.WithValue(ctx, itemLoaderKey{}, loader)
+
+	return s.schema.Exec(ctx, query, operationName, variables)
+}
+
+func getUserID(ctx context.Context) int {
+	id, _ := ctx.Value(userIDKey{}).(int)
+
+	return id
+}
+
+func getItemLoader(ctx context.Context) *itemLoader {
+	loader, _ := ctx.Value(itemLoaderKey{}).(*itemLoader)
+
+	return loader
+}
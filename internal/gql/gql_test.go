@@ -0,0 +1,130 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	mockService "github.com/Lapp-coder/todo-app/internal/service/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema_Exec(t *testing.T) {
+	// Arrange
+	type mockBehavior func(list *mockService.MockTodoList, item *mockService.MockTodoItem, userID int)
+
+	testCases := []struct {
+		name             string
+		query            string
+		variables        map[string]interface{}
+		inputUserID      int
+		mockBehavior     mockBehavior
+		expectedResponse string
+	}{
+		{
+			name:        "OK_ListsWithItems",
+			query:       `{ lists { id items { id } } }`,
+			inputUserID: 1,
+			mockBehavior: func(list *mockService.MockTodoList, item *mockService.MockTodoItem, userID int) {
+				lists := make([]model.TodoList, 0, 5)
+				for id := 1; id <= 5; id++ {
+					lists = append(lists, model.TodoList{ID: id})
+				}
+				list.EXPECT().GetAll(gomock.Any(), userID).Return(lists, nil)
+				// The items of all the lists are loaded with one call.
+				item.EXPECT().GetAllByLists(gomock.Any(), userID, []int{1, 2, 3, 4, 5}).Return([]model.TodoItem{
+					{ID: 1, ListID: 2},
+					{ID: 2, ListID: 5},
+				}, nil).Times(1)
+			},
+			expectedResponse: `{"data":{"lists":[` +
+				`{"id":"1","items":[]},{"id":"2","items":[{"id":"1"}]},{"id":"3","items":[]},` +
+				`{"id":"4","items":[]},{"id":"5","items":[{"id":"2"}]}]}}`,
+		},
+		{
+			name:        "OK_ListWithItems",
+			query:       `{ list(id: "2") { id items { id } } }`,
+			inputUserID: 1,
+			mockBehavior: func(list *mockService.MockTodoList, item *mockService.MockTodoItem, userID int) {
+				list.EXPECT().GetByID(gomock.Any(), userID, 2).Return(model.TodoList{ID: 2}, nil)
+				item.EXPECT().GetAllByLists(gomock.Any(), userID, []int{2}).Return([]model.TodoItem{{ID: 1, ListID: 2}}, nil)
+			},
+			expectedResponse: `{"data":{"list":{"id":"2","items":[{"id":"1"}]}}}`,
+		},
+		{
+			name:        "Items failure",
+			query:       `{ lists { id items { id } } }`,
+			inputUserID: 1,
+			mockBehavior: func(list *mockService.MockTodoList, item *mockService.MockTodoItem, userID int) {
+				list.EXPECT().GetAll(gomock.Any(), userID).Return([]model.TodoList{{ID: 1}}, nil)
+				item.EXPECT().GetAllByLists(gomock.Any(), userID, []int{1}).Return(nil, service.ErrFailedToGetAllItems)
+			},
+			expectedResponse: `{"errors":[{"message":"failed to get all items","path":["lists",0,"items"]}],"data":null}`,
+		},
+		{
+			name:             "Invalid input",
+			query:            `mutation { createItem(listId: "1", input: {title: ""}) }`,
+			inputUserID:      1,
+			mockBehavior:     func(list *mockService.MockTodoList, item *mockService.MockTodoItem, userID int) {},
+			expectedResponse: `{"errors":[{"message":"invalid input","path":["createItem"]}],"data":null}`,
+		},
+		{
+			name:             "Invalid id",
+			query:            `mutation { deleteList(id: "invalid") }`,
+			inputUserID:      1,
+			mockBehavior:     func(list *mockService.MockTodoList, item *mockService.MockTodoItem, userID int) {},
+			expectedResponse: `{"errors":[{"message":"invalid id","path":["deleteList"]}],"data":null}`,
+		},
+		{
+			name:             "Empty update",
+			query:            `mutation { updateItem(id: "1", input: {}) }`,
+			inputUserID:      1,
+			mockBehavior:     func(list *mockService.MockTodoList, item *mockService.MockTodoItem, userID int) {},
+			expectedResponse: `{"errors":[{"message":"update has no fields","path":["updateItem"]}],"data":null}`,
+		},
+		{
+			name:             "Without user",
+			query:            `mutation($title: String!) { createList(input: {title: $title}) }`,
+			variables:        map[string]interface{}{"title": "test"},
+			mockBehavior:     func(list *mockService.MockTodoList, item *mockService.MockTodoItem, userID int) {},
+			expectedResponse: `{"errors":[{"message":"failed to get user id","path":["createList"]}],"data":null}`,
+		},
+		{
+			name:        "Service failure",
+			query:       `mutation { updateList(id: "1", input: {title: "test"}) }`,
+			inputUserID: 1,
+			mockBehavior: func(list *mockService.MockTodoList, item *mockService.MockTodoItem, userID int) {
+				title := "test"
+				list.EXPECT().Update(gomock.Any(), userID, 1, model.UpdateTodoList{Title: &title}).Return(service.ErrWorkspaceReadOnly)
+			},
+			expectedResponse: `{"errors":[{"message":"` + service.ErrWorkspaceReadOnly.Error() + `","path":["updateList"]}],"data":null}`,
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			list := mockService.NewMockTodoList(c)
+			item := mockService.NewMockTodoItem(c)
+			tc.mockBehavior(list, item, tc.inputUserID)
+
+			schema := NewSchema(&service.Service{TodoList: list, TodoItem: item})
+
+			// Perform request
+			response := schema.Exec(context.Background(), tc.inputUserID, tc.query, "", tc.variables)
+
+			// Assert
+			body, err := json.Marshal(response)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedResponse, string(body))
+		})
+	}
+}
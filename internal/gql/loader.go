@@ -0,0 +1,70 @@
+package gql
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+)
+
+// itemLoader batches the item lookups of a single request by list ID.
+// The lists resolved by the query are primed into the loader, so the first
+// items field to be resolved loads the items of all of them with one query
+// and the rest are served from the loaded results.
+type itemLoader struct {
+	mu      sync.Mutex
+	fetch   func(listIDs []int) ([]model.TodoItem, error)
+	pending map[int]struct{}
+	items   map[int][]model.TodoItem
+}
+
+func newItemLoader(fetch func(listIDs []int) ([]model.TodoItem, error)) *itemLoader {
+	return &itemLoader{
+		fetch:   fetch,
+		pending: make(map[int]struct{}),
+		items:   make(map[int][]model.TodoItem),
+	}
+}
+
+func (l *itemLoader) prime(listIDs ...int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, id := range listIDs {
+		if _, ok := l.items[id]; !ok {
+			l.pending[id] = struct{}{}
+		}
+	}
+}
+
+func (l *itemLoader) load(listID int) ([]model.TodoItem, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if items, ok := l.items[listID]; ok {
+		return items, nil
+	}
+
+	l.pending[listID] = struct{}{}
+	listIDs := make([]int, 0, len(l.pending))
+	for id := range l.pending {
+		listIDs = append(listIDs, id)
+	}
+	sort.Ints(listIDs)
+
+	items, err := l.fetch(listIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	l.pending = make(map[int]struct{})
+	for _, id := range listIDs {
+		l.items[id] = []model.TodoItem{}
+	}
+
+	for _, item := range items {
+		l.items[item.ListID] = append(l.items[item.ListID], item)
+	}
+
+	return l.items[listID], nil
+}
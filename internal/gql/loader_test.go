@@ -0,0 +1,86 @@
+package gql
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemLoader_load(t *testing.T) {
+	// Arrange
+	var calls [][]int
+	loader := newItemLoader(func(listIDs []int) ([]model.TodoItem, error) {
+		calls = append(calls, listIDs)
+		return []model.TodoItem{
+			{ID: 1, ListID: 1, Title: "milk"},
+			{ID: 2, ListID: 3, Title: "bread"},
+			{ID: 3, ListID: 1, Title: "eggs"},
+		}, nil
+	})
+
+	// Act
+	loader.prime(3, 1, 2)
+
+	first, err := loader.load(1)
+	require.NoError(t, err)
+	second, err := loader.load(2)
+	require.NoError(t, err)
+	third, err := loader.load(3)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, [][]int{{1, 2, 3}}, calls)
+	assert.Equal(t, []model.TodoItem{{ID: 1, ListID: 1, Title: "milk"}, {ID: 3, ListID: 1, Title: "eggs"}}, first)
+	assert.Equal(t, []model.TodoItem{}, second)
+	assert.Equal(t, []model.TodoItem{{ID: 2, ListID: 3, Title: "bread"}}, third)
+}
+
+func TestItemLoader_loadNotPrimed(t *testing.T) {
+	// Arrange
+	var calls [][]int
+	loader := newItemLoader(func(listIDs []int) ([]model.TodoItem, error) {
+		calls = append(calls, listIDs)
+		return nil, nil
+	})
+
+	// Act
+	_, err := loader.load(5)
+	require.NoError(t, err)
+	_, err = loader.load(5)
+	require.NoError(t, err)
+	_, err = loader.load(6)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, [][]int{{5}, {6}}, calls)
+}
+
+func TestItemLoader_loadFailure(t *testing.T) {
+	// Arrange
+	errFetch := errors.New("failed to get items")
+	fail := true
+	var calls [][]int
+	loader := newItemLoader(func(listIDs []int) ([]model.TodoItem, error) {
+		calls = append(calls, listIDs)
+		if fail {
+			return nil, errFetch
+		}
+		return nil, nil
+	})
+
+	// Act
+	loader.prime(1, 2)
+	_, err := loader.load(1)
+
+	// Assert
+	assert.ErrorIs(t, err, errFetch)
+
+	// The failed lists stay pending and are fetched again.
+	fail = false
+	_, err = loader.load(2)
+	require.NoError(t, err)
+	assert.Equal(t, [][]int{{1, 2}, {1, 2}}, calls)
+}
@@ -0,0 +1,283 @@
+package gql
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/graph-gophers/graphql-go"
+)
+
+type resolver struct {
+	service *service.Service
+}
+
+type idArgs struct {
+	ID graphql.ID
+}
+
+type createListArgs struct {
+	Input struct {
+		Title          string
+		Description    *string
+		CompletionDate *string
+	}
+}
+
+type updateListArgs struct {
+	ID    graphql.ID
+	Input struct {
+		Title          *string
+		Description    *string
+		CompletionDate *string
+	}
+}
+
+type createItemArgs struct {
+	ListID graphql.ID
+	Input  struct {
+		Title          string
+		Description    *string
+		CompletionDate *string
+		Done           *bool
+	}
+}
+
+type updateItemArgs struct {
+	ID    graphql.ID
+	Input struct {
+		Title          *string
+		Description    *string
+		CompletionDate *string
+		Done           *bool
+	}
+}
+
+func (r *resolver) Lists(ctx context.Context) ([]*listResolver, error) {
+	userID := getUserID(ctx)
+	if userID == 0 {
+		return nil, errFailedToGetUserID
+	}
+
+	lists, err := r.service.TodoList.GetAll(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*listResolver, 0, len(lists))
+	listIDs := make([]int, 0, len(lists))
+	for _, list := range lists {
+		resolvers = append(resolvers, &listResolver{list: list})
+		listIDs = append(listIDs, list.ID)
+	}
+
+	getItemLoader(ctx).prime(listIDs...)
+
+	return resolvers, nil
+}
+
+func (r *resolver) List(ctx context.Context, args idArgs) (*listResolver, error) {
+	userID := getUserID(ctx)
+	if userID == 0 {
+		return nil, errFailedToGetUserID
+	}
+
+	listID, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := r.service.TodoList.GetByID(ctx, userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &listResolver{list: list}, nil
+}
+
+func (r *resolver) Item(ctx context.Context, args idArgs) (*itemResolver, error) {
+	userID := getUserID(ctx)
+	if userID == 0 {
+		return nil, errFailedToGetUserID
+	}
+
+	itemID, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := r.service.TodoItem.GetByID(ctx, userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &itemResolver{item: item}, nil
+}
+
+func (r *resolver) CreateList(ctx context.Context, args createListArgs) (graphql.ID, error) {
+	userID := getUserID(ctx)
+	if userID == 0 {
+		return "", errFailedToGetUserID
+	}
+
+	req := model.CreateTodoList{
+		Title:          args.Input.Title,
+		Description:    stringValue(args.Input.Description),
+		CompletionDate: stringValue(args.Input.CompletionDate),
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return "", errInvalidInput
+	}
+
+	list := model.TodoList{Title: req.Title, Description: req.Description, CompletionDate: req.CompletionDate}
+	listID, err := r.service.TodoList.Create(ctx, userID, list)
+	if err != nil {
+		return "", err
+	}
+
+	return formatID(listID), nil
+}
+
+func (r *resolver) UpdateList(ctx context.Context, args updateListArgs) (bool, error) {
+	userID := getUserID(ctx)
+	if userID == 0 {
+		return false, errFailedToGetUserID
+	}
+
+	listID, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	update := model.UpdateTodoList{
+		Title:          args.Input.Title,
+		Description:    args.Input.Description,
+		CompletionDate: args.Input.CompletionDate,
+	}
+	if update.IsNilAllFields() {
+		return false, errEmptyUpdate
+	}
+
+	if err = r.service.TodoList.Update(ctx, userID, listID, update); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *resolver) DeleteList(ctx context.Context, args idArgs) (bool, error) {
+	userID := getUserID(ctx)
+	if userID == 0 {
+		return false, errFailedToGetUserID
+	}
+
+	listID, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if err = r.service.TodoList.Delete(ctx, userID, listID); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *resolver) CreateItem(ctx context.Context, args createItemArgs) (graphql.ID, error) {
+	userID := getUserID(ctx)
+	if userID == 0 {
+		return "", errFailedToGetUserID
+	}
+
+	listID, err := parseID(args.ListID)
+	if err != nil {
+		return "", err
+	}
+
+	req := model.CreateTodoItem{
+		Title:          args.Input.Title,
+		Description:    stringValue(args.Input.Description),
+		CompletionDate: stringValue(args.Input.CompletionDate),
+		Done:           args.Input.Done != nil && *args.Input.Done,
+	}
+	if err = binding.Validator.ValidateStruct(req); err != nil {
+		return "", errInvalidInput
+	}
+
+	item := model.TodoItem{Title: req.Title, Description: req.Description, CompletionDate: req.CompletionDate, Done: req.Done}
+	itemID, err := r.service.TodoItem.Create(ctx, userID, listID, item)
+	if err != nil {
+		return "", err
+	}
+
+	return formatID(itemID), nil
+}
+
+func (r *resolver) UpdateItem(ctx context.Context, args updateItemArgs) (bool, error) {
+	userID := getUserID(ctx)
+	if userID == 0 {
+		return false, errFailedToGetUserID
+	}
+
+	itemID, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	update := model.UpdateTodoItem{
+		Title:          args.Input.Title,
+		Description:    args.Input.Description,
+		CompletionDate: args.Input.CompletionDate,
+		Done:           args.Input.Done,
+	}
+	if update.IsNilAllFields() {
+		return false, errEmptyUpdate
+	}
+
+	if err = r.service.TodoItem.Update(ctx, userID, itemID, update); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *resolver) DeleteItem(ctx context.Context, args idArgs) (bool, error) {
+	userID := getUserID(ctx)
+	if userID == 0 {
+		return false, errFailedToGetUserID
+	}
+
+	itemID, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if err = r.service.TodoItem.Delete(ctx, userID, itemID); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func parseID(id graphql.ID) (int, error) {
+	value, err := strconv.Atoi(string(id))
+	if err != nil || value <= 0 {
+		return 0, errInvalidID
+	}
+
+	return value, nil
+}
+
+func formatID(id int) graphql.ID {
+	return graphql.ID(strconv.Itoa(id))
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
@@ -0,0 +1,70 @@
+package gql
+
+import (
+	"context"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/graph-gophers/graphql-go"
+)
+
+type listResolver struct {
+	list model.TodoList
+}
+
+func (r *listResolver) ID() graphql.ID {
+	return formatID(r.list.ID)
+}
+
+func (r *listResolver) Title() string {
+	return r.list.Title
+}
+
+func (r *listResolver) Description() string {
+	return r.list.Description
+}
+
+func (r *listResolver) CompletionDate() string {
+	return r.list.CompletionDate
+}
+
+func (r *listResolver) Items(ctx context.Context) ([]*itemResolver, error) {
+	items, err := getItemLoader(ctx).load(r.list.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*itemResolver, 0, len(items))
+	for _, item := range items {
+		resolvers = append(resolvers, &itemResolver{item: item})
+	}
+
+	return resolvers, nil
+}
+
+type itemResolver struct {
+	item model.TodoItem
+}
+
+func (r *itemResolver) ID() graphql.ID {
+	return formatID(r.item.ID)
+}
+
+func (r *itemResolver) ListID() graphql.ID {
+	return formatID(r.item.ListID)
+}
+
+func (r *itemResolver) Title() string {
+	return r.item.Title
+}
+
+func (r *itemResolver) Description() string {
+	return r.item.Description
+}
+
+func (r *itemResolver) CompletionDate() string {
+	return r.item.CompletionDate
+}
+
+func (r *itemResolver) Done() bool {
+	return r.item.Done
+}
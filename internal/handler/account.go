@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// getProfile godoc
+// @Summary Get profile
+// @Security ApiKeyAuth
+// @Tags account
+// @Description get the profile of the signed in user
+// @ID get-profile
+// @Produce json
+// @Success 200 {object} model.Profile
+// @Failure 401,403 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /api/me [get]
+func (h Handler) getProfile(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	profile, err := h.service.Authorization.GetProfile(ctx.Request.Context(), userID)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, profile)
+}
+
+// updateProfile godoc
+// @Summary Update profile
+// @Security ApiKeyAuth
+// @Tags account
+// @Description change the name, the email, the timezone or the locale, a new email needs the current password and is pending until it's verified
+// @ID update-profile
+// @Accept json
+// @Produce json
+// @Param input body model.UpdateProfile true "Update values"
+// @Success 200 {object} model.Profile
+// @Failure 400 {object} swagger.ErrorResponse
+// @Failure 401,403,409 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /api/me [patch]
+func (h Handler) updateProfile(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	var req model.UpdateProfile
+	if err := ctx.BindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidInputBody)
+		return
+	}
+
+	if req.IsNilAllFields() {
+		respondError(ctx, http.StatusBadRequest, errInvalidInputBody)
+		return
+	}
+
+	profile, err := h.service.Authorization.UpdateProfile(ctx.Request.Context(), userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTimezone) || errors.Is(err, service.ErrInvalidLocale) {
+			respondError(ctx, http.StatusBadRequest, err)
+			return
+		}
+
+		if errors.Is(err, service.ErrIncorrectPassword) {
+			respondError(ctx, http.StatusForbidden, err)
+			return
+		}
+
+		if errors.Is(err, service.ErrEmailAlreadyTaken) {
+			respondError(ctx, http.StatusConflict, err)
+			return
+		}
+
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, profile)
+}
+
+// deleteAccount godoc
+// @Summary Delete account
+// @Security ApiKeyAuth
+// @Tags account
+// @Description delete the account with its personal workspace and tokens, it can't be undone. The lists created in the shared workspaces go to their owners, the last owner of a shared workspace can't delete the account
+// @ID delete-account
+// @Produce json
+// @Success 200 {string} string "Result"
+// @Failure 401,403,409 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /api/me [delete]
+func (h Handler) deleteAccount(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	if err := h.service.Authorization.DeleteAccount(ctx.Request.Context(), userID); err != nil {
+		if errors.Is(err, service.ErrLastWorkspaceOwner) {
+			respondError(ctx, http.StatusConflict, err)
+			return
+		}
+
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"result": "the account deletion was successful",
+	})
+}
+
+// changePassword godoc
+// @Summary Change password
+// @Security ApiKeyAuth
+// @Tags account
+// @Description change the password, all the sessions are signed out and a new token is returned for this one
+// @ID change-password
+// @Accept json
+// @Produce json
+// @Param input body model.ChangePassword true "Current and new password"
+// @Success 200 {string} string "Token"
+// @Failure 400 {object} swagger.ErrorResponse
+// @Failure 401 {object} swagger.ErrorResponse
+// @Failure 403 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /api/me/password [put]
+func (h Handler) changePassword(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	var req model.ChangePassword
+	if err := ctx.BindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidInputBody)
+		return
+	}
+
+	token, err := h.service.Authorization.ChangePassword(ctx.Request.Context(), userID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		if errors.Is(err, service.ErrIncorrectPassword) {
+			respondError(ctx, http.StatusForbidden, err)
+			return
+		}
+
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"token": token,
+	})
+}
+
+// setupTwoFactor godoc
+// @Summary Set up two-factor authentication
+// @Security ApiKeyAuth
+// @Tags account
+// @Description generate a secret for an authenticator app, the two-factor authentication is enabled once a code confirms it
+// @ID setup-2fa
+// @Produce json
+// @Success 200 {object} model.TwoFactorSetup
+// @Failure 401 {object} swagger.ErrorResponse
+// @Failure 409 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /api/me/2fa/setup [post]
+func (h Handler) setupTwoFactor(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	setup, err := h.service.Authorization.SetupTwoFactor(ctx.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTwoFactorEnabled) {
+			respondError(ctx, http.StatusConflict, err)
+			return
+		}
+
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, setup)
+}
+
+// confirmTwoFactor godoc
+// @Summary Confirm two-factor authentication
+// @Security ApiKeyAuth
+// @Tags account
+// @Description enable the two-factor authentication with a code from the app, the recovery codes are only returned here
+// @ID confirm-2fa
+// @Accept json
+// @Produce json
+// @Param input body model.ConfirmTwoFactor true "Code"
+// @Success 200 {array} string "Recovery codes"
+// @Failure 400 {object} swagger.ErrorResponse
+// @Failure 401 {object} swagger.ErrorResponse
+// @Failure 409 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /api/me/2fa/confirm [post]
+func (h Handler) confirmTwoFactor(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	var req model.ConfirmTwoFactor
+	if err := ctx.BindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidInputBody)
+		return
+	}
+
+	codes, err := h.service.Authorization.ConfirmTwoFactor(ctx.Request.Context(), userID, req.Code)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTwoFactorCode) || errors.Is(err, service.ErrTwoFactorNotSetUp) {
+			respondError(ctx, http.StatusBadRequest, err)
+			return
+		}
+
+		if errors.Is(err, service.ErrTwoFactorEnabled) {
+			respondError(ctx, http.StatusConflict, err)
+			return
+		}
+
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"recovery_codes": codes,
+	})
+}
@@ -0,0 +1,628 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/Lapp-coder/todo-app/internal/config"
+	"github.com/Lapp-coder/todo-app/internal/mail/mailtest"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/repository"
+	"github.com/Lapp-coder/todo-app/internal/repository/memory"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	mockService "github.com/Lapp-coder/todo-app/internal/service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_updateProfile(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockAuthorization, userID interface{})
+
+	name, email, timezone := "renamed", "new@mail.ru", "Europe/Moscow"
+	profile := model.Profile{ID: 1, Name: name, Email: email, Timezone: timezone, Locale: "en"}
+
+	testCases := []struct {
+		name                 string
+		inputUserID          interface{}
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			inputUserID: 1,
+			inputBody:   `{"name": "renamed", "email": "new@mail.ru", "timezone": "Europe/Moscow"}`,
+			mockBehavior: func(s *mockService.MockAuthorization, userID interface{}) {
+				update := model.UpdateProfile{Name: &name, Email: &email, Timezone: &timezone}
+				s.EXPECT().UpdateProfile(gomock.Any(), userID, update).Return(profile, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"id":1,"name":"renamed","email":"new@mail.ru","email_verified":false,` +
+				`"timezone":"Europe/Moscow","locale":"en","two_factor_enabled":false}`,
+		},
+		{
+			name:                 "Invalid user id",
+			inputUserID:          "invalid",
+			inputBody:            `{"name": "renamed"}`,
+			mockBehavior:         func(s *mockService.MockAuthorization, userID interface{}) {},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errFailedToGetUserID.Error()),
+		},
+		{
+			name:                 "Empty update",
+			inputUserID:          1,
+			inputBody:            `{}`,
+			mockBehavior:         func(s *mockService.MockAuthorization, userID interface{}) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidInputBody.Error()),
+		},
+		{
+			name:                 "Invalid email",
+			inputUserID:          1,
+			inputBody:            `{"email": "new"}`,
+			mockBehavior:         func(s *mockService.MockAuthorization, userID interface{}) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidInputBody.Error()),
+		},
+		{
+			name:        "Invalid timezone",
+			inputUserID: 1,
+			inputBody:   `{"timezone": "Europe/Moscow"}`,
+			mockBehavior: func(s *mockService.MockAuthorization, userID interface{}) {
+				s.EXPECT().UpdateProfile(gomock.Any(), userID, model.UpdateProfile{Timezone: &timezone}).
+					Return(model.Profile{}, service.ErrInvalidTimezone)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrInvalidTimezone.Error()),
+		},
+		{
+			name:        "Incorrect password",
+			inputUserID: 1,
+			inputBody:   `{"email": "new@mail.ru"}`,
+			mockBehavior: func(s *mockService.MockAuthorization, userID interface{}) {
+				s.EXPECT().UpdateProfile(gomock.Any(), userID, model.UpdateProfile{Email: &email}).
+					Return(model.Profile{}, service.ErrIncorrectPassword)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrIncorrectPassword.Error()),
+		},
+		{
+			name:        "Email taken",
+			inputUserID: 1,
+			inputBody:   `{"email": "new@mail.ru"}`,
+			mockBehavior: func(s *mockService.MockAuthorization, userID interface{}) {
+				s.EXPECT().UpdateProfile(gomock.Any(), userID, model.UpdateProfile{Email: &email}).
+					Return(model.Profile{}, service.ErrEmailAlreadyTaken)
+			},
+			expectedStatusCode:   409,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrEmailAlreadyTaken.Error()),
+		},
+		{
+			name:        "Service failure",
+			inputUserID: 1,
+			inputBody:   `{"name": "renamed"}`,
+			mockBehavior: func(s *mockService.MockAuthorization, userID interface{}) {
+				s.EXPECT().UpdateProfile(gomock.Any(), userID, model.UpdateProfile{Name: &name}).
+					Return(model.Profile{}, service.ErrFailedToUpdateProfile)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToUpdateProfile.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mockService.NewMockAuthorization(c)
+			tc.mockBehavior(auth, tc.inputUserID)
+
+			services := &service.Service{Authorization: auth}
+			handler := New(services, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			r.PATCH(
+				"/api/me",
+				func(c *gin.Context) {
+					c.Set(userCtx, tc.inputUserID)
+				},
+				handler.updateProfile)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PATCH", "/api/me", bytes.NewBufferString(tc.inputBody))
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_deleteAccount(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockAuthorization, userID interface{})
+
+	testCases := []struct {
+		name                 string
+		inputUserID          interface{}
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			inputUserID: 1,
+			mockBehavior: func(s *mockService.MockAuthorization, userID interface{}) {
+				s.EXPECT().DeleteAccount(gomock.Any(), userID).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"result":"the account deletion was successful"}`,
+		},
+		{
+			name:                 "Invalid user id",
+			inputUserID:          "invalid",
+			mockBehavior:         func(s *mockService.MockAuthorization, userID interface{}) {},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errFailedToGetUserID.Error()),
+		},
+		{
+			name:        "Last owner of a workspace",
+			inputUserID: 1,
+			mockBehavior: func(s *mockService.MockAuthorization, userID interface{}) {
+				s.EXPECT().DeleteAccount(gomock.Any(), userID).Return(service.ErrLastWorkspaceOwner)
+			},
+			expectedStatusCode:   409,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrLastWorkspaceOwner.Error()),
+		},
+		{
+			name:        "Service failure",
+			inputUserID: 1,
+			mockBehavior: func(s *mockService.MockAuthorization, userID interface{}) {
+				s.EXPECT().DeleteAccount(gomock.Any(), userID).Return(service.ErrFailedToDeleteAccount)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToDeleteAccount.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mockService.NewMockAuthorization(c)
+			tc.mockBehavior(auth, tc.inputUserID)
+
+			services := &service.Service{Authorization: auth}
+			handler := New(services, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			r.DELETE(
+				"/api/me",
+				func(c *gin.Context) {
+					c.Set(userCtx, tc.inputUserID)
+				},
+				handler.deleteAccount)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", "/api/me", nil)
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+// TestHandler_profile changes the email, which is pending until it's verified, and deletes the account.
+func TestHandler_profile(t *testing.T) {
+	mailer := &mailtest.Mailer{}
+	cfg := config.Service{
+		SigningKey:      "key",
+		Salt:            "salt",
+		TokenTTL:        60,
+		VerificationTTL: 60,
+		BaseURL:         "http://todo.local",
+	}
+	services := service.New(repository.NewMemory(memory.NewStore()), cfg, nil, service.LogNotifier{}, mailer, nil)
+	r := New(services, nil).InitRoutes()
+
+	request := func(method, target, token, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	getProfile := func(token string) model.Profile {
+		w := request("GET", "/api/me", token, "")
+		require.Equal(t, 200, w.Code)
+
+		var profile model.Profile
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &profile))
+		return profile
+	}
+
+	w := request("POST", "/auth/sign-up", "", `{"name": "test", "email": "test@mail.ru", "password": "testing"}`)
+	require.Equal(t, 201, w.Code)
+	w = request("POST", "/auth/sign-up", "", `{"name": "other", "email": "other@mail.ru", "password": "testing"}`)
+	require.Equal(t, 201, w.Code)
+
+	w = request("POST", "/auth/sign-in", "", `{"email": "test@mail.ru", "password": "testing"}`)
+	require.Equal(t, 200, w.Code)
+
+	var session model.SignInResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &session))
+
+	profile := getProfile(session.Token)
+	assert.Equal(t, "test", profile.Name)
+	assert.Equal(t, model.DefaultTimezone, profile.Timezone)
+	assert.Equal(t, model.DefaultLocale, profile.Locale)
+
+	assert.Equal(t, 400, request("PATCH", "/api/me", session.Token, `{"timezone": "Mars/Olympus"}`).Code)
+	assert.Equal(t, 400, request("PATCH", "/api/me", session.Token, `{"locale": "not a locale"}`).Code)
+	assert.Equal(t, 403, request("PATCH", "/api/me", session.Token, `{"email": "new@mail.ru"}`).Code)
+	assert.Equal(t, 403, request("PATCH", "/api/me", session.Token, `{"email": "new@mail.ru", "current_password": "wrong"}`).Code)
+	assert.Equal(t, 409, request("PATCH", "/api/me", session.Token, `{"email": "other@mail.ru", "current_password": "testing"}`).Code)
+
+	w = request("POST", "/auth/forgot-password", "", `{"email": "test@mail.ru"}`)
+	require.Equal(t, 202, w.Code)
+
+	w = request("PATCH", "/api/me", session.Token,
+		`{"email": "new@mail.ru", "current_password": "testing", "timezone": "Europe/Moscow", "locale": "ru-ru"}`)
+	require.Equal(t, 200, w.Code)
+
+	// The new email is pending, the account keeps signing in with the old one until it's verified.
+	profile = getProfile(session.Token)
+	assert.Equal(t, "test@mail.ru", profile.Email)
+	assert.Equal(t, "new@mail.ru", profile.PendingEmail)
+	assert.Equal(t, "Europe/Moscow", profile.Timezone)
+	assert.Equal(t, "ru-RU", profile.Locale)
+	assert.Equal(t, 200, request("POST", "/auth/sign-in", "", `{"email": "test@mail.ru", "password": "testing"}`).Code)
+
+	messages := mailer.Messages()
+	require.Len(t, messages, 4)
+	assert.Equal(t, "new@mail.ru", messages[3].To)
+
+	link := regexp.MustCompile(`http://todo\.local(/auth/verify\?token=\S+)`).FindStringSubmatch(messages[3].Body)
+	require.Len(t, link, 2)
+	assert.Equal(t, 200, request("GET", link[1], "", "").Code)
+
+	profile = getProfile(session.Token)
+	assert.Equal(t, "new@mail.ru", profile.Email)
+	assert.Empty(t, profile.PendingEmail)
+	assert.True(t, profile.EmailVerified)
+
+	// The reset token mailed to the old email doesn't work anymore.
+	reset := regexp.MustCompile(`(?m)^[0-9a-f]{64}$`).FindString(messages[2].Body)
+	require.NotEmpty(t, reset)
+	w = request("POST", "/auth/reset-password", "", fmt.Sprintf(`{"token": "%s", "password": "resetted"}`, reset))
+	assert.Equal(t, 400, w.Code)
+
+	w = request("POST", "/api/lists/", session.Token, `{"title": "list"}`)
+	require.Equal(t, 201, w.Code)
+
+	w = request("DELETE", "/api/me", session.Token, "")
+	require.Equal(t, 200, w.Code)
+
+	assert.Equal(t, 401, request("GET", "/api/me", session.Token, "").Code)
+	assert.NotEqual(t, 200, request("POST", "/auth/sign-in", "", `{"email": "new@mail.ru", "password": "testing"}`).Code)
+}
+
+func TestHandler_changePassword(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockAuthorization, userID interface{})
+
+	testCases := []struct {
+		name                 string
+		inputUserID          interface{}
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			inputUserID: 1,
+			inputBody:   `{"current_password": "qwerty", "new_password": "asdfgh"}`,
+			mockBehavior: func(s *mockService.MockAuthorization, userID interface{}) {
+				s.EXPECT().ChangePassword(gomock.Any(), userID, "qwerty", "asdfgh").Return("token", nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"token":"token"}`,
+		},
+		{
+			name:                 "Invalid user id",
+			inputUserID:          "invalid",
+			inputBody:            `{"current_password": "qwerty", "new_password": "asdfgh"}`,
+			mockBehavior:         func(s *mockService.MockAuthorization, userID interface{}) {},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errFailedToGetUserID.Error()),
+		},
+		{
+			name:                 "Short new password",
+			inputUserID:          1,
+			inputBody:            `{"current_password": "qwerty", "new_password": "asd"}`,
+			mockBehavior:         func(s *mockService.MockAuthorization, userID interface{}) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidInputBody.Error()),
+		},
+		{
+			name:        "Incorrect password",
+			inputUserID: 1,
+			inputBody:   `{"current_password": "qwerty", "new_password": "asdfgh"}`,
+			mockBehavior: func(s *mockService.MockAuthorization, userID interface{}) {
+				s.EXPECT().ChangePassword(gomock.Any(), userID, "qwerty", "asdfgh").Return("", service.ErrIncorrectPassword)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrIncorrectPassword.Error()),
+		},
+		{
+			name:        "Service failure",
+			inputUserID: 1,
+			inputBody:   `{"current_password": "qwerty", "new_password": "asdfgh"}`,
+			mockBehavior: func(s *mockService.MockAuthorization, userID interface{}) {
+				s.EXPECT().ChangePassword(gomock.Any(), userID, "qwerty", "asdfgh").Return("", service.ErrFailedToChangePassword)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToChangePassword.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mockService.NewMockAuthorization(c)
+			tc.mockBehavior(auth, tc.inputUserID)
+
+			services := &service.Service{Authorization: auth}
+			handler := New(services, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			r.PUT(
+				"/api/me/password",
+				func(c *gin.Context) {
+					c.Set(userCtx, tc.inputUserID)
+				},
+				handler.changePassword)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", "/api/me/password", bytes.NewBufferString(tc.inputBody))
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_setupTwoFactor(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockAuthorization, userID interface{})
+
+	testCases := []struct {
+		name                 string
+		inputUserID          interface{}
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			inputUserID: 1,
+			mockBehavior: func(s *mockService.MockAuthorization, userID interface{}) {
+				s.EXPECT().SetupTwoFactor(gomock.Any(), userID).Return(model.TwoFactorSetup{Secret: "SECRET", URI: "otpauth://totp/todo-app"}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"secret":"SECRET","uri":"otpauth://totp/todo-app"}`,
+		},
+		{
+			name:                 "Invalid user id",
+			inputUserID:          "invalid",
+			mockBehavior:         func(s *mockService.MockAuthorization, userID interface{}) {},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errFailedToGetUserID.Error()),
+		},
+		{
+			name:        "Already enabled",
+			inputUserID: 1,
+			mockBehavior: func(s *mockService.MockAuthorization, userID interface{}) {
+				s.EXPECT().SetupTwoFactor(gomock.Any(), userID).Return(model.TwoFactorSetup{}, service.ErrTwoFactorEnabled)
+			},
+			expectedStatusCode:   409,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrTwoFactorEnabled.Error()),
+		},
+		{
+			name:        "Service failure",
+			inputUserID: 1,
+			mockBehavior: func(s *mockService.MockAuthorization, userID interface{}) {
+				s.EXPECT().SetupTwoFactor(gomock.Any(), userID).Return(model.TwoFactorSetup{}, service.ErrFailedToSetUpTwoFactor)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToSetUpTwoFactor.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mockService.NewMockAuthorization(c)
+			tc.mockBehavior(auth, tc.inputUserID)
+
+			services := &service.Service{Authorization: auth}
+			handler := New(services, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			r.POST(
+				"/api/me/2fa/setup",
+				func(c *gin.Context) {
+					c.Set(userCtx, tc.inputUserID)
+				},
+				handler.setupTwoFactor)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/me/2fa/setup", nil)
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_confirmTwoFactor(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockAuthorization, userID interface{})
+
+	testCases := []struct {
+		name                 string
+		inputUserID          interface{}
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			inputUserID: 1,
+			inputBody:   `{"code": "123456"}`,
+			mockBehavior: func(s *mockService.MockAuthorization, userID interface{}) {
+				s.EXPECT().ConfirmTwoFactor(gomock.Any(), userID, "123456").Return([]string{"aaaaa-bbbbb", "ccccc-ddddd"}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"recovery_codes":["aaaaa-bbbbb","ccccc-ddddd"]}`,
+		},
+		{
+			name:                 "Invalid code format",
+			inputUserID:          1,
+			inputBody:            `{"code": "12345a"}`,
+			mockBehavior:         func(s *mockService.MockAuthorization, userID interface{}) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidInputBody.Error()),
+		},
+		{
+			name:        "Wrong code",
+			inputUserID: 1,
+			inputBody:   `{"code": "123456"}`,
+			mockBehavior: func(s *mockService.MockAuthorization, userID interface{}) {
+				s.EXPECT().ConfirmTwoFactor(gomock.Any(), userID, "123456").Return(nil, service.ErrInvalidTwoFactorCode)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrInvalidTwoFactorCode.Error()),
+		},
+		{
+			name:        "Not set up",
+			inputUserID: 1,
+			inputBody:   `{"code": "123456"}`,
+			mockBehavior: func(s *mockService.MockAuthorization, userID interface{}) {
+				s.EXPECT().ConfirmTwoFactor(gomock.Any(), userID, "123456").Return(nil, service.ErrTwoFactorNotSetUp)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrTwoFactorNotSetUp.Error()),
+		},
+		{
+			name:        "Already enabled",
+			inputUserID: 1,
+			inputBody:   `{"code": "123456"}`,
+			mockBehavior: func(s *mockService.MockAuthorization, userID interface{}) {
+				s.EXPECT().ConfirmTwoFactor(gomock.Any(), userID, "123456").Return(nil, service.ErrTwoFactorEnabled)
+			},
+			expectedStatusCode:   409,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrTwoFactorEnabled.Error()),
+		},
+		{
+			name:        "Service failure",
+			inputUserID: 1,
+			inputBody:   `{"code": "123456"}`,
+			mockBehavior: func(s *mockService.MockAuthorization, userID interface{}) {
+				s.EXPECT().ConfirmTwoFactor(gomock.Any(), userID, "123456").Return(nil, service.ErrFailedToEnableTwoFactor)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToEnableTwoFactor.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mockService.NewMockAuthorization(c)
+			tc.mockBehavior(auth, tc.inputUserID)
+
+			services := &service.Service{Authorization: auth}
+			handler := New(services, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			r.POST(
+				"/api/me/2fa/confirm",
+				func(c *gin.Context) {
+					c.Set(userCtx, tc.inputUserID)
+				},
+				handler.confirmTwoFactor)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/me/2fa/confirm", bytes.NewBufferString(tc.inputBody))
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
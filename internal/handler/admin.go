@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	_ "github.com/Lapp-coder/todo-app/docs/swagger"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// getLockouts godoc
+// @Summary Get lockouts
+// @Security AdminAuth
+// @Tags admin
+// @Description get the accounts with failed sign-ins or lockouts
+// @ID get-lockouts
+// @Produce json
+// @Success 200 {object} swagger.GetLockoutsResponse
+// @Failure 401,404 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /admin/lockouts [get]
+func (h Handler) getLockouts(ctx *gin.Context) {
+	lockouts, err := h.service.Authorization.GetLockouts(ctx.Request.Context())
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"lockouts": lockouts,
+	})
+}
+
+// unlockUser godoc
+// @Summary Unlock user
+// @Security AdminAuth
+// @Tags admin
+// @Description lift the lockout of the user and reset their failed sign-ins
+// @ID unlock-user
+// @Produce json
+// @Param id path int true "User id"
+// @Success 200 {string} string "Result"
+// @Failure 400,401,404 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /admin/lockouts/{id} [delete]
+func (h Handler) unlockUser(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidParamID)
+		return
+	}
+
+	if err = h.service.Authorization.Unlock(ctx.Request.Context(), userID); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			respondError(ctx, http.StatusNotFound, err)
+			return
+		}
+
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"result": "the user was unlocked",
+	})
+}
+
+// getUsers godoc
+// @Summary Get users
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description find the users by their name or email, all of them without the query
+// @ID get-users
+// @Produce json
+// @Param q query string false "Part of the name or the email"
+// @Param limit query int false "Page size, 50 by default"
+// @Param offset query int false "Users to skip"
+// @Success 200 {object} swagger.GetUsersResponse
+// @Failure 400,401,403 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /admin/users [get]
+func (h Handler) getUsers(ctx *gin.Context) {
+	var req model.SearchUsers
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidInputQuery)
+		return
+	}
+
+	users, err := h.service.Authorization.GetUsers(ctx.Request.Context(), req)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"users": users,
+	})
+}
+
+// setUserRole godoc
+// @Summary Set user role
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description make the user an admin or a regular user, which signs them out
+// @ID set-user-role
+// @Accept json
+// @Produce json
+// @Param id path int true "User id"
+// @Param input body model.SetRole true "Role"
+// @Success 200 {string} string "Result"
+// @Failure 400,401,403,404 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /admin/users/{id}/role [put]
+func (h Handler) setUserRole(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidParamID)
+		return
+	}
+
+	var req model.SetRole
+	if err = ctx.BindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidInputBody)
+		return
+	}
+
+	if err = h.service.Authorization.SetRole(ctx.Request.Context(), userID, req.Role); err != nil {
+		respondUserError(ctx, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"result": "the role of the user was set",
+	})
+}
+
+// disableUser godoc
+// @Summary Disable user
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description sign the user out and keep them from signing in or using their access tokens
+// @ID disable-user
+// @Produce json
+// @Param id path int true "User id"
+// @Success 200 {string} string "Result"
+// @Failure 400,401,403,404 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /admin/users/{id}/disable [post]
+func (h Handler) disableUser(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidParamID)
+		return
+	}
+
+	if err = h.service.Authorization.DisableUser(ctx.Request.Context(), userID); err != nil {
+		respondUserError(ctx, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"result": "the user was disabled",
+	})
+}
+
+// enableUser godoc
+// @Summary Enable user
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description let the disabled user sign in again
+// @ID enable-user
+// @Produce json
+// @Param id path int true "User id"
+// @Success 200 {string} string "Result"
+// @Failure 400,401,403,404 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /admin/users/{id}/enable [post]
+func (h Handler) enableUser(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidParamID)
+		return
+	}
+
+	if err = h.service.Authorization.EnableUser(ctx.Request.Context(), userID); err != nil {
+		respondUserError(ctx, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"result": "the user was enabled",
+	})
+}
+
+// revokeUserSessions godoc
+// @Summary Sign out user
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description sign out all the sessions of the user, their access tokens keep working
+// @ID revoke-user-sessions
+// @Produce json
+// @Param id path int true "User id"
+// @Success 200 {string} string "Result"
+// @Failure 400,401,403,404 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /admin/users/{id}/sessions [delete]
+func (h Handler) revokeUserSessions(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidParamID)
+		return
+	}
+
+	if err = h.service.Authorization.RevokeSessions(ctx.Request.Context(), userID); err != nil {
+		respondUserError(ctx, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"result": "the sessions of the user were revoked",
+	})
+}
+
+// respondUserError responds to the failed changes of the user.
+func respondUserError(ctx *gin.Context, err error) {
+	if errors.Is(err, service.ErrUserNotFound) {
+		respondError(ctx, http.StatusNotFound, err)
+		return
+	}
+
+	respondError(ctx, http.StatusInternalServerError, err)
+}
+
+// getStats godoc
+// @Summary Get stats
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description get the numbers of the users, their lists, items and access tokens
+// @ID get-stats
+// @Produce json
+// @Success 200 {object} model.Stats
+// @Failure 401,403 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /admin/stats [get]
+func (h Handler) getStats(ctx *gin.Context) {
+	stats, err := h.service.Authorization.GetStats(ctx.Request.Context())
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, stats)
+}
@@ -0,0 +1,699 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/config"
+	"github.com/Lapp-coder/todo-app/internal/mail/mailtest"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/repository"
+	"github.com/Lapp-coder/todo-app/internal/repository/memory"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	mockService "github.com/Lapp-coder/todo-app/internal/service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_getLockouts(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockAuthorization)
+
+	until := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mockService.MockAuthorization) {
+				s.EXPECT().GetLockouts(gomock.Any()).Return([]model.Lockout{
+					{UserID: 1, Email: "test@mail.ru", Lockouts: 1, LockedUntil: &until, Locked: true},
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"lockouts":[{"user_id":1,"email":"test@mail.ru","failed_logins":0,"lockouts":1,` +
+				`"locked_until":"2030-01-02T03:04:05Z","locked":true}]}`,
+		},
+		{
+			name: "Service failure",
+			mockBehavior: func(s *mockService.MockAuthorization) {
+				s.EXPECT().GetLockouts(gomock.Any()).Return(nil, service.ErrFailedToGetLockouts)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToGetLockouts.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mockService.NewMockAuthorization(c)
+			tc.mockBehavior(auth)
+
+			services := &service.Service{Authorization: auth}
+			handler := New(services, nil)
+
+			// Test server
+			r := gin.New()
+			r.GET("/admin/lockouts", handler.getLockouts)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/admin/lockouts", nil)
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_unlockUser(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockAuthorization)
+
+	testTable := []struct {
+		name                 string
+		userID               string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "OK",
+			userID: "1",
+			mockBehavior: func(s *mockService.MockAuthorization) {
+				s.EXPECT().Unlock(gomock.Any(), 1).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"result":"the user was unlocked"}`,
+		},
+		{
+			name:                 "Invalid id",
+			userID:               "one",
+			mockBehavior:         func(s *mockService.MockAuthorization) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidParamID.Error()),
+		},
+		{
+			name:   "Not found",
+			userID: "2",
+			mockBehavior: func(s *mockService.MockAuthorization) {
+				s.EXPECT().Unlock(gomock.Any(), 2).Return(service.ErrUserNotFound)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrUserNotFound.Error()),
+		},
+		{
+			name:   "Service failure",
+			userID: "3",
+			mockBehavior: func(s *mockService.MockAuthorization) {
+				s.EXPECT().Unlock(gomock.Any(), 3).Return(errors.New("failed to unlock user"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"error":"failed to unlock user"}`,
+		},
+	}
+
+	// Act
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mockService.NewMockAuthorization(c)
+			tc.mockBehavior(auth)
+
+			services := &service.Service{Authorization: auth}
+			handler := New(services, nil)
+
+			// Test server
+			r := gin.New()
+			r.DELETE("/admin/lockouts/:id", handler.unlockUser)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", "/admin/lockouts/"+tc.userID, nil)
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getUsers(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockAuthorization)
+
+	testTable := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "OK",
+			query: "?q=mail&limit=10&offset=20",
+			mockBehavior: func(s *mockService.MockAuthorization) {
+				s.EXPECT().GetUsers(gomock.Any(), model.SearchUsers{Query: "mail", Limit: 10, Offset: 20}).Return([]model.UserSummary{
+					{ID: 1, Name: "test", Email: "test@mail.ru", Role: model.RoleAdmin, EmailVerified: true},
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"users":[{"id":1,"name":"test","email":"test@mail.ru","role":"admin",` +
+				`"email_verified":true,"two_factor_enabled":false,"disabled":false}]}`,
+		},
+		{
+			name:                 "Invalid limit",
+			query:                "?limit=1000",
+			mockBehavior:         func(s *mockService.MockAuthorization) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidInputQuery.Error()),
+		},
+		{
+			name:                 "Invalid offset",
+			query:                "?offset=first",
+			mockBehavior:         func(s *mockService.MockAuthorization) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidInputQuery.Error()),
+		},
+		{
+			name:  "Service failure",
+			query: "",
+			mockBehavior: func(s *mockService.MockAuthorization) {
+				s.EXPECT().GetUsers(gomock.Any(), model.SearchUsers{}).Return(nil, service.ErrFailedToGetUsers)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToGetUsers.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mockService.NewMockAuthorization(c)
+			tc.mockBehavior(auth)
+
+			services := &service.Service{Authorization: auth}
+			handler := New(services, nil)
+
+			// Test server
+			r := gin.New()
+			r.GET("/admin/users", handler.getUsers)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/admin/users"+tc.query, nil)
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_setUserRole(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockAuthorization)
+
+	testTable := []struct {
+		name                 string
+		userID               string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			userID:    "1",
+			inputBody: `{"role": "admin"}`,
+			mockBehavior: func(s *mockService.MockAuthorization) {
+				s.EXPECT().SetRole(gomock.Any(), 1, model.RoleAdmin).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"result":"the role of the user was set"}`,
+		},
+		{
+			name:                 "Invalid id",
+			userID:               "one",
+			inputBody:            `{"role": "admin"}`,
+			mockBehavior:         func(s *mockService.MockAuthorization) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidParamID.Error()),
+		},
+		{
+			name:                 "Unknown role",
+			userID:               "1",
+			inputBody:            `{"role": "owner"}`,
+			mockBehavior:         func(s *mockService.MockAuthorization) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidInputBody.Error()),
+		},
+		{
+			name:      "Not found",
+			userID:    "2",
+			inputBody: `{"role": "user"}`,
+			mockBehavior: func(s *mockService.MockAuthorization) {
+				s.EXPECT().SetRole(gomock.Any(), 2, model.RoleUser).Return(service.ErrUserNotFound)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrUserNotFound.Error()),
+		},
+		{
+			name:      "Service failure",
+			userID:    "3",
+			inputBody: `{"role": "user"}`,
+			mockBehavior: func(s *mockService.MockAuthorization) {
+				s.EXPECT().SetRole(gomock.Any(), 3, model.RoleUser).Return(service.ErrFailedToSetRole)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToSetRole.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mockService.NewMockAuthorization(c)
+			tc.mockBehavior(auth)
+
+			services := &service.Service{Authorization: auth}
+			handler := New(services, nil)
+
+			// Test server
+			r := gin.New()
+			r.PUT("/admin/users/:id/role", handler.setUserRole)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", "/admin/users/"+tc.userID+"/role", bytes.NewBufferString(tc.inputBody))
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_disableUser(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockAuthorization)
+
+	testTable := []struct {
+		name                 string
+		userID               string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "OK",
+			userID: "1",
+			mockBehavior: func(s *mockService.MockAuthorization) {
+				s.EXPECT().DisableUser(gomock.Any(), 1).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"result":"the user was disabled"}`,
+		},
+		{
+			name:                 "Invalid id",
+			userID:               "one",
+			mockBehavior:         func(s *mockService.MockAuthorization) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidParamID.Error()),
+		},
+		{
+			name:   "Not found",
+			userID: "2",
+			mockBehavior: func(s *mockService.MockAuthorization) {
+				s.EXPECT().DisableUser(gomock.Any(), 2).Return(service.ErrUserNotFound)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrUserNotFound.Error()),
+		},
+		{
+			name:   "Service failure",
+			userID: "3",
+			mockBehavior: func(s *mockService.MockAuthorization) {
+				s.EXPECT().DisableUser(gomock.Any(), 3).Return(service.ErrFailedToDisableUser)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToDisableUser.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mockService.NewMockAuthorization(c)
+			tc.mockBehavior(auth)
+
+			services := &service.Service{Authorization: auth}
+			handler := New(services, nil)
+
+			// Test server
+			r := gin.New()
+			r.POST("/admin/users/:id/disable", handler.disableUser)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/admin/users/"+tc.userID+"/disable", nil)
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getStats(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockAuthorization)
+
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mockService.MockAuthorization) {
+				s.EXPECT().GetStats(gomock.Any()).Return(model.Stats{Users: 3, Admins: 1, Lists: 2, Items: 5, DoneItems: 1}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"users":3,"admins":1,"disabled_users":0,"verified_users":0,"two_factor_users":0,` +
+				`"lists":2,"items":5,"done_items":1,"access_tokens":0}`,
+		},
+		{
+			name: "Service failure",
+			mockBehavior: func(s *mockService.MockAuthorization) {
+				s.EXPECT().GetStats(gomock.Any()).Return(model.Stats{}, service.ErrFailedToGetStats)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToGetStats.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mockService.NewMockAuthorization(c)
+			tc.mockBehavior(auth)
+
+			services := &service.Service{Authorization: auth}
+			handler := New(services, nil)
+
+			// Test server
+			r := gin.New()
+			r.GET("/admin/stats", handler.getStats)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/admin/stats", nil)
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_adminAuthentication(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockAuthorization, a *mockService.MockAccessToken)
+
+	testTable := []struct {
+		name               string
+		headerValue        string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name:        "Admin token",
+			headerValue: "Bearer admin",
+			mockBehavior: func(s *mockService.MockAuthorization, a *mockService.MockAccessToken) {
+				s.EXPECT().AuthorizeAdmin("admin").Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "Invalid header",
+			headerValue:        "Bearer",
+			mockBehavior:       func(s *mockService.MockAuthorization, a *mockService.MockAccessToken) {},
+			expectedStatusCode: 401,
+		},
+		{
+			name:        "Admin session",
+			headerValue: "Bearer session",
+			mockBehavior: func(s *mockService.MockAuthorization, a *mockService.MockAccessToken) {
+				s.EXPECT().AuthorizeAdmin("session").Return(service.ErrInvalidAdminToken)
+				s.EXPECT().ParseToken(gomock.Any(), "session").Return(model.Session{UserID: 1, Role: model.RoleAdmin}, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:        "Admin session with the admin token disabled",
+			headerValue: "Bearer session",
+			mockBehavior: func(s *mockService.MockAuthorization, a *mockService.MockAccessToken) {
+				s.EXPECT().AuthorizeAdmin("session").Return(service.ErrAdminAPIDisabled)
+				s.EXPECT().ParseToken(gomock.Any(), "session").Return(model.Session{UserID: 1, Role: model.RoleAdmin}, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:        "User session",
+			headerValue: "Bearer session",
+			mockBehavior: func(s *mockService.MockAuthorization, a *mockService.MockAccessToken) {
+				s.EXPECT().AuthorizeAdmin("session").Return(service.ErrInvalidAdminToken)
+				s.EXPECT().ParseToken(gomock.Any(), "session").Return(model.Session{UserID: 2, Role: model.RoleUser}, nil)
+			},
+			expectedStatusCode: 403,
+		},
+		{
+			name:        "Access token of an admin",
+			headerValue: "Bearer todo_pat_secret",
+			mockBehavior: func(s *mockService.MockAuthorization, a *mockService.MockAccessToken) {
+				s.EXPECT().AuthorizeAdmin("todo_pat_secret").Return(service.ErrInvalidAdminToken)
+				a.EXPECT().Authenticate(gomock.Any(), "todo_pat_secret").
+					Return(model.AccessToken{ID: 1, UserID: 1, Scopes: model.Scopes{model.ScopeListsRead}}, nil)
+			},
+			expectedStatusCode: 403,
+		},
+		{
+			name:        "Invalid token",
+			headerValue: "Bearer user",
+			mockBehavior: func(s *mockService.MockAuthorization, a *mockService.MockAccessToken) {
+				s.EXPECT().AuthorizeAdmin("user").Return(service.ErrInvalidAdminToken)
+				s.EXPECT().ParseToken(gomock.Any(), "user").Return(model.Session{}, service.ErrSessionRevoked)
+			},
+			expectedStatusCode: 401,
+		},
+	}
+
+	// Act
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mockService.NewMockAuthorization(c)
+			accessToken := mockService.NewMockAccessToken(c)
+			tc.mockBehavior(auth, accessToken)
+
+			services := &service.Service{Authorization: auth, AccessToken: accessToken}
+			handler := New(services, nil)
+
+			// Test server
+			r := gin.New()
+			r.GET("/admin", handler.adminAuthentication, handler.requireRole(model.RoleAdmin), func(ctx *gin.Context) {
+				ctx.Status(200)
+			})
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/admin", nil)
+			req.Header.Set("Authorization", tc.headerValue)
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+		})
+	}
+}
+
+// TestHandler_admin promotes an admin, who then disables and signs out another user. The admin token
+// only lifts the lockouts.
+func TestHandler_admin(t *testing.T) {
+	cfg := config.Service{
+		SigningKey:      "key",
+		Salt:            "salt",
+		TokenTTL:        60,
+		VerificationTTL: 60,
+		AdminToken:      "admin-token",
+	}
+	repos := repository.NewMemory(memory.NewStore())
+	services := service.New(repos, cfg, nil, service.LogNotifier{}, &mailtest.Mailer{}, nil)
+	r := New(services, nil).InitRoutes()
+
+	request := func(method, target, token, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	signIn := func(email string) (int, string) {
+		w := request("POST", "/auth/sign-in", "", fmt.Sprintf(`{"email": "%s", "password": "testing"}`, email))
+
+		var result model.SignInResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		return w.Code, result.Token
+	}
+
+	getUsers := func(token, query string) []model.UserSummary {
+		w := request("GET", "/admin/users"+query, token, "")
+		require.Equal(t, 200, w.Code)
+
+		var result struct {
+			Users []model.UserSummary `json:"users"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		return result.Users
+	}
+
+	require.Equal(t, 201, request("POST", "/auth/sign-up", "", `{"name": "alice", "email": "alice@mail.ru", "password": "testing"}`).Code)
+	require.Equal(t, 201, request("POST", "/auth/sign-up", "", `{"name": "bob", "email": "bob@mail.ru", "password": "testing"}`).Code)
+
+	_, alice := signIn("alice@mail.ru")
+	_, bob := signIn("bob@mail.ru")
+	assert.Equal(t, 403, request("GET", "/admin/users", bob, "").Code)
+	assert.Equal(t, 401, request("GET", "/admin/users", "admin-token", "").Code)
+	assert.Equal(t, 401, request("PUT", "/admin/users/1/role", "admin-token", `{"role": "admin"}`).Code)
+	assert.Equal(t, 200, request("GET", "/admin/lockouts", "admin-token", "").Code)
+
+	// The first admin is appointed with the role command, which sets the role in the storage.
+	user, err := repos.Authorization.GetUser(context.Background(), "alice@mail.ru")
+	require.NoError(t, err)
+	require.NoError(t, repos.Authorization.SetRole(context.Background(), user.ID, model.RoleAdmin))
+
+	// The role is carried by the token, so the sessions with the old role are revoked.
+	assert.Equal(t, 401, request("GET", "/admin/users", alice, "").Code)
+
+	_, alice = signIn("alice@mail.ru")
+	users := getUsers(alice, "")
+	require.Len(t, users, 2)
+	assert.Equal(t, model.RoleAdmin, users[0].Role)
+	bobID := strconv.Itoa(users[1].ID)
+
+	w := request("POST", "/api/me/tokens", bob, `{"name": "ci", "scopes": ["lists:read"]}`)
+	require.Equal(t, 201, w.Code)
+
+	var accessToken model.CreatedAccessToken
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &accessToken))
+
+	w = request("POST", "/api/me/tokens", alice, `{"name": "ci", "scopes": ["lists:read"]}`)
+	require.Equal(t, 201, w.Code)
+
+	var adminAccessToken model.CreatedAccessToken
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &adminAccessToken))
+	assert.Equal(t, 403, request("GET", "/admin/users", adminAccessToken.Token, "").Code)
+
+	w = request("POST", "/api/calendar/token", bob, "")
+	require.Equal(t, 201, w.Code)
+
+	var feed struct {
+		URL string `json:"url"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &feed))
+	require.Equal(t, 200, request("GET", feed.URL, "", "").Code)
+	assert.Equal(t, 409, request("POST", "/api/calendar/token", bob, "").Code)
+
+	// The disabled user is signed out, can't sign in and their access tokens and calendar feed stop working.
+	require.Equal(t, 200, request("POST", "/admin/users/"+bobID+"/disable", alice, "").Code)
+	assert.Equal(t, 401, request("GET", "/api/lists/", bob, "").Code)
+	assert.Equal(t, 401, request("GET", "/api/lists/", accessToken.Token, "").Code)
+	assert.Equal(t, 404, request("GET", feed.URL, "", "").Code)
+	code, _ := signIn("bob@mail.ru")
+	assert.Equal(t, 403, code)
+	assert.True(t, getUsers(alice, "?q=bob")[0].Disabled)
+
+	require.Equal(t, 200, request("POST", "/admin/users/"+bobID+"/enable", alice, "").Code)
+	assert.Equal(t, 200, request("GET", "/api/lists/", accessToken.Token, "").Code)
+	assert.Equal(t, 200, request("GET", feed.URL, "", "").Code)
+	code, bob = signIn("bob@mail.ru")
+	require.Equal(t, 200, code)
+	assert.Equal(t, 200, request("GET", "/api/lists/", bob, "").Code)
+
+	require.Equal(t, 200, request("DELETE", "/admin/users/"+bobID+"/sessions", alice, "").Code)
+	assert.Equal(t, 401, request("GET", "/api/lists/", bob, "").Code)
+	assert.Equal(t, 404, request("DELETE", "/admin/users/100/sessions", alice, "").Code)
+
+	w = request("GET", "/admin/stats", alice, "")
+	require.Equal(t, 200, w.Code)
+
+	var stats model.Stats
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	assert.Equal(t, model.Stats{Users: 2, Admins: 1, AccessTokens: 2}, stats)
+}
@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	_ "github.com/Lapp-coder/todo-app/docs/swagger"
 	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/ratelimit"
+	"github.com/Lapp-coder/todo-app/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
@@ -19,6 +22,7 @@ import (
 // @Success 201 {integer} integer "User id"
 // @Failure 400 {object} swagger.ErrorResponse
 // @Failure 404 {object} swagger.ErrorResponse
+// @Failure 429 {object} swagger.ErrorResponse
 // @Failure 500 {object} swagger.ErrorResponse
 // @Failure default {object} swagger.ErrorResponse
 // @Router /auth/sign-up [post]
@@ -30,7 +34,7 @@ func (h Handler) signUp(ctx *gin.Context) {
 	}
 
 	user := model.User{Name: req.Name, Email: req.Email, Password: req.Password}
-	id, err := h.service.Authorization.CreateUser(user)
+	id, err := h.service.Authorization.CreateUser(ctx.Request.Context(), user)
 	if err != nil {
 		respondError(ctx, http.StatusInternalServerError, err)
 		return
@@ -44,14 +48,16 @@ func (h Handler) signUp(ctx *gin.Context) {
 // signIn godoc
 // @Summary Sign in
 // @Tags auth
-// @Description login
+// @Description login, the accounts with the two-factor authentication get a challenge token to complete the sign-in with
 // @ID login
 // @Accept json
 // @Produce json
 // @Param input body model.SignIn true "Credentials"
-// @Success 201 {string} string "Token"
+// @Success 200 {object} model.SignInResult
 // @Failure 400 {object} swagger.ErrorResponse
+// @Failure 403 {object} swagger.ErrorResponse
 // @Failure 404 {object} swagger.ErrorResponse
+// @Failure 429 {object} swagger.ErrorResponse
 // @Failure 500 {object} swagger.ErrorResponse
 // @Failure default {object} swagger.ErrorResponse
 // @Router /auth/sign-in [post]
@@ -62,8 +68,65 @@ func (h Handler) signIn(ctx *gin.Context) {
 		return
 	}
 
-	token, err := h.service.Authorization.GenerateToken(req.Email, req.Password)
+	if !h.allow(ctx, ratelimit.PolicyAuthEmail, ratelimit.EmailKey(req.Email)) {
+		return
+	}
+
+	result, err := h.service.Authorization.GenerateToken(ctx.Request.Context(), req.Email, req.Password)
 	if err != nil {
+		if errors.Is(err, service.ErrAccountLocked) || errors.Is(err, service.ErrAccountDisabled) ||
+			errors.Is(err, service.ErrEmailNotVerified) {
+			respondError(ctx, http.StatusForbidden, err)
+			return
+		}
+
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, result)
+}
+
+// completeSignIn godoc
+// @Summary Complete sign in
+// @Tags auth
+// @Description exchange the challenge token from the sign-in and a code from the authenticator app, or a recovery code, for the token
+// @ID complete-login
+// @Accept json
+// @Produce json
+// @Param input body model.CompleteSignIn true "Challenge token and code"
+// @Success 200 {string} string "Token"
+// @Failure 400 {object} swagger.ErrorResponse
+// @Failure 401 {object} swagger.ErrorResponse
+// @Failure 403 {object} swagger.ErrorResponse
+// @Failure 429 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /auth/sign-in/2fa [post]
+func (h Handler) completeSignIn(ctx *gin.Context) {
+	var req model.CompleteSignIn
+	if err := ctx.BindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidInputBody)
+		return
+	}
+
+	// The codes are short, so the guesses are limited for every challenge on top of the client IP.
+	if !h.allow(ctx, ratelimit.PolicyAuthEmail, ratelimit.TokenKey(req.ChallengeToken)) {
+		return
+	}
+
+	token, err := h.service.Authorization.CompleteSignIn(ctx.Request.Context(), req.ChallengeToken, req.Code)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidChallengeToken) || errors.Is(err, service.ErrInvalidTwoFactorCode) {
+			respondError(ctx, http.StatusUnauthorized, err)
+			return
+		}
+
+		if errors.Is(err, service.ErrAccountLocked) {
+			respondError(ctx, http.StatusForbidden, err)
+			return
+		}
+
 		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
@@ -72,3 +135,150 @@ func (h Handler) signIn(ctx *gin.Context) {
 		"token": token,
 	})
 }
+
+// verifyEmail godoc
+// @Summary Verify email
+// @Tags auth
+// @Description verify the email of the account with the token from the link sent to it, a pending email replaces the old one
+// @ID verify-email
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 200 {string} string "Result"
+// @Failure 400 {object} swagger.ErrorResponse
+// @Failure 409 {object} swagger.ErrorResponse
+// @Failure 429 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /auth/verify [get]
+func (h Handler) verifyEmail(ctx *gin.Context) {
+	token := ctx.Query("token")
+	if token == "" {
+		respondError(ctx, http.StatusBadRequest, errEmptyToken)
+		return
+	}
+
+	if err := h.service.Authorization.VerifyEmail(ctx.Request.Context(), token); err != nil {
+		if errors.Is(err, service.ErrInvalidVerificationToken) {
+			respondError(ctx, http.StatusBadRequest, err)
+			return
+		}
+
+		if errors.Is(err, service.ErrEmailAlreadyTaken) {
+			respondError(ctx, http.StatusConflict, err)
+			return
+		}
+
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"result": "the email was verified",
+	})
+}
+
+// resendVerification godoc
+// @Summary Resend verification
+// @Tags auth
+// @Description send a new verification link to the email, if it belongs to an unverified account
+// @ID resend-verification
+// @Accept json
+// @Produce json
+// @Param input body model.ResendVerification true "Email"
+// @Success 202 {string} string "Result"
+// @Failure 400 {object} swagger.ErrorResponse
+// @Failure 429 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /auth/verify/resend [post]
+func (h Handler) resendVerification(ctx *gin.Context) {
+	var req model.ResendVerification
+	if err := ctx.BindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidInputBody)
+		return
+	}
+
+	if !h.allow(ctx, ratelimit.PolicyAuthEmail, ratelimit.EmailKey(req.Email)) {
+		return
+	}
+
+	if err := h.service.Authorization.ResendVerification(ctx.Request.Context(), req.Email); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(ctx, http.StatusAccepted, gin.H{
+		"result": "the verification email is sent if the account exists and isn't verified yet",
+	})
+}
+
+// forgotPassword godoc
+// @Summary Forgot password
+// @Tags auth
+// @Description mail a password reset token to the email, if it belongs to an account
+// @ID forgot-password
+// @Accept json
+// @Produce json
+// @Param input body model.ForgotPassword true "Email"
+// @Success 202 {string} string "Result"
+// @Failure 400 {object} swagger.ErrorResponse
+// @Failure 429 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /auth/forgot-password [post]
+func (h Handler) forgotPassword(ctx *gin.Context) {
+	var req model.ForgotPassword
+	if err := ctx.BindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidInputBody)
+		return
+	}
+
+	if !h.allow(ctx, ratelimit.PolicyAuthEmail, ratelimit.EmailKey(req.Email)) {
+		return
+	}
+
+	if err := h.service.Authorization.ForgotPassword(ctx.Request.Context(), req.Email); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(ctx, http.StatusAccepted, gin.H{
+		"result": "the password reset email is sent if the account exists",
+	})
+}
+
+// resetPassword godoc
+// @Summary Reset password
+// @Tags auth
+// @Description set a new password with the token from the password reset email, all the sessions are signed out
+// @ID reset-password
+// @Accept json
+// @Produce json
+// @Param input body model.ResetPassword true "Token and new password"
+// @Success 200 {string} string "Result"
+// @Failure 400 {object} swagger.ErrorResponse
+// @Failure 429 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /auth/reset-password [post]
+func (h Handler) resetPassword(ctx *gin.Context) {
+	var req model.ResetPassword
+	if err := ctx.BindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidInputBody)
+		return
+	}
+
+	if err := h.service.Authorization.ResetPassword(ctx.Request.Context(), req.Token, req.Password); err != nil {
+		if errors.Is(err, service.ErrInvalidResetToken) {
+			respondError(ctx, http.StatusBadRequest, err)
+			return
+		}
+
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"result": "the password was reset",
+	})
+}
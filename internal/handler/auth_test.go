@@ -2,16 +2,26 @@ package handler
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http/httptest"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/Lapp-coder/todo-app/internal/config"
+	"github.com/Lapp-coder/todo-app/internal/mail/mailtest"
 	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/repository"
+	"github.com/Lapp-coder/todo-app/internal/repository/memory"
 	"github.com/Lapp-coder/todo-app/internal/service"
 	mockService "github.com/Lapp-coder/todo-app/internal/service/mocks"
+	"github.com/Lapp-coder/todo-app/internal/totp"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHandler_signUp(t *testing.T) {
@@ -31,7 +41,7 @@ func TestHandler_signUp(t *testing.T) {
 			inputBody: `{"name": "Test", "email":"test@mail.ru", "password":"testing"}`,
 			inputUser: model.User{Name: "Test", Email: "test@mail.ru", Password: "testing"},
 			mockBehavior: func(s *mockService.MockAuthorization, user model.User) {
-				s.EXPECT().CreateUser(user).Return(1, nil)
+				s.EXPECT().CreateUser(gomock.Any(), user).Return(1, nil)
 			},
 			expectedStatusCode:   201,
 			expectedResponseBody: `{"id":1}`,
@@ -83,7 +93,7 @@ func TestHandler_signUp(t *testing.T) {
 			inputBody: `{"name": "Test", "email":"test@mail.ru", "password":"testing"}`,
 			inputUser: model.User{Name: "Test", Email: "test@mail.ru", Password: "testing"},
 			mockBehavior: func(s *mockService.MockAuthorization, user model.User) {
-				s.EXPECT().CreateUser(user).Return(0, service.ErrIncorrectEmailOrPassword)
+				s.EXPECT().CreateUser(gomock.Any(), user).Return(0, service.ErrIncorrectEmailOrPassword)
 			},
 			expectedStatusCode:   500,
 			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrIncorrectEmailOrPassword.Error()),
@@ -101,7 +111,7 @@ func TestHandler_signUp(t *testing.T) {
 			tc.mockBehavior(auth, tc.inputUser)
 
 			services := &service.Service{Authorization: auth}
-			handler := New(services)
+			handler := New(services, nil)
 
 			// Test server
 			gin.SetMode("test")
@@ -141,11 +151,22 @@ func TestHandler_signIn(t *testing.T) {
 			inputEmail:    "test@mail.ru",
 			inputPassword: "testing",
 			mockBehavior: func(s *mockService.MockAuthorization, email, password string) {
-				s.EXPECT().GenerateToken(email, password).Return("generatedToken", nil)
+				s.EXPECT().GenerateToken(gomock.Any(), email, password).Return(model.SignInResult{Token: "generatedToken"}, nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"token":"generatedToken"}`,
 		},
+		{
+			name:          "Two-factor",
+			inputBody:     `{"email": "test@mail.ru", "password": "testing"}`,
+			inputEmail:    "test@mail.ru",
+			inputPassword: "testing",
+			mockBehavior: func(s *mockService.MockAuthorization, email, password string) {
+				s.EXPECT().GenerateToken(gomock.Any(), email, password).Return(model.SignInResult{ChallengeToken: "challenge"}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"challenge_token":"challenge"}`,
+		},
 		{
 			name:                 "Invalid email",
 			inputBody:            `{"email":"test", "password":"testing"}`,
@@ -180,11 +201,22 @@ func TestHandler_signIn(t *testing.T) {
 			inputEmail:    "test@mail.ru",
 			inputPassword: "testing",
 			mockBehavior: func(s *mockService.MockAuthorization, email, password string) {
-				s.EXPECT().GenerateToken(email, password).Return("", service.ErrIncorrectEmailOrPassword)
+				s.EXPECT().GenerateToken(gomock.Any(), email, password).Return(model.SignInResult{}, service.ErrIncorrectEmailOrPassword)
 			},
 			expectedStatusCode:   500,
 			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrIncorrectEmailOrPassword.Error()),
 		},
+		{
+			name:          "Account locked",
+			inputBody:     `{"email": "test@mail.ru", "password": "testing"}`,
+			inputEmail:    "test@mail.ru",
+			inputPassword: "testing",
+			mockBehavior: func(s *mockService.MockAuthorization, email, password string) {
+				s.EXPECT().GenerateToken(gomock.Any(), email, password).Return(model.SignInResult{}, service.ErrAccountLocked)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrAccountLocked.Error()),
+		},
 	}
 
 	// Act
@@ -198,7 +230,7 @@ func TestHandler_signIn(t *testing.T) {
 			tc.mockBehavior(auth, tc.inputEmail, tc.inputPassword)
 
 			services := &service.Service{Authorization: auth}
-			handler := New(services)
+			handler := New(services, nil)
 
 			// Test server
 			gin.SetMode("test")
@@ -218,3 +250,638 @@ func TestHandler_signIn(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_completeSignIn(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockAuthorization, challenge, code string)
+
+	testCases := []struct {
+		name                 string
+		inputBody            string
+		inputChallenge       string
+		inputCode            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:           "OK",
+			inputBody:      `{"challenge_token": "challenge", "code": "123456"}`,
+			inputChallenge: "challenge",
+			inputCode:      "123456",
+			mockBehavior: func(s *mockService.MockAuthorization, challenge, code string) {
+				s.EXPECT().CompleteSignIn(gomock.Any(), challenge, code).Return("token", nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"token":"token"}`,
+		},
+		{
+			name:                 "Empty code",
+			inputBody:            `{"challenge_token": "challenge"}`,
+			mockBehavior:         func(s *mockService.MockAuthorization, challenge, code string) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidInputBody.Error()),
+		},
+		{
+			name:           "Invalid code",
+			inputBody:      `{"challenge_token": "challenge", "code": "123456"}`,
+			inputChallenge: "challenge",
+			inputCode:      "123456",
+			mockBehavior: func(s *mockService.MockAuthorization, challenge, code string) {
+				s.EXPECT().CompleteSignIn(gomock.Any(), challenge, code).Return("", service.ErrInvalidTwoFactorCode)
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrInvalidTwoFactorCode.Error()),
+		},
+		{
+			name:           "Invalid challenge",
+			inputBody:      `{"challenge_token": "challenge", "code": "123456"}`,
+			inputChallenge: "challenge",
+			inputCode:      "123456",
+			mockBehavior: func(s *mockService.MockAuthorization, challenge, code string) {
+				s.EXPECT().CompleteSignIn(gomock.Any(), challenge, code).Return("", service.ErrInvalidChallengeToken)
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrInvalidChallengeToken.Error()),
+		},
+		{
+			name:           "Account locked",
+			inputBody:      `{"challenge_token": "challenge", "code": "123456"}`,
+			inputChallenge: "challenge",
+			inputCode:      "123456",
+			mockBehavior: func(s *mockService.MockAuthorization, challenge, code string) {
+				s.EXPECT().CompleteSignIn(gomock.Any(), challenge, code).Return("", service.ErrAccountLocked)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrAccountLocked.Error()),
+		},
+		{
+			name:           "Service failure",
+			inputBody:      `{"challenge_token": "challenge", "code": "123456"}`,
+			inputChallenge: "challenge",
+			inputCode:      "123456",
+			mockBehavior: func(s *mockService.MockAuthorization, challenge, code string) {
+				s.EXPECT().CompleteSignIn(gomock.Any(), challenge, code).Return("", service.ErrFailedToCompleteSignIn)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToCompleteSignIn.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mockService.NewMockAuthorization(c)
+			tc.mockBehavior(auth, tc.inputChallenge, tc.inputCode)
+
+			services := &service.Service{Authorization: auth}
+			handler := New(services, nil)
+
+			// Test server
+			r := gin.New()
+			r.POST("/auth/sign-in/2fa", handler.completeSignIn)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/auth/sign-in/2fa", bytes.NewBufferString(tc.inputBody))
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_verifyEmail(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockAuthorization, token string)
+
+	testCases := []struct {
+		name                 string
+		token                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "OK",
+			token: "token",
+			mockBehavior: func(s *mockService.MockAuthorization, token string) {
+				s.EXPECT().VerifyEmail(gomock.Any(), token).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"result":"the email was verified"}`,
+		},
+		{
+			name:                 "Empty token",
+			mockBehavior:         func(s *mockService.MockAuthorization, token string) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errEmptyToken.Error()),
+		},
+		{
+			name:  "Invalid token",
+			token: "expired",
+			mockBehavior: func(s *mockService.MockAuthorization, token string) {
+				s.EXPECT().VerifyEmail(gomock.Any(), token).Return(service.ErrInvalidVerificationToken)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrInvalidVerificationToken.Error()),
+		},
+		{
+			name:  "Service failure",
+			token: "token",
+			mockBehavior: func(s *mockService.MockAuthorization, token string) {
+				s.EXPECT().VerifyEmail(gomock.Any(), token).Return(service.ErrFailedToVerifyEmail)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToVerifyEmail.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mockService.NewMockAuthorization(c)
+			tc.mockBehavior(auth, tc.token)
+
+			services := &service.Service{Authorization: auth}
+			handler := New(services, nil)
+
+			// Test server
+			r := gin.New()
+			r.GET("/auth/verify", handler.verifyEmail)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/auth/verify?token="+tc.token, nil)
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_resendVerification(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockAuthorization, email string)
+
+	testCases := []struct {
+		name                 string
+		inputBody            string
+		inputEmail           string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:       "OK",
+			inputBody:  `{"email": "test@mail.ru"}`,
+			inputEmail: "test@mail.ru",
+			mockBehavior: func(s *mockService.MockAuthorization, email string) {
+				s.EXPECT().ResendVerification(gomock.Any(), email).Return(nil)
+			},
+			expectedStatusCode:   202,
+			expectedResponseBody: `{"result":"the verification email is sent if the account exists and isn't verified yet"}`,
+		},
+		{
+			name:                 "Invalid email",
+			inputBody:            `{"email": "test"}`,
+			mockBehavior:         func(s *mockService.MockAuthorization, email string) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidInputBody.Error()),
+		},
+		{
+			name:       "Service failure",
+			inputBody:  `{"email": "test@mail.ru"}`,
+			inputEmail: "test@mail.ru",
+			mockBehavior: func(s *mockService.MockAuthorization, email string) {
+				s.EXPECT().ResendVerification(gomock.Any(), email).Return(service.ErrFailedToSendVerification)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToSendVerification.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mockService.NewMockAuthorization(c)
+			tc.mockBehavior(auth, tc.inputEmail)
+
+			services := &service.Service{Authorization: auth}
+			handler := New(services, nil)
+
+			// Test server
+			r := gin.New()
+			r.POST("/auth/verify/resend", handler.resendVerification)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/auth/verify/resend", bytes.NewBufferString(tc.inputBody))
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_forgotPassword(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockAuthorization, email string)
+
+	testCases := []struct {
+		name                 string
+		inputBody            string
+		inputEmail           string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:       "OK",
+			inputBody:  `{"email": "test@mail.ru"}`,
+			inputEmail: "test@mail.ru",
+			mockBehavior: func(s *mockService.MockAuthorization, email string) {
+				s.EXPECT().ForgotPassword(gomock.Any(), email).Return(nil)
+			},
+			expectedStatusCode:   202,
+			expectedResponseBody: `{"result":"the password reset email is sent if the account exists"}`,
+		},
+		{
+			name:                 "Invalid email",
+			inputBody:            `{"email": "test"}`,
+			mockBehavior:         func(s *mockService.MockAuthorization, email string) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidInputBody.Error()),
+		},
+		{
+			name:       "Service failure",
+			inputBody:  `{"email": "test@mail.ru"}`,
+			inputEmail: "test@mail.ru",
+			mockBehavior: func(s *mockService.MockAuthorization, email string) {
+				s.EXPECT().ForgotPassword(gomock.Any(), email).Return(service.ErrFailedToSendPasswordReset)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToSendPasswordReset.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mockService.NewMockAuthorization(c)
+			tc.mockBehavior(auth, tc.inputEmail)
+
+			services := &service.Service{Authorization: auth}
+			handler := New(services, nil)
+
+			// Test server
+			r := gin.New()
+			r.POST("/auth/forgot-password", handler.forgotPassword)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/auth/forgot-password", bytes.NewBufferString(tc.inputBody))
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_resetPassword(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockAuthorization, token, password string)
+
+	testCases := []struct {
+		name                 string
+		inputBody            string
+		inputToken           string
+		inputPassword        string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:          "OK",
+			inputBody:     `{"token": "token", "password": "qwerty"}`,
+			inputToken:    "token",
+			inputPassword: "qwerty",
+			mockBehavior: func(s *mockService.MockAuthorization, token, password string) {
+				s.EXPECT().ResetPassword(gomock.Any(), token, password).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"result":"the password was reset"}`,
+		},
+		{
+			name:                 "Short password",
+			inputBody:            `{"token": "token", "password": "qwe"}`,
+			mockBehavior:         func(s *mockService.MockAuthorization, token, password string) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidInputBody.Error()),
+		},
+		{
+			name:                 "Empty token",
+			inputBody:            `{"password": "qwerty"}`,
+			mockBehavior:         func(s *mockService.MockAuthorization, token, password string) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidInputBody.Error()),
+		},
+		{
+			name:          "Invalid token",
+			inputBody:     `{"token": "token", "password": "qwerty"}`,
+			inputToken:    "token",
+			inputPassword: "qwerty",
+			mockBehavior: func(s *mockService.MockAuthorization, token, password string) {
+				s.EXPECT().ResetPassword(gomock.Any(), token, password).Return(service.ErrInvalidResetToken)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrInvalidResetToken.Error()),
+		},
+		{
+			name:          "Service failure",
+			inputBody:     `{"token": "token", "password": "qwerty"}`,
+			inputToken:    "token",
+			inputPassword: "qwerty",
+			mockBehavior: func(s *mockService.MockAuthorization, token, password string) {
+				s.EXPECT().ResetPassword(gomock.Any(), token, password).Return(service.ErrFailedToResetPassword)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToResetPassword.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mockService.NewMockAuthorization(c)
+			tc.mockBehavior(auth, tc.inputToken, tc.inputPassword)
+
+			services := &service.Service{Authorization: auth}
+			handler := New(services, nil)
+
+			// Test server
+			r := gin.New()
+			r.POST("/auth/reset-password", handler.resetPassword)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/auth/reset-password", bytes.NewBufferString(tc.inputBody))
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+// TestHandler_emailVerification goes through the sign-up, the link from the captured email
+// and the sign-in with the services on top of the in-memory repositories.
+func TestHandler_emailVerification(t *testing.T) {
+	mailer := &mailtest.Mailer{}
+	cfg := config.Service{
+		SigningKey:          "key",
+		Salt:                "salt",
+		TokenTTL:            60,
+		RequireVerification: true,
+		VerificationTTL:     60,
+		BaseURL:             "http://todo.local",
+	}
+	services := service.New(repository.NewMemory(memory.NewStore()), cfg, nil, service.LogNotifier{}, mailer, nil)
+	r := New(services, nil).InitRoutes()
+
+	request := func(method, target, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, target, bytes.NewBufferString(body)))
+		return w
+	}
+
+	signIn := `{"email": "test@mail.ru", "password": "testing"}`
+
+	w := request("POST", "/auth/sign-up", `{"name": "test", "email": "test@mail.ru", "password": "testing"}`)
+	assert.Equal(t, 201, w.Code)
+
+	w = request("POST", "/auth/sign-in", signIn)
+	assert.Equal(t, 403, w.Code)
+	assert.Equal(t, fmt.Sprintf(`{"error":"%s"}`, service.ErrEmailNotVerified.Error()), w.Body.String())
+
+	messages := mailer.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "test@mail.ru", messages[0].To)
+
+	link := regexp.MustCompile(`http://todo\.local(/auth/verify\?token=\S+)`).FindStringSubmatch(messages[0].Body)
+	require.Len(t, link, 2)
+
+	w = request("GET", link[1]+"x", "")
+	assert.Equal(t, 400, w.Code)
+
+	w = request("GET", link[1], "")
+	assert.Equal(t, 200, w.Code)
+
+	w = request("POST", "/auth/sign-in", signIn)
+	assert.Equal(t, 200, w.Code)
+
+	w = request("POST", "/auth/verify/resend", `{"email": "test@mail.ru"}`)
+	assert.Equal(t, 202, w.Code)
+	assert.Len(t, mailer.Messages(), 1)
+}
+
+// TestHandler_passwordReset goes through the password change and the reset with the token
+// from the captured email, checking that both sign out the sessions issued before them.
+func TestHandler_passwordReset(t *testing.T) {
+	mailer := &mailtest.Mailer{}
+	cfg := config.Service{
+		SigningKey:       "key",
+		Salt:             "salt",
+		TokenTTL:         60,
+		VerificationTTL:  60,
+		PasswordResetTTL: 60,
+	}
+	services := service.New(repository.NewMemory(memory.NewStore()), cfg, nil, service.LogNotifier{}, mailer, nil)
+	r := New(services, nil).InitRoutes()
+
+	request := func(method, target, token, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	signIn := func(password string) string {
+		w := request("POST", "/auth/sign-in", "", fmt.Sprintf(`{"email": "test@mail.ru", "password": "%s"}`, password))
+		require.Equal(t, 200, w.Code)
+
+		var resp struct{ Token string }
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Token
+	}
+
+	w := request("POST", "/auth/sign-up", "", `{"name": "test", "email": "test@mail.ru", "password": "testing"}`)
+	require.Equal(t, 201, w.Code)
+
+	first := signIn("testing")
+
+	w = request("PUT", "/api/me/password", first, `{"current_password": "wrong!", "new_password": "changed"}`)
+	assert.Equal(t, 403, w.Code)
+
+	w = request("PUT", "/api/me/password", first, `{"current_password": "testing", "new_password": "changed"}`)
+	require.Equal(t, 200, w.Code)
+
+	var changed struct{ Token string }
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &changed))
+
+	w = request("GET", "/api/lists/", first, "")
+	assert.Equal(t, 401, w.Code)
+	assert.Equal(t, fmt.Sprintf(`{"error":"%s"}`, service.ErrSessionRevoked.Error()), w.Body.String())
+
+	w = request("GET", "/api/lists/", changed.Token, "")
+	assert.Equal(t, 200, w.Code)
+
+	w = request("POST", "/auth/forgot-password", "", `{"email": "unknown@mail.ru"}`)
+	assert.Equal(t, 202, w.Code)
+
+	w = request("POST", "/auth/forgot-password", "", `{"email": "test@mail.ru"}`)
+	assert.Equal(t, 202, w.Code)
+
+	messages := mailer.Messages()
+	require.Len(t, messages, 2)
+	assert.Equal(t, "test@mail.ru", messages[1].To)
+
+	token := regexp.MustCompile(`(?m)^[0-9a-f]{64}$`).FindString(messages[1].Body)
+	require.NotEmpty(t, token)
+
+	reset := fmt.Sprintf(`{"token": "%s", "password": "resetted"}`, token)
+
+	w = request("POST", "/auth/reset-password", "", reset)
+	assert.Equal(t, 200, w.Code)
+
+	w = request("POST", "/auth/reset-password", "", reset)
+	assert.Equal(t, 400, w.Code)
+
+	w = request("GET", "/api/lists/", changed.Token, "")
+	assert.Equal(t, 401, w.Code)
+
+	w = request("GET", "/api/lists/", signIn("resetted"), "")
+	assert.Equal(t, 200, w.Code)
+}
+
+// TestHandler_twoFactor enrolls an authenticator app and signs in with its codes and
+// a recovery code, checking that neither can be used twice.
+func TestHandler_twoFactor(t *testing.T) {
+	cfg := config.Service{
+		SigningKey:            "key",
+		Salt:                  "salt",
+		TokenTTL:              60,
+		LockoutThreshold:      5,
+		LockoutDuration:       60,
+		TwoFactorChallengeTTL: 60,
+	}
+	services := service.New(repository.NewMemory(memory.NewStore()), cfg, nil, service.LogNotifier{}, &mailtest.Mailer{}, nil)
+	r := New(services, nil).InitRoutes()
+
+	request := func(method, target, token, body string, resp interface{}) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		r.ServeHTTP(w, req)
+		if resp != nil {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), resp))
+		}
+		return w.Code
+	}
+
+	var signIn model.SignInResult
+	signInBody := `{"email": "test@mail.ru", "password": "testing"}`
+
+	require.Equal(t, 201, request("POST", "/auth/sign-up", "", `{"name": "test", "email": "test@mail.ru", "password": "testing"}`, nil))
+	require.Equal(t, 200, request("POST", "/auth/sign-in", "", signInBody, &signIn))
+	require.NotEmpty(t, signIn.Token)
+	token := signIn.Token
+
+	var setup model.TwoFactorSetup
+	require.Equal(t, 200, request("POST", "/api/me/2fa/setup", token, "", &setup))
+	assert.Contains(t, setup.URI, "otpauth://totp/todo-app:test@mail.ru?")
+
+	code := func(step int64) string {
+		c, err := totp.Code(setup.Secret, step)
+		require.NoError(t, err)
+		return c
+	}
+	step := totp.Step(time.Now())
+
+	assert.Equal(t, 400, request("POST", "/api/me/2fa/confirm", token, `{"code": "abcdef"}`, nil))
+
+	var confirmed struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+	require.Equal(t, 200, request("POST", "/api/me/2fa/confirm", token, fmt.Sprintf(`{"code": "%s"}`, code(step-1)), &confirmed))
+	require.Len(t, confirmed.RecoveryCodes, 10)
+
+	assert.Equal(t, 409, request("POST", "/api/me/2fa/setup", token, "", nil))
+
+	signIn = model.SignInResult{}
+	require.Equal(t, 200, request("POST", "/auth/sign-in", "", signInBody, &signIn))
+	assert.Empty(t, signIn.Token)
+	require.NotEmpty(t, signIn.ChallengeToken)
+
+	assert.Equal(t, 401, request("GET", "/api/lists/", signIn.ChallengeToken, "", nil))
+
+	complete := func(code string) int {
+		return request("POST", "/auth/sign-in/2fa", "", fmt.Sprintf(`{"challenge_token": "%s", "code": "%s"}`, signIn.ChallengeToken, code), nil)
+	}
+
+	assert.Equal(t, 401, complete(code(step-1)))
+	assert.Equal(t, 200, complete(code(step)))
+	assert.Equal(t, 401, complete(code(step)))
+	assert.Equal(t, 200, complete(strings.ToUpper(confirmed.RecoveryCodes[0])))
+	assert.Equal(t, 401, complete(confirmed.RecoveryCodes[0]))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("PROPFIND", "/caldav/", nil)
+	req.SetBasicAuth("test@mail.ru", "testing")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 401, w.Code)
+}
@@ -0,0 +1,542 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Lapp-coder/todo-app/internal/caldav"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	caldavRootHref         = "/caldav/"
+	caldavPrincipalHref    = "/caldav/principal/"
+	caldavHomeHref         = "/caldav/calendars/"
+	caldavAllowedMethods   = "OPTIONS, PROPFIND, REPORT, GET, PUT, DELETE"
+	multistatusContentType = "application/xml; charset=utf-8"
+)
+
+type caldavPathKind int
+
+const (
+	caldavRoot caldavPathKind = iota
+	caldavPrincipal
+	caldavHome
+	caldavCalendar
+	caldavObject
+)
+
+type caldavPath struct {
+	kind   caldavPathKind
+	listID int
+	name   string
+}
+
+type caldavObjectInfo struct {
+	item model.TodoItem
+	name string
+	uid  string
+}
+
+func (o caldavObjectInfo) etag() string {
+	return caldav.ETag(o.item, o.uid)
+}
+
+func caldavCalendarHref(listID int) string {
+	return fmt.Sprintf("%s%d/", caldavHomeHref, listID)
+}
+
+func caldavObjectHref(listID int, name string) string {
+	return caldavCalendarHref(listID) + name
+}
+
+func parseCalDAVPath(path string) (caldavPath, bool) {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return caldavPath{kind: caldavRoot}, true
+	}
+
+	parts := strings.Split(path, "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "principal":
+		return caldavPath{kind: caldavPrincipal}, true
+	case parts[0] != "calendars" || len(parts) > 3:
+		return caldavPath{}, false
+	case len(parts) == 1:
+		return caldavPath{kind: caldavHome}, true
+	}
+
+	listID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return caldavPath{}, false
+	}
+
+	if len(parts) == 2 {
+		return caldavPath{kind: caldavCalendar, listID: listID}, true
+	}
+
+	return caldavPath{kind: caldavObject, listID: listID, name: parts[2]}, true
+}
+
+func (h Handler) caldavWellKnown(ctx *gin.Context) {
+	ctx.Redirect(http.StatusMovedPermanently, caldavRootHref)
+}
+
+func (h Handler) caldavOptions(ctx *gin.Context) {
+	ctx.Header("DAV", "1, 3, calendar-access")
+	ctx.Header("Allow", caldavAllowedMethods)
+	ctx.Status(http.StatusOK)
+}
+
+func (h Handler) caldavPropfind(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	path, ok := parseCalDAVPath(ctx.Param("path"))
+	if !ok {
+		respondError(ctx, http.StatusNotFound, errCalDAVResourceNotFound)
+		return
+	}
+
+	req, err := caldav.ParsePropfind(ctx.Request.Body)
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	depth := 1
+	if ctx.GetHeader("Depth") == "0" {
+		depth = 0
+	}
+
+	multistatus := caldav.NewMultistatus()
+	switch path.kind {
+	case caldavRoot:
+		multistatus.AddResource(caldavRootResource(), req.Names)
+		if depth > 0 {
+			multistatus.AddResource(caldavPrincipalResource(), req.Names)
+			multistatus.AddResource(caldavHomeResource(), req.Names)
+		}
+	case caldavPrincipal:
+		multistatus.AddResource(caldavPrincipalResource(), req.Names)
+	case caldavHome:
+		multistatus.AddResource(caldavHomeResource(), req.Names)
+		if depth > 0 {
+			lists, err := h.service.TodoList.GetAll(ctx.Request.Context(), userID)
+			if err != nil {
+				respondError(ctx, http.StatusInternalServerError, err)
+				return
+			}
+
+			for _, list := range lists {
+				objects, err := h.caldavObjects(ctx.Request.Context(), userID, list.ID)
+				if err != nil {
+					respondError(ctx, http.StatusInternalServerError, err)
+					return
+				}
+
+				multistatus.AddResource(caldavCalendarResource(list, objects), req.Names)
+			}
+		}
+	case caldavCalendar:
+		list, err := h.service.TodoList.GetByID(ctx.Request.Context(), userID, path.listID)
+		if err != nil {
+			respondError(ctx, http.StatusNotFound, errCalDAVResourceNotFound)
+			return
+		}
+
+		objects, err := h.caldavObjects(ctx.Request.Context(), userID, list.ID)
+		if err != nil {
+			respondError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+
+		multistatus.AddResource(caldavCalendarResource(list, objects), req.Names)
+		if depth > 0 {
+			for _, object := range objects {
+				multistatus.AddResource(caldavObjectResource(object, false), req.Names)
+			}
+		}
+	case caldavObject:
+		object, err := h.caldavObject(ctx.Request.Context(), userID, path.listID, path.name)
+		if err != nil {
+			h.respondCalDAVObjectError(ctx, err)
+			return
+		}
+
+		multistatus.AddResource(caldavObjectResource(object, false), req.Names)
+	}
+
+	respondMultistatus(ctx, multistatus)
+}
+
+func (h Handler) caldavReport(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	path, ok := parseCalDAVPath(ctx.Param("path"))
+	if !ok || path.kind != caldavCalendar {
+		respondError(ctx, http.StatusMethodNotAllowed, errCalDAVMethodNotAllowed)
+		return
+	}
+
+	req, err := caldav.ParseReport(ctx.Request.Body)
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err = h.service.TodoList.GetByID(ctx.Request.Context(), userID, path.listID); err != nil {
+		respondError(ctx, http.StatusNotFound, errCalDAVResourceNotFound)
+		return
+	}
+
+	multistatus := caldav.NewMultistatus()
+	switch req.Name {
+	case caldav.ReportCalendarMultiget:
+		for _, href := range req.Hrefs {
+			name := strings.TrimPrefix(href, caldavCalendarHref(path.listID))
+			if name == href || name == "" {
+				multistatus.AddStatus(href, http.StatusNotFound)
+				continue
+			}
+
+			object, err := h.caldavObject(ctx.Request.Context(), userID, path.listID, name)
+			if err != nil {
+				multistatus.AddStatus(href, http.StatusNotFound)
+				continue
+			}
+
+			resource := caldavObjectResource(object, true)
+			resource.Href = href
+			multistatus.AddResource(resource, req.Names)
+		}
+	case caldav.ReportCalendarQuery:
+		if !caldavQueryMatchesTodos(req.Components) {
+			break
+		}
+
+		objects, err := h.caldavObjects(ctx.Request.Context(), userID, path.listID)
+		if err != nil {
+			respondError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+
+		for _, object := range objects {
+			multistatus.AddResource(caldavObjectResource(object, true), req.Names)
+		}
+	default:
+		respondError(ctx, http.StatusForbidden, errCalDAVUnsupportedReport)
+		return
+	}
+
+	respondMultistatus(ctx, multistatus)
+}
+
+func (h Handler) caldavGet(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	path, ok := parseCalDAVPath(ctx.Param("path"))
+	if !ok || path.kind != caldavObject {
+		respondError(ctx, http.StatusMethodNotAllowed, errCalDAVMethodNotAllowed)
+		return
+	}
+
+	object, err := h.caldavObject(ctx.Request.Context(), userID, path.listID, path.name)
+	if err != nil {
+		h.respondCalDAVObjectError(ctx, err)
+		return
+	}
+
+	data, err := caldav.EncodeItem(object.item, object.uid)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Header("ETag", object.etag())
+	ctx.Data(http.StatusOK, caldav.ContentType, data)
+}
+
+func (h Handler) caldavPut(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	path, ok := parseCalDAVPath(ctx.Param("path"))
+	if !ok || path.kind != caldavObject {
+		respondError(ctx, http.StatusMethodNotAllowed, errCalDAVMethodNotAllowed)
+		return
+	}
+
+	item, uid, err := caldav.DecodeItem(ctx.Request.Body)
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	existing, err := h.caldavObject(ctx.Request.Context(), userID, path.listID, path.name)
+	exists := err == nil
+	if err != nil && !errors.Is(err, errCalDAVResourceNotFound) {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	if !checkCalDAVPreconditions(ctx, existing, exists) {
+		respondError(ctx, http.StatusPreconditionFailed, errCalDAVPreconditionFailed)
+		return
+	}
+
+	object := model.CalendarObject{Name: path.name, UID: uid}
+	statusCode := http.StatusNoContent
+	if exists {
+		object.ItemID = existing.item.ID
+	} else {
+		if _, err = h.service.TodoList.GetByID(ctx.Request.Context(), userID, path.listID); err != nil {
+			respondError(ctx, http.StatusConflict, errCalDAVResourceNotFound)
+			return
+		}
+
+		statusCode = http.StatusCreated
+	}
+
+	if object, err = h.service.CalDAV.PutItem(ctx.Request.Context(), userID, path.listID, object, item); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	saved, err := h.service.TodoItem.GetByID(ctx.Request.Context(), userID, object.ItemID)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Header("ETag", caldav.ETag(saved, object.UID))
+	ctx.Status(statusCode)
+}
+
+func (h Handler) caldavDelete(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	path, ok := parseCalDAVPath(ctx.Param("path"))
+	if !ok || (path.kind != caldavObject && path.kind != caldavCalendar) {
+		respondError(ctx, http.StatusMethodNotAllowed, errCalDAVMethodNotAllowed)
+		return
+	}
+
+	if path.kind == caldavCalendar {
+		if err := h.service.TodoList.Delete(ctx.Request.Context(), userID, path.listID); err != nil {
+			respondError(ctx, http.StatusNotFound, errCalDAVResourceNotFound)
+			return
+		}
+
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+
+	object, err := h.caldavObject(ctx.Request.Context(), userID, path.listID, path.name)
+	if err != nil {
+		h.respondCalDAVObjectError(ctx, err)
+		return
+	}
+
+	if !checkCalDAVPreconditions(ctx, object, true) {
+		respondError(ctx, http.StatusPreconditionFailed, errCalDAVPreconditionFailed)
+		return
+	}
+
+	if err = h.service.TodoItem.Delete(ctx.Request.Context(), userID, object.item.ID); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// caldavObjects returns all items of the list along with the names and UIDs
+// they're known by to CalDAV clients.
+func (h Handler) caldavObjects(ctx context.Context, userID, listID int) ([]caldavObjectInfo, error) {
+	items, err := h.service.TodoItem.GetAll(ctx, userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	mapped, err := h.service.CalDAV.GetObjects(ctx, userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	byItemID := make(map[int]model.CalendarObject, len(mapped))
+	for _, object := range mapped {
+		byItemID[object.ItemID] = object
+	}
+
+	objects := make([]caldavObjectInfo, 0, len(items))
+	for _, item := range items {
+		object := caldavObjectInfo{item: item, name: caldav.DefaultName(item.ID), uid: caldav.DefaultUID(item.ID)}
+		if m, ok := byItemID[item.ID]; ok {
+			object.name, object.uid = m.Name, m.UID
+		}
+
+		objects = append(objects, object)
+	}
+
+	return objects, nil
+}
+
+func (h Handler) caldavObject(ctx context.Context, userID, listID int, name string) (caldavObjectInfo, error) {
+	object, err := h.service.CalDAV.GetObjectByName(ctx, userID, listID, name)
+	switch {
+	case err == nil:
+		item, err := h.service.TodoItem.GetByID(ctx, userID, object.ItemID)
+		if err != nil {
+			return caldavObjectInfo{}, errCalDAVResourceNotFound
+		}
+
+		return caldavObjectInfo{item: item, name: object.Name, uid: object.UID}, nil
+	case !errors.Is(err, service.ErrCalendarObjectNotFound):
+		return caldavObjectInfo{}, err
+	}
+
+	itemID, ok := caldav.ParseDefaultName(name)
+	if !ok {
+		return caldavObjectInfo{}, errCalDAVResourceNotFound
+	}
+
+	item, err := h.service.TodoItem.GetByID(ctx, userID, itemID)
+	if err != nil || item.ListID != listID {
+		return caldavObjectInfo{}, errCalDAVResourceNotFound
+	}
+
+	return caldavObjectInfo{item: item, name: name, uid: caldav.DefaultUID(item.ID)}, nil
+}
+
+func (h Handler) respondCalDAVObjectError(ctx *gin.Context, err error) {
+	if errors.Is(err, errCalDAVResourceNotFound) {
+		respondError(ctx, http.StatusNotFound, err)
+		return
+	}
+
+	respondError(ctx, http.StatusInternalServerError, err)
+}
+
+func checkCalDAVPreconditions(ctx *gin.Context, object caldavObjectInfo, exists bool) bool {
+	if ctx.GetHeader("If-None-Match") == "*" && exists {
+		return false
+	}
+
+	if ifMatch := ctx.GetHeader("If-Match"); ifMatch != "" {
+		if !exists {
+			return false
+		}
+
+		return ifMatch == "*" || ifMatch == object.etag()
+	}
+
+	return true
+}
+
+func caldavQueryMatchesTodos(components []string) bool {
+	for _, component := range components {
+		if component != "VCALENDAR" && component != "VTODO" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func caldavRootResource() *caldav.Resource {
+	resource := caldav.NewResource(caldavRootHref)
+	resource.Set(caldav.PropResourceType, caldav.CollectionType)
+	resource.Set(caldav.PropCurrentUserPrincipal, caldav.Href(caldavPrincipalHref))
+
+	return resource
+}
+
+func caldavPrincipalResource() *caldav.Resource {
+	resource := caldav.NewResource(caldavPrincipalHref)
+	resource.Set(caldav.PropResourceType, caldav.PrincipalType)
+	resource.Set(caldav.PropCurrentUserPrincipal, caldav.Href(caldavPrincipalHref))
+	resource.Set(caldav.PropPrincipalURL, caldav.Href(caldavPrincipalHref))
+	resource.Set(caldav.PropCalendarHomeSet, caldav.Href(caldavHomeHref))
+
+	return resource
+}
+
+func caldavHomeResource() *caldav.Resource {
+	resource := caldav.NewResource(caldavHomeHref)
+	resource.Set(caldav.PropResourceType, caldav.CollectionType)
+	resource.Set(caldav.PropCurrentUserPrincipal, caldav.Href(caldavPrincipalHref))
+	resource.Set(caldav.PropCurrentUserPrivilegeSet, caldav.AllPrivileges)
+
+	return resource
+}
+
+func caldavCalendarResource(list model.TodoList, objects []caldavObjectInfo) *caldav.Resource {
+	etags := make([]string, 0, len(objects))
+	for _, object := range objects {
+		etags = append(etags, object.etag())
+	}
+
+	resource := caldav.NewResource(caldavCalendarHref(list.ID))
+	resource.Set(caldav.PropResourceType, caldav.CalendarType)
+	resource.SetText(caldav.PropDisplayName, list.Title)
+	resource.SetText(caldav.PropCalendarDescription, list.Description)
+	resource.Set(caldav.PropSupportedCalendarComponentSet, caldav.TodoComponentSet)
+	resource.Set(caldav.PropSupportedReportSet, caldav.CalendarSupportedReport)
+	resource.Set(caldav.PropCurrentUserPrincipal, caldav.Href(caldavPrincipalHref))
+	resource.Set(caldav.PropCurrentUserPrivilegeSet, caldav.AllPrivileges)
+	resource.Set(caldav.PropOwner, caldav.Href(caldavPrincipalHref))
+	resource.SetText(caldav.PropGetCTag, caldav.CTag(etags))
+
+	return resource
+}
+
+func caldavObjectResource(object caldavObjectInfo, withData bool) *caldav.Resource {
+	resource := caldav.NewResource(caldavObjectHref(object.item.ListID, object.name))
+	resource.Set(caldav.PropResourceType, "")
+	resource.SetText(caldav.PropGetETag, object.etag())
+	resource.SetText(caldav.PropGetContentType, caldav.ContentType)
+
+	if withData {
+		if data, err := caldav.EncodeItem(object.item, object.uid); err == nil {
+			resource.SetText(caldav.PropCalendarData, string(data))
+		}
+	}
+
+	return resource
+}
+
+func respondMultistatus(ctx *gin.Context, multistatus *caldav.Multistatus) {
+	var buf bytes.Buffer
+	if err := multistatus.Encode(&buf); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Data(http.StatusMultiStatus, multistatusContentType, buf.Bytes())
+}
@@ -0,0 +1,493 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Lapp-coder/todo-app/internal/caldav"
+	"github.com/Lapp-coder/todo-app/internal/config"
+	"github.com/Lapp-coder/todo-app/internal/mail/mailtest"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/repository"
+	"github.com/Lapp-coder/todo-app/internal/repository/memory"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	mockService "github.com/Lapp-coder/todo-app/internal/service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type caldavMocks struct {
+	todoList *mockService.MockTodoList
+	todoItem *mockService.MockTodoItem
+	caldav   *mockService.MockCalDAV
+}
+
+func newCalDAVTestRouter(t *testing.T, mockBehavior func(m caldavMocks)) (*gin.Engine, *gomock.Controller) {
+	c := gomock.NewController(t)
+
+	m := caldavMocks{
+		todoList: mockService.NewMockTodoList(c),
+		todoItem: mockService.NewMockTodoItem(c),
+		caldav:   mockService.NewMockCalDAV(c),
+	}
+	mockBehavior(m)
+
+	services := &service.Service{TodoList: m.todoList, TodoItem: m.todoItem, CalDAV: m.caldav}
+	handler := New(services, nil)
+
+	gin.SetMode("test")
+	r := gin.New()
+	group := r.Group("/caldav", func(c *gin.Context) {
+		c.Set(userCtx, 1)
+	})
+	group.Handle("PROPFIND", "/*path", handler.caldavPropfind)
+	group.Handle("REPORT", "/*path", handler.caldavReport)
+	group.GET("/*path", handler.caldavGet)
+	group.PUT("/*path", handler.caldavPut)
+	group.DELETE("/*path", handler.caldavDelete)
+
+	return r, c
+}
+
+func TestHandler_caldavGet(t *testing.T) {
+	// Arrange
+	item := model.TodoItem{ID: 2, ListID: 1, Title: "test", CompletionDate: "2021-11-21T10:00:00Z"}
+	body, _ := caldav.EncodeItem(item, "client-uid")
+
+	testCases := []struct {
+		name                 string
+		inputPath            string
+		mockBehavior         func(m caldavMocks)
+		expectedStatusCode   int
+		expectedETag         string
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK_ClientName",
+			inputPath: "/caldav/calendars/1/client.ics",
+			mockBehavior: func(m caldavMocks) {
+				m.caldav.EXPECT().GetObjectByName(gomock.Any(), 1, 1, "client.ics").
+					Return(model.CalendarObject{ItemID: 2, Name: "client.ics", UID: "client-uid"}, nil)
+				m.todoItem.EXPECT().GetByID(gomock.Any(), 1, 2).Return(item, nil)
+			},
+			expectedStatusCode:   200,
+			expectedETag:         caldav.ETag(item, "client-uid"),
+			expectedResponseBody: string(body),
+		},
+		{
+			name:      "Item of another list",
+			inputPath: "/caldav/calendars/3/2.ics",
+			mockBehavior: func(m caldavMocks) {
+				m.caldav.EXPECT().GetObjectByName(gomock.Any(), 1, 3, "2.ics").Return(model.CalendarObject{}, service.ErrCalendarObjectNotFound)
+				m.todoItem.EXPECT().GetByID(gomock.Any(), 1, 2).Return(item, nil)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errCalDAVResourceNotFound.Error()),
+		},
+		{
+			name:                 "Collection",
+			inputPath:            "/caldav/calendars/1/",
+			mockBehavior:         func(m caldavMocks) {},
+			expectedStatusCode:   405,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errCalDAVMethodNotAllowed.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, c := newCalDAVTestRouter(t, tc.mockBehavior)
+			defer c.Finish()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", tc.inputPath, nil)
+
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedETag, w.Header().Get("ETag"))
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_caldavPut(t *testing.T) {
+	// Arrange
+	const body = "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:client-uid\r\nSUMMARY:test\r\nDUE:20211121T100000\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+
+	existing := model.TodoItem{ID: 2, ListID: 1, Title: "old", CompletionDate: "2021-11-20T10:00:00Z"}
+	saved := model.TodoItem{ID: 2, ListID: 1, Title: "test", CompletionDate: "2021-11-21T10:00:00Z"}
+
+	testCases := []struct {
+		name               string
+		inputPath          string
+		inputBody          string
+		headers            map[string]string
+		mockBehavior       func(m caldavMocks)
+		expectedStatusCode int
+		expectedETag       string
+	}{
+		{
+			name:      "OK_Create",
+			inputPath: "/caldav/calendars/1/client.ics",
+			inputBody: body,
+			headers:   map[string]string{"If-None-Match": "*"},
+			mockBehavior: func(m caldavMocks) {
+				m.caldav.EXPECT().GetObjectByName(gomock.Any(), 1, 1, "client.ics").Return(model.CalendarObject{}, service.ErrCalendarObjectNotFound)
+				m.todoList.EXPECT().GetByID(gomock.Any(), 1, 1).Return(model.TodoList{ID: 1}, nil)
+				m.caldav.EXPECT().PutItem(gomock.Any(), 1, 1, model.CalendarObject{Name: "client.ics", UID: "client-uid"},
+					model.TodoItem{Title: "test", CompletionDate: "2021-11-21 10:00:00"}).
+					Return(model.CalendarObject{ItemID: 2, Name: "client.ics", UID: "client-uid"}, nil)
+				m.todoItem.EXPECT().GetByID(gomock.Any(), 1, 2).Return(saved, nil)
+			},
+			expectedStatusCode: 201,
+			expectedETag:       caldav.ETag(saved, "client-uid"),
+		},
+		{
+			name:      "OK_Update",
+			inputPath: "/caldav/calendars/1/client.ics",
+			inputBody: body,
+			headers:   map[string]string{"If-Match": caldav.ETag(existing, "client-uid")},
+			mockBehavior: func(m caldavMocks) {
+				m.caldav.EXPECT().GetObjectByName(gomock.Any(), 1, 1, "client.ics").
+					Return(model.CalendarObject{ItemID: 2, Name: "client.ics", UID: "client-uid"}, nil)
+				m.todoItem.EXPECT().GetByID(gomock.Any(), 1, 2).Return(existing, nil)
+				m.caldav.EXPECT().PutItem(gomock.Any(), 1, 1, model.CalendarObject{ItemID: 2, Name: "client.ics", UID: "client-uid"},
+					model.TodoItem{Title: "test", CompletionDate: "2021-11-21 10:00:00"}).
+					Return(model.CalendarObject{ItemID: 2, Name: "client.ics", UID: "client-uid"}, nil)
+				m.todoItem.EXPECT().GetByID(gomock.Any(), 1, 2).Return(saved, nil)
+			},
+			expectedStatusCode: 204,
+			expectedETag:       caldav.ETag(saved, "client-uid"),
+		},
+		{
+			name:      "Outdated ETag",
+			inputPath: "/caldav/calendars/1/client.ics",
+			inputBody: body,
+			headers:   map[string]string{"If-Match": `"outdated"`},
+			mockBehavior: func(m caldavMocks) {
+				m.caldav.EXPECT().GetObjectByName(gomock.Any(), 1, 1, "client.ics").
+					Return(model.CalendarObject{ItemID: 2, Name: "client.ics", UID: "client-uid"}, nil)
+				m.todoItem.EXPECT().GetByID(gomock.Any(), 1, 2).Return(existing, nil)
+			},
+			expectedStatusCode: 412,
+		},
+		{
+			name:      "Service failure",
+			inputPath: "/caldav/calendars/1/client.ics",
+			inputBody: body,
+			mockBehavior: func(m caldavMocks) {
+				m.caldav.EXPECT().GetObjectByName(gomock.Any(), 1, 1, "client.ics").Return(model.CalendarObject{}, service.ErrCalendarObjectNotFound)
+				m.todoList.EXPECT().GetByID(gomock.Any(), 1, 1).Return(model.TodoList{ID: 1}, nil)
+				m.caldav.EXPECT().PutItem(gomock.Any(), 1, 1, model.CalendarObject{Name: "client.ics", UID: "client-uid"},
+					model.TodoItem{Title: "test", CompletionDate: "2021-11-21 10:00:00"}).
+					Return(model.CalendarObject{}, service.ErrFailedToSaveCalendarObject)
+			},
+			expectedStatusCode: 500,
+		},
+		{
+			name:      "Unknown list",
+			inputPath: "/caldav/calendars/5/client.ics",
+			inputBody: body,
+			mockBehavior: func(m caldavMocks) {
+				m.caldav.EXPECT().GetObjectByName(gomock.Any(), 1, 5, "client.ics").Return(model.CalendarObject{}, service.ErrCalendarObjectNotFound)
+				m.todoList.EXPECT().GetByID(gomock.Any(), 1, 5).Return(model.TodoList{}, service.ErrFailedToGetListByID)
+			},
+			expectedStatusCode: 409,
+		},
+		{
+			name:               "Invalid calendar data",
+			inputPath:          "/caldav/calendars/1/client.ics",
+			inputBody:          "invalid",
+			mockBehavior:       func(m caldavMocks) {},
+			expectedStatusCode: 400,
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, c := newCalDAVTestRouter(t, tc.mockBehavior)
+			defer c.Finish()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", tc.inputPath, bytes.NewBufferString(tc.inputBody))
+			for name, value := range tc.headers {
+				req.Header.Set(name, value)
+			}
+
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedETag, w.Header().Get("ETag"))
+		})
+	}
+}
+
+// failingCalDAV fails to save the objects, like a database going away in the middle of a request.
+type failingCalDAV struct {
+	repository.CalDAV
+}
+
+func (r failingCalDAV) SaveObject(ctx context.Context, object model.CalendarObject) error {
+	return errors.New("connection reset")
+}
+
+// TestHandler_caldavPutRollback checks a failed save of the object leaves no item behind,
+// so the retry of the PUT doesn't make a duplicate.
+func TestHandler_caldavPutRollback(t *testing.T) {
+	const body = "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:client-uid\r\nSUMMARY:test\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+
+	repos := repository.NewMemory(memory.NewStore())
+	repos.CalDAV = failingCalDAV{repos.CalDAV}
+
+	cfg := config.Service{SigningKey: "key", Salt: "salt", TokenTTL: 60}
+	services := service.New(repos, cfg, nil, service.LogNotifier{}, &mailtest.Mailer{}, nil)
+	r := New(services, nil).InitRoutes()
+
+	ctx := context.Background()
+	userID, err := services.Authorization.CreateUser(ctx, model.User{Name: "test", Email: "test@mail.ru", Password: "testing"})
+	require.NoError(t, err)
+	listID, err := services.TodoList.Create(ctx, userID, model.TodoList{Title: "test"})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("PUT", fmt.Sprintf("/caldav/calendars/%d/client.ics", listID), bytes.NewBufferString(body))
+		req.SetBasicAuth("test@mail.ru", "testing")
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, 500, w.Code)
+	}
+
+	items, err := services.TodoItem.GetAll(ctx, userID, listID)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+}
+
+func TestHandler_caldavDelete(t *testing.T) {
+	// Arrange
+	item := model.TodoItem{ID: 2, ListID: 1, Title: "test"}
+
+	testCases := []struct {
+		name               string
+		inputPath          string
+		headers            map[string]string
+		mockBehavior       func(m caldavMocks)
+		expectedStatusCode int
+	}{
+		{
+			name:      "OK_Object",
+			inputPath: "/caldav/calendars/1/2.ics",
+			headers:   map[string]string{"If-Match": caldav.ETag(item, caldav.DefaultUID(2))},
+			mockBehavior: func(m caldavMocks) {
+				m.caldav.EXPECT().GetObjectByName(gomock.Any(), 1, 1, "2.ics").Return(model.CalendarObject{}, service.ErrCalendarObjectNotFound)
+				m.todoItem.EXPECT().GetByID(gomock.Any(), 1, 2).Return(item, nil)
+				m.todoItem.EXPECT().Delete(gomock.Any(), 1, 2).Return(nil)
+			},
+			expectedStatusCode: 204,
+		},
+		{
+			name:      "OK_Calendar",
+			inputPath: "/caldav/calendars/1/",
+			mockBehavior: func(m caldavMocks) {
+				m.todoList.EXPECT().Delete(gomock.Any(), 1, 1).Return(nil)
+			},
+			expectedStatusCode: 204,
+		},
+		{
+			name:      "Not found",
+			inputPath: "/caldav/calendars/1/unknown.ics",
+			mockBehavior: func(m caldavMocks) {
+				m.caldav.EXPECT().GetObjectByName(gomock.Any(), 1, 1, "unknown.ics").Return(model.CalendarObject{}, service.ErrCalendarObjectNotFound)
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name:               "Principal",
+			inputPath:          "/caldav/principal/",
+			mockBehavior:       func(m caldavMocks) {},
+			expectedStatusCode: 405,
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, c := newCalDAVTestRouter(t, tc.mockBehavior)
+			defer c.Finish()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", tc.inputPath, nil)
+			for name, value := range tc.headers {
+				req.Header.Set(name, value)
+			}
+
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+		})
+	}
+}
+
+func TestHandler_caldavPropfind(t *testing.T) {
+	// Arrange
+	const propfind = `<d:propfind xmlns:d="DAV:" xmlns:cs="http://calendarserver.org/ns/"><d:prop><d:displayname/><d:getetag/></d:prop></d:propfind>`
+
+	item := model.TodoItem{ID: 2, ListID: 1, Title: "test"}
+
+	testCases := []struct {
+		name                 string
+		inputPath            string
+		depth                string
+		mockBehavior         func(m caldavMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK_Calendar",
+			inputPath: "/caldav/calendars/1/",
+			depth:     "1",
+			mockBehavior: func(m caldavMocks) {
+				m.todoList.EXPECT().GetByID(gomock.Any(), 1, 1).Return(model.TodoList{ID: 1, Title: "work"}, nil)
+				m.todoItem.EXPECT().GetAll(gomock.Any(), 1, 1).Return([]model.TodoItem{item}, nil)
+				m.caldav.EXPECT().GetObjects(gomock.Any(), 1, 1).Return(nil, nil)
+			},
+			expectedStatusCode: 207,
+			expectedResponseBody: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<D:multistatus xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav" xmlns:CS="http://calendarserver.org/ns/">` +
+				`<D:response><D:href>/caldav/calendars/1/</D:href>` +
+				`<D:propstat><D:prop><D:displayname>work</D:displayname></D:prop><D:status>HTTP/1.1 200 OK</D:status></D:propstat>` +
+				`<D:propstat><D:prop><D:getetag></D:getetag></D:prop><D:status>HTTP/1.1 404 Not Found</D:status></D:propstat></D:response>` +
+				`<D:response><D:href>/caldav/calendars/1/2.ics</D:href>` +
+				`<D:propstat><D:prop><D:getetag>` + escape(caldav.ETag(item, caldav.DefaultUID(2))) + `</D:getetag></D:prop><D:status>HTTP/1.1 200 OK</D:status></D:propstat>` +
+				`<D:propstat><D:prop><D:displayname></D:displayname></D:prop><D:status>HTTP/1.1 404 Not Found</D:status></D:propstat></D:response>` +
+				`</D:multistatus>`,
+		},
+		{
+			name:      "Unknown calendar",
+			inputPath: "/caldav/calendars/5/",
+			depth:     "0",
+			mockBehavior: func(m caldavMocks) {
+				m.todoList.EXPECT().GetByID(gomock.Any(), 1, 5).Return(model.TodoList{}, service.ErrFailedToGetListByID)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errCalDAVResourceNotFound.Error()),
+		},
+		{
+			name:                 "Unknown path",
+			inputPath:            "/caldav/unknown/",
+			depth:                "0",
+			mockBehavior:         func(m caldavMocks) {},
+			expectedStatusCode:   404,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errCalDAVResourceNotFound.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, c := newCalDAVTestRouter(t, tc.mockBehavior)
+			defer c.Finish()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PROPFIND", tc.inputPath, bytes.NewBufferString(propfind))
+			req.Header.Set("Depth", tc.depth)
+
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_caldavReport(t *testing.T) {
+	// Arrange
+	const multiget = `<c:calendar-multiget xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav"><d:prop><d:getetag/></d:prop>` +
+		`<d:href>/caldav/calendars/1/2.ics</d:href><d:href>/caldav/calendars/1/3.ics</d:href></c:calendar-multiget>`
+
+	item := model.TodoItem{ID: 2, ListID: 1, Title: "test"}
+
+	testCases := []struct {
+		name                 string
+		inputPath            string
+		inputBody            string
+		mockBehavior         func(m caldavMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK_Multiget",
+			inputPath: "/caldav/calendars/1/",
+			inputBody: multiget,
+			mockBehavior: func(m caldavMocks) {
+				m.todoList.EXPECT().GetByID(gomock.Any(), 1, 1).Return(model.TodoList{ID: 1}, nil)
+				m.caldav.EXPECT().GetObjectByName(gomock.Any(), 1, 1, "2.ics").Return(model.CalendarObject{}, service.ErrCalendarObjectNotFound)
+				m.todoItem.EXPECT().GetByID(gomock.Any(), 1, 2).Return(item, nil)
+				m.caldav.EXPECT().GetObjectByName(gomock.Any(), 1, 1, "3.ics").Return(model.CalendarObject{}, service.ErrCalendarObjectNotFound)
+				m.todoItem.EXPECT().GetByID(gomock.Any(), 1, 3).Return(model.TodoItem{}, service.ErrFailedToGetItemByID)
+			},
+			expectedStatusCode: 207,
+			expectedResponseBody: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<D:multistatus xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav" xmlns:CS="http://calendarserver.org/ns/">` +
+				`<D:response><D:href>/caldav/calendars/1/2.ics</D:href>` +
+				`<D:propstat><D:prop><D:getetag>` + escape(caldav.ETag(item, caldav.DefaultUID(2))) + `</D:getetag></D:prop><D:status>HTTP/1.1 200 OK</D:status></D:propstat></D:response>` +
+				`<D:response><D:href>/caldav/calendars/1/3.ics</D:href><D:status>HTTP/1.1 404 Not Found</D:status></D:response>` +
+				`</D:multistatus>`,
+		},
+		{
+			name:      "Unsupported report",
+			inputPath: "/caldav/calendars/1/",
+			inputBody: `<d:sync-collection xmlns:d="DAV:"/>`,
+			mockBehavior: func(m caldavMocks) {
+				m.todoList.EXPECT().GetByID(gomock.Any(), 1, 1).Return(model.TodoList{ID: 1}, nil)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errCalDAVUnsupportedReport.Error()),
+		},
+		{
+			name:                 "Not a calendar",
+			inputPath:            "/caldav/calendars/",
+			inputBody:            multiget,
+			mockBehavior:         func(m caldavMocks) {},
+			expectedStatusCode:   405,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errCalDAVMethodNotAllowed.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, c := newCalDAVTestRouter(t, tc.mockBehavior)
+			defer c.Finish()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("REPORT", tc.inputPath, bytes.NewBufferString(tc.inputBody))
+
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func escape(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+
+	return buf.String()
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	_ "github.com/Lapp-coder/todo-app/docs/swagger"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	calendarFeedPath      = "/calendar/"
+	calendarFeedExtension = ".ics"
+	calendarContentType   = "text/calendar; charset=utf-8"
+)
+
+// createCalendarToken godoc
+// @Summary Create calendar feed token
+// @Security ApiKeyAuth
+// @Tags calendar
+// @Description create a secret token for the iCalendar feed of the user, it's shown only once and a lost one is rotated
+// @ID create-calendar-token
+// @Produce json
+// @Success 201 {object} swagger.CalendarTokenResponse
+// @Failure 400,404,409 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /api/calendar/token [post]
+func (h Handler) createCalendarToken(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	token, err := h.service.Calendar.CreateFeedToken(ctx.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrFeedAlreadyExists) {
+			respondError(ctx, http.StatusConflict, err)
+			return
+		}
+
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(ctx, http.StatusCreated, gin.H{
+		"token": token,
+		"url":   calendarFeedPath + token + calendarFeedExtension,
+	})
+}
+
+// rotateCalendarToken godoc
+// @Summary Rotate calendar feed token
+// @Security ApiKeyAuth
+// @Tags calendar
+// @Description replace the calendar feed token, the previous feed url stops working
+// @ID rotate-calendar-token
+// @Produce json
+// @Success 200 {object} swagger.CalendarTokenResponse
+// @Failure 400,404 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /api/calendar/token [put]
+func (h Handler) rotateCalendarToken(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	token, err := h.service.Calendar.RotateFeedToken(ctx.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrFeedNotFound) {
+			respondError(ctx, http.StatusNotFound, err)
+			return
+		}
+
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"token": token,
+		"url":   calendarFeedPath + token + calendarFeedExtension,
+	})
+}
+
+// getCalendarFeed godoc
+// @Summary Get calendar feed
+// @Tags calendar
+// @Description get items of the user as an iCalendar feed, type is one of "event" or "todo", both are included by default
+// @ID get-calendar-feed
+// @Produce text/calendar
+// @Param token path string true "Feed token with the .ics extension"
+// @Param type query string false "Component type"
+// @Success 200 {string} string "iCalendar feed"
+// @Failure 400,404 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /calendar/{token} [get]
+func (h Handler) getCalendarFeed(ctx *gin.Context) {
+	param := ctx.Param("token")
+	if !strings.HasSuffix(param, calendarFeedExtension) {
+		respondError(ctx, http.StatusNotFound, service.ErrFeedNotFound)
+		return
+	}
+
+	token := strings.TrimSuffix(param, calendarFeedExtension)
+	if token == "" {
+		respondError(ctx, http.StatusNotFound, service.ErrFeedNotFound)
+		return
+	}
+
+	var events, todos bool
+	switch ctx.Query("type") {
+	case "":
+		events, todos = true, true
+	case "event":
+		events = true
+	case "todo":
+		todos = true
+	default:
+		respondError(ctx, http.StatusBadRequest, errInvalidFeedType)
+		return
+	}
+
+	feed, err := h.service.Calendar.GetFeed(ctx.Request.Context(), token, events, todos)
+	if err != nil {
+		if errors.Is(err, service.ErrFeedNotFound) {
+			respondError(ctx, http.StatusNotFound, err)
+			return
+		}
+
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Data(http.StatusOK, calendarContentType, feed)
+}
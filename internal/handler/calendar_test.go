@@ -0,0 +1,283 @@
+package handler
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Lapp-coder/todo-app/internal/service"
+	mockService "github.com/Lapp-coder/todo-app/internal/service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_createCalendarToken(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockCalendar, userID interface{})
+
+	testCases := []struct {
+		name                 string
+		inputUserID          interface{}
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			inputUserID: 1,
+			mockBehavior: func(s *mockService.MockCalendar, userID interface{}) {
+				s.EXPECT().CreateFeedToken(gomock.Any(), userID).Return("token", nil)
+			},
+			expectedStatusCode:   201,
+			expectedResponseBody: `{"token":"token","url":"/calendar/token.ics"}`,
+		},
+		{
+			name:                 "Invalid user id",
+			inputUserID:          "invalid",
+			mockBehavior:         func(s *mockService.MockCalendar, userID interface{}) {},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errFailedToGetUserID.Error()),
+		},
+		{
+			name:        "Feed already exists",
+			inputUserID: 1,
+			mockBehavior: func(s *mockService.MockCalendar, userID interface{}) {
+				s.EXPECT().CreateFeedToken(gomock.Any(), userID).Return("", service.ErrFeedAlreadyExists)
+			},
+			expectedStatusCode:   409,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFeedAlreadyExists.Error()),
+		},
+		{
+			name:        "Service failure",
+			inputUserID: 1,
+			mockBehavior: func(s *mockService.MockCalendar, userID interface{}) {
+				s.EXPECT().CreateFeedToken(gomock.Any(), userID).Return("", service.ErrFailedToCreateFeedToken)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToCreateFeedToken.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			calendar := mockService.NewMockCalendar(c)
+			tc.mockBehavior(calendar, tc.inputUserID)
+
+			services := &service.Service{Calendar: calendar}
+			handler := New(services, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			r.POST(
+				"/api/calendar/token",
+				func(c *gin.Context) {
+					c.Set(userCtx, tc.inputUserID)
+				},
+				handler.createCalendarToken)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/calendar/token", nil)
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_rotateCalendarToken(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockCalendar, userID interface{})
+
+	testCases := []struct {
+		name                 string
+		inputUserID          interface{}
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			inputUserID: 1,
+			mockBehavior: func(s *mockService.MockCalendar, userID interface{}) {
+				s.EXPECT().RotateFeedToken(gomock.Any(), userID).Return("token", nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"token":"token","url":"/calendar/token.ics"}`,
+		},
+		{
+			name:        "Feed not found",
+			inputUserID: 1,
+			mockBehavior: func(s *mockService.MockCalendar, userID interface{}) {
+				s.EXPECT().RotateFeedToken(gomock.Any(), userID).Return("", service.ErrFeedNotFound)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFeedNotFound.Error()),
+		},
+		{
+			name:        "Service failure",
+			inputUserID: 1,
+			mockBehavior: func(s *mockService.MockCalendar, userID interface{}) {
+				s.EXPECT().RotateFeedToken(gomock.Any(), userID).Return("", service.ErrFailedToRotateFeedToken)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToRotateFeedToken.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			calendar := mockService.NewMockCalendar(c)
+			tc.mockBehavior(calendar, tc.inputUserID)
+
+			services := &service.Service{Calendar: calendar}
+			handler := New(services, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			r.PUT(
+				"/api/calendar/token",
+				func(c *gin.Context) {
+					c.Set(userCtx, tc.inputUserID)
+				},
+				handler.rotateCalendarToken)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", "/api/calendar/token", nil)
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getCalendarFeed(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockCalendar, token string, events, todos bool)
+
+	testCases := []struct {
+		name                 string
+		inputPath            string
+		token                string
+		events               bool
+		todos                bool
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputPath: "/calendar/token.ics",
+			token:     "token",
+			events:    true,
+			todos:     true,
+			mockBehavior: func(s *mockService.MockCalendar, token string, events, todos bool) {
+				s.EXPECT().GetFeed(gomock.Any(), token, events, todos).Return([]byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"), nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n",
+		},
+		{
+			name:      "OK_OnlyTodos",
+			inputPath: "/calendar/token.ics?type=todo",
+			token:     "token",
+			todos:     true,
+			mockBehavior: func(s *mockService.MockCalendar, token string, events, todos bool) {
+				s.EXPECT().GetFeed(gomock.Any(), token, events, todos).Return([]byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"), nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n",
+		},
+		{
+			name:                 "Without extension",
+			inputPath:            "/calendar/token",
+			mockBehavior:         func(s *mockService.MockCalendar, token string, events, todos bool) {},
+			expectedStatusCode:   404,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFeedNotFound.Error()),
+		},
+		{
+			name:                 "Invalid type",
+			inputPath:            "/calendar/token.ics?type=invalid",
+			mockBehavior:         func(s *mockService.MockCalendar, token string, events, todos bool) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidFeedType.Error()),
+		},
+		{
+			name:      "Unknown token",
+			inputPath: "/calendar/unknown.ics",
+			token:     "unknown",
+			events:    true,
+			todos:     true,
+			mockBehavior: func(s *mockService.MockCalendar, token string, events, todos bool) {
+				s.EXPECT().GetFeed(gomock.Any(), token, events, todos).Return(nil, service.ErrFeedNotFound)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFeedNotFound.Error()),
+		},
+		{
+			name:      "Service failure",
+			inputPath: "/calendar/token.ics",
+			token:     "token",
+			events:    true,
+			todos:     true,
+			mockBehavior: func(s *mockService.MockCalendar, token string, events, todos bool) {
+				s.EXPECT().GetFeed(gomock.Any(), token, events, todos).Return(nil, service.ErrFailedToGetFeed)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToGetFeed.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			calendar := mockService.NewMockCalendar(c)
+			tc.mockBehavior(calendar, tc.token, tc.events, tc.todos)
+
+			services := &service.Service{Calendar: calendar}
+			handler := New(services, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			r.GET("/calendar/:token", handler.getCalendarFeed)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", tc.inputPath, nil)
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -6,8 +6,23 @@ var (
 	errInvalidInputBody   = errors.New("invalid input body")
 	errFailedToGetUserID  = errors.New("failed to get user id")
 	errInvalidParamID     = errors.New("invalid id param")
+	errInvalidWorkspaceID = errors.New("invalid workspace id")
 	errEmptyAuthHeader    = errors.New("empty auth header")
 	errInvalidAuthHeader  = errors.New("invalid auth header")
 	errEmptyToken         = errors.New("token is empty")
+	errOIDCDenied         = errors.New("sign-in was denied by the identity provider")
+	errInsufficientScope  = errors.New("access token is missing the scope")
+	errSessionRequired    = errors.New("not allowed with an access token")
+	errInsufficientRole   = errors.New("not allowed for the role of the user")
 	errFailedToParseToken = errors.New("failed to parse token")
+	errInvalidFeedType    = errors.New("invalid feed type")
+	errInvalidListIDQuery = errors.New("invalid list_id query")
+	errInvalidInclude     = errors.New("invalid include query")
+	errInvalidInputQuery  = errors.New("invalid input query")
+	errTooManyRequests    = errors.New("too many requests")
+
+	errCalDAVResourceNotFound   = errors.New("resource not found")
+	errCalDAVMethodNotAllowed   = errors.New("method not allowed")
+	errCalDAVPreconditionFailed = errors.New("precondition failed")
+	errCalDAVUnsupportedReport  = errors.New("unsupported report")
 )
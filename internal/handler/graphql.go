@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+
+	_ "github.com/Lapp-coder/todo-app/docs/swagger"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+// graphQL godoc
+// @Summary GraphQL
+// @Security ApiKeyAuth
+// @Tags graphql
+// @Description execute a GraphQL query or mutation on lists and their items
+// @ID graphql
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header int false "Workspace id, the personal workspace by default"
+// @Param input body model.GraphQLRequest true "GraphQL query"
+// @Success 200 {object} swagger.GraphQLResponse
+// @Failure 400,404 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /graphql [post]
+func (h Handler) graphQL(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	var req model.GraphQLRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidInputBody)
+		return
+	}
+
+	response := h.graphQLSchema.Exec(ctx.Request.Context(), userID, req.Query, req.OperationName, req.Variables)
+
+	respond(ctx, http.StatusOK, response)
+}
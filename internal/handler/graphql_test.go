@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	mockService "github.com/Lapp-coder/todo-app/internal/service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_graphQL(t *testing.T) {
+	// Arrange
+	type mockBehavior func(list *mockService.MockTodoList, item *mockService.MockTodoItem, userID int)
+
+	testCases := []struct {
+		name                 string
+		inputBody            string
+		inputUserID          int
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK_ListsWithItems",
+			inputBody:   `{"query":"{ lists { id title items { id title done } } }"}`,
+			inputUserID: 1,
+			mockBehavior: func(list *mockService.MockTodoList, item *mockService.MockTodoItem, userID int) {
+				list.EXPECT().GetAll(gomock.Any(), userID).Return([]model.TodoList{
+					{ID: 1, UserID: userID, Title: "first"},
+					{ID: 2, UserID: userID, Title: "second"},
+					{ID: 3, UserID: userID, Title: "third"},
+				}, nil)
+				item.EXPECT().GetAllByLists(gomock.Any(), userID, []int{1, 2, 3}).Return([]model.TodoItem{
+					{ID: 1, ListID: 1, Title: "milk", Done: true},
+					{ID: 2, ListID: 2, Title: "bread"},
+					{ID: 3, ListID: 1, Title: "eggs"},
+				}, nil).Times(1)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"data":{"lists":[` +
+				`{"id":"1","title":"first","items":[{"id":"1","title":"milk","done":true},{"id":"3","title":"eggs","done":false}]},` +
+				`{"id":"2","title":"second","items":[{"id":"2","title":"bread","done":false}]},` +
+				`{"id":"3","title":"third","items":[]}]}}`,
+		},
+		{
+			name:        "OK_CreateList",
+			inputBody:   `{"query":"mutation($title: String!) { createList(input: {title: $title}) }","variables":{"title":"test"}}`,
+			inputUserID: 1,
+			mockBehavior: func(list *mockService.MockTodoList, item *mockService.MockTodoItem, userID int) {
+				list.EXPECT().Create(gomock.Any(), userID, model.TodoList{Title: "test"}).Return(1, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"data":{"createList":"1"}}`,
+		},
+		{
+			name:                 "Invalid input",
+			inputBody:            `{"query":"mutation { createList(input: {title: \"ab\"}) }"}`,
+			inputUserID:          1,
+			mockBehavior:         func(list *mockService.MockTodoList, item *mockService.MockTodoItem, userID int) {},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"errors":[{"message":"invalid input","path":["createList"]}],"data":null}`,
+		},
+		{
+			name:                 "Empty query",
+			inputBody:            `{}`,
+			inputUserID:          1,
+			mockBehavior:         func(list *mockService.MockTodoList, item *mockService.MockTodoItem, userID int) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidInputBody.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			todoList := mockService.NewMockTodoList(c)
+			todoItem := mockService.NewMockTodoItem(c)
+			tc.mockBehavior(todoList, todoItem, tc.inputUserID)
+
+			services := &service.Service{TodoList: todoList, TodoItem: todoItem}
+			handler := New(services, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			r.POST(
+				"/graphql",
+				func(c *gin.Context) {
+					c.Set(userCtx, tc.inputUserID)
+				},
+				handler.graphQL)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/graphql", bytes.NewBufferString(tc.inputBody))
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
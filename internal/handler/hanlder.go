@@ -2,56 +2,154 @@ package handler
 
 import (
 	_ "github.com/Lapp-coder/todo-app/docs"
+	"github.com/Lapp-coder/todo-app/internal/gql"
+	"github.com/Lapp-coder/todo-app/internal/metrics"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/ratelimit"
 	"github.com/Lapp-coder/todo-app/internal/service"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
 type Handler struct {
-	service *service.Service
+	service       *service.Service
+	graphQLSchema *gql.Schema
+	// limiter is nil when the rate limiting is disabled.
+	limiter *ratelimit.Limiter
 }
 
-func New(service *service.Service) *Handler {
-	return &Handler{service: service}
+func New(service *service.Service, limiter *ratelimit.Limiter) *Handler {
+	return &Handler{service: service, graphQLSchema: gql.NewSchema(service), limiter: limiter}
 }
 
 func (h Handler) InitRoutes() *gin.Engine {
 	router := gin.New()
-	router.Use(gin.Logger())
+	router.Use(h.recordMetrics, h.traceRequest, h.requestID, h.logRequest)
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	auth := router.Group("/auth")
+	router.GET("/healthz", h.healthz)
+	router.GET("/readyz", h.readyz)
+	router.GET("/version", h.getVersion)
+	router.GET("/metrics", gin.WrapH(promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})))
+
+	auth := router.Group("/auth", h.limitByIP(ratelimit.PolicyAuthIP))
 	{
 		auth.POST("/sign-up", h.signUp)
 		auth.POST("/sign-in", h.signIn)
+		auth.POST("/sign-in/2fa", h.completeSignIn)
+		auth.GET("/verify", h.verifyEmail)
+		auth.POST("/verify/resend", h.resendVerification)
+		auth.POST("/forgot-password", h.forgotPassword)
+		auth.POST("/reset-password", h.resetPassword)
+		auth.GET("/oidc/login", h.oidcLogin)
+		auth.GET("/oidc/callback", h.oidcCallback)
+	}
+
+	// The static admin token only lifts the lockouts, the rest of the admin API takes the session of an admin.
+	lockouts := router.Group("/admin/lockouts", h.adminAuthentication, h.requireRole(model.RoleAdmin))
+	{
+		lockouts.GET("", h.getLockouts)
+		lockouts.DELETE("/:id", h.unlockUser)
 	}
 
-	api := router.Group("/api", h.userAuthentication)
+	admin := router.Group("/admin", h.userAuthentication, h.requireRole(model.RoleAdmin))
 	{
-		lists := api.Group("/lists")
+		admin.GET("/users", h.getUsers)
+		admin.PUT("/users/:id/role", h.setUserRole)
+		admin.POST("/users/:id/disable", h.disableUser)
+		admin.POST("/users/:id/enable", h.enableUser)
+		admin.DELETE("/users/:id/sessions", h.revokeUserSessions)
+		admin.GET("/stats", h.getStats)
+	}
+
+	router.GET("/calendar/:token", h.getCalendarFeed)
+
+	router.GET("/.well-known/caldav", h.caldavWellKnown)
+	router.Handle("PROPFIND", "/.well-known/caldav", h.caldavWellKnown)
+
+	caldav := router.Group("/caldav", h.basicAuthentication)
+	{
+		caldav.OPTIONS("/*path", h.caldavOptions)
+		caldav.Handle("PROPFIND", "/*path", h.caldavPropfind)
+		caldav.Handle("REPORT", "/*path", h.caldavReport)
+		caldav.GET("/*path", h.caldavGet)
+		caldav.PUT("/*path", h.caldavPut)
+		caldav.DELETE("/*path", h.caldavDelete)
+	}
+
+	// The graphql queries aren't checked against the scopes, so they need a session.
+	router.POST("/graphql", h.userAuthentication, h.requireSession, h.limitByUser, h.selectWorkspace, h.graphQL)
+
+	api := router.Group("/api", h.userAuthentication, h.limitByUser)
+	{
+		h.initTodoRoutes(api)
+
+		workspaces := api.Group("/workspaces", h.requireSession)
 		{
-			lists.POST("/", h.createList)
-			lists.GET("/", h.getAllLists)
-			lists.GET("/:id", h.getListByID)
-			lists.PUT("/:id", h.updateList)
-			lists.DELETE("/:id", h.deleteList)
-
-			items := lists.Group("/:id/items")
-			{
-				items.POST("/", h.createItem)
-				items.GET("/", h.getAllItems)
-			}
+			workspaces.POST("/", h.createWorkspace)
+			workspaces.GET("/", h.getWorkspaces)
+			workspaces.GET("/:workspace_id", h.getWorkspaceByID)
+			workspaces.PUT("/:workspace_id", h.updateWorkspace)
+			workspaces.DELETE("/:workspace_id", h.deleteWorkspace)
+			workspaces.GET("/:workspace_id/members", h.getWorkspaceMembers)
+			workspaces.POST("/:workspace_id/members", h.addWorkspaceMember)
+			workspaces.PUT("/:workspace_id/members/:user_id", h.setWorkspaceMemberRole)
+			workspaces.DELETE("/:workspace_id/members/:user_id", h.removeWorkspaceMember)
 		}
 
-		items := api.Group("/items")
+		// The access tokens reach the lists and the items of the workspaces by the path prefix too.
+		h.initTodoRoutes(api.Group("/workspaces/:workspace_id"))
+
+		me := api.Group("/me", h.requireSession)
 		{
-			items.GET("/:id", h.getItemByID)
-			items.PUT("/:id", h.updateItem)
-			items.DELETE("/:id", h.deleteItem)
+			me.GET("", h.getProfile)
+			me.PATCH("", h.updateProfile)
+			me.DELETE("", h.deleteAccount)
+			me.PUT("/password", h.changePassword)
+			me.POST("/2fa/setup", h.setupTwoFactor)
+			me.POST("/2fa/confirm", h.confirmTwoFactor)
+
+			me.POST("/tokens", h.createAccessToken)
+			me.GET("/tokens", h.getAccessTokens)
+			me.GET("/tokens/:id", h.getAccessTokenByID)
+			me.DELETE("/tokens/:id", h.deleteAccessToken)
+		}
+
+		calendar := api.Group("/calendar", h.requireSession)
+		{
+			calendar.POST("/token", h.createCalendarToken)
+			calendar.PUT("/token", h.rotateCalendarToken)
 		}
 	}
 
 	return router
 }
+
+// initTodoRoutes registers the lists and the items scoped to the selected workspace.
+func (h Handler) initTodoRoutes(api *gin.RouterGroup) {
+	lists := api.Group("/lists", h.selectWorkspace)
+	{
+		lists.POST("/", h.requireScope(model.ScopeListsWrite), h.createList)
+		lists.GET("/", h.requireScope(model.ScopeListsRead), h.getAllLists)
+		lists.GET("/:id", h.requireScope(model.ScopeListsRead), h.getListByID)
+		lists.PUT("/:id", h.requireScope(model.ScopeListsWrite), h.updateList)
+		lists.DELETE("/:id", h.requireScope(model.ScopeListsWrite), h.deleteList)
+
+		items := lists.Group("/:id/items")
+		{
+			items.POST("/", h.requireScope(model.ScopeItemsWrite), h.createItem)
+			items.GET("/", h.requireScope(model.ScopeItemsRead), h.getAllItems)
+		}
+	}
+
+	items := api.Group("/items", h.selectWorkspace)
+	{
+		items.GET("/", h.requireScope(model.ScopeItemsRead), h.getItems)
+		items.GET("/:id", h.requireScope(model.ScopeItemsRead), h.getItemByID)
+		items.PUT("/:id", h.requireScope(model.ScopeItemsWrite), h.updateItem)
+		items.DELETE("/:id", h.requireScope(model.ScopeItemsWrite), h.deleteItem)
+	}
+}
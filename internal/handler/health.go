@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	_ "github.com/Lapp-coder/todo-app/docs/swagger"
+	"github.com/Lapp-coder/todo-app/internal/version"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	statusOK          = "ok"
+	statusUnavailable = "unavailable"
+
+	// readinessTimeout is shorter than the usual probe timeout of the orchestrator,
+	// so a hanging check is reported as failed instead of timing out the probe.
+	readinessTimeout = 3 * time.Second
+)
+
+// healthz godoc
+// @Summary Liveness probe
+// @Tags health
+// @Description report that the process is alive, it doesn't check the dependencies
+// @ID healthz
+// @Produce json
+// @Success 200 {object} swagger.HealthResponse
+// @Router /healthz [get]
+func (h Handler) healthz(ctx *gin.Context) {
+	respond(ctx, http.StatusOK, gin.H{
+		"status": statusOK,
+	})
+}
+
+// readyz godoc
+// @Summary Readiness probe
+// @Tags health
+// @Description report whether the app can serve requests: the database answers, the schema is up to date and the app isn't shutting down
+// @ID readyz
+// @Produce json
+// @Success 200 {object} swagger.ReadinessResponse
+// @Failure 503 {object} swagger.ReadinessResponse
+// @Router /readyz [get]
+func (h Handler) readyz(ctx *gin.Context) {
+	checkCtx, cancel := context.WithTimeout(ctx.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	checks, err := h.service.Health.Ready(checkCtx)
+	if err != nil {
+		respond(ctx, http.StatusServiceUnavailable, gin.H{
+			"status": statusUnavailable,
+			"error":  err.Error(),
+			"checks": checks,
+		})
+		return
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"status": statusOK,
+		"checks": checks,
+	})
+}
+
+// getVersion godoc
+// @Summary Build information
+// @Tags health
+// @Description get the commit, the build time and the go version of the running binary
+// @ID version
+// @Produce json
+// @Success 200 {object} swagger.VersionResponse
+// @Router /version [get]
+func (h Handler) getVersion(ctx *gin.Context) {
+	respond(ctx, http.StatusOK, gin.H{
+		"commit":     version.Commit,
+		"build_time": version.BuildTime,
+		"go_version": version.GoVersion(),
+	})
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/Lapp-coder/todo-app/internal/service"
+	mockService "github.com/Lapp-coder/todo-app/internal/service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_healthz(t *testing.T) {
+	// Arrange
+	handler := New(&service.Service{}, nil)
+
+	gin.SetMode("test")
+	r := gin.New()
+	r.GET("/healthz", handler.healthz)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/healthz", nil)
+
+	// Act
+	r.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"status":"ok"}`, w.Body.String())
+}
+
+func TestHandler_readyz(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockHealth)
+
+	testCases := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mockService.MockHealth) {
+				s.EXPECT().Ready(gomock.Any()).Return(map[string]string{"database": "ok"}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"checks":{"database":"ok"},"status":"ok"}`,
+		},
+		{
+			name: "Failed check",
+			mockBehavior: func(s *mockService.MockHealth) {
+				s.EXPECT().Ready(gomock.Any()).Return(map[string]string{"database": "connection refused"}, service.ErrNotReady)
+			},
+			expectedStatusCode: 503,
+			expectedResponseBody: fmt.Sprintf(`{"checks":{"database":"connection refused"},"error":"%s","status":"unavailable"}`,
+				service.ErrNotReady.Error()),
+		},
+		{
+			name: "Shutting down",
+			mockBehavior: func(s *mockService.MockHealth) {
+				s.EXPECT().Ready(gomock.Any()).Return(nil, service.ErrShuttingDown)
+			},
+			expectedStatusCode: 503,
+			expectedResponseBody: fmt.Sprintf(`{"checks":null,"error":"%s","status":"unavailable"}`,
+				service.ErrShuttingDown.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			health := mockService.NewMockHealth(c)
+			tc.mockBehavior(health)
+
+			services := &service.Service{Health: health}
+			handler := New(services, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			r.GET("/readyz", handler.readyz)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/readyz", nil)
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getVersion(t *testing.T) {
+	// Arrange
+	handler := New(&service.Service{}, nil)
+
+	gin.SetMode("test")
+	r := gin.New()
+	r.GET("/version", handler.getVersion)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/version", nil)
+
+	// Act
+	r.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, fmt.Sprintf(`{"build_time":"unknown","commit":"unknown","go_version":"%s"}`, runtime.Version()), w.Body.String())
+}
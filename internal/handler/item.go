@@ -1,14 +1,23 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	_ "github.com/Lapp-coder/todo-app/docs/swagger"
 	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	listIDQuery     = "list_id"
+	listIDSeparator = ","
+	allLists        = "all"
+)
+
 // createItem godoc
 // @Summary Create item
 // @Security ApiKeyAuth
@@ -19,8 +28,9 @@ import (
 // @Produce json
 // @Param id path int true "List id"
 // @Param input body model.CreateTodoItem true "Item info"
+// @Param X-Workspace-ID header int false "Workspace id, the personal workspace by default"
 // @Success 201 {integer} integer "Item id"
-// @Failure 400,404 {object} swagger.ErrorResponse
+// @Failure 400,403,404 {object} swagger.ErrorResponse
 // @Failure 500 {object} swagger.ErrorResponse
 // @Failure default {object} swagger.ErrorResponse
 // @Router /api/lists/{id}/items/ [post]
@@ -44,8 +54,13 @@ func (h Handler) createItem(ctx *gin.Context) {
 	}
 
 	item := model.TodoItem{Title: req.Title, Description: req.Description, CompletionDate: req.CompletionDate, Done: req.Done}
-	itemID, err := h.service.TodoItem.Create(userID, listID, item)
+	itemID, err := h.service.TodoItem.Create(ctx.Request.Context(), userID, listID, item)
 	if err != nil {
+		if errors.Is(err, service.ErrWorkspaceReadOnly) {
+			respondError(ctx, http.StatusForbidden, err)
+			return
+		}
+
 		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
@@ -63,6 +78,7 @@ func (h Handler) createItem(ctx *gin.Context) {
 // @ID get-all-items
 // @Produce json
 // @Param id path int true "List id"
+// @Param X-Workspace-ID header int false "Workspace id, the personal workspace by default"
 // @Success 200 {object} swagger.GetAllItemsResponse
 // @Failure 400,404 {object} swagger.ErrorResponse
 // @Failure 500 {object} swagger.ErrorResponse
@@ -81,7 +97,7 @@ func (h Handler) getAllItems(ctx *gin.Context) {
 		return
 	}
 
-	items, err := h.service.TodoItem.GetAll(userID, listID)
+	items, err := h.service.TodoItem.GetAll(ctx.Request.Context(), userID, listID)
 	if err != nil {
 		respondError(ctx, http.StatusInternalServerError, err)
 		return
@@ -92,6 +108,81 @@ func (h Handler) getAllItems(ctx *gin.Context) {
 	})
 }
 
+// getItems godoc
+// @Summary Get items of several lists
+// @Security ApiKeyAuth
+// @Tags items
+// @Description get the items of the given lists, or of all lists with list_id=all
+// @ID get-items
+// @Produce json
+// @Param list_id query string true "Comma-separated list ids or all"
+// @Param X-Workspace-ID header int false "Workspace id, the personal workspace by default"
+// @Success 200 {object} swagger.GetAllItemsResponse
+// @Failure 400,404 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /api/items/ [get]
+func (h Handler) getItems(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	query := ctx.Query(listIDQuery)
+	if query == "" {
+		respondError(ctx, http.StatusBadRequest, errInvalidListIDQuery)
+		return
+	}
+
+	var items []model.TodoItem
+	var err error
+	if query == allLists {
+		items, err = h.service.TodoItem.GetAllByUser(ctx.Request.Context(), userID)
+	} else {
+		listIDs, ok := parseListIDs(query)
+		if !ok {
+			respondError(ctx, http.StatusBadRequest, errInvalidListIDQuery)
+			return
+		}
+
+		items, err = h.service.TodoItem.GetAllByLists(ctx.Request.Context(), userID, listIDs)
+	}
+
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	if items == nil {
+		items = []model.TodoItem{}
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"items": items,
+	})
+}
+
+func parseListIDs(query string) ([]int, bool) {
+	parts := strings.Split(query, listIDSeparator)
+
+	listIDs := make([]int, 0, len(parts))
+	seen := make(map[int]bool, len(parts))
+	for _, part := range parts {
+		listID, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || listID <= 0 {
+			return nil, false
+		}
+
+		if !seen[listID] {
+			seen[listID] = true
+			listIDs = append(listIDs, listID)
+		}
+	}
+
+	return listIDs, true
+}
+
 // getItemByID godoc
 // @Summary Get item by id
 // @Security ApiKeyAuth
@@ -100,6 +191,7 @@ func (h Handler) getAllItems(ctx *gin.Context) {
 // @ID get-item-by-id
 // @Produce json
 // @Param id path int true "Item id"
+// @Param X-Workspace-ID header int false "Workspace id, the personal workspace by default"
 // @Success 200 {object} swagger.GetItemByIDResponse
 // @Failure 400,404 {object} swagger.ErrorResponse
 // @Failure 500 {object} swagger.ErrorResponse
@@ -118,7 +210,7 @@ func (h Handler) getItemByID(ctx *gin.Context) {
 		return
 	}
 
-	item, err := h.service.TodoItem.GetByID(userID, itemID)
+	item, err := h.service.TodoItem.GetByID(ctx.Request.Context(), userID, itemID)
 	if err != nil {
 		respondError(ctx, http.StatusInternalServerError, err)
 		return
@@ -139,8 +231,9 @@ func (h Handler) getItemByID(ctx *gin.Context) {
 // @Produce json
 // @Param id path int true "Item id"
 // @Param input body model.UpdateTodoItem true "Update values"
+// @Param X-Workspace-ID header int false "Workspace id, the personal workspace by default"
 // @Success 200 {string} string "Result"
-// @Failure 400,404 {object} swagger.ErrorResponse
+// @Failure 400,403,404 {object} swagger.ErrorResponse
 // @Failure 500 {object} swagger.ErrorResponse
 // @Failure default {object} swagger.ErrorResponse
 // @Router /api/items/{id} [put]
@@ -168,7 +261,12 @@ func (h Handler) updateItem(ctx *gin.Context) {
 		return
 	}
 
-	if err = h.service.TodoItem.Update(userID, itemID, req); err != nil {
+	if err = h.service.TodoItem.Update(ctx.Request.Context(), userID, itemID, req); err != nil {
+		if errors.Is(err, service.ErrWorkspaceReadOnly) {
+			respondError(ctx, http.StatusForbidden, err)
+			return
+		}
+
 		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
@@ -186,8 +284,9 @@ func (h Handler) updateItem(ctx *gin.Context) {
 // @ID delete-item
 // @Produce json
 // @Param id path int true "Item id"
+// @Param X-Workspace-ID header int false "Workspace id, the personal workspace by default"
 // @Success 200 {string} string "Result"
-// @Failure 400,404 {object} swagger.ErrorResponse
+// @Failure 400,403,404 {object} swagger.ErrorResponse
 // @Failure 500 {object} swagger.ErrorResponse
 // @Failure default {object} swagger.ErrorResponse
 // @Router /api/items/{id} [delete]
@@ -204,7 +303,12 @@ func (h Handler) deleteItem(ctx *gin.Context) {
 		return
 	}
 
-	if err = h.service.TodoItem.Delete(userID, itemID); err != nil {
+	if err = h.service.TodoItem.Delete(ctx.Request.Context(), userID, itemID); err != nil {
+		if errors.Is(err, service.ErrWorkspaceReadOnly) {
+			respondError(ctx, http.StatusForbidden, err)
+			return
+		}
+
 		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
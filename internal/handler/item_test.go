@@ -36,7 +36,7 @@ func TestHandler_createItem(t *testing.T) {
 			inputBody:   `{"title": "test", "description": "testing", "completion_date": "2021-11-21 00:00:00", "done": false}`,
 			item:        model.TodoItem{Title: "test", Description: "testing", CompletionDate: "2021-11-21 00:00:00", Done: false},
 			mockBehavior: func(s *mockService.MockTodoItem, userID, listID interface{}, item model.TodoItem) {
-				s.EXPECT().Create(userID, listID, item).Return(1, nil)
+				s.EXPECT().Create(gomock.Any(), userID, listID, item).Return(1, nil)
 			},
 			expectedStatusCode:   201,
 			expectedResponseBody: `{"item_id":1}`,
@@ -48,7 +48,7 @@ func TestHandler_createItem(t *testing.T) {
 			inputBody:   `{"title": "test", "description":"testing", "completion_date": "2021-11-21 00:00:00"}`,
 			item:        model.TodoItem{Title: "test", Description: "testing", CompletionDate: "2021-11-21 00:00:00"},
 			mockBehavior: func(s *mockService.MockTodoItem, userID, listID interface{}, item model.TodoItem) {
-				s.EXPECT().Create(userID, listID, item).Return(1, nil)
+				s.EXPECT().Create(gomock.Any(), userID, listID, item).Return(1, nil)
 			},
 			expectedStatusCode:   201,
 			expectedResponseBody: `{"item_id":1}`,
@@ -60,7 +60,7 @@ func TestHandler_createItem(t *testing.T) {
 			inputBody:   `{"title": "test", "completion_date": "2021-11-21 00:00:00"}`,
 			item:        model.TodoItem{Title: "test", CompletionDate: "2021-11-21 00:00:00"},
 			mockBehavior: func(s *mockService.MockTodoItem, userID, listID interface{}, item model.TodoItem) {
-				s.EXPECT().Create(userID, listID, item).Return(1, nil)
+				s.EXPECT().Create(gomock.Any(), userID, listID, item).Return(1, nil)
 			},
 			expectedStatusCode:   201,
 			expectedResponseBody: `{"item_id":1}`,
@@ -72,7 +72,7 @@ func TestHandler_createItem(t *testing.T) {
 			inputBody:   `{"title": "test", "description":"testing"}`,
 			item:        model.TodoItem{Title: "test", Description: "testing"},
 			mockBehavior: func(s *mockService.MockTodoItem, userID, listID interface{}, item model.TodoItem) {
-				s.EXPECT().Create(userID, listID, item).Return(1, nil)
+				s.EXPECT().Create(gomock.Any(), userID, listID, item).Return(1, nil)
 			},
 			expectedStatusCode:   201,
 			expectedResponseBody: `{"item_id":1}`,
@@ -137,7 +137,7 @@ func TestHandler_createItem(t *testing.T) {
 			inputBody:   `{"title": "test", "description":"testing", "completion_date": "2021-11-21 00:00:00"}`,
 			item:        model.TodoItem{Title: "test", Description: "testing", CompletionDate: "2021-11-21 00:00:00"},
 			mockBehavior: func(s *mockService.MockTodoItem, userID, listID interface{}, item model.TodoItem) {
-				s.EXPECT().Create(userID, listID, item).Return(0, service.ErrFailedToCreateItem)
+				s.EXPECT().Create(gomock.Any(), userID, listID, item).Return(0, service.ErrFailedToCreateItem)
 			},
 			expectedStatusCode:   500,
 			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToCreateItem.Error()),
@@ -155,7 +155,7 @@ func TestHandler_createItem(t *testing.T) {
 			tc.mockBehavior(todoItem, tc.inputUserID, tc.inputParam, tc.item)
 
 			services := &service.Service{TodoItem: todoItem}
-			handler := New(services)
+			handler := New(services, nil)
 
 			// Test server
 			gin.SetMode("test")
@@ -206,7 +206,7 @@ func TestHandler_getAllItems(t *testing.T) {
 				{ID: 2, ListID: 1, Title: "test2", Description: "testing2", CompletionDate: "2021-11-21 00:00:00", Done: true},
 			},
 			mockBehavior: func(s *mockService.MockTodoItem, items []model.TodoItem, userID, listID interface{}) {
-				s.EXPECT().GetAll(userID, listID).Return(items, nil)
+				s.EXPECT().GetAll(gomock.Any(), userID, listID).Return(items, nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"items":[{"id":1,"list_id":1,"title":"test","description":"testing","completion_date":"2021-11-21 00:00:00","done":false},{"id":2,"list_id":1,"title":"test2","description":"testing2","completion_date":"2021-11-21 00:00:00","done":true}]}`,
@@ -232,7 +232,7 @@ func TestHandler_getAllItems(t *testing.T) {
 			inputUserID: 1,
 			inputParam:  1,
 			mockBehavior: func(s *mockService.MockTodoItem, items []model.TodoItem, userID, listID interface{}) {
-				s.EXPECT().GetAll(userID, listID).Return(nil, service.ErrFailedToGetAllItems)
+				s.EXPECT().GetAll(gomock.Any(), userID, listID).Return(nil, service.ErrFailedToGetAllItems)
 			},
 			expectedStatusCode:   500,
 			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToGetAllItems.Error()),
@@ -250,7 +250,7 @@ func TestHandler_getAllItems(t *testing.T) {
 			tc.mockBehavior(todoItem, tc.items, tc.inputUserID, tc.inputParam)
 
 			services := &service.Service{TodoItem: todoItem}
-			handler := New(services)
+			handler := New(services, nil)
 
 			// Test server
 			gin.SetMode("test")
@@ -276,6 +276,106 @@ func TestHandler_getAllItems(t *testing.T) {
 	}
 }
 
+func TestHandler_getItems(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockTodoItem, userID int)
+
+	testCases := []struct {
+		name                 string
+		inputQuery           string
+		inputUserID          int
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			inputQuery:  "?list_id=1,2,1",
+			inputUserID: 1,
+			mockBehavior: func(s *mockService.MockTodoItem, userID int) {
+				s.EXPECT().GetAllByLists(gomock.Any(), userID, []int{1, 2}).Return([]model.TodoItem{
+					{ID: 1, ListID: 1, Title: "test", Done: true},
+					{ID: 2, ListID: 2, Title: "test2"},
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"items":[{"id":1,"list_id":1,"title":"test","description":"","completion_date":"","done":true},` +
+				`{"id":2,"list_id":2,"title":"test2","description":"","completion_date":"","done":false}]}`,
+		},
+		{
+			name:        "OK_All",
+			inputQuery:  "?list_id=all",
+			inputUserID: 1,
+			mockBehavior: func(s *mockService.MockTodoItem, userID int) {
+				s.EXPECT().GetAllByUser(gomock.Any(), userID).Return(nil, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"items":[]}`,
+		},
+		{
+			name:                 "Empty list_id",
+			inputUserID:          1,
+			mockBehavior:         func(s *mockService.MockTodoItem, userID int) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidListIDQuery.Error()),
+		},
+		{
+			name:                 "Invalid list_id",
+			inputQuery:           "?list_id=1,abc",
+			inputUserID:          1,
+			mockBehavior:         func(s *mockService.MockTodoItem, userID int) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidListIDQuery.Error()),
+		},
+		{
+			name:        "Service failure",
+			inputQuery:  "?list_id=1",
+			inputUserID: 1,
+			mockBehavior: func(s *mockService.MockTodoItem, userID int) {
+				s.EXPECT().GetAllByLists(gomock.Any(), userID, []int{1}).Return(nil, service.ErrFailedToGetAllItems)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToGetAllItems.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			todoItem := mockService.NewMockTodoItem(c)
+			tc.mockBehavior(todoItem, tc.inputUserID)
+
+			services := &service.Service{TodoItem: todoItem}
+			handler := New(services, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			r.GET(
+				"/api/items",
+				func(c *gin.Context) {
+					c.Set(userCtx, tc.inputUserID)
+				},
+				handler.getItems)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/api/items"+tc.inputQuery, nil)
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
 func TestHandler_getItemByID(t *testing.T) {
 	// Arrange
 	type mockBehavior func(s *mockService.MockTodoItem, userID, itemID interface{}, item model.TodoItem)
@@ -295,7 +395,7 @@ func TestHandler_getItemByID(t *testing.T) {
 			inputParam:  1,
 			item:        model.TodoItem{ID: 1, ListID: 1, Title: "test", Description: "testing", CompletionDate: "2021-11-21 00:00:00", Done: false},
 			mockBehavior: func(s *mockService.MockTodoItem, userID, itemID interface{}, item model.TodoItem) {
-				s.EXPECT().GetByID(userID, itemID).Return(item, nil)
+				s.EXPECT().GetByID(gomock.Any(), userID, itemID).Return(item, nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"item":{"id":1,"list_id":1,"title":"test","description":"testing","completion_date":"2021-11-21 00:00:00","done":false}}`,
@@ -324,7 +424,7 @@ func TestHandler_getItemByID(t *testing.T) {
 			inputParam:  1,
 			item:        model.TodoItem{ID: 1, ListID: 1, Title: "test", Description: "testing", CompletionDate: "2021-11-21 00:00:00", Done: false},
 			mockBehavior: func(s *mockService.MockTodoItem, userID, itemID interface{}, item model.TodoItem) {
-				s.EXPECT().GetByID(userID, itemID).Return(item, service.ErrFailedToGetItemByID)
+				s.EXPECT().GetByID(gomock.Any(), userID, itemID).Return(item, service.ErrFailedToGetItemByID)
 			},
 			expectedStatusCode:   500,
 			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToGetItemByID.Error()),
@@ -342,7 +442,7 @@ func TestHandler_getItemByID(t *testing.T) {
 			tc.mockBehavior(todoItem, tc.inputUserID, tc.inputParam, tc.item)
 
 			services := &service.Service{TodoItem: todoItem}
-			handler := New(services)
+			handler := New(services, nil)
 
 			// Test server
 			gin.SetMode("test")
@@ -394,7 +494,7 @@ func TestHandler_updateItem(t *testing.T) {
 				Done:           test.BoolPointer(true),
 			},
 			mockBehavior: func(s *mockService.MockTodoItem, userID, itemID interface{}, update model.UpdateTodoItem) {
-				s.EXPECT().Update(userID, itemID, update).Return(nil)
+				s.EXPECT().Update(gomock.Any(), userID, itemID, update).Return(nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"result":"the item update was successful"}`,
@@ -410,7 +510,7 @@ func TestHandler_updateItem(t *testing.T) {
 				CompletionDate: test.StringPointer("2021-11-21 00:00:00"),
 			},
 			mockBehavior: func(s *mockService.MockTodoItem, userID, itemID interface{}, update model.UpdateTodoItem) {
-				s.EXPECT().Update(userID, itemID, update).Return(nil)
+				s.EXPECT().Update(gomock.Any(), userID, itemID, update).Return(nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"result":"the item update was successful"}`,
@@ -425,7 +525,7 @@ func TestHandler_updateItem(t *testing.T) {
 				CompletionDate: test.StringPointer("2021-11-21 00:00:00"),
 			},
 			mockBehavior: func(s *mockService.MockTodoItem, userID, itemID interface{}, update model.UpdateTodoItem) {
-				s.EXPECT().Update(userID, itemID, update).Return(nil)
+				s.EXPECT().Update(gomock.Any(), userID, itemID, update).Return(nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"result":"the item update was successful"}`,
@@ -440,7 +540,7 @@ func TestHandler_updateItem(t *testing.T) {
 				Done:           test.BoolPointer(true),
 			},
 			mockBehavior: func(s *mockService.MockTodoItem, userID, itemID interface{}, update model.UpdateTodoItem) {
-				s.EXPECT().Update(userID, itemID, update).Return(nil)
+				s.EXPECT().Update(gomock.Any(), userID, itemID, update).Return(nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"result":"the item update was successful"}`,
@@ -456,7 +556,7 @@ func TestHandler_updateItem(t *testing.T) {
 				Done:        test.BoolPointer(true),
 			},
 			mockBehavior: func(s *mockService.MockTodoItem, userID, itemID interface{}, update model.UpdateTodoItem) {
-				s.EXPECT().Update(userID, itemID, update).Return(nil)
+				s.EXPECT().Update(gomock.Any(), userID, itemID, update).Return(nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"result":"the item update was successful"}`,
@@ -498,7 +598,7 @@ func TestHandler_updateItem(t *testing.T) {
 				Done:           test.BoolPointer(true),
 			},
 			mockBehavior: func(s *mockService.MockTodoItem, userID, itemID interface{}, update model.UpdateTodoItem) {
-				s.EXPECT().Update(userID, itemID, update).Return(service.ErrFailedToUpdateItem)
+				s.EXPECT().Update(gomock.Any(), userID, itemID, update).Return(service.ErrFailedToUpdateItem)
 			},
 			expectedStatusCode:   500,
 			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToUpdateItem.Error()),
@@ -516,7 +616,7 @@ func TestHandler_updateItem(t *testing.T) {
 			tc.mockBehavior(todoItem, tc.inputUserID, tc.inputParam, tc.update)
 
 			services := &service.Service{TodoItem: todoItem}
-			handler := New(services)
+			handler := New(services, nil)
 
 			// Test server
 			gin.SetMode("test")
@@ -559,7 +659,7 @@ func TestHandler_deleteItem(t *testing.T) {
 			inputUserID: 1,
 			inputParam:  1,
 			mockBehavior: func(s *mockService.MockTodoItem, userID, itemID interface{}) {
-				s.EXPECT().Delete(userID, itemID).Return(nil)
+				s.EXPECT().Delete(gomock.Any(), userID, itemID).Return(nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"result":"the item deletion was successful"}`,
@@ -585,7 +685,7 @@ func TestHandler_deleteItem(t *testing.T) {
 			inputUserID: 1,
 			inputParam:  1,
 			mockBehavior: func(s *mockService.MockTodoItem, userID, itemID interface{}) {
-				s.EXPECT().Delete(userID, itemID).Return(service.ErrFailedToDeleteItem)
+				s.EXPECT().Delete(gomock.Any(), userID, itemID).Return(service.ErrFailedToDeleteItem)
 			},
 			expectedStatusCode:   500,
 			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToDeleteItem.Error()),
@@ -603,7 +703,7 @@ func TestHandler_deleteItem(t *testing.T) {
 			tc.mockBehavior(todoItem, tc.inputUserID, tc.inputParam)
 
 			services := &service.Service{TodoItem: todoItem}
-			handler := New(services)
+			handler := New(services, nil)
 
 			// Test server
 			gin.SetMode("test")
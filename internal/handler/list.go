@@ -1,14 +1,21 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	_ "github.com/Lapp-coder/todo-app/docs/swagger"
 	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	includeQuery = "include"
+	includeItems = "items"
+)
+
 // createList godoc
 // @Summary Create list
 // @Security ApiKeyAuth
@@ -18,8 +25,9 @@ import (
 // @Accept json
 // @Produce json
 // @Param input body model.CreateTodoList true "List info"
+// @Param X-Workspace-ID header int false "Workspace id, the personal workspace by default"
 // @Success 201 {integer} integer "List id"
-// @Failure 400,404 {object} swagger.ErrorResponse
+// @Failure 400,403,404 {object} swagger.ErrorResponse
 // @Failure 500 {object} swagger.ErrorResponse
 // @Failure default {object} swagger.ErrorResponse
 // @Router /api/lists/ [post]
@@ -37,8 +45,13 @@ func (h Handler) createList(ctx *gin.Context) {
 	}
 
 	list := model.TodoList{Title: req.Title, Description: req.Description, CompletionDate: req.CompletionDate}
-	listID, err := h.service.TodoList.Create(userID, list)
+	listID, err := h.service.TodoList.Create(ctx.Request.Context(), userID, list)
 	if err != nil {
+		if errors.Is(err, service.ErrWorkspaceReadOnly) {
+			respondError(ctx, http.StatusForbidden, err)
+			return
+		}
+
 		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
@@ -52,9 +65,11 @@ func (h Handler) createList(ctx *gin.Context) {
 // @Summary Get all lists
 // @Security ApiKeyAuth
 // @Tags lists
-// @Description get all lists
+// @Description get all lists, with include=items the items of every list are embedded
 // @ID get-all-lists
 // @Produce json
+// @Param include query string false "Embed the items of the lists" Enums(items)
+// @Param X-Workspace-ID header int false "Workspace id, the personal workspace by default"
 // @Success 200 {object} swagger.GetAllListsResponse
 // @Failure 400,404 {object} swagger.ErrorResponse
 // @Failure 500 {object} swagger.ErrorResponse
@@ -67,14 +82,53 @@ func (h Handler) getAllLists(ctx *gin.Context) {
 		return
 	}
 
-	lists, err := h.service.TodoList.GetAll(userID)
+	include := ctx.Query(includeQuery)
+	if include != "" && include != includeItems {
+		respondError(ctx, http.StatusBadRequest, errInvalidInclude)
+		return
+	}
+
+	lists, err := h.service.TodoList.GetAll(ctx.Request.Context(), userID)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	if include != includeItems {
+		respond(ctx, http.StatusOK, gin.H{
+			"lists": lists,
+		})
+		return
+	}
+
+	listIDs := make([]int, 0, len(lists))
+	for _, list := range lists {
+		listIDs = append(listIDs, list.ID)
+	}
+
+	items, err := h.service.TodoItem.GetAllByLists(ctx.Request.Context(), userID, listIDs)
 	if err != nil {
 		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
+	itemsByList := make(map[int][]model.TodoItem, len(lists))
+	for _, item := range items {
+		itemsByList[item.ListID] = append(itemsByList[item.ListID], item)
+	}
+
+	listsWithItems := make([]model.TodoListWithItems, 0, len(lists))
+	for _, list := range lists {
+		listItems := itemsByList[list.ID]
+		if listItems == nil {
+			listItems = []model.TodoItem{}
+		}
+
+		listsWithItems = append(listsWithItems, model.TodoListWithItems{TodoList: list, Items: listItems})
+	}
+
 	respond(ctx, http.StatusOK, gin.H{
-		"lists": lists,
+		"lists": listsWithItems,
 	})
 }
 
@@ -86,6 +140,7 @@ func (h Handler) getAllLists(ctx *gin.Context) {
 // @ID get-list-by-id
 // @Produce json
 // @Param id path int true "List id"
+// @Param X-Workspace-ID header int false "Workspace id, the personal workspace by default"
 // @Success 200 {object} swagger.GetListByIDResponse
 // @Failure 400,404 {object} swagger.ErrorResponse
 // @Failure 500 {object} swagger.ErrorResponse
@@ -104,7 +159,7 @@ func (h Handler) getListByID(ctx *gin.Context) {
 		return
 	}
 
-	list, err := h.service.TodoList.GetByID(userID, listID)
+	list, err := h.service.TodoList.GetByID(ctx.Request.Context(), userID, listID)
 	if err != nil {
 		respondError(ctx, http.StatusInternalServerError, err)
 		return
@@ -125,8 +180,9 @@ func (h Handler) getListByID(ctx *gin.Context) {
 // @Produce json
 // @Param id path int true "List id"
 // @Param input body model.UpdateTodoList true "Update values"
+// @Param X-Workspace-ID header int false "Workspace id, the personal workspace by default"
 // @Success 200 {string} string "Result"
-// @Failure 400,404 {object} swagger.ErrorResponse
+// @Failure 400,403,404 {object} swagger.ErrorResponse
 // @Failure 500 {object} swagger.ErrorResponse
 // @Failure default {object} swagger.ErrorResponse
 // @Router /api/lists/{id} [put]
@@ -154,7 +210,12 @@ func (h Handler) updateList(ctx *gin.Context) {
 		return
 	}
 
-	if err = h.service.TodoList.Update(userID, listID, req); err != nil {
+	if err = h.service.TodoList.Update(ctx.Request.Context(), userID, listID, req); err != nil {
+		if errors.Is(err, service.ErrWorkspaceReadOnly) {
+			respondError(ctx, http.StatusForbidden, err)
+			return
+		}
+
 		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
@@ -172,8 +233,9 @@ func (h Handler) updateList(ctx *gin.Context) {
 // @ID delete-list
 // @Produce json
 // @Param id path int true "List id"
+// @Param X-Workspace-ID header int false "Workspace id, the personal workspace by default"
 // @Success 200 {string} string "Result"
-// @Failure 400,404 {object} swagger.ErrorResponse
+// @Failure 400,403,404 {object} swagger.ErrorResponse
 // @Failure 500 {object} swagger.ErrorResponse
 // @Failure default {object} swagger.ErrorResponse
 // @Router /api/lists/{id} [delete]
@@ -190,7 +252,12 @@ func (h Handler) deleteList(ctx *gin.Context) {
 		return
 	}
 
-	if err = h.service.TodoList.Delete(userID, listID); err != nil {
+	if err = h.service.TodoList.Delete(ctx.Request.Context(), userID, listID); err != nil {
+		if errors.Is(err, service.ErrWorkspaceReadOnly) {
+			respondError(ctx, http.StatusForbidden, err)
+			return
+		}
+
 		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
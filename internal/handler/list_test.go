@@ -35,7 +35,7 @@ func TestHandler_createList(t *testing.T) {
 			inputUserID: 1,
 			inputList:   model.TodoList{Title: "Test", Description: "testing, testing, testing...", CompletionDate: "2021-11-21 00:00:00"},
 			mockBehavior: func(s *mockService.MockTodoList, userID int, list model.TodoList) {
-				s.EXPECT().Create(userID, list).Return(1, nil)
+				s.EXPECT().Create(gomock.Any(), userID, list).Return(1, nil)
 			},
 			expectedStatusCode:   201,
 			expectedResponseBody: `{"list id":1}`,
@@ -46,7 +46,7 @@ func TestHandler_createList(t *testing.T) {
 			inputUserID: 1,
 			inputList:   model.TodoList{Title: "Test", CompletionDate: "2021-11-21 00:00:00"},
 			mockBehavior: func(s *mockService.MockTodoList, userID int, list model.TodoList) {
-				s.EXPECT().Create(userID, list).Return(1, nil)
+				s.EXPECT().Create(gomock.Any(), userID, list).Return(1, nil)
 			},
 			expectedStatusCode:   201,
 			expectedResponseBody: `{"list id":1}`,
@@ -57,7 +57,7 @@ func TestHandler_createList(t *testing.T) {
 			inputUserID: 1,
 			inputList:   model.TodoList{Title: "Test", Description: "testing"},
 			mockBehavior: func(s *mockService.MockTodoList, userID int, list model.TodoList) {
-				s.EXPECT().Create(userID, list).Return(1, nil)
+				s.EXPECT().Create(gomock.Any(), userID, list).Return(1, nil)
 			},
 			expectedStatusCode:   201,
 			expectedResponseBody: `{"list id":1}`,
@@ -100,7 +100,7 @@ func TestHandler_createList(t *testing.T) {
 			inputList:   model.TodoList{Title: "test", Description: "testing", CompletionDate: "2021-11-21 00:00:00"},
 			inputUserID: 1,
 			mockBehavior: func(s *mockService.MockTodoList, userID int, list model.TodoList) {
-				s.EXPECT().Create(userID, list).Return(0, service.ErrFailedToCreateList)
+				s.EXPECT().Create(gomock.Any(), userID, list).Return(0, service.ErrFailedToCreateList)
 			},
 			expectedStatusCode:   500,
 			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToCreateList.Error()),
@@ -118,7 +118,7 @@ func TestHandler_createList(t *testing.T) {
 			tc.mockBehavior(todoList, tc.inputUserID, tc.inputList)
 
 			services := &service.Service{TodoList: todoList}
-			handler := New(services)
+			handler := New(services, nil)
 
 			// Test server
 			gin.SetMode("test")
@@ -164,16 +164,16 @@ func TestHandler_getAllLists(t *testing.T) {
 				{ID: 2, UserID: 1, Title: "test2", Description: "testing2", CompletionDate: "2021-11-21 00:00:00"},
 			},
 			mockBehavior: func(s *mockService.MockTodoList, userID interface{}, lists []model.TodoList) {
-				s.EXPECT().GetAll(userID).Return(lists, nil)
+				s.EXPECT().GetAll(gomock.Any(), userID).Return(lists, nil)
 			},
 			expectedStatusCode:   200,
-			expectedResponseBody: `{"lists":[{"id":1,"user_id":1,"title":"test","description":"testing","completion_date":"2021-11-21 00:00:00"},{"id":2,"user_id":1,"title":"test2","description":"testing2","completion_date":"2021-11-21 00:00:00"}]}`,
+			expectedResponseBody: `{"lists":[{"id":1,"user_id":1,"workspace_id":0,"title":"test","description":"testing","completion_date":"2021-11-21 00:00:00"},{"id":2,"user_id":1,"workspace_id":0,"title":"test2","description":"testing2","completion_date":"2021-11-21 00:00:00"}]}`,
 		},
 		{
 			name:        "No lists",
 			inputUserID: 1,
 			mockBehavior: func(s *mockService.MockTodoList, userID interface{}, lists []model.TodoList) {
-				s.EXPECT().GetAll(userID).Return(lists, nil)
+				s.EXPECT().GetAll(gomock.Any(), userID).Return(lists, nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"lists":null}`,
@@ -189,7 +189,7 @@ func TestHandler_getAllLists(t *testing.T) {
 			name:        "Service failure",
 			inputUserID: 1,
 			mockBehavior: func(s *mockService.MockTodoList, userID interface{}, lists []model.TodoList) {
-				s.EXPECT().GetAll(userID).Return(nil, service.ErrFailedToGetAllLists)
+				s.EXPECT().GetAll(gomock.Any(), userID).Return(nil, service.ErrFailedToGetAllLists)
 			},
 			expectedStatusCode:   500,
 			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToGetAllLists.Error()),
@@ -207,7 +207,7 @@ func TestHandler_getAllLists(t *testing.T) {
 			tc.mockBehavior(todoList, tc.inputUserID, tc.lists)
 
 			services := &service.Service{TodoList: todoList}
-			handler := New(services)
+			handler := New(services, nil)
 
 			// Test server
 			gin.SetMode("test")
@@ -233,6 +233,90 @@ func TestHandler_getAllLists(t *testing.T) {
 	}
 }
 
+func TestHandler_getAllListsWithItems(t *testing.T) {
+	// Arrange
+	type mockBehavior func(list *mockService.MockTodoList, item *mockService.MockTodoItem, userID int)
+
+	testCases := []struct {
+		name                 string
+		inputQuery           string
+		inputUserID          int
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			inputQuery:  "?include=items",
+			inputUserID: 1,
+			mockBehavior: func(list *mockService.MockTodoList, item *mockService.MockTodoItem, userID int) {
+				list.EXPECT().GetAll(gomock.Any(), userID).Return([]model.TodoList{{ID: 1, UserID: 1, Title: "test"}, {ID: 2, UserID: 1, Title: "test2"}}, nil)
+				item.EXPECT().GetAllByLists(gomock.Any(), userID, []int{1, 2}).Return([]model.TodoItem{{ID: 1, ListID: 1, Title: "item"}}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"lists":[{"id":1,"user_id":1,"workspace_id":0,"title":"test","description":"","completion_date":"",` +
+				`"items":[{"id":1,"list_id":1,"title":"item","description":"","completion_date":"","done":false}]},` +
+				`{"id":2,"user_id":1,"workspace_id":0,"title":"test2","description":"","completion_date":"","items":[]}]}`,
+		},
+		{
+			name:                 "Invalid include",
+			inputQuery:           "?include=lists",
+			inputUserID:          1,
+			mockBehavior:         func(list *mockService.MockTodoList, item *mockService.MockTodoItem, userID int) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidInclude.Error()),
+		},
+		{
+			name:        "Service failure",
+			inputQuery:  "?include=items",
+			inputUserID: 1,
+			mockBehavior: func(list *mockService.MockTodoList, item *mockService.MockTodoItem, userID int) {
+				list.EXPECT().GetAll(gomock.Any(), userID).Return([]model.TodoList{{ID: 1, UserID: 1, Title: "test"}}, nil)
+				item.EXPECT().GetAllByLists(gomock.Any(), userID, []int{1}).Return(nil, service.ErrFailedToGetAllItems)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToGetAllItems.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			todoList := mockService.NewMockTodoList(c)
+			todoItem := mockService.NewMockTodoItem(c)
+			tc.mockBehavior(todoList, todoItem, tc.inputUserID)
+
+			services := &service.Service{TodoList: todoList, TodoItem: todoItem}
+			handler := New(services, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			r.GET(
+				"/api/lists",
+				func(c *gin.Context) {
+					c.Set(userCtx, tc.inputUserID)
+				},
+				handler.getAllLists)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/api/lists"+tc.inputQuery, nil)
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
 func TestHandler_getListByID(t *testing.T) {
 	// Arrange
 	type mockBehavior func(s *mockService.MockTodoList, list model.TodoList, userID, listID interface{})
@@ -252,10 +336,10 @@ func TestHandler_getListByID(t *testing.T) {
 			inputParam:  1,
 			list:        model.TodoList{ID: 1, UserID: 1, Title: "test", Description: "testing", CompletionDate: "2021-11-21 00:00:00"},
 			mockBehavior: func(s *mockService.MockTodoList, list model.TodoList, userID, listID interface{}) {
-				s.EXPECT().GetByID(userID, listID).Return(list, nil)
+				s.EXPECT().GetByID(gomock.Any(), userID, listID).Return(list, nil)
 			},
 			expectedStatusCode:   200,
-			expectedResponseBody: `{"list":{"id":1,"user_id":1,"title":"test","description":"testing","completion_date":"2021-11-21 00:00:00"}}`,
+			expectedResponseBody: `{"list":{"id":1,"user_id":1,"workspace_id":0,"title":"test","description":"testing","completion_date":"2021-11-21 00:00:00"}}`,
 		},
 		{
 			name:                 "Invalid param",
@@ -279,7 +363,7 @@ func TestHandler_getListByID(t *testing.T) {
 			inputParam:  1,
 			list:        model.TodoList{ID: 1, Title: "test", UserID: 1, Description: "testing", CompletionDate: "2021-11-21 00:00:00"},
 			mockBehavior: func(s *mockService.MockTodoList, list model.TodoList, userID, listID interface{}) {
-				s.EXPECT().GetByID(userID, listID).Return(model.TodoList{}, service.ErrFailedToGetListByID)
+				s.EXPECT().GetByID(gomock.Any(), userID, listID).Return(model.TodoList{}, service.ErrFailedToGetListByID)
 			},
 			expectedStatusCode:   500,
 			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToGetListByID.Error()),
@@ -297,7 +381,7 @@ func TestHandler_getListByID(t *testing.T) {
 			tc.mockBehavior(todoList, tc.list, tc.inputUserID, tc.inputParam)
 
 			services := &service.Service{TodoList: todoList}
-			handler := New(services)
+			handler := New(services, nil)
 
 			// Test server
 			gin.SetMode("test")
@@ -348,7 +432,7 @@ func TestHandler_updateList(t *testing.T) {
 				CompletionDate: test.StringPointer("2021-11-21 00:00:00"),
 			},
 			mockBehavior: func(s *mockService.MockTodoList, userID, listID interface{}, update model.UpdateTodoList) {
-				s.EXPECT().Update(userID, listID, update).Return(nil)
+				s.EXPECT().Update(gomock.Any(), userID, listID, update).Return(nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"result":"the list update was successful"}`,
@@ -363,7 +447,7 @@ func TestHandler_updateList(t *testing.T) {
 				CompletionDate: test.StringPointer("2021-11-21 00:00:00"),
 			},
 			mockBehavior: func(s *mockService.MockTodoList, userID, listID interface{}, update model.UpdateTodoList) {
-				s.EXPECT().Update(userID, listID, update).Return(nil)
+				s.EXPECT().Update(gomock.Any(), userID, listID, update).Return(nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"result":"the list update was successful"}`,
@@ -378,7 +462,7 @@ func TestHandler_updateList(t *testing.T) {
 				CompletionDate: test.StringPointer("2021-11-21 00:00:00"),
 			},
 			mockBehavior: func(s *mockService.MockTodoList, userID, listID interface{}, update model.UpdateTodoList) {
-				s.EXPECT().Update(userID, listID, update).Return(nil)
+				s.EXPECT().Update(gomock.Any(), userID, listID, update).Return(nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"result":"the list update was successful"}`,
@@ -393,7 +477,7 @@ func TestHandler_updateList(t *testing.T) {
 				Description: test.StringPointer("testing"),
 			},
 			mockBehavior: func(s *mockService.MockTodoList, userID, listID interface{}, update model.UpdateTodoList) {
-				s.EXPECT().Update(userID, listID, update).Return(nil)
+				s.EXPECT().Update(gomock.Any(), userID, listID, update).Return(nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"result":"the list update was successful"}`,
@@ -435,7 +519,7 @@ func TestHandler_updateList(t *testing.T) {
 				CompletionDate: test.StringPointer("2021-11-21 00:00:00"),
 			},
 			mockBehavior: func(s *mockService.MockTodoList, userID, listID interface{}, update model.UpdateTodoList) {
-				s.EXPECT().Update(userID, listID, update).Return(service.ErrFailedToUpdateList)
+				s.EXPECT().Update(gomock.Any(), userID, listID, update).Return(service.ErrFailedToUpdateList)
 			},
 			expectedStatusCode:   500,
 			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToUpdateList.Error()),
@@ -453,7 +537,7 @@ func TestHandler_updateList(t *testing.T) {
 			tc.mockBehavior(todoList, tc.inputUserID, tc.inputParam, tc.updateList)
 
 			services := &service.Service{TodoList: todoList}
-			handler := New(services)
+			handler := New(services, nil)
 
 			// Test server
 			gin.SetMode("test")
@@ -502,11 +586,11 @@ func TestHandler_deleteList(t *testing.T) {
 			dbUsersLists: map[int]int{1: 1},
 			mockBehavior: func(s *mockService.MockTodoList, dbUsersLists map[int]int, userID, listID interface{}) {
 				if dbUsersLists[userID.(int)] == listID {
-					s.EXPECT().Delete(userID, listID).Return(nil)
+					s.EXPECT().Delete(gomock.Any(), userID, listID).Return(nil)
 					return
 				}
 
-				s.EXPECT().Delete(userID, listID).Return(service.ErrFailedToDeleteList)
+				s.EXPECT().Delete(gomock.Any(), userID, listID).Return(service.ErrFailedToDeleteList)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"result":"the list deletion was successful"}`,
@@ -534,11 +618,11 @@ func TestHandler_deleteList(t *testing.T) {
 			dbUsersLists: map[int]int{1: 1},
 			mockBehavior: func(s *mockService.MockTodoList, dbUsersLists map[int]int, userID, listID interface{}) {
 				if dbUsersLists[userID.(int)] == listID {
-					s.EXPECT().Delete(userID, listID).Return(service.ErrFailedToDeleteList)
+					s.EXPECT().Delete(gomock.Any(), userID, listID).Return(service.ErrFailedToDeleteList)
 					return
 				}
 
-				s.EXPECT().Delete(userID, listID).Return(service.ErrFailedToDeleteList)
+				s.EXPECT().Delete(gomock.Any(), userID, listID).Return(service.ErrFailedToDeleteList)
 			},
 			expectedStatusCode:   500,
 			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToDeleteList.Error()),
@@ -556,7 +640,7 @@ func TestHandler_deleteList(t *testing.T) {
 			tc.mockBehavior(todoList, tc.dbUsersLists, tc.inputUserID, tc.inputParam)
 
 			services := &service.Service{TodoList: todoList}
-			handler := New(services)
+			handler := New(services, nil)
 
 			// Test server
 			gin.SetMode("test")
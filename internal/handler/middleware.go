@@ -1,45 +1,310 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/Lapp-coder/todo-app/internal/logger"
+	"github.com/Lapp-coder/todo-app/internal/metrics"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/ratelimit"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	"github.com/Lapp-coder/todo-app/internal/tracing"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
-	userCtx     = "userID"
-	indexBearer = 0
-	indexToken  = 1
+	basicAuthRealm = `Basic realm="todo-app"`
+	serviceName    = "todo-app"
+
+	userCtx      = "userID"
+	scopesCtx    = "scopes"
+	roleCtx      = "role"
+	workspaceCtx = "workspace"
+	indexBearer  = 0
+	indexToken   = 1
+
+	// unmatchedRoute labels the requests to unknown paths, so they don't blow up
+	// the number of the label values.
+	unmatchedRoute = "unmatched"
+
+	requestIDHeader    = "X-Request-ID"
+	maxRequestIDLength = 128
+
+	workspaceHeader = "X-Workspace-ID"
 )
 
-func (h Handler) userAuthentication(ctx *gin.Context) {
+// requestID takes the id of the request from the header set by the proxy, or generates
+// a new one, and puts the logger carrying it into the request context.
+func (h Handler) requestID(ctx *gin.Context) {
+	id := ctx.GetHeader(requestIDHeader)
+	if !validRequestID(id) {
+		id = uuid.NewString()
+	}
+
+	ctx.Header(requestIDHeader, id)
+
+	entry := logger.FromContext(ctx.Request.Context()).WithField(logger.RequestIDField, id)
+	if spanCtx := trace.SpanContextFromContext(ctx.Request.Context()); spanCtx.HasTraceID() {
+		entry = entry.WithField(logger.TraceIDField, spanCtx.TraceID().String())
+	}
+
+	ctx.Request = ctx.Request.WithContext(logger.WithContext(ctx.Request.Context(), entry))
+}
+
+// validRequestID keeps the ids sent by the clients from breaking the log lines.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, r := range id {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// logRequest writes an access log line with the fields of the request logger.
+func (h Handler) logRequest(ctx *gin.Context) {
+	start := time.Now()
+
+	ctx.Next()
+
+	entry := logger.FromContext(ctx.Request.Context()).WithFields(logrus.Fields{
+		"method":    ctx.Request.Method,
+		"path":      ctx.Request.URL.Path,
+		"route":     ctx.FullPath(),
+		"status":    ctx.Writer.Status(),
+		"latency":   time.Since(start).String(),
+		"client_ip": ctx.ClientIP(),
+	})
+
+	switch status := ctx.Writer.Status(); {
+	case status >= http.StatusInternalServerError:
+		entry.Error("request failed")
+	case status >= http.StatusBadRequest:
+		entry.Warn("request rejected")
+	default:
+		entry.Info("request handled")
+	}
+}
+
+func (h Handler) recordMetrics(ctx *gin.Context) {
+	start := time.Now()
+
+	ctx.Next()
+
+	route := ctx.FullPath()
+	if route == "" {
+		route = unmatchedRoute
+	}
+
+	status := strconv.Itoa(ctx.Writer.Status())
+	metrics.HTTPRequests.WithLabelValues(ctx.Request.Method, route, status).Inc()
+	metrics.HTTPRequestDuration.WithLabelValues(ctx.Request.Method, route, status).Observe(time.Since(start).Seconds())
+}
+
+// traceRequest continues the trace from the traceparent header, or starts a new one,
+// with a span covering the whole request.
+func (h Handler) traceRequest(ctx *gin.Context) {
+	route := ctx.FullPath()
+	if route == "" {
+		route = unmatchedRoute
+	}
+
+	reqCtx := otel.GetTextMapPropagator().Extract(ctx.Request.Context(), propagation.HeaderCarrier(ctx.Request.Header))
+	reqCtx, span := tracing.Start(reqCtx, ctx.Request.Method+" "+route,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest(serviceName, route, ctx.Request)...))
+	defer span.End()
+
+	ctx.Request = ctx.Request.WithContext(reqCtx)
+
+	ctx.Next()
+
+	status := ctx.Writer.Status()
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(status))
+	span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(status, trace.SpanKindServer))
+}
+
+// bearerToken takes the token from the "Authorization: Bearer <token>" header.
+func bearerToken(ctx *gin.Context) (string, error) {
 	header := ctx.GetHeader("Authorization")
 	if header == "" {
-		respondError(ctx, http.StatusUnauthorized, errEmptyAuthHeader)
-		return
+		return "", errEmptyAuthHeader
 	}
 
 	headerParts := strings.Split(header, " ")
-	bearerPart := headerParts[indexBearer]
+	if len(headerParts) != 2 || headerParts[indexBearer] != "Bearer" {
+		return "", errInvalidAuthHeader
+	}
+
 	token := headerParts[indexToken]
-	if len(headerParts) != 2 || bearerPart != "Bearer" {
+	if token == "" {
+		return "", errEmptyToken
+	}
+
+	return token, nil
+}
+
+// userAuthentication accepts the session tokens and the personal access tokens, the scopes
+// of the latter are checked by requireScope.
+func (h Handler) userAuthentication(ctx *gin.Context) {
+	token, err := bearerToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	if strings.HasPrefix(token, service.AccessTokenPrefix) {
+		accessToken, err := h.service.AccessToken.Authenticate(ctx.Request.Context(), token)
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidAccessToken) {
+				respondError(ctx, http.StatusUnauthorized, err)
+				return
+			}
+
+			respondError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+
+		ctx.Set(scopesCtx, accessToken.Scopes)
+		h.setUserID(ctx, accessToken.UserID)
+		return
+	}
+
+	session, err := h.service.Authorization.ParseToken(ctx.Request.Context(), token)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	ctx.Set(roleCtx, session.Role)
+	h.setUserID(ctx, session.UserID)
+}
+
+// requireRole lets through the sessions of the users with the role, the access tokens
+// have no role.
+func (h Handler) requireRole(role string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetString(roleCtx) != role {
+			respondError(ctx, http.StatusForbidden, errInsufficientRole)
+			return
+		}
+	}
+}
+
+// requireScope lets through the sessions and the access tokens with the scope.
+func (h Handler) requireScope(scope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if scopes, ok := h.getScopes(ctx); ok && !scopes.Has(scope) {
+			respondError(ctx, http.StatusForbidden, errInsufficientScope)
+			return
+		}
+	}
+}
+
+// requireSession keeps the access tokens out of the routes managing the account.
+func (h Handler) requireSession(ctx *gin.Context) {
+	if _, ok := h.getScopes(ctx); ok {
+		respondError(ctx, http.StatusForbidden, errSessionRequired)
+		return
+	}
+}
+
+// adminAuthentication guards the lockout routes, it lets through the requests with the admin
+// token as admins, the rest are authenticated as users, so requireRole lets through only the
+// sessions of the admins.
+func (h Handler) adminAuthentication(ctx *gin.Context) {
+	token, err := bearerToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	if err = h.service.Authorization.AuthorizeAdmin(token); err == nil {
+		ctx.Set(roleCtx, model.RoleAdmin)
+		return
+	}
+
+	h.userAuthentication(ctx)
+}
+
+func (h Handler) basicAuthentication(ctx *gin.Context) {
+	email, password, ok := ctx.Request.BasicAuth()
+	if !ok {
+		ctx.Header("WWW-Authenticate", basicAuthRealm)
 		respondError(ctx, http.StatusUnauthorized, errInvalidAuthHeader)
 		return
 	}
 
-	if token == "" {
-		respondError(ctx, http.StatusUnauthorized, errEmptyToken)
+	// Every request checks the password, so it's limited like a sign-in.
+	if !h.allow(ctx, ratelimit.PolicyAuthIP, ctx.ClientIP()) || !h.allow(ctx, ratelimit.PolicyAuthEmail, ratelimit.EmailKey(email)) {
 		return
 	}
 
-	userID, err := h.service.Authorization.ParseToken(token)
+	userID, err := h.service.Authorization.Authenticate(ctx.Request.Context(), email, password)
 	if err != nil {
+		ctx.Header("WWW-Authenticate", basicAuthRealm)
 		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
 
+	h.setUserID(ctx, userID)
+}
+
+// selectWorkspace scopes the lists and the items to the workspace from the path or the
+// X-Workspace-ID header, without them the personal workspace of the user is used.
+func (h Handler) selectWorkspace(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	param := ctx.Param("workspace_id")
+	if param == "" {
+		param = ctx.GetHeader(workspaceHeader)
+	}
+
+	var workspace model.Workspace
+	var err error
+	if param == "" {
+		workspace, err = h.service.Workspace.GetPersonal(ctx.Request.Context(), userID)
+	} else {
+		workspaceID, convErr := strconv.Atoi(param)
+		if convErr != nil || workspaceID <= 0 {
+			respondError(ctx, http.StatusBadRequest, errInvalidWorkspaceID)
+			return
+		}
+
+		workspace, err = h.service.Workspace.GetByID(ctx.Request.Context(), userID, workspaceID)
+	}
+	if err != nil {
+		respondWorkspaceError(ctx, err)
+		return
+	}
+
+	ctx.Set(workspaceCtx, workspace)
+	ctx.Request = ctx.Request.WithContext(service.WithWorkspace(ctx.Request.Context(), userID, workspace))
+}
+
+// setUserID stores the authenticated user for the handlers and adds them to the request logger.
+func (h Handler) setUserID(ctx *gin.Context, userID int) {
 	ctx.Set(userCtx, userID)
+	ctx.Request = ctx.Request.WithContext(logger.WithField(ctx.Request.Context(), logger.UserIDField, userID))
 }
 
 func (h Handler) getUserID(ctx *gin.Context) int {
@@ -55,3 +320,15 @@ func (h Handler) getUserID(ctx *gin.Context) int {
 
 	return id
 }
+
+// getScopes returns the scopes of the access token, ok is false for the sessions.
+func (h Handler) getScopes(ctx *gin.Context) (model.Scopes, bool) {
+	v, ok := ctx.Get(scopesCtx)
+	if !ok {
+		return nil, false
+	}
+
+	scopes, ok := v.(model.Scopes)
+
+	return scopes, ok
+}
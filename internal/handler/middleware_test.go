@@ -7,11 +7,18 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/Lapp-coder/todo-app/internal/logger"
+	"github.com/Lapp-coder/todo-app/internal/metrics"
+	"github.com/Lapp-coder/todo-app/internal/model"
 	"github.com/Lapp-coder/todo-app/internal/service"
 	mockService "github.com/Lapp-coder/todo-app/internal/service/mocks"
+	"github.com/Lapp-coder/todo-app/internal/tracing/tracingtest"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestHandler_userAuthentication(t *testing.T) {
@@ -33,7 +40,7 @@ func TestHandler_userAuthentication(t *testing.T) {
 			headerValue: "Bearer token",
 			token:       "token",
 			mockBehavior: func(s *mockService.MockAuthorization, token string) {
-				s.EXPECT().ParseToken(token).Return(1, nil)
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(model.Session{UserID: 1}, nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: "1",
@@ -85,7 +92,7 @@ func TestHandler_userAuthentication(t *testing.T) {
 			headerValue: "Bearer token",
 			token:       "token",
 			mockBehavior: func(s *mockService.MockAuthorization, token string) {
-				s.EXPECT().ParseToken(token).Return(0, errFailedToParseToken)
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(model.Session{}, errFailedToParseToken)
 			},
 			expectedStatusCode:   401,
 			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errFailedToParseToken),
@@ -103,7 +110,7 @@ func TestHandler_userAuthentication(t *testing.T) {
 			tc.mockBehavior(auth, tc.token)
 
 			services := &service.Service{Authorization: auth}
-			handler := New(services)
+			handler := New(services, nil)
 
 			// Test server
 			gin.SetMode("test")
@@ -128,6 +135,226 @@ func TestHandler_userAuthentication(t *testing.T) {
 	}
 }
 
+func TestHandler_userAuthenticationAccessToken(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockAccessToken, token string)
+
+	testTable := []struct {
+		name                 string
+		token                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "OK",
+			token: "todo_pat_token",
+			mockBehavior: func(s *mockService.MockAccessToken, token string) {
+				s.EXPECT().Authenticate(gomock.Any(), token).Return(model.AccessToken{UserID: 1, Scopes: model.Scopes{model.ScopeListsRead}}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "1 [lists:read]",
+		},
+		{
+			name:  "Invalid token",
+			token: "todo_pat_token",
+			mockBehavior: func(s *mockService.MockAccessToken, token string) {
+				s.EXPECT().Authenticate(gomock.Any(), token).Return(model.AccessToken{}, service.ErrInvalidAccessToken)
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrInvalidAccessToken.Error()),
+		},
+		{
+			name:  "Service failure",
+			token: "todo_pat_token",
+			mockBehavior: func(s *mockService.MockAccessToken, token string) {
+				s.EXPECT().Authenticate(gomock.Any(), token).Return(model.AccessToken{}, service.ErrFailedToCheckAccessToken)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToCheckAccessToken.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			tokens := mockService.NewMockAccessToken(c)
+			tc.mockBehavior(tokens, tc.token)
+
+			services := &service.Service{AccessToken: tokens}
+			handler := New(services, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			r.GET("/protected", handler.userAuthentication, func(c *gin.Context) {
+				scopes, _ := handler.getScopes(c)
+				c.String(200, fmt.Sprintf("%d %v", handler.getUserID(c), scopes))
+			})
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/protected", nil)
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_requireScope(t *testing.T) {
+	// Arrange
+	testTable := []struct {
+		name                 string
+		scopes               model.Scopes
+		session              bool
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Session",
+			session:              true,
+			expectedStatusCode:   200,
+			expectedResponseBody: "ok",
+		},
+		{
+			name:                 "Token with the scope",
+			scopes:               model.Scopes{model.ScopeItemsRead, model.ScopeListsRead},
+			expectedStatusCode:   200,
+			expectedResponseBody: "ok",
+		},
+		{
+			name:                 "Token without the scope",
+			scopes:               model.Scopes{model.ScopeListsWrite, model.ScopeItemsRead},
+			expectedStatusCode:   403,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInsufficientScope.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			handler := New(&service.Service{}, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			r.GET("/protected", func(c *gin.Context) {
+				if !tc.session {
+					c.Set(scopesCtx, tc.scopes)
+				}
+			}, handler.requireScope(model.ScopeListsRead), func(c *gin.Context) {
+				c.String(200, "ok")
+			})
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/protected", nil)
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_basicAuthentication(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockAuthorization, email, password string)
+
+	testTable := []struct {
+		name                 string
+		withCredentials      bool
+		email                string
+		password             string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:            "OK",
+			withCredentials: true,
+			email:           "user@gmail.com",
+			password:        "password",
+			mockBehavior: func(s *mockService.MockAuthorization, email, password string) {
+				s.EXPECT().Authenticate(gomock.Any(), email, password).Return(1, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "1",
+		},
+		{
+			name:                 "Without credentials",
+			mockBehavior:         func(s *mockService.MockAuthorization, email, password string) {},
+			expectedStatusCode:   401,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidAuthHeader.Error()),
+		},
+		{
+			name:            "Incorrect password",
+			withCredentials: true,
+			email:           "user@gmail.com",
+			password:        "incorrect",
+			mockBehavior: func(s *mockService.MockAuthorization, email, password string) {
+				s.EXPECT().Authenticate(gomock.Any(), email, password).Return(0, service.ErrIncorrectEmailOrPassword)
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrIncorrectEmailOrPassword.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mockService.NewMockAuthorization(c)
+			tc.mockBehavior(auth, tc.email, tc.password)
+
+			services := &service.Service{Authorization: auth}
+			handler := New(services, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			r.GET("/protected", handler.basicAuthentication, func(c *gin.Context) {
+				id, _ := c.Get(userCtx)
+				c.String(200, strconv.Itoa(id.(int)))
+			})
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/protected", nil)
+			if tc.withCredentials {
+				req.SetBasicAuth(tc.email, tc.password)
+			}
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+			if tc.expectedStatusCode == 401 {
+				assert.Equal(t, basicAuthRealm, w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
 func TestHandler_getUserID(t *testing.T) {
 	// Arrange
 	testTable := []struct {
@@ -164,7 +391,7 @@ func TestHandler_getUserID(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			// Init dependency
 			services := &service.Service{}
-			handler := New(services)
+			handler := New(services, nil)
 
 			// Test server
 			gin.SetMode("test")
@@ -200,3 +427,287 @@ func TestHandler_getUserID(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_recordMetrics(t *testing.T) {
+	// Arrange
+	testTable := []struct {
+		name          string
+		path          string
+		expectedRoute string
+		expectedCode  string
+	}{
+		{
+			name:          "Route template",
+			path:          "/items/42",
+			expectedRoute: "/items/:id",
+			expectedCode:  "200",
+		},
+		{
+			name:          "Unmatched route",
+			path:          "/unknown",
+			expectedRoute: unmatchedRoute,
+			expectedCode:  "404",
+		},
+	}
+
+	// Act
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			handler := New(&service.Service{}, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			r.Use(handler.recordMetrics)
+			r.GET("/items/:id", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			counter := metrics.HTTPRequests.WithLabelValues("GET", tc.expectedRoute, tc.expectedCode)
+			before := testutil.ToFloat64(counter)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", tc.path, nil)
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, before+1, testutil.ToFloat64(counter))
+		})
+	}
+}
+
+func TestHandler_traceRequest(t *testing.T) {
+	// Arrange
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	testTable := []struct {
+		name         string
+		traceparent  string
+		path         string
+		expectedName string
+		expectedCode int
+	}{
+		{
+			name:         "New trace",
+			path:         "/items/42",
+			expectedName: "GET /items/:id",
+			expectedCode: 200,
+		},
+		{
+			name:         "Continued trace",
+			traceparent:  "00-" + traceID + "-00f067aa0ba902b7-01",
+			path:         "/items/42",
+			expectedName: "GET /items/:id",
+			expectedCode: 200,
+		},
+		{
+			name:         "Unmatched route",
+			path:         "/unknown",
+			expectedName: "GET " + unmatchedRoute,
+			expectedCode: 404,
+		},
+	}
+
+	// Act
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			exporter := tracingtest.Record(t)
+			handler := New(&service.Service{}, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			r.Use(handler.traceRequest)
+			r.GET("/items/:id", func(c *gin.Context) {
+				assert.True(t, trace.SpanContextFromContext(c.Request.Context()).IsValid())
+				c.Status(http.StatusOK)
+			})
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", tc.path, nil)
+			if tc.traceparent != "" {
+				req.Header.Set("traceparent", tc.traceparent)
+			}
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			spans := exporter.GetSpans()
+			if assert.Len(t, spans, 1) {
+				assert.Equal(t, tc.expectedName, spans[0].Name)
+				assert.Contains(t, spans[0].Attributes, semconv.HTTPStatusCodeKey.Int(tc.expectedCode))
+				if tc.traceparent != "" {
+					assert.Equal(t, traceID, spans[0].SpanContext.TraceID().String())
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_requestID(t *testing.T) {
+	// Arrange
+	testTable := []struct {
+		name       string
+		headerID   string
+		expectedID string
+	}{
+		{
+			name:       "Incoming id",
+			headerID:   "abc-123",
+			expectedID: "abc-123",
+		},
+		{
+			name: "Generated id",
+		},
+		{
+			name:     "Invalid incoming id",
+			headerID: "abc 123\n",
+		},
+	}
+
+	// Act
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			handler := New(&service.Service{}, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			var loggedID interface{}
+			r.GET("/", handler.requestID, func(c *gin.Context) {
+				loggedID = logger.FromContext(c.Request.Context()).Data[logger.RequestIDField]
+				c.Status(http.StatusOK)
+			})
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/", nil)
+			if tc.headerID != "" {
+				req.Header.Set(requestIDHeader, tc.headerID)
+			}
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			id := w.Header().Get(requestIDHeader)
+			if tc.expectedID != "" {
+				assert.Equal(t, tc.expectedID, id)
+			} else {
+				assert.NotEmpty(t, id)
+				assert.NotEqual(t, tc.headerID, id)
+			}
+			assert.Equal(t, id, loggedID)
+		})
+	}
+}
+
+func TestHandler_selectWorkspace(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockWorkspace)
+
+	testCases := []struct {
+		name                 string
+		path                 string
+		header               string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Personal",
+			path: "/lists",
+			mockBehavior: func(s *mockService.MockWorkspace) {
+				s.EXPECT().GetPersonal(gomock.Any(), 1).Return(model.Workspace{ID: 1, Personal: true, Role: model.WorkspaceOwner}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "1",
+		},
+		{
+			name:   "Header",
+			path:   "/lists",
+			header: "2",
+			mockBehavior: func(s *mockService.MockWorkspace) {
+				s.EXPECT().GetByID(gomock.Any(), 1, 2).Return(model.Workspace{ID: 2, Role: model.WorkspaceViewer}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "2",
+		},
+		{
+			name:   "Path prefix",
+			path:   "/workspaces/3/lists",
+			header: "2",
+			mockBehavior: func(s *mockService.MockWorkspace) {
+				s.EXPECT().GetByID(gomock.Any(), 1, 3).Return(model.Workspace{ID: 3, Role: model.WorkspaceEditor}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "3",
+		},
+		{
+			name:                 "Invalid header",
+			path:                 "/lists",
+			header:               "invalid",
+			mockBehavior:         func(s *mockService.MockWorkspace) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidWorkspaceID.Error()),
+		},
+		{
+			name: "Not a member",
+			path: "/workspaces/3/lists",
+			mockBehavior: func(s *mockService.MockWorkspace) {
+				s.EXPECT().GetByID(gomock.Any(), 1, 3).Return(model.Workspace{}, service.ErrWorkspaceNotFound)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrWorkspaceNotFound.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			workspaces := mockService.NewMockWorkspace(c)
+			tc.mockBehavior(workspaces)
+
+			handler := New(&service.Service{Workspace: workspaces}, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			setUser := func(c *gin.Context) {
+				c.Set(userCtx, 1)
+			}
+			respondWorkspace := func(c *gin.Context) {
+				workspace, _ := c.Get(workspaceCtx)
+				c.String(200, strconv.Itoa(workspace.(model.Workspace).ID))
+			}
+			r.GET("/lists", setUser, handler.selectWorkspace, respondWorkspace)
+			r.GET("/workspaces/:workspace_id/lists", setUser, handler.selectWorkspace, respondWorkspace)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", tc.path, nil)
+			if tc.header != "" {
+				req.Header.Set(workspaceHeader, tc.header)
+			}
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
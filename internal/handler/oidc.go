@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Lapp-coder/todo-app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	oidcStateCookie = "oidc_state"
+	oidcCookiePath  = "/auth/oidc"
+)
+
+// oidcLogin godoc
+// @Summary Sign in with single sign-on
+// @Tags auth
+// @Description redirect to the identity provider, which sends the user back to the callback
+// @ID oidc-login
+// @Success 302
+// @Failure 404 {object} swagger.ErrorResponse
+// @Failure 429 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /auth/oidc/login [get]
+func (h Handler) oidcLogin(ctx *gin.Context) {
+	authURL, state, err := h.service.SSO.OIDCLogin(ctx.Request.Context())
+	if err != nil {
+		if errors.Is(err, service.ErrOIDCDisabled) {
+			respondError(ctx, http.StatusNotFound, err)
+			return
+		}
+
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	setOIDCState(ctx, state, 0)
+	ctx.Redirect(http.StatusFound, authURL)
+}
+
+// oidcCallback godoc
+// @Summary Single sign-on callback
+// @Tags auth
+// @Description complete the sign-in with the code from the identity provider, the accounts with the two-factor authentication get a challenge token
+// @ID oidc-callback
+// @Produce json
+// @Param code query string true "Authorization code"
+// @Param state query string true "State"
+// @Success 200 {object} model.SignInResult
+// @Failure 400 {object} swagger.ErrorResponse
+// @Failure 401 {object} swagger.ErrorResponse
+// @Failure 403 {object} swagger.ErrorResponse
+// @Failure 404 {object} swagger.ErrorResponse
+// @Failure 429 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /auth/oidc/callback [get]
+func (h Handler) oidcCallback(ctx *gin.Context) {
+	if ctx.Query("error") != "" {
+		respondError(ctx, http.StatusUnauthorized, errOIDCDenied)
+		return
+	}
+
+	state, err := ctx.Cookie(oidcStateCookie)
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, service.ErrInvalidOIDCState)
+		return
+	}
+
+	// The state is single-use, the callback can't be replayed from the browser history.
+	setOIDCState(ctx, "", -1)
+
+	result, err := h.service.SSO.OIDCCallback(ctx.Request.Context(), state, ctx.Query("state"), ctx.Query("code"))
+	if err != nil {
+		respondError(ctx, oidcErrorStatus(err), err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, result)
+}
+
+// setOIDCState keeps the state in a cookie sent back on the redirect from the identity
+// provider, which is a top-level navigation, so the lax same-site mode lets it through.
+func setOIDCState(ctx *gin.Context, state string, maxAge int) {
+	secure := ctx.Request.TLS != nil || ctx.GetHeader("X-Forwarded-Proto") == "https"
+
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(oidcStateCookie, state, maxAge, oidcCookiePath, "", secure, true)
+}
+
+func oidcErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrOIDCDisabled):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidOIDCState):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrOIDCSignInFailed):
+		return http.StatusUnauthorized
+	case errors.Is(err, service.ErrOIDCEmailNotVerified), errors.Is(err, service.ErrOIDCAccountNotVerified),
+		errors.Is(err, service.ErrAccountDisabled):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,298 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/Lapp-coder/todo-app/internal/config"
+	"github.com/Lapp-coder/todo-app/internal/mail/mailtest"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/oidc"
+	"github.com/Lapp-coder/todo-app/internal/oidc/oidctest"
+	"github.com/Lapp-coder/todo-app/internal/repository"
+	"github.com/Lapp-coder/todo-app/internal/repository/memory"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	mockService "github.com/Lapp-coder/todo-app/internal/service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_oidcLogin(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockSSO)
+
+	testCases := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedLocation     string
+		expectedCookie       string
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mockService.MockSSO) {
+				s.EXPECT().OIDCLogin(gomock.Any()).Return("https://idp.example.com/authorize?state=s", "state", nil)
+			},
+			expectedStatusCode: 302,
+			expectedLocation:   "https://idp.example.com/authorize?state=s",
+			expectedCookie:     "oidc_state=state; Path=/auth/oidc; HttpOnly; SameSite=Lax",
+		},
+		{
+			name: "Disabled",
+			mockBehavior: func(s *mockService.MockSSO) {
+				s.EXPECT().OIDCLogin(gomock.Any()).Return("", "", service.ErrOIDCDisabled)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrOIDCDisabled.Error()),
+		},
+		{
+			name: "Service failure",
+			mockBehavior: func(s *mockService.MockSSO) {
+				s.EXPECT().OIDCLogin(gomock.Any()).Return("", "", service.ErrFailedToStartOIDCLogin)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToStartOIDCLogin.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			sso := mockService.NewMockSSO(c)
+			tc.mockBehavior(sso)
+
+			services := &service.Service{SSO: sso}
+			handler := New(services, nil)
+
+			// Test server
+			r := gin.New()
+			r.GET("/auth/oidc/login", handler.oidcLogin)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/auth/oidc/login", nil)
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedLocation, w.Header().Get("Location"))
+			assert.Equal(t, tc.expectedCookie, w.Header().Get("Set-Cookie"))
+			if tc.expectedResponseBody != "" {
+				assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_oidcCallback(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockSSO)
+
+	testCases := []struct {
+		name                 string
+		inputQuery           string
+		inputCookie          string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			inputQuery:  "?code=code&state=s",
+			inputCookie: "state",
+			mockBehavior: func(s *mockService.MockSSO) {
+				s.EXPECT().OIDCCallback(gomock.Any(), "state", "s", "code").Return(model.SignInResult{Token: "token"}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"token":"token"}`,
+		},
+		{
+			name:                 "Denied",
+			inputQuery:           "?error=access_denied&state=s",
+			inputCookie:          "state",
+			mockBehavior:         func(s *mockService.MockSSO) {},
+			expectedStatusCode:   401,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errOIDCDenied.Error()),
+		},
+		{
+			name:                 "No state cookie",
+			inputQuery:           "?code=code&state=s",
+			mockBehavior:         func(s *mockService.MockSSO) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrInvalidOIDCState.Error()),
+		},
+		{
+			name:        "Invalid state",
+			inputQuery:  "?code=code&state=other",
+			inputCookie: "state",
+			mockBehavior: func(s *mockService.MockSSO) {
+				s.EXPECT().OIDCCallback(gomock.Any(), "state", "other", "code").Return(model.SignInResult{}, service.ErrInvalidOIDCState)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrInvalidOIDCState.Error()),
+		},
+		{
+			name:        "Exchange failure",
+			inputQuery:  "?code=code&state=s",
+			inputCookie: "state",
+			mockBehavior: func(s *mockService.MockSSO) {
+				s.EXPECT().OIDCCallback(gomock.Any(), "state", "s", "code").Return(model.SignInResult{}, service.ErrOIDCSignInFailed)
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrOIDCSignInFailed.Error()),
+		},
+		{
+			name:        "Email not verified",
+			inputQuery:  "?code=code&state=s",
+			inputCookie: "state",
+			mockBehavior: func(s *mockService.MockSSO) {
+				s.EXPECT().OIDCCallback(gomock.Any(), "state", "s", "code").Return(model.SignInResult{}, service.ErrOIDCEmailNotVerified)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrOIDCEmailNotVerified.Error()),
+		},
+		{
+			name:        "Service failure",
+			inputQuery:  "?code=code&state=s",
+			inputCookie: "state",
+			mockBehavior: func(s *mockService.MockSSO) {
+				s.EXPECT().OIDCCallback(gomock.Any(), "state", "s", "code").Return(model.SignInResult{}, service.ErrFailedToSignInWithOIDC)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToSignInWithOIDC.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			sso := mockService.NewMockSSO(c)
+			tc.mockBehavior(sso)
+
+			services := &service.Service{SSO: sso}
+			handler := New(services, nil)
+
+			// Test server
+			r := gin.New()
+			r.GET("/auth/oidc/callback", handler.oidcCallback)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/auth/oidc/callback"+tc.inputQuery, nil)
+			if tc.inputCookie != "" {
+				req.AddCookie(&http.Cookie{Name: oidcStateCookie, Value: tc.inputCookie})
+			}
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+// TestHandler_oidc signs in through the stub identity provider: the first sign-in links the
+// verified account with the same email, another user is created on their first sign-in.
+func TestHandler_oidc(t *testing.T) {
+	idp, err := oidctest.NewProvider("todo-app", "secret")
+	require.NoError(t, err)
+	defer idp.Close()
+
+	cfg := config.Service{SigningKey: "key", Salt: "salt", TokenTTL: 60, RequireVerification: true}
+	provider := oidc.New(idp.Config("http://todo.local/auth/oidc/callback"), idp.Client())
+	mailer := &mailtest.Mailer{}
+	services := service.New(repository.NewMemory(memory.NewStore()), cfg, nil, service.LogNotifier{}, mailer, provider)
+	r := New(services, nil).InitRoutes()
+
+	signIn := func(user oidctest.User) (int, string) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/auth/oidc/login", nil))
+		require.Equal(t, 302, w.Code)
+
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+
+		callback, err := idp.Authorize(w.Header().Get("Location"), user)
+		require.NoError(t, err)
+
+		u, err := url.Parse(callback)
+		require.NoError(t, err)
+
+		w = httptest.NewRecorder()
+		req := httptest.NewRequest("GET", u.RequestURI(), nil)
+		req.AddCookie(cookies[0])
+		r.ServeHTTP(w, req)
+
+		var result model.SignInResult
+		if w.Code == 200 {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		}
+
+		return w.Code, result.Token
+	}
+
+	userID := func(token string) int {
+		session, err := services.Authorization.ParseToken(context.Background(), token)
+		require.NoError(t, err)
+		return session.UserID
+	}
+
+	// The account signed up with the password isn't linked until its email is verified, or
+	// whoever signed up with somebody else's email would share the account with them.
+	id, err := services.Authorization.CreateUser(context.Background(), model.User{Name: "test", Email: "test@mail.ru", Password: "testing"})
+	require.NoError(t, err)
+
+	status, _ := signIn(oidctest.User{Subject: "1", Email: "test@mail.ru", EmailVerified: true})
+	assert.Equal(t, 403, status)
+
+	messages := mailer.Messages()
+	require.Len(t, messages, 1)
+	verification := regexp.MustCompile(`token=(\S+)`).FindStringSubmatch(messages[0].Body)
+	require.Len(t, verification, 2)
+	require.NoError(t, services.Authorization.VerifyEmail(context.Background(), verification[1]))
+
+	status, token := signIn(oidctest.User{Subject: "1", Email: "test@mail.ru", EmailVerified: true})
+	require.Equal(t, 200, status)
+	assert.Equal(t, id, userID(token))
+
+	_, err = services.Authorization.GenerateToken(context.Background(), "test@mail.ru", "testing")
+	assert.NoError(t, err)
+
+	// Once linked, the account is found by the subject even after the email has changed.
+	status, token = signIn(oidctest.User{Subject: "1", Email: "renamed@mail.ru", EmailVerified: true})
+	require.Equal(t, 200, status)
+	assert.Equal(t, id, userID(token))
+
+	status, _ = signIn(oidctest.User{Subject: "2", Email: "new@mail.ru"})
+	assert.Equal(t, 403, status)
+
+	status, token = signIn(oidctest.User{Subject: "2", Email: "new@mail.ru", EmailVerified: true, Name: "New"})
+	require.Equal(t, 200, status)
+	assert.NotEqual(t, id, userID(token))
+
+	// The state cookie is single-use.
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/auth/oidc/callback?code=code&state=state", nil))
+	assert.Equal(t, 400, w.Code)
+}
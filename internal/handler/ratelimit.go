@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Lapp-coder/todo-app/internal/logger"
+	"github.com/Lapp-coder/todo-app/internal/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	rateLimitLimitHeader     = "X-RateLimit-Limit"
+	rateLimitRemainingHeader = "X-RateLimit-Remaining"
+	rateLimitResetHeader     = "X-RateLimit-Reset"
+	retryAfterHeader         = "Retry-After"
+)
+
+// limitByIP limits the requests of a client IP under the policy.
+func (h Handler) limitByIP(policy string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		h.allow(ctx, policy, ctx.ClientIP())
+	}
+}
+
+// limitByUser limits the requests of the user set by userAuthentication.
+func (h Handler) limitByUser(ctx *gin.Context) {
+	h.allow(ctx, ratelimit.PolicyAPIUser, strconv.Itoa(h.getUserID(ctx)))
+}
+
+// allow takes a token for the key, sets the rate limit headers and aborts the request
+// with 429 when the limit is exceeded. The requests are let through when the store fails,
+// so an outage of a shared store doesn't take the app down with it.
+func (h Handler) allow(ctx *gin.Context, policy, key string) bool {
+	if h.limiter == nil {
+		return true
+	}
+
+	result, err := h.limiter.Allow(ctx.Request.Context(), policy, key)
+	if err != nil {
+		logger.FromContext(ctx.Request.Context()).WithError(err).WithField("policy", policy).Error("failed to check rate limit")
+		return true
+	}
+
+	if result.Limit == 0 {
+		return true
+	}
+
+	ctx.Header(rateLimitLimitHeader, strconv.Itoa(result.Limit))
+	ctx.Header(rateLimitRemainingHeader, strconv.Itoa(result.Remaining))
+	ctx.Header(rateLimitResetHeader, strconv.Itoa(ratelimit.Seconds(result.Reset)))
+
+	if !result.Allowed {
+		ctx.Header(retryAfterHeader, strconv.Itoa(ratelimit.Seconds(result.RetryAfter)))
+		respondError(ctx, http.StatusTooManyRequests, errTooManyRequests)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/ratelimit"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	mockService "github.com/Lapp-coder/todo-app/internal/service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Policy) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store is down")
+}
+
+func TestHandler_allow(t *testing.T) {
+	// Arrange
+	policies := map[string]ratelimit.Policy{ratelimit.PolicyAuthIP: {Requests: 1, Period: time.Minute}}
+
+	testTable := []struct {
+		name                 string
+		limiter              *ratelimit.Limiter
+		requests             int
+		expectedStatusCode   int
+		expectedHeaders      map[string]string
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Allowed",
+			limiter:              ratelimit.New(ratelimit.NewMemoryStore(), policies),
+			requests:             1,
+			expectedStatusCode:   200,
+			expectedHeaders:      map[string]string{"X-RateLimit-Limit": "1", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "60"},
+			expectedResponseBody: "ok",
+		},
+		{
+			name:                 "Too many requests",
+			limiter:              ratelimit.New(ratelimit.NewMemoryStore(), policies),
+			requests:             2,
+			expectedStatusCode:   429,
+			expectedHeaders:      map[string]string{"X-RateLimit-Remaining": "0", "Retry-After": "60"},
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errTooManyRequests.Error()),
+		},
+		{
+			name:                 "Disabled",
+			requests:             2,
+			expectedStatusCode:   200,
+			expectedHeaders:      map[string]string{"X-RateLimit-Limit": "", "Retry-After": ""},
+			expectedResponseBody: "ok",
+		},
+		{
+			name:                 "Store failure",
+			limiter:              ratelimit.New(failingStore{}, policies),
+			requests:             2,
+			expectedStatusCode:   200,
+			expectedHeaders:      map[string]string{"X-RateLimit-Limit": ""},
+			expectedResponseBody: "ok",
+		},
+	}
+
+	// Act
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			handler := New(&service.Service{}, tc.limiter)
+
+			// Test server
+			r := gin.New()
+			r.GET("/auth", handler.limitByIP(ratelimit.PolicyAuthIP), func(ctx *gin.Context) {
+				ctx.String(http.StatusOK, "ok")
+			})
+
+			// Test request
+			var w *httptest.ResponseRecorder
+			for i := 0; i < tc.requests; i++ {
+				w = httptest.NewRecorder()
+				req := httptest.NewRequest("GET", "/auth", nil)
+				r.ServeHTTP(w, req)
+			}
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			for name, value := range tc.expectedHeaders {
+				assert.Equal(t, value, w.Header().Get(name), name)
+			}
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+// TestHandler_limitGuesses checks the limits of the routes taking the passwords and the codes
+// outside of the sign-in: every CalDAV request and the two-factor codes of a challenge.
+func TestHandler_limitGuesses(t *testing.T) {
+	// Arrange
+	policies := map[string]ratelimit.Policy{ratelimit.PolicyAuthEmail: {Requests: 1, Period: time.Minute}}
+
+	// Init dependency
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	auth := mockService.NewMockAuthorization(c)
+	auth.EXPECT().Authenticate(gomock.Any(), "test@mail.ru", "wrong").Return(0, service.ErrIncorrectEmailOrPassword)
+	auth.EXPECT().CompleteSignIn(gomock.Any(), "challenge", "000000").Return("", service.ErrInvalidTwoFactorCode)
+	auth.EXPECT().CompleteSignIn(gomock.Any(), "other", "000000").Return("", service.ErrInvalidTwoFactorCode)
+
+	handler := New(&service.Service{Authorization: auth}, ratelimit.New(ratelimit.NewMemoryStore(), policies))
+
+	// Test server
+	r := gin.New()
+	r.GET("/caldav/", handler.basicAuthentication)
+	r.POST("/auth/sign-in/2fa", handler.completeSignIn)
+
+	caldav := func(email string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/caldav/", nil)
+		req.SetBasicAuth(email, "wrong")
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	completeSignIn := func(challenge string) int {
+		w := httptest.NewRecorder()
+		body := fmt.Sprintf(`{"challenge_token": "%s", "code": "000000"}`, challenge)
+		r.ServeHTTP(w, httptest.NewRequest("POST", "/auth/sign-in/2fa", bytes.NewBufferString(body)))
+		return w.Code
+	}
+
+	// Perform request & Assert
+	assert.Equal(t, 401, caldav("test@mail.ru"))
+	assert.Equal(t, 429, caldav("Test@mail.ru"))
+
+	assert.Equal(t, 401, completeSignIn("challenge"))
+	assert.Equal(t, 429, completeSignIn("challenge"))
+	assert.Equal(t, 401, completeSignIn("other"))
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	_ "github.com/Lapp-coder/todo-app/docs/swagger"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// createAccessToken godoc
+// @Summary Create personal access token
+// @Security ApiKeyAuth
+// @Tags tokens
+// @Description create a personal access token for the scripts, it's only shown in this response
+// @ID create-access-token
+// @Accept json
+// @Produce json
+// @Param input body model.CreateAccessToken true "Token name, scopes and expiry"
+// @Success 201 {object} model.CreatedAccessToken
+// @Failure 400 {object} swagger.ErrorResponse
+// @Failure 401,403 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /api/me/tokens [post]
+func (h Handler) createAccessToken(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	var req model.CreateAccessToken
+	if err := ctx.BindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidInputBody)
+		return
+	}
+
+	token, err := h.service.AccessToken.Create(ctx.Request.Context(), userID, req)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(ctx, http.StatusCreated, token)
+}
+
+// getAccessTokens godoc
+// @Summary Get personal access tokens
+// @Security ApiKeyAuth
+// @Tags tokens
+// @Description get the personal access tokens, without the tokens themselves
+// @ID get-access-tokens
+// @Produce json
+// @Success 200 {object} swagger.GetAccessTokensResponse
+// @Failure 401,403 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /api/me/tokens [get]
+func (h Handler) getAccessTokens(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	tokens, err := h.service.AccessToken.GetAll(ctx.Request.Context(), userID)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	if tokens == nil {
+		tokens = []model.AccessToken{}
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"tokens": tokens,
+	})
+}
+
+// getAccessTokenByID godoc
+// @Summary Get personal access token by id
+// @Security ApiKeyAuth
+// @Tags tokens
+// @Description get a personal access token, without the token itself
+// @ID get-access-token-by-id
+// @Produce json
+// @Param id path int true "Token id"
+// @Success 200 {object} swagger.GetAccessTokenResponse
+// @Failure 400 {object} swagger.ErrorResponse
+// @Failure 401,403,404 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /api/me/tokens/{id} [get]
+func (h Handler) getAccessTokenByID(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	tokenID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidParamID)
+		return
+	}
+
+	token, err := h.service.AccessToken.GetByID(ctx.Request.Context(), userID, tokenID)
+	if err != nil {
+		if errors.Is(err, service.ErrAccessTokenNotFound) {
+			respondError(ctx, http.StatusNotFound, err)
+			return
+		}
+
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"token": token,
+	})
+}
+
+// deleteAccessToken godoc
+// @Summary Delete personal access token
+// @Security ApiKeyAuth
+// @Tags tokens
+// @Description revoke a personal access token
+// @ID delete-access-token
+// @Produce json
+// @Param id path int true "Token id"
+// @Success 200 {string} string "Result"
+// @Failure 400 {object} swagger.ErrorResponse
+// @Failure 401,403,404 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /api/me/tokens/{id} [delete]
+func (h Handler) deleteAccessToken(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	tokenID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidParamID)
+		return
+	}
+
+	if err = h.service.AccessToken.Delete(ctx.Request.Context(), userID, tokenID); err != nil {
+		if errors.Is(err, service.ErrAccessTokenNotFound) {
+			respondError(ctx, http.StatusNotFound, err)
+			return
+		}
+
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"result": "the token deletion was successful",
+	})
+}
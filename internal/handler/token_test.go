@@ -0,0 +1,367 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/config"
+	"github.com/Lapp-coder/todo-app/internal/mail/mailtest"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/repository"
+	"github.com/Lapp-coder/todo-app/internal/repository/memory"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	mockService "github.com/Lapp-coder/todo-app/internal/service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_createAccessToken(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockAccessToken, userID interface{})
+
+	createdAt := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		name                 string
+		inputUserID          interface{}
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			inputUserID: 1,
+			inputBody:   `{"name": "ci", "scopes": ["lists:read", "items:write"]}`,
+			mockBehavior: func(s *mockService.MockAccessToken, userID interface{}) {
+				input := model.CreateAccessToken{Name: "ci", Scopes: []string{"lists:read", "items:write"}}
+				s.EXPECT().Create(gomock.Any(), userID, input).Return(model.CreatedAccessToken{
+					AccessToken: model.AccessToken{ID: 1, Name: "ci", Scopes: model.Scopes{"lists:read", "items:write"}, CreatedAt: createdAt},
+					Token:       "todo_pat_token",
+				}, nil)
+			},
+			expectedStatusCode: 201,
+			expectedResponseBody: `{"id":1,"name":"ci","scopes":["lists:read","items:write"],"created_at":"2021-01-02T03:04:05Z",` +
+				`"token":"todo_pat_token"}`,
+		},
+		{
+			name:                 "Invalid user id",
+			inputUserID:          "invalid",
+			inputBody:            `{"name": "ci", "scopes": ["lists:read"]}`,
+			mockBehavior:         func(s *mockService.MockAccessToken, userID interface{}) {},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errFailedToGetUserID.Error()),
+		},
+		{
+			name:                 "Unknown scope",
+			inputUserID:          1,
+			inputBody:            `{"name": "ci", "scopes": ["lists:admin"]}`,
+			mockBehavior:         func(s *mockService.MockAccessToken, userID interface{}) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidInputBody.Error()),
+		},
+		{
+			name:                 "No scopes",
+			inputUserID:          1,
+			inputBody:            `{"name": "ci", "scopes": []}`,
+			mockBehavior:         func(s *mockService.MockAccessToken, userID interface{}) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidInputBody.Error()),
+		},
+		{
+			name:                 "Negative expiry",
+			inputUserID:          1,
+			inputBody:            `{"name": "ci", "scopes": ["lists:read"], "expires_in_days": -1}`,
+			mockBehavior:         func(s *mockService.MockAccessToken, userID interface{}) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidInputBody.Error()),
+		},
+		{
+			name:        "Service failure",
+			inputUserID: 1,
+			inputBody:   `{"name": "ci", "scopes": ["lists:read"], "expires_in_days": 30}`,
+			mockBehavior: func(s *mockService.MockAccessToken, userID interface{}) {
+				input := model.CreateAccessToken{Name: "ci", Scopes: []string{"lists:read"}, ExpiresInDays: 30}
+				s.EXPECT().Create(gomock.Any(), userID, input).Return(model.CreatedAccessToken{}, service.ErrFailedToCreateAccessToken)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToCreateAccessToken.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			tokens := mockService.NewMockAccessToken(c)
+			tc.mockBehavior(tokens, tc.inputUserID)
+
+			services := &service.Service{AccessToken: tokens}
+			handler := New(services, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			r.POST(
+				"/api/me/tokens",
+				func(c *gin.Context) {
+					c.Set(userCtx, tc.inputUserID)
+				},
+				handler.createAccessToken)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/me/tokens", bytes.NewBufferString(tc.inputBody))
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getAccessTokens(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockAccessToken, userID interface{})
+
+	createdAt := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		name                 string
+		inputUserID          interface{}
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			inputUserID: 1,
+			mockBehavior: func(s *mockService.MockAccessToken, userID interface{}) {
+				s.EXPECT().GetAll(gomock.Any(), userID).Return([]model.AccessToken{
+					{ID: 1, Name: "ci", Scopes: model.Scopes{"lists:read"}, CreatedAt: createdAt, LastUsedAt: &createdAt},
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"tokens":[{"id":1,"name":"ci","scopes":["lists:read"],` +
+				`"last_used_at":"2021-01-02T03:04:05Z","created_at":"2021-01-02T03:04:05Z"}]}`,
+		},
+		{
+			name:        "No tokens",
+			inputUserID: 1,
+			mockBehavior: func(s *mockService.MockAccessToken, userID interface{}) {
+				s.EXPECT().GetAll(gomock.Any(), userID).Return(nil, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"tokens":[]}`,
+		},
+		{
+			name:        "Service failure",
+			inputUserID: 1,
+			mockBehavior: func(s *mockService.MockAccessToken, userID interface{}) {
+				s.EXPECT().GetAll(gomock.Any(), userID).Return(nil, service.ErrFailedToGetAccessTokens)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToGetAccessTokens.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			tokens := mockService.NewMockAccessToken(c)
+			tc.mockBehavior(tokens, tc.inputUserID)
+
+			services := &service.Service{AccessToken: tokens}
+			handler := New(services, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			r.GET(
+				"/api/me/tokens",
+				func(c *gin.Context) {
+					c.Set(userCtx, tc.inputUserID)
+				},
+				handler.getAccessTokens)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/api/me/tokens", nil)
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_deleteAccessToken(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockAccessToken, userID interface{})
+
+	testCases := []struct {
+		name                 string
+		inputUserID          interface{}
+		inputTokenID         string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:         "OK",
+			inputUserID:  1,
+			inputTokenID: "1",
+			mockBehavior: func(s *mockService.MockAccessToken, userID interface{}) {
+				s.EXPECT().Delete(gomock.Any(), userID, 1).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"result":"the token deletion was successful"}`,
+		},
+		{
+			name:                 "Invalid token id",
+			inputUserID:          1,
+			inputTokenID:         "invalid",
+			mockBehavior:         func(s *mockService.MockAccessToken, userID interface{}) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidParamID.Error()),
+		},
+		{
+			name:         "Not found",
+			inputUserID:  1,
+			inputTokenID: "2",
+			mockBehavior: func(s *mockService.MockAccessToken, userID interface{}) {
+				s.EXPECT().Delete(gomock.Any(), userID, 2).Return(service.ErrAccessTokenNotFound)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrAccessTokenNotFound.Error()),
+		},
+		{
+			name:         "Service failure",
+			inputUserID:  1,
+			inputTokenID: "1",
+			mockBehavior: func(s *mockService.MockAccessToken, userID interface{}) {
+				s.EXPECT().Delete(gomock.Any(), userID, 1).Return(service.ErrFailedToDeleteAccessToken)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToDeleteAccessToken.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			tokens := mockService.NewMockAccessToken(c)
+			tc.mockBehavior(tokens, tc.inputUserID)
+
+			services := &service.Service{AccessToken: tokens}
+			handler := New(services, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			r.DELETE(
+				"/api/me/tokens/:id",
+				func(c *gin.Context) {
+					c.Set(userCtx, tc.inputUserID)
+				},
+				handler.deleteAccessToken)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", "/api/me/tokens/"+tc.inputTokenID, nil)
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+// TestHandler_accessTokens uses a personal access token on the routes its scopes allow,
+// and checks it stops working once deleted.
+func TestHandler_accessTokens(t *testing.T) {
+	cfg := config.Service{SigningKey: "key", Salt: "salt", TokenTTL: 60}
+	services := service.New(repository.NewMemory(memory.NewStore()), cfg, nil, service.LogNotifier{}, &mailtest.Mailer{}, nil)
+	r := New(services, nil).InitRoutes()
+
+	request := func(method, target, token, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request("POST", "/auth/sign-up", "", `{"name": "test", "email": "test@mail.ru", "password": "testing"}`)
+	require.Equal(t, 201, w.Code)
+
+	w = request("POST", "/auth/sign-in", "", `{"email": "test@mail.ru", "password": "testing"}`)
+	require.Equal(t, 200, w.Code)
+
+	var session model.SignInResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &session))
+
+	w = request("POST", "/api/lists/", session.Token, `{"title": "list"}`)
+	require.Equal(t, 201, w.Code)
+
+	w = request("POST", "/api/me/tokens", session.Token, `{"name": "ci", "scopes": ["lists:read"], "expires_in_days": 30}`)
+	require.Equal(t, 201, w.Code)
+
+	var created model.CreatedAccessToken
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, model.Scopes{model.ScopeListsRead}, created.Scopes)
+	require.NotNil(t, created.ExpiresAt)
+
+	assert.Equal(t, 200, request("GET", "/api/lists/", created.Token, "").Code)
+	assert.Equal(t, 403, request("POST", "/api/lists/", created.Token, `{"title": "other"}`).Code)
+	assert.Equal(t, 403, request("GET", "/api/items/", created.Token, "").Code)
+
+	// The tokens can't manage the account, or mint the tokens with more scopes.
+	assert.Equal(t, 403, request("GET", "/api/me/tokens", created.Token, "").Code)
+	assert.Equal(t, 403, request("POST", "/graphql", created.Token, `{"query": "{ lists { id } }"}`).Code)
+
+	w = request("GET", "/api/me/tokens", session.Token, "")
+	require.Equal(t, 200, w.Code)
+
+	var listed struct {
+		Tokens []model.AccessToken `json:"tokens"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	require.Len(t, listed.Tokens, 1)
+	assert.Equal(t, created.ID, listed.Tokens[0].ID)
+	assert.NotNil(t, listed.Tokens[0].LastUsedAt)
+	assert.NotContains(t, w.Body.String(), created.Token)
+
+	w = request("DELETE", fmt.Sprintf("/api/me/tokens/%d", created.ID), session.Token, "")
+	require.Equal(t, 200, w.Code)
+
+	assert.Equal(t, 401, request("GET", "/api/lists/", created.Token, "").Code)
+}
@@ -0,0 +1,400 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	_ "github.com/Lapp-coder/todo-app/docs/swagger"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// createWorkspace godoc
+// @Summary Create workspace
+// @Security ApiKeyAuth
+// @Tags workspaces
+// @Description create a workspace with the user as its owner
+// @ID create-workspace
+// @Accept json
+// @Produce json
+// @Param input body model.CreateWorkspace true "Workspace name"
+// @Success 201 {integer} integer "Workspace id"
+// @Failure 400 {object} swagger.ErrorResponse
+// @Failure 401,403 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /api/workspaces/ [post]
+func (h Handler) createWorkspace(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	var req model.CreateWorkspace
+	if err := ctx.BindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidInputBody)
+		return
+	}
+
+	workspaceID, err := h.service.Workspace.Create(ctx.Request.Context(), userID, req)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(ctx, http.StatusCreated, gin.H{
+		"workspace_id": workspaceID,
+	})
+}
+
+// getWorkspaces godoc
+// @Summary Get workspaces
+// @Security ApiKeyAuth
+// @Tags workspaces
+// @Description get the workspaces the user is a member of, with their role
+// @ID get-workspaces
+// @Produce json
+// @Success 200 {object} swagger.GetWorkspacesResponse
+// @Failure 401,403 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /api/workspaces/ [get]
+func (h Handler) getWorkspaces(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	workspaces, err := h.service.Workspace.GetAll(ctx.Request.Context(), userID)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	if workspaces == nil {
+		workspaces = []model.Workspace{}
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"workspaces": workspaces,
+	})
+}
+
+// getWorkspaceByID godoc
+// @Summary Get workspace by id
+// @Security ApiKeyAuth
+// @Tags workspaces
+// @Description get a workspace the user is a member of, with their role
+// @ID get-workspace-by-id
+// @Produce json
+// @Param workspace_id path int true "Workspace id"
+// @Success 200 {object} swagger.GetWorkspaceResponse
+// @Failure 400 {object} swagger.ErrorResponse
+// @Failure 401,403,404 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /api/workspaces/{workspace_id} [get]
+func (h Handler) getWorkspaceByID(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	workspaceID, err := strconv.Atoi(ctx.Param("workspace_id"))
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidParamID)
+		return
+	}
+
+	workspace, err := h.service.Workspace.GetByID(ctx.Request.Context(), userID, workspaceID)
+	if err != nil {
+		respondWorkspaceError(ctx, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"workspace": workspace,
+	})
+}
+
+// updateWorkspace godoc
+// @Summary Update workspace
+// @Security ApiKeyAuth
+// @Tags workspaces
+// @Description rename a workspace, only the owners may do it
+// @ID update-workspace
+// @Accept json
+// @Produce json
+// @Param workspace_id path int true "Workspace id"
+// @Param input body model.UpdateWorkspace true "Workspace name"
+// @Success 200 {string} string "Result"
+// @Failure 400 {object} swagger.ErrorResponse
+// @Failure 401,403,404 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /api/workspaces/{workspace_id} [put]
+func (h Handler) updateWorkspace(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	workspaceID, err := strconv.Atoi(ctx.Param("workspace_id"))
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidParamID)
+		return
+	}
+
+	var req model.UpdateWorkspace
+	if err = ctx.BindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidInputBody)
+		return
+	}
+
+	if err = h.service.Workspace.Update(ctx.Request.Context(), userID, workspaceID, req); err != nil {
+		respondWorkspaceError(ctx, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"result": "the workspace update was successful",
+	})
+}
+
+// deleteWorkspace godoc
+// @Summary Delete workspace
+// @Security ApiKeyAuth
+// @Tags workspaces
+// @Description delete a workspace with its lists and items, only the owners may do it and the personal workspaces are kept
+// @ID delete-workspace
+// @Produce json
+// @Param workspace_id path int true "Workspace id"
+// @Success 200 {string} string "Result"
+// @Failure 400 {object} swagger.ErrorResponse
+// @Failure 401,403,404,409 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /api/workspaces/{workspace_id} [delete]
+func (h Handler) deleteWorkspace(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	workspaceID, err := strconv.Atoi(ctx.Param("workspace_id"))
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidParamID)
+		return
+	}
+
+	if err = h.service.Workspace.Delete(ctx.Request.Context(), userID, workspaceID); err != nil {
+		respondWorkspaceError(ctx, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"result": "the workspace deletion was successful",
+	})
+}
+
+// getWorkspaceMembers godoc
+// @Summary Get workspace members
+// @Security ApiKeyAuth
+// @Tags workspaces
+// @Description get the members of a workspace with their roles
+// @ID get-workspace-members
+// @Produce json
+// @Param workspace_id path int true "Workspace id"
+// @Success 200 {object} swagger.GetWorkspaceMembersResponse
+// @Failure 400 {object} swagger.ErrorResponse
+// @Failure 401,403,404 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /api/workspaces/{workspace_id}/members [get]
+func (h Handler) getWorkspaceMembers(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	workspaceID, err := strconv.Atoi(ctx.Param("workspace_id"))
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidParamID)
+		return
+	}
+
+	members, err := h.service.Workspace.GetMembers(ctx.Request.Context(), userID, workspaceID)
+	if err != nil {
+		respondWorkspaceError(ctx, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"members": members,
+	})
+}
+
+// addWorkspaceMember godoc
+// @Summary Add workspace member
+// @Security ApiKeyAuth
+// @Tags workspaces
+// @Description add the user with the email to a workspace, only the owners may do it
+// @ID add-workspace-member
+// @Accept json
+// @Produce json
+// @Param workspace_id path int true "Workspace id"
+// @Param input body model.AddWorkspaceMember true "Email and role of the member"
+// @Success 201 {string} string "Result"
+// @Failure 400 {object} swagger.ErrorResponse
+// @Failure 401,403,404,409 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /api/workspaces/{workspace_id}/members [post]
+func (h Handler) addWorkspaceMember(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	workspaceID, err := strconv.Atoi(ctx.Param("workspace_id"))
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidParamID)
+		return
+	}
+
+	var req model.AddWorkspaceMember
+	if err = ctx.BindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidInputBody)
+		return
+	}
+
+	if err = h.service.Workspace.AddMember(ctx.Request.Context(), userID, workspaceID, req); err != nil {
+		respondWorkspaceError(ctx, err)
+		return
+	}
+
+	respond(ctx, http.StatusCreated, gin.H{
+		"result": "the member was added to the workspace",
+	})
+}
+
+// setWorkspaceMemberRole godoc
+// @Summary Set workspace member role
+// @Security ApiKeyAuth
+// @Tags workspaces
+// @Description change the role of a member, only the owners may do it and the workspace keeps an owner
+// @ID set-workspace-member-role
+// @Accept json
+// @Produce json
+// @Param workspace_id path int true "Workspace id"
+// @Param user_id path int true "User id of the member"
+// @Param input body model.SetWorkspaceRole true "Role of the member"
+// @Success 200 {string} string "Result"
+// @Failure 400 {object} swagger.ErrorResponse
+// @Failure 401,403,404,409 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /api/workspaces/{workspace_id}/members/{user_id} [put]
+func (h Handler) setWorkspaceMemberRole(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	workspaceID, err := strconv.Atoi(ctx.Param("workspace_id"))
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidParamID)
+		return
+	}
+
+	memberID, err := strconv.Atoi(ctx.Param("user_id"))
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidParamID)
+		return
+	}
+
+	var req model.SetWorkspaceRole
+	if err = ctx.BindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidInputBody)
+		return
+	}
+
+	if err = h.service.Workspace.SetMemberRole(ctx.Request.Context(), userID, workspaceID, memberID, req.Role); err != nil {
+		respondWorkspaceError(ctx, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"result": "the role of the member was changed",
+	})
+}
+
+// removeWorkspaceMember godoc
+// @Summary Remove workspace member
+// @Security ApiKeyAuth
+// @Tags workspaces
+// @Description remove a member from a workspace, the owners may remove anyone and the rest of the members may leave
+// @ID remove-workspace-member
+// @Produce json
+// @Param workspace_id path int true "Workspace id"
+// @Param user_id path int true "User id of the member"
+// @Success 200 {string} string "Result"
+// @Failure 400 {object} swagger.ErrorResponse
+// @Failure 401,403,404,409 {object} swagger.ErrorResponse
+// @Failure 500 {object} swagger.ErrorResponse
+// @Failure default {object} swagger.ErrorResponse
+// @Router /api/workspaces/{workspace_id}/members/{user_id} [delete]
+func (h Handler) removeWorkspaceMember(ctx *gin.Context) {
+	userID := h.getUserID(ctx)
+	if userID == 0 {
+		respondError(ctx, http.StatusInternalServerError, errFailedToGetUserID)
+		return
+	}
+
+	workspaceID, err := strconv.Atoi(ctx.Param("workspace_id"))
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidParamID)
+		return
+	}
+
+	memberID, err := strconv.Atoi(ctx.Param("user_id"))
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, errInvalidParamID)
+		return
+	}
+
+	if err = h.service.Workspace.RemoveMember(ctx.Request.Context(), userID, workspaceID, memberID); err != nil {
+		respondWorkspaceError(ctx, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, gin.H{
+		"result": "the member was removed from the workspace",
+	})
+}
+
+// respondWorkspaceError responds to the failed requests to the workspaces.
+func respondWorkspaceError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrWorkspaceNotFound), errors.Is(err, service.ErrMemberNotFound),
+		errors.Is(err, service.ErrUserNotFound):
+		respondError(ctx, http.StatusNotFound, err)
+	case errors.Is(err, service.ErrWorkspaceReadOnly):
+		respondError(ctx, http.StatusForbidden, err)
+	case errors.Is(err, service.ErrPersonalWorkspace), errors.Is(err, service.ErrLastWorkspaceOwner),
+		errors.Is(err, service.ErrAlreadyMember):
+		respondError(ctx, http.StatusConflict, err)
+	default:
+		respondError(ctx, http.StatusInternalServerError, err)
+	}
+}
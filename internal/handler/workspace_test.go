@@ -0,0 +1,439 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Lapp-coder/todo-app/internal/config"
+	"github.com/Lapp-coder/todo-app/internal/mail/mailtest"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/repository"
+	"github.com/Lapp-coder/todo-app/internal/repository/memory"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	mockService "github.com/Lapp-coder/todo-app/internal/service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_createWorkspace(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockWorkspace, userID interface{})
+
+	testCases := []struct {
+		name                 string
+		inputUserID          interface{}
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			inputUserID: 1,
+			inputBody:   `{"name": "team"}`,
+			mockBehavior: func(s *mockService.MockWorkspace, userID interface{}) {
+				s.EXPECT().Create(gomock.Any(), userID, model.CreateWorkspace{Name: "team"}).Return(2, nil)
+			},
+			expectedStatusCode:   201,
+			expectedResponseBody: `{"workspace_id":2}`,
+		},
+		{
+			name:                 "Invalid user id",
+			inputUserID:          "invalid",
+			inputBody:            `{"name": "team"}`,
+			mockBehavior:         func(s *mockService.MockWorkspace, userID interface{}) {},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errFailedToGetUserID.Error()),
+		},
+		{
+			name:                 "Empty name",
+			inputUserID:          1,
+			inputBody:            `{"name": ""}`,
+			mockBehavior:         func(s *mockService.MockWorkspace, userID interface{}) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidInputBody.Error()),
+		},
+		{
+			name:        "Service failure",
+			inputUserID: 1,
+			inputBody:   `{"name": "team"}`,
+			mockBehavior: func(s *mockService.MockWorkspace, userID interface{}) {
+				s.EXPECT().Create(gomock.Any(), userID, model.CreateWorkspace{Name: "team"}).Return(0, service.ErrFailedToCreateWorkspace)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToCreateWorkspace.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			workspaces := mockService.NewMockWorkspace(c)
+			tc.mockBehavior(workspaces, tc.inputUserID)
+
+			services := &service.Service{Workspace: workspaces}
+			handler := New(services, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			r.POST(
+				"/api/workspaces/",
+				func(c *gin.Context) {
+					c.Set(userCtx, tc.inputUserID)
+				},
+				handler.createWorkspace)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/workspaces/", bytes.NewBufferString(tc.inputBody))
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_addWorkspaceMember(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockWorkspace, input model.AddWorkspaceMember)
+
+	testCases := []struct {
+		name                 string
+		inputWorkspaceID     string
+		inputBody            string
+		inputMember          model.AddWorkspaceMember
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:             "OK",
+			inputWorkspaceID: "2",
+			inputBody:        `{"email": "test@mail.ru", "role": "viewer"}`,
+			inputMember:      model.AddWorkspaceMember{Email: "test@mail.ru", Role: model.WorkspaceViewer},
+			mockBehavior: func(s *mockService.MockWorkspace, input model.AddWorkspaceMember) {
+				s.EXPECT().AddMember(gomock.Any(), 1, 2, input).Return(nil)
+			},
+			expectedStatusCode:   201,
+			expectedResponseBody: `{"result":"the member was added to the workspace"}`,
+		},
+		{
+			name:                 "Invalid workspace id",
+			inputWorkspaceID:     "invalid",
+			inputBody:            `{"email": "test@mail.ru", "role": "viewer"}`,
+			mockBehavior:         func(s *mockService.MockWorkspace, input model.AddWorkspaceMember) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidParamID.Error()),
+		},
+		{
+			name:                 "Unknown role",
+			inputWorkspaceID:     "2",
+			inputBody:            `{"email": "test@mail.ru", "role": "admin"}`,
+			mockBehavior:         func(s *mockService.MockWorkspace, input model.AddWorkspaceMember) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidInputBody.Error()),
+		},
+		{
+			name:             "Not an owner",
+			inputWorkspaceID: "2",
+			inputBody:        `{"email": "test@mail.ru", "role": "editor"}`,
+			inputMember:      model.AddWorkspaceMember{Email: "test@mail.ru", Role: model.WorkspaceEditor},
+			mockBehavior: func(s *mockService.MockWorkspace, input model.AddWorkspaceMember) {
+				s.EXPECT().AddMember(gomock.Any(), 1, 2, input).Return(service.ErrWorkspaceReadOnly)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrWorkspaceReadOnly.Error()),
+		},
+		{
+			name:             "Unknown user",
+			inputWorkspaceID: "2",
+			inputBody:        `{"email": "test@mail.ru", "role": "viewer"}`,
+			inputMember:      model.AddWorkspaceMember{Email: "test@mail.ru", Role: model.WorkspaceViewer},
+			mockBehavior: func(s *mockService.MockWorkspace, input model.AddWorkspaceMember) {
+				s.EXPECT().AddMember(gomock.Any(), 1, 2, input).Return(service.ErrUserNotFound)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrUserNotFound.Error()),
+		},
+		{
+			name:             "Personal workspace",
+			inputWorkspaceID: "2",
+			inputBody:        `{"email": "test@mail.ru", "role": "viewer"}`,
+			inputMember:      model.AddWorkspaceMember{Email: "test@mail.ru", Role: model.WorkspaceViewer},
+			mockBehavior: func(s *mockService.MockWorkspace, input model.AddWorkspaceMember) {
+				s.EXPECT().AddMember(gomock.Any(), 1, 2, input).Return(service.ErrPersonalWorkspace)
+			},
+			expectedStatusCode:   409,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrPersonalWorkspace.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			workspaces := mockService.NewMockWorkspace(c)
+			tc.mockBehavior(workspaces, tc.inputMember)
+
+			services := &service.Service{Workspace: workspaces}
+			handler := New(services, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			r.POST(
+				"/api/workspaces/:workspace_id/members",
+				func(c *gin.Context) {
+					c.Set(userCtx, 1)
+				},
+				handler.addWorkspaceMember)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", fmt.Sprintf("/api/workspaces/%s/members", tc.inputWorkspaceID),
+				bytes.NewBufferString(tc.inputBody))
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_removeWorkspaceMember(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockWorkspace)
+
+	testCases := []struct {
+		name                 string
+		inputMemberID        string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:          "OK",
+			inputMemberID: "3",
+			mockBehavior: func(s *mockService.MockWorkspace) {
+				s.EXPECT().RemoveMember(gomock.Any(), 1, 2, 3).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"result":"the member was removed from the workspace"}`,
+		},
+		{
+			name:                 "Invalid user id",
+			inputMemberID:        "invalid",
+			mockBehavior:         func(s *mockService.MockWorkspace) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, errInvalidParamID.Error()),
+		},
+		{
+			name:          "Not a member",
+			inputMemberID: "3",
+			mockBehavior: func(s *mockService.MockWorkspace) {
+				s.EXPECT().RemoveMember(gomock.Any(), 1, 2, 3).Return(service.ErrMemberNotFound)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrMemberNotFound.Error()),
+		},
+		{
+			name:          "Last owner",
+			inputMemberID: "1",
+			mockBehavior: func(s *mockService.MockWorkspace) {
+				s.EXPECT().RemoveMember(gomock.Any(), 1, 2, 1).Return(service.ErrLastWorkspaceOwner)
+			},
+			expectedStatusCode:   409,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrLastWorkspaceOwner.Error()),
+		},
+		{
+			name:          "Service failure",
+			inputMemberID: "3",
+			mockBehavior: func(s *mockService.MockWorkspace) {
+				s.EXPECT().RemoveMember(gomock.Any(), 1, 2, 3).Return(service.ErrFailedToRemoveMember)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: fmt.Sprintf(`{"error":"%s"}`, service.ErrFailedToRemoveMember.Error()),
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			workspaces := mockService.NewMockWorkspace(c)
+			tc.mockBehavior(workspaces)
+
+			services := &service.Service{Workspace: workspaces}
+			handler := New(services, nil)
+
+			// Test server
+			gin.SetMode("test")
+			r := gin.New()
+			r.DELETE(
+				"/api/workspaces/:workspace_id/members/:user_id",
+				func(c *gin.Context) {
+					c.Set(userCtx, 1)
+				},
+				handler.removeWorkspaceMember)
+
+			// Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", fmt.Sprintf("/api/workspaces/2/members/%s", tc.inputMemberID), nil)
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+// TestHandler_workspaces shares a workspace between two users, and checks the lists are
+// scoped to the selected workspace and the viewers can't change them.
+func TestHandler_workspaces(t *testing.T) {
+	cfg := config.Service{SigningKey: "key", Salt: "salt", TokenTTL: 60}
+	services := service.New(repository.NewMemory(memory.NewStore()), cfg, nil, service.LogNotifier{}, &mailtest.Mailer{}, nil)
+	r := New(services, nil).InitRoutes()
+
+	request := func(method, target, token, workspace, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		if workspace != "" {
+			req.Header.Set(workspaceHeader, workspace)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	signIn := func(name string) string {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("POST", "/auth/sign-up", bytes.NewBufferString(
+			fmt.Sprintf(`{"name": "%s", "email": "%s@mail.ru", "password": "testing"}`, name, name))))
+		require.Equal(t, 201, w.Code)
+
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("POST", "/auth/sign-in", bytes.NewBufferString(
+			fmt.Sprintf(`{"email": "%s@mail.ru", "password": "testing"}`, name))))
+		require.Equal(t, 200, w.Code)
+
+		var session model.SignInResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &session))
+		return session.Token
+	}
+
+	owner, viewer := signIn("owner"), signIn("viewer")
+
+	w := request("POST", "/api/lists/", owner, "", `{"title": "personal"}`)
+	require.Equal(t, 201, w.Code)
+
+	w = request("POST", "/api/workspaces/", owner, "", `{"name": "team"}`)
+	require.Equal(t, 201, w.Code)
+
+	var created struct {
+		WorkspaceID int `json:"workspace_id"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	workspace := fmt.Sprint(created.WorkspaceID)
+
+	w = request("POST", "/api/workspaces/"+workspace+"/lists/", owner, "", `{"title": "shared"}`)
+	require.Equal(t, 201, w.Code)
+
+	// The workspace is hidden from the users outside of it.
+	assert.Equal(t, 404, request("GET", "/api/lists/", viewer, workspace, "").Code)
+	assert.Equal(t, 404, request("POST", "/graphql", viewer, workspace, `{"query":"{ lists { title } }"}`).Code)
+
+	w = request("POST", "/api/workspaces/"+workspace+"/members", owner, "", `{"email": "viewer@mail.ru", "role": "viewer"}`)
+	require.Equal(t, 201, w.Code)
+
+	var lists struct {
+		Lists []model.TodoList `json:"lists"`
+	}
+
+	w = request("GET", "/api/lists/", viewer, workspace, "")
+	require.Equal(t, 200, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &lists))
+	require.Len(t, lists.Lists, 1)
+	assert.Equal(t, "shared", lists.Lists[0].Title)
+
+	w = request("GET", "/api/workspaces/"+workspace+"/lists/", viewer, "", "")
+	require.Equal(t, 200, w.Code)
+	assert.Equal(t, 403, request("POST", "/api/lists/", viewer, workspace, `{"title": "other"}`).Code)
+
+	// The graphql queries and mutations use the workspace from the header too.
+	w = request("POST", "/graphql", viewer, workspace, `{"query":"{ lists { title } }"}`)
+	require.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"data":{"lists":[{"title":"shared"}]}}`, w.Body.String())
+
+	w = request("POST", "/graphql", viewer, workspace, `{"query":"mutation { createList(input: {title: \"other\"}) }"}`)
+	require.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), service.ErrWorkspaceReadOnly.Error())
+
+	// The personal workspace stays the default.
+	w = request("GET", "/api/lists/", owner, "", "")
+	require.Equal(t, 200, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &lists))
+	require.Len(t, lists.Lists, 1)
+	assert.Equal(t, "personal", lists.Lists[0].Title)
+
+	w = request("POST", "/graphql", owner, "", `{"query":"{ lists { title } }"}`)
+	require.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"data":{"lists":[{"title":"personal"}]}}`, w.Body.String())
+
+	w = request("GET", "/api/workspaces/"+workspace+"/members", viewer, "", "")
+	require.Equal(t, 200, w.Code)
+
+	var members struct {
+		Members []model.WorkspaceMember `json:"members"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &members))
+	require.Len(t, members.Members, 2)
+
+	var viewerID int
+	for _, member := range members.Members {
+		if member.Role == model.WorkspaceViewer {
+			viewerID = member.UserID
+		}
+	}
+	require.NotZero(t, viewerID)
+
+	w = request("PUT", fmt.Sprintf("/api/workspaces/%s/members/%d", workspace, viewerID), owner, "", `{"role": "editor"}`)
+	require.Equal(t, 200, w.Code)
+	assert.Equal(t, 201, request("POST", "/api/lists/", viewer, workspace, `{"title": "other"}`).Code)
+
+	assert.Equal(t, 403, request("DELETE", "/api/workspaces/"+workspace, viewer, "", "").Code)
+
+	// The only owner can't leave the workspace behind by deleting the account.
+	assert.Equal(t, 409, request("DELETE", "/api/me", owner, "", "").Code)
+	assert.Equal(t, 200, request("DELETE", fmt.Sprintf("/api/workspaces/%s/members/%d", workspace, viewerID), viewer, "", "").Code)
+	assert.Equal(t, 404, request("GET", "/api/lists/", viewer, workspace, "").Code)
+
+	assert.Equal(t, 400, request("GET", "/api/lists/", owner, "invalid", "").Code)
+	assert.Equal(t, 200, request("DELETE", "/api/workspaces/"+workspace, owner, "", "").Code)
+	assert.Equal(t, 404, request("GET", "/api/workspaces/"+workspace+"/lists/", owner, "", "").Code)
+}
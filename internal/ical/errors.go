@@ -0,0 +1,10 @@
+package ical
+
+import "errors"
+
+var (
+	ErrInvalidContentLine       = errors.New("invalid content line")
+	ErrPropertyOutsideComponent = errors.New("property outside of component")
+	ErrUnexpectedEnd            = errors.New("unexpected end of component")
+	ErrUnexpectedEOF            = errors.New("unexpected end of input")
+)
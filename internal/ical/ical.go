@@ -0,0 +1,294 @@
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	maxLineOctets     = 75
+	dateLayout        = "20060102"
+	dateTimeLayout    = "20060102T150405"
+	utcDateTimeLayout = "20060102T150405Z"
+)
+
+type Param struct {
+	Name  string
+	Value string
+}
+
+type Property struct {
+	Name   string
+	Params []Param
+	Value  string
+}
+
+type Component struct {
+	Name       string
+	Properties []Property
+	Components []Component
+}
+
+func NewComponent(name string) Component {
+	return Component{Name: name}
+}
+
+func (c *Component) Add(name, value string) {
+	c.Properties = append(c.Properties, Property{Name: name, Value: value})
+}
+
+func (c *Component) AddText(name, value string) {
+	c.Add(name, EscapeText(value))
+}
+
+func (c *Component) AddComponent(component Component) {
+	c.Components = append(c.Components, component)
+}
+
+func (c Component) Get(name string) (Property, bool) {
+	for _, p := range c.Properties {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+
+	return Property{}, false
+}
+
+func Encode(w io.Writer, c Component) error {
+	bw := bufio.NewWriter(w)
+	encodeComponent(bw, c)
+
+	return bw.Flush()
+}
+
+func encodeComponent(w *bufio.Writer, c Component) {
+	writeLine(w, "BEGIN:"+c.Name)
+
+	for _, p := range c.Properties {
+		var line strings.Builder
+		line.WriteString(p.Name)
+		for _, param := range p.Params {
+			line.WriteString(";" + param.Name + "=" + param.Value)
+		}
+		line.WriteString(":" + p.Value)
+
+		writeLine(w, line.String())
+	}
+
+	for _, child := range c.Components {
+		encodeComponent(w, child)
+	}
+
+	writeLine(w, "END:"+c.Name)
+}
+
+// writeLine folds content lines longer than 75 octets as required by RFC 5545,
+// taking care not to split multi-byte UTF-8 sequences.
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+
+		w.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = maxLineOctets - 1
+	}
+
+	w.WriteString(line + "\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+func EscapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// FormatDateTime formats t as a floating date-time, i.e. without a time zone,
+// because completion dates are stored as timestamps without time zone.
+func FormatDateTime(t time.Time) string {
+	return t.Format(dateTimeLayout)
+}
+
+func FormatUTCDateTime(t time.Time) string {
+	return t.UTC().Format(utcDateTimeLayout)
+}
+
+func Decode(r io.Reader) (Component, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return Component{}, err
+	}
+
+	var stack []Component
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+
+		property, err := parseLine(line)
+		if err != nil {
+			return Component{}, err
+		}
+
+		switch strings.ToUpper(property.Name) {
+		case "BEGIN":
+			stack = append(stack, NewComponent(strings.ToUpper(property.Value)))
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(property.Value) {
+				return Component{}, ErrUnexpectedEnd
+			}
+
+			component := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				return component, nil
+			}
+
+			stack[len(stack)-1].AddComponent(component)
+		default:
+			if len(stack) == 0 {
+				return Component{}, ErrPropertyOutsideComponent
+			}
+
+			current := &stack[len(stack)-1]
+			current.Properties = append(current.Properties, property)
+		}
+	}
+
+	return Component{}, ErrUnexpectedEOF
+}
+
+// readLines splits the input into content lines and unfolds continuation lines.
+func readLines(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+func parseLine(line string) (Property, error) {
+	var property Property
+
+	nameEnd := strings.IndexAny(line, ";:")
+	if nameEnd <= 0 {
+		return Property{}, ErrInvalidContentLine
+	}
+
+	property.Name = strings.ToUpper(line[:nameEnd])
+	rest := line[nameEnd:]
+
+	for strings.HasPrefix(rest, ";") {
+		rest = rest[1:]
+
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return Property{}, ErrInvalidContentLine
+		}
+
+		param := Param{Name: strings.ToUpper(rest[:eq])}
+		rest = rest[eq+1:]
+
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				return Property{}, ErrInvalidContentLine
+			}
+
+			param.Value = rest[1 : end+1]
+			rest = rest[end+2:]
+		} else {
+			end := strings.IndexAny(rest, ";:")
+			if end < 0 {
+				return Property{}, ErrInvalidContentLine
+			}
+
+			param.Value = rest[:end]
+			rest = rest[end:]
+		}
+
+		property.Params = append(property.Params, param)
+	}
+
+	if !strings.HasPrefix(rest, ":") {
+		return Property{}, ErrInvalidContentLine
+	}
+
+	property.Value = rest[1:]
+
+	return property, nil
+}
+
+func UnescapeText(s string) string {
+	return strings.NewReplacer(
+		`\\`, `\`,
+		`\;`, ";",
+		`\,`, ",",
+		`\n`, "\n",
+		`\N`, "\n",
+	).Replace(s)
+}
+
+// ParseDateTime parses DATE and DATE-TIME values. Values in UTC are converted
+// to the local time zone, floating values are returned as is.
+func ParseDateTime(value string) (time.Time, error) {
+	if t, err := time.Parse(utcDateTimeLayout, value); err == nil {
+		return t.Local(), nil
+	}
+
+	if t, err := time.Parse(dateTimeLayout, value); err == nil {
+		return t, nil
+	}
+
+	return time.Parse(dateLayout, value)
+}
+
+func (p Property) Param(name string) (string, bool) {
+	for _, param := range p.Params {
+		if strings.EqualFold(param.Name, name) {
+			return param.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// DateTime parses the property value as a date-time, honoring the TZID parameter.
+func (p Property) DateTime() (time.Time, error) {
+	if tzid, ok := p.Param("TZID"); ok {
+		if location, err := time.LoadLocation(tzid); err == nil {
+			if t, err := time.ParseInLocation(dateTimeLayout, p.Value, location); err == nil {
+				return t.Local(), nil
+			}
+		}
+	}
+
+	return ParseDateTime(p.Value)
+}
@@ -0,0 +1,120 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode(t *testing.T) {
+	testCases := []struct {
+		name         string
+		input        func() Component
+		expectedBody string
+	}{
+		{
+			name: "OK",
+			input: func() Component {
+				calendar := NewComponent("VCALENDAR")
+				calendar.Add("VERSION", "2.0")
+
+				todo := NewComponent("VTODO")
+				todo.AddText("SUMMARY", "buy milk, bread; eggs")
+				calendar.AddComponent(todo)
+
+				return calendar
+			},
+			expectedBody: "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VTODO\r\nSUMMARY:buy milk\\, bread\\; eggs\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+		},
+		{
+			name: "Long line",
+			input: func() Component {
+				todo := NewComponent("VTODO")
+				todo.Add("DESCRIPTION", strings.Repeat("a", 100))
+
+				return todo
+			},
+			expectedBody: "BEGIN:VTODO\r\nDESCRIPTION:" + strings.Repeat("a", 63) + "\r\n " + strings.Repeat("a", 37) + "\r\nEND:VTODO\r\n",
+		},
+		{
+			name: "Multi-byte characters",
+			input: func() Component {
+				todo := NewComponent("VTODO")
+				todo.Add("SUMMARY", strings.Repeat("я", 40))
+
+				return todo
+			},
+			expectedBody: "BEGIN:VTODO\r\nSUMMARY:" + strings.Repeat("я", 33) + "\r\n " + strings.Repeat("я", 7) + "\r\nEND:VTODO\r\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := Encode(&buf, tc.input())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedBody, buf.String())
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	testCases := []struct {
+		name              string
+		input             string
+		expectedComponent Component
+		wantErr           bool
+	}{
+		{
+			name: "OK",
+			input: "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VTODO\r\nSUMMARY:buy milk\\, bread\r\n" +
+				"DUE;TZID=\"Europe/Berlin\":20211121T100000\r\nDESCRIPTION:first\r\n  line\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+			expectedComponent: Component{
+				Name:       "VCALENDAR",
+				Properties: []Property{{Name: "VERSION", Value: "2.0"}},
+				Components: []Component{
+					{
+						Name: "VTODO",
+						Properties: []Property{
+							{Name: "SUMMARY", Value: "buy milk\\, bread"},
+							{Name: "DUE", Params: []Param{{Name: "TZID", Value: "Europe/Berlin"}}, Value: "20211121T100000"},
+							{Name: "DESCRIPTION", Value: "first line"},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Unexpected end",
+			input:   "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nEND:VCALENDAR\r\n",
+			wantErr: true,
+		},
+		{
+			name:    "Unexpected EOF",
+			input:   "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid line",
+			input:   "BEGIN:VCALENDAR\r\nVERSION\r\nEND:VCALENDAR\r\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Decode(strings.NewReader(tc.input))
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedComponent, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Package logger configures logrus and carries the request-scoped logger in the context,
+// so the logs of one request can be correlated by its fields.
+package logger
+
+import (
+	"context"
+
+	"github.com/Lapp-coder/todo-app/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+
+	RequestIDField = "request_id"
+	UserIDField    = "user_id"
+	TraceIDField   = "trace_id"
+)
+
+type loggerKey struct{}
+
+// Configure sets the level and the format of the global logger.
+func Configure(cfg config.Log) error {
+	level, err := logrus.ParseLevel(cfg.Level)
+	if err != nil {
+		return err
+	}
+
+	logrus.SetLevel(level)
+
+	switch cfg.Format {
+	case FormatJSON:
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+	case FormatText:
+		logrus.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+	default:
+		return ErrUnknownFormat
+	}
+
+	return nil
+}
+
+// WithContext returns a copy of ctx carrying the logger.
+func WithContext(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, loggerKey{}, entry)
+}
+
+// FromContext returns the logger of the request, or the global one outside of a request.
+func FromContext(ctx context.Context) *logrus.Entry {
+	if entry, ok := ctx.Value(loggerKey{}).(*logrus.Entry); ok {
+		return entry
+	}
+
+	return logrus.NewEntry(logrus.StandardLogger())
+}
+
+// WithField adds the field to the logger of the request.
+func WithField(ctx context.Context, key string, value interface{}) context.Context {
+	return WithContext(ctx, FromContext(ctx).WithField(key, value))
+}
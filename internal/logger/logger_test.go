@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Lapp-coder/todo-app/internal/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigure(t *testing.T) {
+	testTable := []struct {
+		name              string
+		cfg               config.Log
+		expectedLevel     logrus.Level
+		expectedFormatter logrus.Formatter
+		wantErr           bool
+	}{
+		{
+			name:              "JSON",
+			cfg:               config.Log{Level: "debug", Format: FormatJSON},
+			expectedLevel:     logrus.DebugLevel,
+			expectedFormatter: &logrus.JSONFormatter{},
+		},
+		{
+			name:              "Text",
+			cfg:               config.Log{Level: "warn", Format: FormatText},
+			expectedLevel:     logrus.WarnLevel,
+			expectedFormatter: &logrus.TextFormatter{FullTimestamp: true},
+		},
+		{
+			name:    "Invalid level",
+			cfg:     config.Log{Level: "verbose", Format: FormatJSON},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid format",
+			cfg:     config.Log{Level: "info", Format: "xml"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			defer logrus.SetLevel(logrus.GetLevel())
+			defer logrus.SetFormatter(logrus.StandardLogger().Formatter)
+
+			err := Configure(tc.cfg)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedLevel, logrus.GetLevel())
+			assert.Equal(t, tc.expectedFormatter, logrus.StandardLogger().Formatter)
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, FromContext(ctx).Data)
+
+	ctx = WithField(ctx, RequestIDField, "abc")
+	ctx = WithField(ctx, UserIDField, 1)
+
+	assert.Equal(t, logrus.Fields{RequestIDField: "abc", UserIDField: 1}, FromContext(ctx).Data)
+}
@@ -0,0 +1,91 @@
+// Package mail sends the emails of the app, e.g. the links verifying the addresses
+// of the new accounts.
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/config"
+	"github.com/Lapp-coder/todo-app/internal/logger"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// New creates the SMTP mailer, or the mailer writing the messages to the log
+// when no SMTP host is configured.
+func New(cfg config.Mail) Mailer {
+	if cfg.Host == "" {
+		return LogMailer{}
+	}
+
+	return NewSMTPMailer(cfg)
+}
+
+type sendFunc func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+	send sendFunc
+}
+
+func NewSMTPMailer(cfg config.Mail) *SMTPMailer {
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	return &SMTPMailer{addr: net.JoinHostPort(cfg.Host, cfg.Port), auth: auth, from: cfg.From, send: smtp.SendMail}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, message Message) error {
+	to := headerValue(message.To)
+	if err := m.send(m.addr, m.auth, m.from, []string{to}, m.format(message, time.Now())); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+
+	return nil
+}
+
+func (m *SMTPMailer) format(message Message, date time.Time) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", headerValue(m.from))
+	fmt.Fprintf(&b, "To: %s\r\n", headerValue(message.To))
+	fmt.Fprintf(&b, "Subject: %s\r\n", headerValue(message.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+
+	return []byte(b.String())
+}
+
+// headerValue drops the line breaks, which would let the value add headers of its own.
+func headerValue(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
+
+// LogMailer writes the messages to the log instead of sending them, for the local runs.
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, message Message) error {
+	logger.FromContext(ctx).WithField("to", message.To).WithField("subject", message.Subject).
+		Info(message.Body)
+
+	return nil
+}
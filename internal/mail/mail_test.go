@@ -0,0 +1,83 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"net/smtp"
+	"testing"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSMTPMailer_Send(t *testing.T) {
+	testTable := []struct {
+		name          string
+		message       Message
+		sendErr       error
+		expectedTo    string
+		expectedHeads string
+		wantErr       bool
+	}{
+		{
+			name:          "OK",
+			message:       Message{To: "user@mail.ru", Subject: "Hello", Body: "line\nline"},
+			expectedTo:    "user@mail.ru",
+			expectedHeads: "From: todo@mail.ru\r\nTo: user@mail.ru\r\nSubject: Hello\r\n",
+		},
+		{
+			name:          "Header injection",
+			message:       Message{To: "user@mail.ru\r\nBcc: other@mail.ru", Subject: "Hello\nBcc: other@mail.ru"},
+			expectedTo:    "user@mail.ruBcc: other@mail.ru",
+			expectedHeads: "From: todo@mail.ru\r\nTo: user@mail.ruBcc: other@mail.ru\r\nSubject: HelloBcc: other@mail.ru\r\n",
+		},
+		{
+			name:    "Send failure",
+			message: Message{To: "user@mail.ru"},
+			sendErr: errors.New("connection refused"),
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotAddr string
+			var gotTo []string
+			var gotMsg []byte
+
+			mailer := NewSMTPMailer(config.Mail{Host: "smtp.mail.ru", Port: "587", From: "todo@mail.ru"})
+			mailer.send = func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+				gotAddr, gotTo, gotMsg = addr, to, msg
+				return tc.sendErr
+			}
+
+			err := mailer.Send(context.Background(), tc.message)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "smtp.mail.ru:587", gotAddr)
+			assert.Equal(t, []string{tc.expectedTo}, gotTo)
+			assert.Contains(t, string(gotMsg), tc.expectedHeads)
+		})
+	}
+}
+
+func TestSMTPMailer_format(t *testing.T) {
+	mailer := NewSMTPMailer(config.Mail{Host: "smtp.mail.ru", Port: "587", From: "todo@mail.ru"})
+	date := time.Date(2021, 12, 1, 10, 0, 0, 0, time.UTC)
+
+	got := mailer.format(Message{To: "user@mail.ru", Subject: "Hello", Body: "first\nsecond"}, date)
+
+	assert.Equal(t, "From: todo@mail.ru\r\n"+
+		"To: user@mail.ru\r\n"+
+		"Subject: Hello\r\n"+
+		"Date: Wed, 01 Dec 2021 10:00:00 +0000\r\n"+
+		"MIME-Version: 1.0\r\n"+
+		"Content-Type: text/plain; charset=UTF-8\r\n"+
+		"\r\n"+
+		"first\r\nsecond", string(got))
+}
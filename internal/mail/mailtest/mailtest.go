@@ -0,0 +1,37 @@
+// Package mailtest captures the messages sent by the app in memory for the tests.
+package mailtest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Lapp-coder/todo-app/internal/mail"
+)
+
+type Mailer struct {
+	mu       sync.Mutex
+	messages []mail.Message
+	// Err is returned by Send instead of capturing the message when it's set.
+	Err error
+}
+
+func (m *Mailer) Send(ctx context.Context, message mail.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return m.Err
+	}
+
+	m.messages = append(m.messages, message)
+
+	return nil
+}
+
+// Messages returns the messages sent so far.
+func (m *Mailer) Messages() []mail.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]mail.Message(nil), m.messages...)
+}
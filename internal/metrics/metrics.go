@@ -0,0 +1,89 @@
+// Package metrics defines the Prometheus metrics of the app, they are served by
+// the /metrics endpoint from the Registry.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "todo_app"
+
+// The results of the ownership cache lookups.
+const (
+	CacheHit  = "hit"
+	CacheMiss = "miss"
+)
+
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Number of the handled HTTP requests by route template and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time spent handling the HTTP requests by route template and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	ItemsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "items_created_total",
+		Help:      "Number of the created todo items.",
+	})
+
+	ItemsCompleted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "items_completed_total",
+		Help:      "Number of the updates marking a todo item as done.",
+	})
+
+	OwnershipCacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ownership_cache_lookups_total",
+		Help:      "Lookups of the list and item ownership cache of the service layer by result.",
+	}, []string{"cache", "result"})
+
+	QueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Time spent in the repository methods, including the time waiting for a connection.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"repository", "method"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		ItemsCreated,
+		ItemsCompleted,
+		OwnershipCacheLookups,
+		QueryDuration,
+	)
+}
+
+// ObserveQuery records the time since start, it's meant to be deferred
+// at the beginning of a repository method.
+func ObserveQuery(repository, method string, start time.Time) {
+	QueryDuration.WithLabelValues(repository, method).Observe(time.Since(start).Seconds())
+}
+
+// ObserveCacheLookup records whether the ownership cache had the entry.
+func ObserveCacheLookup(cache string, hit bool) {
+	result := CacheMiss
+	if hit {
+		result = CacheHit
+	}
+
+	OwnershipCacheLookups.WithLabelValues(cache, result).Inc()
+}
@@ -0,0 +1,12 @@
+package migrate
+
+import "errors"
+
+var (
+	ErrInvalidFileName  = errors.New("invalid migration file name")
+	ErrDuplicateVersion = errors.New("duplicate migration version")
+	ErrMissingDown      = errors.New("migration has no down file")
+	ErrDirty            = errors.New("database is dirty, fix the schema and the schema_migrations table manually")
+	ErrUnknownVersion   = errors.New("database version has no migration")
+	ErrPending          = errors.New("database has pending migrations")
+)
@@ -0,0 +1,300 @@
+package migrate
+
+import (
+	"context"
+	"io/fs"
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Lapp-coder/todo-app/migrations"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+var testSource = fstest.MapFS{
+	"000001_init.up.sql":    {Data: []byte("CREATE TABLE users (id INT)")},
+	"000001_init.down.sql":  {Data: []byte("DROP TABLE users")},
+	"000002_lists.up.sql":   {Data: []byte("CREATE TABLE lists (id INT)")},
+	"000002_lists.down.sql": {Data: []byte("DROP TABLE lists")},
+	"000003_items.up.sql":   {Data: []byte("CREATE TABLE items (id INT)")},
+	"000003_items.down.sql": {Data: []byte("DROP TABLE items")},
+	"migrations.go":         {Data: []byte("package migrations")},
+}
+
+func TestReadMigrations(t *testing.T) {
+	testCases := []struct {
+		name             string
+		source           fs.FS
+		expectedVersions []uint
+		wantErr          error
+	}{
+		{
+			name:             "OK",
+			source:           testSource,
+			expectedVersions: []uint{1, 2, 3},
+		},
+		{
+			name:             "Embedded",
+			source:           migrations.FS,
+			expectedVersions: []uint{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14},
+		},
+		{
+			name: "Invalid file name",
+			source: fstest.MapFS{
+				"init.up.sql": {Data: []byte("CREATE TABLE users (id INT)")},
+			},
+			wantErr: ErrInvalidFileName,
+		},
+		{
+			name: "Missing down",
+			source: fstest.MapFS{
+				"000001_init.up.sql": {Data: []byte("CREATE TABLE users (id INT)")},
+			},
+			wantErr: ErrMissingDown,
+		},
+		{
+			name: "Duplicate version",
+			source: fstest.MapFS{
+				"000001_init.up.sql":    {Data: []byte("CREATE TABLE users (id INT)")},
+				"000001_init.down.sql":  {Data: []byte("DROP TABLE users")},
+				"000001_other.up.sql":   {Data: []byte("CREATE TABLE lists (id INT)")},
+				"000001_other.down.sql": {Data: []byte("DROP TABLE lists")},
+			},
+			wantErr: ErrDuplicateVersion,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := readMigrations(tc.source)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			versions := make([]uint, 0, len(got))
+			for _, migration := range got {
+				versions = append(versions, migration.Version)
+			}
+
+			assert.Equal(t, tc.expectedVersions, versions)
+		})
+	}
+}
+
+func expectLock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock($1)")).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS " + schemaTable).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectVersion(mock sqlmock.Sqlmock, version int, dirty bool) {
+	rows := mock.NewRows([]string{"version", "dirty"})
+	if version > 0 {
+		rows.AddRow(version, dirty)
+	}
+
+	mock.ExpectQuery("SELECT version, dirty FROM " + schemaTable).WillReturnRows(rows)
+}
+
+func expectApply(mock sqlmock.Sqlmock, migration string, version int) {
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(migration)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM " + schemaTable).WillReturnResult(sqlmock.NewResult(0, 1))
+	if version > 0 {
+		mock.ExpectExec("INSERT INTO " + schemaTable).WithArgs(version).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+}
+
+func TestMigrator_Up(t *testing.T) {
+	type mockBehavior func(mock sqlmock.Sqlmock)
+
+	testCases := []struct {
+		name             string
+		mockBehavior     mockBehavior
+		expectedVersions []uint
+		wantErr          bool
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				expectLock(mock)
+				expectVersion(mock, 1, false)
+				expectApply(mock, "CREATE TABLE lists (id INT)", 2)
+				expectApply(mock, "CREATE TABLE items (id INT)", 3)
+				expectUnlock(mock)
+			},
+			expectedVersions: []uint{2, 3},
+		},
+		{
+			name: "Up to date",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				expectLock(mock)
+				expectVersion(mock, 3, false)
+				expectUnlock(mock)
+			},
+		},
+		{
+			name: "Dirty",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				expectLock(mock)
+				expectVersion(mock, 2, true)
+				expectUnlock(mock)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Migration failure",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				expectLock(mock)
+				expectVersion(mock, 2, false)
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE items (id INT)")).WillReturnError(sqlmock.ErrCancelled)
+				mock.ExpectRollback()
+				expectUnlock(mock)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+			}
+			defer mockDB.Close()
+
+			migrator, err := New(sqlx.NewDb(mockDB, "sqlmock"), testSource)
+			assert.NoError(t, err)
+
+			tc.mockBehavior(mock)
+
+			applied, err := migrator.Up(context.Background())
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			versions := make([]uint, 0, len(applied))
+			for _, migration := range applied {
+				versions = append(versions, migration.Version)
+			}
+
+			if tc.expectedVersions == nil {
+				tc.expectedVersions = []uint{}
+			}
+
+			assert.Equal(t, tc.expectedVersions, versions)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMigrator_Down(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+	defer mockDB.Close()
+
+	migrator, err := New(sqlx.NewDb(mockDB, "sqlmock"), testSource)
+	assert.NoError(t, err)
+
+	expectLock(mock)
+	expectVersion(mock, 2, false)
+	expectApply(mock, "DROP TABLE lists", 1)
+	expectApply(mock, "DROP TABLE users", 0)
+	expectUnlock(mock)
+
+	reverted, err := migrator.Down(context.Background(), 5)
+
+	assert.NoError(t, err)
+	assert.Len(t, reverted, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Status(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+	defer mockDB.Close()
+
+	migrator, err := New(sqlx.NewDb(mockDB, "sqlmock"), testSource)
+	assert.NoError(t, err)
+
+	expectLock(mock)
+	expectVersion(mock, 2, false)
+	expectUnlock(mock)
+
+	status, err := migrator.Status(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), status.Version)
+	assert.Len(t, status.Applied, 2)
+	assert.Len(t, status.Pending, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Check(t *testing.T) {
+	testCases := []struct {
+		name        string
+		version     int
+		dirty       bool
+		expectedErr error
+	}{
+		{
+			name:    "OK",
+			version: 3,
+		},
+		{
+			name:        "Pending",
+			version:     2,
+			expectedErr: ErrPending,
+		},
+		{
+			name:        "Not migrated",
+			expectedErr: ErrPending,
+		},
+		{
+			name:        "Dirty",
+			version:     3,
+			dirty:       true,
+			expectedErr: ErrDirty,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+			}
+			defer mockDB.Close()
+
+			migrator, err := New(sqlx.NewDb(mockDB, "sqlmock"), testSource)
+			assert.NoError(t, err)
+
+			expectVersion(mock, tc.version, tc.dirty)
+
+			err = migrator.Check(context.Background())
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -12,6 +12,84 @@ type SignIn struct {
 	Password string `json:"password" binding:"required,min=6,max=50"`
 }
 
+type CompleteSignIn struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required,max=50"`
+}
+
+type ResendVerification struct {
+	Email string `json:"email" binding:"required,email,min=1,max=50"`
+}
+
+type ForgotPassword struct {
+	Email string `json:"email" binding:"required,email,min=1,max=50"`
+}
+
+type ResetPassword struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6,max=50"`
+}
+
+type ChangePassword struct {
+	CurrentPassword string `json:"current_password" binding:"required,max=50"`
+	NewPassword     string `json:"new_password" binding:"required,min=6,max=50"`
+}
+
+type ConfirmTwoFactor struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// UpdateProfile changes the fields set, a new email has to be verified again.
+type UpdateProfile struct {
+	Name     *string `json:"name" binding:"omitempty,min=3,max=20"`
+	Email    *string `json:"email" binding:"omitempty,email,max=50"`
+	Timezone *string `json:"timezone" binding:"omitempty,max=64"`
+	Locale   *string `json:"locale" binding:"omitempty,max=35"`
+	// CurrentPassword is required to change the email.
+	CurrentPassword *string `json:"current_password" binding:"omitempty,max=50"`
+}
+
+func (p UpdateProfile) IsNilAllFields() bool {
+	return p.Name == nil && p.Email == nil && p.Timezone == nil && p.Locale == nil
+}
+
+type CreateAccessToken struct {
+	Name   string   `json:"name" binding:"required,max=100"`
+	Scopes []string `json:"scopes" binding:"required,min=1,dive,oneof=lists:read lists:write items:read items:write"`
+	// ExpiresInDays is the days the token is valid for, it doesn't expire when omitted.
+	ExpiresInDays int `json:"expires_in_days" binding:"min=0,max=366"`
+}
+
+// SearchUsers finds the users whose name or email contains the query, all of them when it's empty.
+type SearchUsers struct {
+	Query  string `form:"q" binding:"max=50"`
+	Limit  int    `form:"limit" binding:"min=0,max=100"`
+	Offset int    `form:"offset" binding:"min=0"`
+}
+
+type SetRole struct {
+	Role string `json:"role" binding:"required,oneof=user admin"`
+}
+
+// Workspace
+type CreateWorkspace struct {
+	Name string `json:"name" binding:"required,min=1,max=40"`
+}
+
+type UpdateWorkspace struct {
+	Name string `json:"name" binding:"required,min=1,max=40"`
+}
+
+// AddWorkspaceMember adds the user with the email to the workspace.
+type AddWorkspaceMember struct {
+	Email string `json:"email" binding:"required,email,max=50"`
+	Role  string `json:"role" binding:"required,oneof=owner editor viewer"`
+}
+
+type SetWorkspaceRole struct {
+	Role string `json:"role" binding:"required,oneof=owner editor viewer"`
+}
+
 // List
 type CreateTodoList struct {
 	Title          string `json:"title" binding:"required,min=3,max=30"`
@@ -47,3 +125,10 @@ type UpdateTodoItem struct {
 func (i UpdateTodoItem) IsNilAllFields() bool {
 	return i.Title == nil && i.Description == nil && i.CompletionDate == nil && i.Done == nil
 }
+
+// GraphQL
+type GraphQLRequest struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
@@ -1,8 +1,20 @@
 package model
 
+import "time"
+
+const CompletionDateLayout = "2006-01-02 15:04:05"
+
+var completionDateLayouts = []string{
+	time.RFC3339Nano,
+	CompletionDateLayout,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
 type TodoList struct {
 	ID             int    `json:"id" db:"id"`
 	UserID         int    `json:"user_id" db:"user_id"`
+	WorkspaceID    int    `json:"workspace_id" db:"workspace_id"`
 	Title          string `json:"title" db:"title"`
 	Description    string `json:"description" db:"description"`
 	CompletionDate string `json:"completion_date" db:"completion_date"`
@@ -16,3 +28,28 @@ type TodoItem struct {
 	CompletionDate string `json:"completion_date" db:"completion_date"`
 	Done           bool   `json:"done" db:"done"`
 }
+
+// TodoListWithItems is a list with its items embedded, it's returned when the items
+// are requested together with the lists.
+type TodoListWithItems struct {
+	TodoList
+	Items []TodoItem `json:"items"`
+}
+
+type CalendarObject struct {
+	ItemID int    `json:"item_id" db:"item_id"`
+	Name   string `json:"name" db:"name"`
+	UID    string `json:"uid" db:"uid"`
+}
+
+// ParseCompletionDate parses a completion date in one of the formats
+// it can be stored or returned from the database.
+func ParseCompletionDate(value string) (time.Time, bool) {
+	for _, layout := range completionDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
@@ -1,8 +1,182 @@
 package model
 
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
 type User struct {
 	ID       int    `json:"id"`
 	Name     string `json:"name"`
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	Verified bool   `json:"-"`
+	// TokenVersion is bumped to sign out all the sessions of the user.
+	TokenVersion int `json:"-"`
+	// FailedLogins are the failed sign-ins since the last successful one or the last lockout.
+	FailedLogins int        `json:"-"`
+	Lockouts     int        `json:"-"`
+	LockedUntil  *time.Time `json:"-"`
+	// TOTPSecret is set on the two-factor setup and checked once TOTPEnabled, TOTPLastStep
+	// is the time step of the last accepted code.
+	TOTPSecret   string `json:"-"`
+	TOTPEnabled  bool   `json:"-"`
+	TOTPLastStep int64  `json:"-"`
+	// Timezone is an IANA time zone name and Locale a BCP 47 language tag.
+	Timezone string `json:"-"`
+	Locale   string `json:"-"`
+	Role     string `json:"-"`
+	// Disabled accounts can't sign in or use their tokens.
+	Disabled bool `json:"-"`
+	// PendingEmail is the changed email waiting for its verification, Email stays in use until then.
+	PendingEmail string `json:"-"`
+}
+
+// The roles of the users, the admins may use the admin API.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// Session is the user signed in with a token, with the role the token was issued for.
+type Session struct {
+	UserID int
+	Role   string
+}
+
+// The profile of the accounts that haven't set the timezone and the locale.
+const (
+	DefaultTimezone = "UTC"
+	DefaultLocale   = "en"
+)
+
+// Profile is the account as shown to its user.
+type Profile struct {
+	ID               int    `json:"id"`
+	Name             string `json:"name"`
+	Email            string `json:"email"`
+	EmailVerified    bool   `json:"email_verified"`
+	PendingEmail     string `json:"pending_email,omitempty"`
+	Timezone         string `json:"timezone"`
+	Locale           string `json:"locale"`
+	TwoFactorEnabled bool   `json:"two_factor_enabled"`
+}
+
+// UserSummary is the account as shown to the admins.
+type UserSummary struct {
+	ID               int    `json:"id" db:"id"`
+	Name             string `json:"name" db:"name"`
+	Email            string `json:"email" db:"email"`
+	Role             string `json:"role" db:"role"`
+	EmailVerified    bool   `json:"email_verified" db:"verified"`
+	TwoFactorEnabled bool   `json:"two_factor_enabled" db:"totp_enabled"`
+	Disabled         bool   `json:"disabled" db:"disabled"`
+}
+
+// Stats are the usage numbers of the whole application.
+type Stats struct {
+	Users          int `json:"users" db:"users"`
+	Admins         int `json:"admins" db:"admins"`
+	DisabledUsers  int `json:"disabled_users" db:"disabled_users"`
+	VerifiedUsers  int `json:"verified_users" db:"verified_users"`
+	TwoFactorUsers int `json:"two_factor_users" db:"two_factor_users"`
+	Lists          int `json:"lists" db:"lists"`
+	Items          int `json:"items" db:"items"`
+	DoneItems      int `json:"done_items" db:"done_items"`
+	AccessTokens   int `json:"access_tokens" db:"access_tokens"`
+}
+
+// Lockout is the brute-force protection state of an account.
+type Lockout struct {
+	UserID       int        `json:"user_id" db:"user_id"`
+	Email        string     `json:"email" db:"email"`
+	FailedLogins int        `json:"failed_logins" db:"failed_logins"`
+	Lockouts     int        `json:"lockouts" db:"lockouts"`
+	LockedUntil  *time.Time `json:"locked_until,omitempty" db:"locked_until"`
+	Locked       bool       `json:"locked" db:"-"`
+}
+
+// Identity links the account of the user at an identity provider, the subject is its id there.
+type Identity struct {
+	Issuer  string
+	Subject string
+	UserID  int
+}
+
+// PasswordReset is a single-use token letting the user set a new password, only its hash is stored.
+type PasswordReset struct {
+	TokenHash string
+	UserID    int
+	ExpiresAt time.Time
+}
+
+// TwoFactorSetup is the secret to add to an authenticator app, as is or through the otpauth URI.
+type TwoFactorSetup struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// SignInResult holds the access token, or the challenge token to complete the sign-in with
+// a two-factor code.
+type SignInResult struct {
+	Token          string `json:"token,omitempty"`
+	ChallengeToken string `json:"challenge_token,omitempty"`
+}
+
+// The scopes of the personal access tokens, the sessions are allowed everything.
+const (
+	ScopeListsRead  = "lists:read"
+	ScopeListsWrite = "lists:write"
+	ScopeItemsRead  = "items:read"
+	ScopeItemsWrite = "items:write"
+)
+
+// Scopes are stored separated by spaces.
+type Scopes []string
+
+func (s Scopes) Has(scope string) bool {
+	for _, v := range s {
+		if v == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s Scopes) Value() (driver.Value, error) {
+	return strings.Join(s, " "), nil
+}
+
+func (s *Scopes) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		*s = strings.Fields(v)
+	case []byte:
+		*s = strings.Fields(string(v))
+	default:
+		return fmt.Errorf("can't scan %T into scopes", src)
+	}
+
+	return nil
+}
+
+// AccessToken is a personal access token of the user, only its hash is stored.
+type AccessToken struct {
+	ID         int        `json:"id" db:"id"`
+	UserID     int        `json:"-" db:"user_id"`
+	Name       string     `json:"name" db:"name"`
+	TokenHash  string     `json:"-" db:"token_hash"`
+	Scopes     Scopes     `json:"scopes" db:"scopes" swaggertype:"array,string"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// CreatedAccessToken carries the token itself, which is shown only once.
+type CreatedAccessToken struct {
+	AccessToken
+	Token string `json:"token"`
 }
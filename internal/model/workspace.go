@@ -0,0 +1,36 @@
+package model
+
+// PersonalWorkspaceName is the name of the workspace every user gets on the sign-up.
+const PersonalWorkspaceName = "Personal"
+
+// The roles of the workspace members. The owners manage the workspace and its members,
+// the editors change the lists and the items, the viewers only read them.
+const (
+	WorkspaceOwner  = "owner"
+	WorkspaceEditor = "editor"
+	WorkspaceViewer = "viewer"
+)
+
+// Workspace owns the lists. The personal workspace of a user has them as the only member,
+// Role is the role of the user the workspace was read for.
+type Workspace struct {
+	ID       int    `json:"id" db:"id"`
+	Name     string `json:"name" db:"name"`
+	Personal bool   `json:"personal" db:"personal"`
+	Role     string `json:"role" db:"role"`
+}
+
+func (w Workspace) CanWrite() bool {
+	return w.Role == WorkspaceOwner || w.Role == WorkspaceEditor
+}
+
+func (w Workspace) CanManage() bool {
+	return w.Role == WorkspaceOwner
+}
+
+type WorkspaceMember struct {
+	UserID int    `json:"user_id" db:"user_id"`
+	Name   string `json:"name" db:"name"`
+	Email  string `json:"email" db:"email"`
+	Role   string `json:"role" db:"role"`
+}
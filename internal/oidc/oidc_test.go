@@ -0,0 +1,133 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/Lapp-coder/todo-app/internal/config"
+	"github.com/Lapp-coder/todo-app/internal/oidc/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const redirectURL = "http://todo.local/auth/oidc/callback"
+
+func TestProvider_Exchange(t *testing.T) {
+	idp, err := oidctest.NewProvider("todo-app", "secret")
+	require.NoError(t, err)
+	defer idp.Close()
+
+	user := oidctest.User{Subject: "42", Email: "user@mail.ru", EmailVerified: true, Name: "User"}
+
+	testTable := []struct {
+		name             string
+		cfg              config.OIDC
+		verifier         string
+		expectedIdentity Identity
+		wantErr          bool
+	}{
+		{
+			name:     "OK",
+			cfg:      idp.Config(redirectURL),
+			verifier: "verifier",
+			expectedIdentity: Identity{
+				Issuer: idp.URL, Subject: "42", Email: "user@mail.ru", EmailVerified: true, Name: "User", Nonce: "nonce",
+			},
+		},
+		{
+			name:     "Wrong verifier",
+			cfg:      idp.Config(redirectURL),
+			verifier: "other",
+			wantErr:  true,
+		},
+		{
+			name: "Wrong client secret",
+			cfg: func() config.OIDC {
+				cfg := idp.Config(redirectURL)
+				cfg.ClientSecret = "wrong"
+				return cfg
+			}(),
+			verifier: "verifier",
+			wantErr:  true,
+		},
+		{
+			name: "Wrong issuer",
+			cfg: func() config.OIDC {
+				cfg := idp.Config(redirectURL)
+				cfg.Issuer = idp.URL + "/"
+				return cfg
+			}(),
+			verifier: "verifier",
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			p := New(tc.cfg, idp.Client())
+
+			authURL, err := p.AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+			if err != nil {
+				assert.True(t, tc.wantErr, err.Error())
+				return
+			}
+
+			callback, err := idp.Authorize(authURL, user)
+			require.NoError(t, err)
+
+			u, err := url.Parse(callback)
+			require.NoError(t, err)
+			assert.Equal(t, "state", u.Query().Get("state"))
+
+			identity, err := p.Exchange(context.Background(), u.Query().Get("code"), tc.verifier)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedIdentity, identity)
+		})
+	}
+}
+
+func TestProvider_Exchange_otherProvider(t *testing.T) {
+	idp, err := oidctest.NewProvider("todo-app", "")
+	require.NoError(t, err)
+	defer idp.Close()
+
+	other, err := oidctest.NewProvider("todo-app", "")
+	require.NoError(t, err)
+	defer other.Close()
+
+	p := New(idp.Config(redirectURL), idp.Client())
+	authURL, err := New(other.Config(redirectURL), other.Client()).AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+	require.NoError(t, err)
+
+	callback, err := other.Authorize(authURL, oidctest.User{Subject: "42"})
+	require.NoError(t, err)
+
+	u, err := url.Parse(callback)
+	require.NoError(t, err)
+
+	// The code of the other provider isn't known to this one.
+	_, err = p.Exchange(context.Background(), u.Query().Get("code"), "verifier")
+	assert.Error(t, err)
+}
+
+func TestNew_disabled(t *testing.T) {
+	assert.Nil(t, New(config.OIDC{}, nil))
+}
+
+func TestAudience_UnmarshalJSON(t *testing.T) {
+	var claims idTokenClaims
+
+	require.NoError(t, json.Unmarshal([]byte(`{"aud": "todo-app"}`), &claims))
+	assert.True(t, claims.Audience.contains("todo-app"))
+
+	require.NoError(t, json.Unmarshal([]byte(`{"aud": ["other", "todo-app"]}`), &claims))
+	assert.True(t, claims.Audience.contains("todo-app"))
+	assert.False(t, claims.Audience.contains("another"))
+}
@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the full buckets are dropped from the memory store,
+// they hold no information since a missing bucket is the same as a full one.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	policy  Policy
+}
+
+// refill must be called with the lock held.
+func (b *bucket) refill(now time.Time) {
+	capacity := b.policy.capacity()
+	elapsed := now.Sub(b.updated)
+	b.tokens += float64(elapsed) / float64(b.policy.interval())
+	if b.tokens > capacity {
+		b.tokens = capacity
+	}
+	b.updated = now
+}
+
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), lastSweep: time.Now(), now: time.Now}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, policy Policy) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: policy.capacity(), updated: now}
+		s.buckets[key] = b
+	}
+	b.policy = policy
+	b.refill(now)
+
+	capacity := policy.capacity()
+	interval := policy.interval()
+	result := Result{Limit: int(capacity)}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - b.tokens) * float64(interval))
+	}
+
+	result.Remaining = int(b.tokens)
+	result.Reset = time.Duration((capacity - b.tokens) * float64(interval))
+
+	return result, nil
+}
+
+// sweep must be called with the lock held.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		b.refill(now)
+		if b.tokens >= b.policy.capacity() {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore_Take(t *testing.T) {
+	policy := Policy{Requests: 2, Period: 2 * time.Second, Burst: 3}
+
+	testTable := []struct {
+		name     string
+		takes    int
+		wait     time.Duration
+		expected Result
+	}{
+		{
+			name:     "First request",
+			takes:    1,
+			expected: Result{Allowed: true, Limit: 3, Remaining: 2, Reset: time.Second},
+		},
+		{
+			name:     "Burst",
+			takes:    3,
+			expected: Result{Allowed: true, Limit: 3, Remaining: 0, Reset: 3 * time.Second},
+		},
+		{
+			name:     "Exceeded",
+			takes:    4,
+			expected: Result{Allowed: false, Limit: 3, Remaining: 0, RetryAfter: time.Second, Reset: 3 * time.Second},
+		},
+		{
+			name:     "Refilled",
+			takes:    3,
+			wait:     time.Second,
+			expected: Result{Allowed: true, Limit: 3, Remaining: 0, Reset: 3 * time.Second},
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			now := time.Unix(0, 0)
+			store := NewMemoryStore()
+			store.now = func() time.Time { return now }
+
+			var result Result
+			for i := 0; i < tc.takes; i++ {
+				var err error
+				result, err = store.Take(context.Background(), "key", policy)
+				assert.NoError(t, err)
+			}
+
+			if tc.wait > 0 {
+				now = now.Add(tc.wait)
+
+				var err error
+				result, err = store.Take(context.Background(), "key", policy)
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestMemoryStore_sweep(t *testing.T) {
+	policy := Policy{Requests: 1, Period: time.Second}
+	now := time.Unix(0, 0)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	store.lastSweep = now
+
+	_, err := store.Take(context.Background(), "idle", policy)
+	assert.NoError(t, err)
+
+	now = now.Add(sweepInterval)
+	_, err = store.Take(context.Background(), "active", policy)
+	assert.NoError(t, err)
+
+	assert.NotContains(t, store.buckets, "idle")
+	assert.Contains(t, store.buckets, "active")
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	limiter := New(NewMemoryStore(), map[string]Policy{
+		PolicyAuthIP:  {Requests: 1, Period: time.Minute},
+		PolicyAPIUser: {},
+	})
+
+	result, err := limiter.Allow(context.Background(), PolicyAuthIP, "127.0.0.1")
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	result, err = limiter.Allow(context.Background(), PolicyAuthIP, "127.0.0.1")
+	assert.NoError(t, err)
+	assert.False(t, result.Allowed)
+
+	// The keys of the policies don't share the buckets.
+	result, err = limiter.Allow(context.Background(), PolicyAuthEmail, "127.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, Result{Allowed: true}, result)
+
+	result, err = limiter.Allow(context.Background(), PolicyAPIUser, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, Result{Allowed: true}, result)
+}
@@ -0,0 +1,115 @@
+// Package ratelimit limits the requests with token buckets. The buckets are kept in a Store,
+// the in-memory one works for a single instance, a shared one (e.g. redis) is needed to
+// limit across the replicas of the app.
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/config"
+)
+
+// The policies applied by the handlers.
+const (
+	PolicyAuthIP    = "auth_ip"
+	PolicyAuthEmail = "auth_email"
+	PolicyAPIUser   = "api_user"
+)
+
+// Policy allows Burst requests at once, refilled at Requests per Period.
+type Policy struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+func (p Policy) enabled() bool {
+	return p.Requests > 0 && p.Period > 0
+}
+
+func (p Policy) capacity() float64 {
+	if p.Burst > 0 {
+		return float64(p.Burst)
+	}
+
+	return float64(p.Requests)
+}
+
+// interval is the time it takes to refill one token.
+func (p Policy) interval() time.Duration {
+	return p.Period / time.Duration(p.Requests)
+}
+
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is the time until the next request is allowed, zero when it's allowed now.
+	RetryAfter time.Duration
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+}
+
+// Store takes a token from the bucket of the key.
+type Store interface {
+	Take(ctx context.Context, key string, policy Policy) (Result, error)
+}
+
+type Limiter struct {
+	store    Store
+	policies map[string]Policy
+}
+
+func New(store Store, policies map[string]Policy) *Limiter {
+	return &Limiter{store: store, policies: policies}
+}
+
+// FromConfig creates the limiter with the configured policies, it returns nil when
+// the rate limiting is disabled.
+func FromConfig(cfg config.RateLimit, store Store) *Limiter {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	return New(store, map[string]Policy{
+		PolicyAuthIP:    policyFromConfig(cfg.AuthIP),
+		PolicyAuthEmail: policyFromConfig(cfg.AuthEmail),
+		PolicyAPIUser:   policyFromConfig(cfg.APIUser),
+	})
+}
+
+func policyFromConfig(cfg config.RateLimitPolicy) Policy {
+	return Policy{Requests: cfg.Requests, Period: time.Duration(cfg.Period) * time.Second, Burst: cfg.Burst}
+}
+
+// Allow takes a token for the key under the policy. The requests of the policies that
+// aren't configured are always allowed and get a result with a zero Limit.
+func (l *Limiter) Allow(ctx context.Context, policy, key string) (Result, error) {
+	p, ok := l.policies[policy]
+	if !ok || !p.enabled() {
+		return Result{Allowed: true}, nil
+	}
+
+	return l.store.Take(ctx, policy+":"+key, p)
+}
+
+// Seconds rounds the duration up to whole seconds for the headers.
+func Seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// EmailKey makes the different spellings of an email share a bucket.
+func EmailKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// TokenKey keys a bucket by a token without keeping the token itself in the store.
+func TokenKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,883 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/config"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/repository"
+	"github.com/Lapp-coder/todo-app/internal/repository/memory"
+	"github.com/Lapp-coder/todo-app/internal/repository/postgres"
+	"github.com/Lapp-coder/todo-app/internal/repository/sqlite"
+	"github.com/Lapp-coder/todo-app/test"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// postgresDSNEnv points the contract tests at a migrated postgres database,
+// the postgres backend is skipped when it's not set.
+const postgresDSNEnv = "TEST_POSTGRES_DSN"
+
+var ctx = context.Background()
+
+func TestRepositoryContract(t *testing.T) {
+	backends := []struct {
+		name string
+		open func(t *testing.T) *repository.Repository
+	}{
+		{
+			name: repository.DriverMemory,
+			open: func(t *testing.T) *repository.Repository {
+				return repository.NewMemory(memory.NewStore())
+			},
+		},
+		{
+			name: repository.DriverSQLite,
+			open: func(t *testing.T) *repository.Repository {
+				db, err := sqlite.NewDB(config.Storage{Driver: repository.DriverSQLite, SQLitePath: ":memory:"})
+				require.NoError(t, err)
+				t.Cleanup(func() { db.Close() })
+
+				return repository.NewSQLite(db)
+			},
+		},
+		{
+			name: repository.DriverPostgres,
+			open: func(t *testing.T) *repository.Repository {
+				dsn := os.Getenv(postgresDSNEnv)
+				if dsn == "" {
+					t.Skipf("%s is not set", postgresDSNEnv)
+				}
+
+				db, err := sqlx.Connect("postgres", dsn)
+				require.NoError(t, err)
+				t.Cleanup(func() { db.Close() })
+
+				return repository.New(postgres.NewCluster(db, nil, 0), 0)
+			},
+		},
+	}
+
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			repos := backend.open(t)
+
+			t.Run("Authorization", func(t *testing.T) { testAuthorization(t, repos) })
+			t.Run("TodoList", func(t *testing.T) { testTodoList(t, repos) })
+			t.Run("TodoItem", func(t *testing.T) { testTodoItem(t, repos) })
+			t.Run("Calendar", func(t *testing.T) { testCalendar(t, repos) })
+			t.Run("CalDAV", func(t *testing.T) { testCalDAV(t, repos) })
+			t.Run("AccessToken", func(t *testing.T) { testAccessToken(t, repos) })
+			t.Run("Workspace", func(t *testing.T) { testWorkspace(t, repos) })
+			t.Run("TxManager", func(t *testing.T) { testTxManager(t, repos) })
+		})
+	}
+}
+
+// uniqueEmail keeps the tests independent from the data already in the database.
+func uniqueEmail(name string) string {
+	return fmt.Sprintf("%s-%d@example.com", name, time.Now().UnixNano())
+}
+
+func createUser(t *testing.T, repos *repository.Repository, name string) int {
+	id, err := repos.Authorization.CreateUser(ctx, model.User{Name: name, Email: uniqueEmail(name), Password: "hash"})
+	require.NoError(t, err)
+
+	return id
+}
+
+func personalWorkspace(t *testing.T, repos *repository.Repository, userID int) int {
+	workspace, err := repos.Workspace.GetPersonal(ctx, userID)
+	require.NoError(t, err)
+
+	return workspace.ID
+}
+
+// createList creates the list in the personal workspace of the user.
+func createList(t *testing.T, repos *repository.Repository, userID int, title string) int {
+	id, err := repos.TodoList.Create(ctx, personalWorkspace(t, repos, userID), userID,
+		model.TodoList{Title: title, CompletionDate: "2021-11-21 10:00:00"})
+	require.NoError(t, err)
+
+	return id
+}
+
+func createItem(t *testing.T, repos *repository.Repository, listID int, item model.TodoItem) int {
+	id, err := repos.TodoItem.Create(ctx, listID, item)
+	require.NoError(t, err)
+
+	return id
+}
+
+// normalizeDates brings the completion dates to a single layout, since the backends
+// return timestamps in different formats.
+func normalizeDates(items []model.TodoItem) []model.TodoItem {
+	for i := range items {
+		if t, ok := model.ParseCompletionDate(items[i].CompletionDate); ok {
+			items[i].CompletionDate = t.Format(model.CompletionDateLayout)
+		}
+	}
+
+	return items
+}
+
+func itemIDs(items []model.TodoItem) []int {
+	ids := make([]int, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+
+	return ids
+}
+
+func testAuthorization(t *testing.T, repos *repository.Repository) {
+	email := uniqueEmail("alice")
+	id, err := repos.Authorization.CreateUser(ctx, model.User{Name: "alice", Email: email, Password: "hash"})
+	require.NoError(t, err)
+	assert.NotZero(t, id)
+
+	user, err := repos.Authorization.GetUser(ctx, email)
+	require.NoError(t, err)
+	assert.Equal(t, model.User{
+		ID: id, Name: "alice", Email: email, Password: "hash", Timezone: model.DefaultTimezone, Locale: model.DefaultLocale,
+		Role: model.RoleUser,
+	}, user)
+
+	_, err = repos.Authorization.CreateUser(ctx, model.User{Name: "alice", Email: email, Password: "hash"})
+	assert.Error(t, err)
+
+	_, err = repos.Authorization.GetUser(ctx, uniqueEmail("nobody"))
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	assert.ErrorIs(t, repos.Authorization.VerifyEmail(ctx, id, uniqueEmail("previous")), sql.ErrNoRows)
+	require.NoError(t, repos.Authorization.VerifyEmail(ctx, id, email))
+
+	user, err = repos.Authorization.GetUser(ctx, email)
+	require.NoError(t, err)
+	assert.True(t, user.Verified)
+
+	user, err = repos.Authorization.GetUserByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, email, user.Email)
+
+	_, err = repos.Authorization.GetUserByID(ctx, -1)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	t.Run("Lockout", func(t *testing.T) { testLockout(t, repos, id, email) })
+	t.Run("PasswordReset", func(t *testing.T) { testPasswordReset(t, repos, id) })
+	t.Run("TwoFactor", func(t *testing.T) { testTwoFactor(t, repos, id) })
+	t.Run("Identity", func(t *testing.T) { testIdentity(t, repos, id) })
+	t.Run("Profile", func(t *testing.T) { testProfile(t, repos) })
+	t.Run("DeleteUser", func(t *testing.T) { testDeleteUser(t, repos) })
+	t.Run("Admin", func(t *testing.T) { testAdmin(t, repos) })
+}
+
+func testProfile(t *testing.T, repos *repository.Repository) {
+	userID := createUser(t, repos, "profile")
+	taken := uniqueEmail("taken")
+	_, err := repos.Authorization.CreateUser(ctx, model.User{Name: "other", Email: taken, Password: "hash"})
+	require.NoError(t, err)
+
+	user, err := repos.Authorization.GetUserByID(ctx, userID)
+	require.NoError(t, err)
+
+	user.Name = "renamed"
+	user.Email = uniqueEmail("renamed")
+	user.Verified = false
+	user.Timezone = "Europe/Moscow"
+	user.Locale = "ru-RU"
+	require.NoError(t, repos.Authorization.UpdateProfile(ctx, user))
+
+	got, err := repos.Authorization.GetUserByID(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, user, got)
+
+	user.Email = taken
+	assert.Error(t, repos.Authorization.UpdateProfile(ctx, user))
+
+	// The confirmed pending email replaces the email, the reset tokens mailed to the old one are dropped.
+	user, err = repos.Authorization.GetUserByID(ctx, userID)
+	require.NoError(t, err)
+	user.PendingEmail = uniqueEmail("pending")
+	require.NoError(t, repos.Authorization.UpdateProfile(ctx, user))
+
+	reset := model.PasswordReset{TokenHash: uniqueEmail("profile-reset"), UserID: userID, ExpiresAt: time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)}
+	require.NoError(t, repos.Authorization.CreatePasswordReset(ctx, reset))
+
+	assert.ErrorIs(t, repos.Authorization.ConfirmEmail(ctx, userID, uniqueEmail("previous")), sql.ErrNoRows)
+	require.NoError(t, repos.Authorization.ConfirmEmail(ctx, userID, user.PendingEmail))
+
+	got, err = repos.Authorization.GetUserByID(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, user.PendingEmail, got.Email)
+	assert.Empty(t, got.PendingEmail)
+	assert.True(t, got.Verified)
+
+	_, err = repos.Authorization.TakePasswordReset(ctx, reset.TokenHash)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.ErrorIs(t, repos.Authorization.ConfirmEmail(ctx, userID, user.PendingEmail), sql.ErrNoRows)
+
+	user.ID = -1
+	assert.ErrorIs(t, repos.Authorization.UpdateProfile(ctx, user), sql.ErrNoRows)
+}
+
+func testDeleteUser(t *testing.T, repos *repository.Repository) {
+	userID := createUser(t, repos, "deleted")
+	otherID := createUser(t, repos, "kept")
+
+	listID := createList(t, repos, userID, "list")
+	itemID := createItem(t, repos, listID, model.TodoItem{Title: "item"})
+	require.NoError(t, repos.CalDAV.SaveObject(ctx, model.CalendarObject{ItemID: itemID, Name: "item.ics", UID: "uid"}))
+	otherListID := createList(t, repos, otherID, "list")
+	otherItemID := createItem(t, repos, otherListID, model.TodoItem{Title: "item"})
+
+	feed := uniqueEmail("feed")
+	require.NoError(t, repos.Calendar.CreateFeedToken(ctx, userID, feed))
+	tokenHash := uniqueEmail("token")
+	_, err := repos.AccessToken.Create(ctx, model.AccessToken{
+		UserID: userID, Name: "ci", TokenHash: tokenHash, Scopes: model.Scopes{model.ScopeListsRead}, CreatedAt: time.Now().UTC(),
+	})
+	require.NoError(t, err)
+	subject := uniqueEmail("subject")
+	require.NoError(t, repos.Authorization.LinkIdentity(ctx, model.Identity{Issuer: "https://idp.example.com", Subject: subject, UserID: userID}))
+	resetHash := uniqueEmail("reset")
+	require.NoError(t, repos.Authorization.CreatePasswordReset(ctx, model.PasswordReset{
+		TokenHash: resetHash, UserID: userID, ExpiresAt: time.Now().Add(time.Hour),
+	}))
+
+	workspaceID := personalWorkspace(t, repos, userID)
+	sharedID, err := repos.Workspace.Create(ctx, otherID, "shared")
+	require.NoError(t, err)
+	require.NoError(t, repos.Workspace.SetMember(ctx, sharedID, userID, model.WorkspaceEditor))
+	teamListID, err := repos.TodoList.Create(ctx, sharedID, userID, model.TodoList{Title: "team"})
+	require.NoError(t, err)
+	teamItemID := createItem(t, repos, teamListID, model.TodoItem{Title: "item"})
+
+	require.NoError(t, repos.Authorization.DeleteUser(ctx, userID))
+	assert.ErrorIs(t, repos.Authorization.DeleteUser(ctx, userID), sql.ErrNoRows)
+
+	_, err = repos.Authorization.GetUserByID(ctx, userID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	lists, err := repos.TodoList.GetAll(ctx, workspaceID)
+	require.NoError(t, err)
+	assert.Empty(t, lists)
+
+	items, err := repos.TodoItem.GetAllByWorkspace(ctx, workspaceID)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+
+	_, err = repos.Workspace.GetPersonal(ctx, userID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	members, err := repos.Workspace.GetMembers(ctx, sharedID)
+	require.NoError(t, err)
+	assert.Len(t, members, 1)
+
+	// The lists created in the shared workspace stay there and go to its owner.
+	teamList, err := repos.TodoList.GetByID(ctx, sharedID, teamListID)
+	require.NoError(t, err)
+	assert.Equal(t, otherID, teamList.UserID)
+	_, err = repos.TodoItem.GetByID(ctx, sharedID, teamItemID)
+	assert.NoError(t, err)
+
+	_, err = repos.CalDAV.GetObjectByName(ctx, userID, listID, "item.ics")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = repos.Calendar.GetUserIDByFeedToken(ctx, feed)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = repos.AccessToken.GetByHash(ctx, tokenHash)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = repos.Authorization.GetUserByIdentity(ctx, "https://idp.example.com", subject)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = repos.Authorization.TakePasswordReset(ctx, resetHash)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	item, err := repos.TodoItem.GetByID(ctx, personalWorkspace(t, repos, otherID), otherItemID)
+	require.NoError(t, err)
+	assert.Equal(t, otherListID, item.ListID)
+}
+
+func testAdmin(t *testing.T, repos *repository.Repository) {
+	before, err := repos.Authorization.GetStats(ctx)
+	require.NoError(t, err)
+
+	// The emails are unique across the runs, so the search only finds the users created here.
+	query := strings.TrimSuffix(uniqueEmail("admin"), "@example.com")
+	adminID, err := repos.Authorization.CreateUser(ctx, model.User{Name: "admin", Email: query + "-a@example.com", Password: "hash"})
+	require.NoError(t, err)
+	userID, err := repos.Authorization.CreateUser(ctx, model.User{Name: "user", Email: strings.ToUpper(query) + "-b@example.com", Password: "hash"})
+	require.NoError(t, err)
+
+	user, err := repos.Authorization.GetUserByID(ctx, adminID)
+	require.NoError(t, err)
+	assert.Equal(t, model.RoleUser, user.Role)
+	assert.False(t, user.Disabled)
+
+	require.NoError(t, repos.Authorization.SetRole(ctx, adminID, model.RoleAdmin))
+	require.NoError(t, repos.Authorization.SetDisabled(ctx, userID, true))
+	require.NoError(t, repos.Authorization.RevokeSessions(ctx, userID))
+
+	user, err = repos.Authorization.GetUserByID(ctx, adminID)
+	require.NoError(t, err)
+	assert.Equal(t, model.RoleAdmin, user.Role)
+	assert.Equal(t, 1, user.TokenVersion)
+
+	user, err = repos.Authorization.GetUserByID(ctx, userID)
+	require.NoError(t, err)
+	assert.True(t, user.Disabled)
+	assert.Equal(t, 2, user.TokenVersion)
+
+	users, err := repos.Authorization.GetUsers(ctx, model.SearchUsers{Query: query, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []model.UserSummary{
+		{ID: adminID, Name: "admin", Email: query + "-a@example.com", Role: model.RoleAdmin},
+		{ID: userID, Name: "user", Email: strings.ToUpper(query) + "-b@example.com", Role: model.RoleUser, Disabled: true},
+	}, users)
+
+	users, err = repos.Authorization.GetUsers(ctx, model.SearchUsers{Query: query, Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, userID, users[0].ID)
+
+	users, err = repos.Authorization.GetUsers(ctx, model.SearchUsers{Query: query + "-c", Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, users)
+
+	tokenHash := uniqueEmail("disabled-token")
+	_, err = repos.AccessToken.Create(ctx, model.AccessToken{
+		UserID: userID, Name: "ci", TokenHash: tokenHash, Scopes: model.Scopes{model.ScopeListsRead}, CreatedAt: time.Now().UTC(),
+	})
+	require.NoError(t, err)
+	_, err = repos.AccessToken.GetByHash(ctx, tokenHash)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.NoError(t, repos.Authorization.SetDisabled(ctx, userID, false))
+	_, err = repos.AccessToken.GetByHash(ctx, tokenHash)
+	assert.NoError(t, err)
+
+	listID := createList(t, repos, userID, "list")
+	createItem(t, repos, listID, model.TodoItem{Title: "item", Done: true})
+
+	after, err := repos.Authorization.GetStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, model.Stats{
+		Users:          before.Users + 2,
+		Admins:         before.Admins + 1,
+		DisabledUsers:  before.DisabledUsers,
+		VerifiedUsers:  before.VerifiedUsers,
+		TwoFactorUsers: before.TwoFactorUsers,
+		Lists:          before.Lists + 1,
+		Items:          before.Items + 1,
+		DoneItems:      before.DoneItems + 1,
+		AccessTokens:   before.AccessTokens + 1,
+	}, after)
+
+	assert.ErrorIs(t, repos.Authorization.SetRole(ctx, -1, model.RoleAdmin), sql.ErrNoRows)
+	assert.ErrorIs(t, repos.Authorization.SetDisabled(ctx, -1, true), sql.ErrNoRows)
+	assert.ErrorIs(t, repos.Authorization.RevokeSessions(ctx, -1), sql.ErrNoRows)
+}
+
+func testPasswordReset(t *testing.T, repos *repository.Repository, userID int) {
+	expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	first := model.PasswordReset{TokenHash: uniqueEmail("first"), UserID: userID, ExpiresAt: expiresAt}
+	second := model.PasswordReset{TokenHash: uniqueEmail("second"), UserID: userID, ExpiresAt: expiresAt}
+	require.NoError(t, repos.Authorization.CreatePasswordReset(ctx, first))
+	require.NoError(t, repos.Authorization.CreatePasswordReset(ctx, second))
+
+	reset, err := repos.Authorization.TakePasswordReset(ctx, first.TokenHash)
+	require.NoError(t, err)
+	assert.Equal(t, userID, reset.UserID)
+	assert.True(t, expiresAt.Equal(reset.ExpiresAt))
+
+	_, err = repos.Authorization.TakePasswordReset(ctx, first.TokenHash)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	before, err := repos.Authorization.GetUserByID(ctx, userID)
+	require.NoError(t, err)
+
+	require.NoError(t, repos.Authorization.UpdatePassword(ctx, userID, "new hash"))
+
+	after, err := repos.Authorization.GetUserByID(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "new hash", after.Password)
+	assert.Equal(t, before.TokenVersion+1, after.TokenVersion)
+
+	_, err = repos.Authorization.TakePasswordReset(ctx, second.TokenHash)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	assert.ErrorIs(t, repos.Authorization.UpdatePassword(ctx, -1, "hash"), sql.ErrNoRows)
+}
+
+func testTwoFactor(t *testing.T, repos *repository.Repository, userID int) {
+	assert.ErrorIs(t, repos.Authorization.EnableTOTP(ctx, userID, 10, nil), sql.ErrNoRows)
+
+	require.NoError(t, repos.Authorization.SetTOTPSecret(ctx, userID, "SECRET"))
+	require.NoError(t, repos.Authorization.EnableTOTP(ctx, userID, 10, []string{"first", "second"}))
+
+	user, err := repos.Authorization.GetUserByID(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "SECRET", user.TOTPSecret)
+	assert.True(t, user.TOTPEnabled)
+	assert.Equal(t, int64(10), user.TOTPLastStep)
+
+	assert.ErrorIs(t, repos.Authorization.SetTOTPSecret(ctx, userID, "OTHER"), sql.ErrNoRows)
+	assert.ErrorIs(t, repos.Authorization.EnableTOTP(ctx, userID, 11, nil), sql.ErrNoRows)
+
+	assert.ErrorIs(t, repos.Authorization.UseTOTPStep(ctx, userID, 10), sql.ErrNoRows)
+	require.NoError(t, repos.Authorization.UseTOTPStep(ctx, userID, 11))
+	assert.ErrorIs(t, repos.Authorization.UseTOTPStep(ctx, userID, 11), sql.ErrNoRows)
+
+	require.NoError(t, repos.Authorization.UseRecoveryCode(ctx, userID, "first"))
+	assert.ErrorIs(t, repos.Authorization.UseRecoveryCode(ctx, userID, "first"), sql.ErrNoRows)
+	assert.ErrorIs(t, repos.Authorization.UseRecoveryCode(ctx, -1, "second"), sql.ErrNoRows)
+	require.NoError(t, repos.Authorization.UseRecoveryCode(ctx, userID, "second"))
+}
+
+func testIdentity(t *testing.T, repos *repository.Repository, userID int) {
+	issuer, subject := "https://idp.example.com", uniqueEmail("subject")
+
+	_, err := repos.Authorization.GetUserByIdentity(ctx, issuer, subject)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	identity := model.Identity{Issuer: issuer, Subject: subject, UserID: userID}
+	require.NoError(t, repos.Authorization.LinkIdentity(ctx, identity))
+	assert.Error(t, repos.Authorization.LinkIdentity(ctx, identity))
+
+	user, err := repos.Authorization.GetUserByIdentity(ctx, issuer, subject)
+	require.NoError(t, err)
+	assert.Equal(t, userID, user.ID)
+
+	_, err = repos.Authorization.GetUserByIdentity(ctx, "https://other.example.com", subject)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func testLockout(t *testing.T, repos *repository.Repository, userID int, email string) {
+	for i := 1; i <= 2; i++ {
+		failedLogins, err := repos.Authorization.RecordFailedLogin(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, i, failedLogins)
+	}
+
+	until := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, repos.Authorization.LockUser(ctx, userID, until))
+
+	user, err := repos.Authorization.GetUser(ctx, email)
+	require.NoError(t, err)
+	assert.Equal(t, 0, user.FailedLogins)
+	assert.Equal(t, 1, user.Lockouts)
+	require.NotNil(t, user.LockedUntil)
+	assert.True(t, until.Equal(*user.LockedUntil))
+
+	lockouts, err := repos.Authorization.GetLockouts(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, userIDsOf(lockouts), userID)
+
+	require.NoError(t, repos.Authorization.ResetLockout(ctx, userID))
+
+	user, err = repos.Authorization.GetUser(ctx, email)
+	require.NoError(t, err)
+	assert.Equal(t, 0, user.Lockouts)
+	assert.Nil(t, user.LockedUntil)
+
+	lockouts, err = repos.Authorization.GetLockouts(ctx)
+	require.NoError(t, err)
+	assert.NotContains(t, userIDsOf(lockouts), userID)
+
+	assert.ErrorIs(t, repos.Authorization.ResetLockout(ctx, -1), sql.ErrNoRows)
+	assert.ErrorIs(t, repos.Authorization.LockUser(ctx, -1, until), sql.ErrNoRows)
+}
+
+func userIDsOf(lockouts []model.Lockout) []int {
+	ids := make([]int, 0, len(lockouts))
+	for _, lockout := range lockouts {
+		ids = append(ids, lockout.UserID)
+	}
+
+	return ids
+}
+
+func testTodoList(t *testing.T, repos *repository.Repository) {
+	userID := createUser(t, repos, "owner")
+	otherID := createUser(t, repos, "other")
+	workspaceID := personalWorkspace(t, repos, userID)
+	otherWorkspaceID := personalWorkspace(t, repos, otherID)
+
+	firstID := createList(t, repos, userID, "first")
+	secondID := createList(t, repos, userID, "second")
+	foreignID := createList(t, repos, otherID, "foreign")
+
+	lists, err := repos.TodoList.GetAll(ctx, workspaceID)
+	require.NoError(t, err)
+	require.Len(t, lists, 2)
+	assert.Equal(t, []string{"first", "second"}, []string{lists[0].Title, lists[1].Title})
+
+	_, err = repos.TodoList.GetByID(ctx, otherWorkspaceID, firstID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = repos.TodoList.Update(ctx, workspaceID, firstID, model.UpdateTodoList{Description: test.StringPointer("updated")})
+	require.NoError(t, err)
+
+	list, err := repos.TodoList.GetByID(ctx, workspaceID, firstID)
+	require.NoError(t, err)
+	assert.Equal(t, "first", list.Title)
+	assert.Equal(t, "updated", list.Description)
+	assert.Equal(t, userID, list.UserID)
+	assert.Equal(t, workspaceID, list.WorkspaceID)
+
+	// The list is found by its own id, not the id of the user, and only in the workspace.
+	require.NotEqual(t, userID, secondID)
+	err = repos.TodoList.Update(ctx, workspaceID, secondID, model.UpdateTodoList{Title: test.StringPointer("renamed")})
+	require.NoError(t, err)
+
+	list, err = repos.TodoList.GetByID(ctx, workspaceID, secondID)
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", list.Title)
+
+	list, err = repos.TodoList.GetByID(ctx, workspaceID, firstID)
+	require.NoError(t, err)
+	assert.Equal(t, "first", list.Title)
+
+	err = repos.TodoList.Update(ctx, workspaceID, foreignID, model.UpdateTodoList{Title: test.StringPointer("taken")})
+	require.NoError(t, err)
+
+	list, err = repos.TodoList.GetByID(ctx, otherWorkspaceID, foreignID)
+	require.NoError(t, err)
+	assert.Equal(t, "foreign", list.Title)
+
+	itemID := createItem(t, repos, secondID, model.TodoItem{Title: "item"})
+
+	require.NoError(t, repos.TodoList.Delete(ctx, secondID))
+
+	_, err = repos.TodoList.GetByID(ctx, workspaceID, secondID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = repos.TodoItem.GetByID(ctx, workspaceID, itemID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func testTodoItem(t *testing.T, repos *repository.Repository) {
+	userID := createUser(t, repos, "owner")
+	otherID := createUser(t, repos, "other")
+	workspaceID := personalWorkspace(t, repos, userID)
+
+	firstID := createList(t, repos, userID, "first")
+	secondID := createList(t, repos, userID, "second")
+	foreignID := createList(t, repos, otherID, "foreign")
+
+	doneID := createItem(t, repos, firstID, model.TodoItem{Title: "done", CompletionDate: "2021-11-21 10:00:00", Done: true})
+	openID := createItem(t, repos, secondID, model.TodoItem{Title: "open", Description: "testing", CompletionDate: "2021-11-22 10:00:00"})
+	foreignItemID := createItem(t, repos, foreignID, model.TodoItem{Title: "foreign"})
+
+	items, err := repos.TodoItem.GetAll(ctx, firstID)
+	require.NoError(t, err)
+	assert.Equal(t, []model.TodoItem{
+		{ID: doneID, ListID: firstID, Title: "done", CompletionDate: "2021-11-21 10:00:00", Done: true},
+	}, normalizeDates(items))
+
+	items, err = repos.TodoItem.GetAllByLists(ctx, workspaceID, []int{firstID, secondID, foreignID})
+	require.NoError(t, err)
+	assert.Equal(t, []int{doneID, openID}, itemIDs(items))
+
+	items, err = repos.TodoItem.GetAllByLists(ctx, workspaceID, nil)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+
+	items, err = repos.TodoItem.GetAllByWorkspace(ctx, workspaceID)
+	require.NoError(t, err)
+	assert.Equal(t, []int{doneID, openID}, itemIDs(items))
+
+	_, err = repos.TodoItem.GetByID(ctx, workspaceID, foreignItemID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = repos.TodoItem.Update(ctx, openID, model.UpdateTodoItem{Title: test.StringPointer("closed"), Done: test.BoolPointer(true)})
+	require.NoError(t, err)
+
+	item, err := repos.TodoItem.GetByID(ctx, workspaceID, openID)
+	require.NoError(t, err)
+	assert.Equal(t, []model.TodoItem{
+		{ID: openID, ListID: secondID, Title: "closed", Description: "testing", CompletionDate: "2021-11-22 10:00:00", Done: true},
+	}, normalizeDates([]model.TodoItem{item}))
+
+	require.NoError(t, repos.TodoItem.Delete(ctx, openID))
+
+	_, err = repos.TodoItem.GetByID(ctx, workspaceID, openID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func testCalendar(t *testing.T, repos *repository.Repository) {
+	userID := createUser(t, repos, "owner")
+	otherID := createUser(t, repos, "other")
+	token := uniqueEmail("token")
+
+	require.NoError(t, repos.Calendar.CreateFeedToken(ctx, userID, token))
+	assert.ErrorIs(t, repos.Calendar.CreateFeedToken(ctx, userID, uniqueEmail("token")), repository.ErrFeedTokenExists)
+
+	id, err := repos.Calendar.GetUserIDByFeedToken(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, userID, id)
+
+	require.NoError(t, repos.Authorization.SetDisabled(ctx, userID, true))
+	_, err = repos.Calendar.GetUserIDByFeedToken(ctx, token)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	require.NoError(t, repos.Authorization.SetDisabled(ctx, userID, false))
+
+	rotated := uniqueEmail("rotated")
+	require.NoError(t, repos.Calendar.UpdateFeedToken(ctx, userID, rotated))
+	assert.ErrorIs(t, repos.Calendar.UpdateFeedToken(ctx, otherID, uniqueEmail("token")), sql.ErrNoRows)
+
+	_, err = repos.Calendar.GetUserIDByFeedToken(ctx, token)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	listID := createList(t, repos, userID, "list")
+	laterID := createItem(t, repos, listID, model.TodoItem{Title: "later", CompletionDate: "2021-11-23 10:00:00"})
+	soonerID := createItem(t, repos, listID, model.TodoItem{Title: "sooner", CompletionDate: "2021-11-21 10:00:00"})
+
+	items, err := repos.Calendar.GetAllItems(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, []int{soonerID, laterID}, itemIDs(items))
+}
+
+func testCalDAV(t *testing.T, repos *repository.Repository) {
+	userID := createUser(t, repos, "owner")
+	otherID := createUser(t, repos, "other")
+
+	listID := createList(t, repos, userID, "list")
+	itemID := createItem(t, repos, listID, model.TodoItem{Title: "item"})
+	deletedID := createItem(t, repos, listID, model.TodoItem{Title: "deleted"})
+
+	require.NoError(t, repos.CalDAV.SaveObject(ctx, model.CalendarObject{ItemID: itemID, Name: "a.ics", UID: "a"}))
+	require.NoError(t, repos.CalDAV.SaveObject(ctx, model.CalendarObject{ItemID: itemID, Name: "b.ics", UID: "b"}))
+	require.NoError(t, repos.CalDAV.SaveObject(ctx, model.CalendarObject{ItemID: deletedID, Name: "c.ics", UID: "c"}))
+
+	object, err := repos.CalDAV.GetObjectByName(ctx, userID, listID, "b.ics")
+	require.NoError(t, err)
+	assert.Equal(t, model.CalendarObject{ItemID: itemID, Name: "b.ics", UID: "b"}, object)
+
+	_, err = repos.CalDAV.GetObjectByName(ctx, userID, listID, "a.ics")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = repos.CalDAV.GetObjectByName(ctx, otherID, listID, "b.ics")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.NoError(t, repos.TodoItem.Delete(ctx, deletedID))
+
+	objects, err := repos.CalDAV.GetObjects(ctx, userID, listID)
+	require.NoError(t, err)
+	assert.Equal(t, []model.CalendarObject{{ItemID: itemID, Name: "b.ics", UID: "b"}}, objects)
+
+	objects, err = repos.CalDAV.GetObjects(ctx, otherID, listID)
+	require.NoError(t, err)
+	assert.Empty(t, objects)
+}
+
+func testAccessToken(t *testing.T, repos *repository.Repository) {
+	userID := createUser(t, repos, "owner")
+	otherID := createUser(t, repos, "other")
+
+	expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	createdAt := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	token := model.AccessToken{
+		UserID:    userID,
+		Name:      "ci",
+		TokenHash: uniqueEmail("token"),
+		Scopes:    model.Scopes{model.ScopeListsRead, model.ScopeItemsWrite},
+		ExpiresAt: &expiresAt,
+		CreatedAt: createdAt,
+	}
+
+	id, err := repos.AccessToken.Create(ctx, token)
+	require.NoError(t, err)
+	_, err = repos.AccessToken.Create(ctx, token)
+	assert.Error(t, err)
+
+	secondID, err := repos.AccessToken.Create(ctx, model.AccessToken{
+		UserID: userID, Name: "script", TokenHash: uniqueEmail("token"), Scopes: model.Scopes{model.ScopeListsRead}, CreatedAt: createdAt,
+	})
+	require.NoError(t, err)
+
+	got, err := repos.AccessToken.GetByHash(ctx, token.TokenHash)
+	require.NoError(t, err)
+	assert.Equal(t, id, got.ID)
+	assert.Equal(t, userID, got.UserID)
+	assert.Equal(t, "ci", got.Name)
+	assert.Equal(t, token.Scopes, got.Scopes)
+	require.NotNil(t, got.ExpiresAt)
+	assert.True(t, expiresAt.Equal(*got.ExpiresAt))
+	assert.Nil(t, got.LastUsedAt)
+	assert.True(t, createdAt.Equal(got.CreatedAt))
+
+	usedAt := time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC)
+	require.NoError(t, repos.AccessToken.UpdateLastUsed(ctx, id, usedAt))
+	assert.ErrorIs(t, repos.AccessToken.UpdateLastUsed(ctx, -1, usedAt), sql.ErrNoRows)
+
+	got, err = repos.AccessToken.GetByID(ctx, userID, id)
+	require.NoError(t, err)
+	require.NotNil(t, got.LastUsedAt)
+	assert.True(t, usedAt.Equal(*got.LastUsedAt))
+
+	_, err = repos.AccessToken.GetByID(ctx, otherID, id)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	tokens, err := repos.AccessToken.GetAll(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+	assert.Equal(t, id, tokens[0].ID)
+	assert.Equal(t, secondID, tokens[1].ID)
+	assert.Nil(t, tokens[1].ExpiresAt)
+
+	tokens, err = repos.AccessToken.GetAll(ctx, otherID)
+	require.NoError(t, err)
+	assert.Empty(t, tokens)
+
+	assert.ErrorIs(t, repos.AccessToken.Delete(ctx, otherID, id), sql.ErrNoRows)
+	require.NoError(t, repos.AccessToken.Delete(ctx, userID, id))
+	assert.ErrorIs(t, repos.AccessToken.Delete(ctx, userID, id), sql.ErrNoRows)
+
+	_, err = repos.AccessToken.GetByHash(ctx, token.TokenHash)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func testWorkspace(t *testing.T, repos *repository.Repository) {
+	ownerID := createUser(t, repos, "owner")
+	memberID := createUser(t, repos, "member")
+	outsiderID := createUser(t, repos, "outsider")
+
+	personal, err := repos.Workspace.GetPersonal(ctx, ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, model.Workspace{ID: personal.ID, Name: model.PersonalWorkspaceName, Personal: true, Role: model.WorkspaceOwner}, personal)
+
+	workspaceID, err := repos.Workspace.Create(ctx, ownerID, "team")
+	require.NoError(t, err)
+	require.NoError(t, repos.Workspace.SetMember(ctx, workspaceID, memberID, model.WorkspaceViewer))
+	require.NoError(t, repos.Workspace.SetMember(ctx, workspaceID, memberID, model.WorkspaceEditor))
+
+	workspaces, err := repos.Workspace.GetAll(ctx, ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Workspace{
+		personal,
+		{ID: workspaceID, Name: "team", Role: model.WorkspaceOwner},
+	}, workspaces)
+
+	workspace, err := repos.Workspace.GetByID(ctx, memberID, workspaceID)
+	require.NoError(t, err)
+	assert.Equal(t, model.Workspace{ID: workspaceID, Name: "team", Role: model.WorkspaceEditor}, workspace)
+
+	_, err = repos.Workspace.GetByID(ctx, outsiderID, workspaceID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = repos.Workspace.GetByID(ctx, memberID, personal.ID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	members, err := repos.Workspace.GetMembers(ctx, workspaceID)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+	assert.Equal(t, []string{model.WorkspaceOwner, model.WorkspaceEditor}, []string{members[0].Role, members[1].Role})
+	assert.Equal(t, memberID, members[1].UserID)
+	assert.Equal(t, "member", members[1].Name)
+
+	require.NoError(t, repos.Workspace.Update(ctx, workspaceID, "renamed"))
+	workspace, err = repos.Workspace.GetByID(ctx, ownerID, workspaceID)
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", workspace.Name)
+
+	// The lists belong to the workspace, whoever of the members created them.
+	listID, err := repos.TodoList.Create(ctx, workspaceID, memberID, model.TodoList{Title: "shared"})
+	require.NoError(t, err)
+	itemID := createItem(t, repos, listID, model.TodoItem{Title: "item"})
+
+	lists, err := repos.TodoList.GetAll(ctx, workspaceID)
+	require.NoError(t, err)
+	require.Len(t, lists, 1)
+	assert.Equal(t, memberID, lists[0].UserID)
+
+	lists, err = repos.TodoList.GetAll(ctx, personalWorkspace(t, repos, memberID))
+	require.NoError(t, err)
+	assert.Empty(t, lists)
+
+	require.NoError(t, repos.Workspace.DeleteMember(ctx, workspaceID, memberID))
+	assert.ErrorIs(t, repos.Workspace.DeleteMember(ctx, workspaceID, memberID), sql.ErrNoRows)
+
+	_, err = repos.Workspace.GetByID(ctx, memberID, workspaceID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.NoError(t, repos.Workspace.Delete(ctx, workspaceID))
+	assert.ErrorIs(t, repos.Workspace.Delete(ctx, workspaceID), sql.ErrNoRows)
+	assert.ErrorIs(t, repos.Workspace.Update(ctx, workspaceID, "gone"), sql.ErrNoRows)
+
+	_, err = repos.TodoList.GetByID(ctx, workspaceID, listID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = repos.TodoItem.GetByID(ctx, workspaceID, itemID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	workspaces, err = repos.Workspace.GetAll(ctx, ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Workspace{personal}, workspaces)
+}
+
+func testTxManager(t *testing.T, repos *repository.Repository) {
+	userID := createUser(t, repos, "tx")
+	workspaceID := personalWorkspace(t, repos, userID)
+	errRollback := errors.New("rollback")
+
+	var committedID int
+	err := repos.TxManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		committedID, err = repos.TodoList.Create(ctx, workspaceID, userID, model.TodoList{Title: "committed"})
+		return err
+	})
+	require.NoError(t, err)
+
+	_, err = repos.TodoList.GetByID(ctx, workspaceID, committedID)
+	assert.NoError(t, err)
+
+	var rolledBackID int
+	err = repos.TxManager.WithinTx(ctx, func(ctx context.Context) error {
+		rolledBackID, _ = repos.TodoList.Create(ctx, workspaceID, userID, model.TodoList{Title: "rolled back"})
+		return errRollback
+	})
+	assert.ErrorIs(t, err, errRollback)
+
+	_, err = repos.TodoList.GetByID(ctx, workspaceID, rolledBackID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	var panickedID int
+	assert.Panics(t, func() {
+		repos.TxManager.WithinTx(ctx, func(ctx context.Context) error {
+			panickedID, _ = repos.TodoList.Create(ctx, workspaceID, userID, model.TodoList{Title: "panicked"})
+			panic("boom")
+		})
+	})
+
+	_, err = repos.TodoList.GetByID(ctx, workspaceID, panickedID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	var outerID, innerID int
+	err = repos.TxManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if outerID, err = repos.TodoList.Create(ctx, workspaceID, userID, model.TodoList{Title: "outer"}); err != nil {
+			return err
+		}
+
+		err = repos.TxManager.WithinTx(ctx, func(ctx context.Context) error {
+			innerID, _ = repos.TodoList.Create(ctx, workspaceID, userID, model.TodoList{Title: "inner"})
+			return errRollback
+		})
+		assert.ErrorIs(t, err, errRollback)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	_, err = repos.TodoList.GetByID(ctx, workspaceID, outerID)
+	assert.NoError(t, err)
+
+	_, err = repos.TodoList.GetByID(ctx, workspaceID, innerID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
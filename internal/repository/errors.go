@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/Lapp-coder/todo-app/internal/repository/repoerr"
+)
+
+var (
+	ErrUnknownDriver = errors.New("unknown storage driver")
+	// ErrFeedTokenExists is returned by Calendar.CreateFeedToken when the user has a feed already.
+	ErrFeedTokenExists = repoerr.ErrFeedTokenExists
+)
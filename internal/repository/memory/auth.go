@@ -0,0 +1,526 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+)
+
+type AuthRepository struct {
+	store *Store
+}
+
+func NewAuthRepository(store *Store) *AuthRepository {
+	return &AuthRepository{store: store}
+}
+
+func (r *AuthRepository) CreateUser(ctx context.Context, user model.User) (int, error) {
+	defer r.store.lock(ctx)()
+
+	for _, u := range r.store.users {
+		if u.Email == user.Email {
+			return 0, errEmailAlreadyExists
+		}
+	}
+
+	if user.Timezone == "" {
+		user.Timezone = model.DefaultTimezone
+	}
+	if user.Locale == "" {
+		user.Locale = model.DefaultLocale
+	}
+	if user.Role == "" {
+		user.Role = model.RoleUser
+	}
+
+	r.store.lastUserID++
+	user.ID = r.store.lastUserID
+	r.store.users[user.ID] = user
+	r.store.createWorkspace(model.PersonalWorkspaceName, user.ID, user.ID)
+
+	return user.ID, nil
+}
+
+func (r *AuthRepository) GetUser(ctx context.Context, email string) (model.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, user := range r.store.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+
+	return model.User{}, sql.ErrNoRows
+}
+
+func (r *AuthRepository) GetUserByID(ctx context.Context, userID int) (model.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	user, ok := r.store.users[userID]
+	if !ok {
+		return model.User{}, sql.ErrNoRows
+	}
+
+	return user, nil
+}
+
+func (r *AuthRepository) GetUserByIdentity(ctx context.Context, issuer, subject string) (model.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	user, ok := r.store.users[r.store.identities[identityKey{issuer, subject}]]
+	if !ok {
+		return model.User{}, sql.ErrNoRows
+	}
+
+	return user, nil
+}
+
+func (r *AuthRepository) LinkIdentity(ctx context.Context, identity model.Identity) error {
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.users[identity.UserID]; !ok {
+		return errUserNotFound
+	}
+
+	key := identityKey{identity.Issuer, identity.Subject}
+	if _, ok := r.store.identities[key]; ok {
+		return errIdentityAlreadyLinked
+	}
+
+	r.store.identities[key] = identity.UserID
+
+	return nil
+}
+
+// UpdatePassword also signs out all the sessions of the user and drops their reset tokens.
+func (r *AuthRepository) UpdatePassword(ctx context.Context, userID int, passwordHash string) error {
+	defer r.store.lock(ctx)()
+
+	user, ok := r.store.users[userID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	user.Password = passwordHash
+	user.TokenVersion++
+	r.store.users[userID] = user
+
+	for hash, reset := range r.store.resets {
+		if reset.UserID == userID {
+			delete(r.store.resets, hash)
+		}
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) UpdateProfile(ctx context.Context, user model.User) error {
+	defer r.store.lock(ctx)()
+
+	stored, ok := r.store.users[user.ID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	for id, u := range r.store.users {
+		if id != user.ID && u.Email == user.Email {
+			return errEmailAlreadyExists
+		}
+	}
+
+	stored.Name = user.Name
+	stored.Email = user.Email
+	stored.Verified = user.Verified
+	stored.PendingEmail = user.PendingEmail
+	stored.Timezone = user.Timezone
+	stored.Locale = user.Locale
+	r.store.users[user.ID] = stored
+
+	return nil
+}
+
+// DeleteUser deletes the user with all their data, the lists they created in the other workspaces
+// are handed to an owner of the workspace.
+func (r *AuthRepository) DeleteUser(ctx context.Context, userID int) error {
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.users[userID]; !ok {
+		return sql.ErrNoRows
+	}
+
+	personalID := r.store.personalWorkspace(userID)
+	for id, list := range r.store.lists {
+		if list.UserID != userID {
+			continue
+		}
+
+		if list.WorkspaceID != personalID {
+			list.UserID = r.store.otherOwner(list.WorkspaceID, userID)
+			r.store.lists[id] = list
+			continue
+		}
+
+		for itemID, item := range r.store.items {
+			if item.ListID == id {
+				r.store.deleteItem(itemID)
+			}
+		}
+
+		delete(r.store.lists, id)
+	}
+
+	for hash, reset := range r.store.resets {
+		if reset.UserID == userID {
+			delete(r.store.resets, hash)
+		}
+	}
+
+	for key, id := range r.store.identities {
+		if id == userID {
+			delete(r.store.identities, key)
+		}
+	}
+
+	for id, token := range r.store.tokens {
+		if token.UserID == userID {
+			delete(r.store.tokens, id)
+		}
+	}
+
+	delete(r.store.workspaces, personalID)
+
+	for key := range r.store.members {
+		if key.userID == userID {
+			delete(r.store.members, key)
+		}
+	}
+
+	delete(r.store.feeds, userID)
+	delete(r.store.recoveryCodes, userID)
+	delete(r.store.users, userID)
+
+	return nil
+}
+
+func (r *AuthRepository) CreatePasswordReset(ctx context.Context, reset model.PasswordReset) error {
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.users[reset.UserID]; !ok {
+		return errUserNotFound
+	}
+
+	r.store.resets[reset.TokenHash] = reset
+
+	return nil
+}
+
+// TakePasswordReset deletes the reset token so it can't be used twice, expired or not.
+func (r *AuthRepository) TakePasswordReset(ctx context.Context, tokenHash string) (model.PasswordReset, error) {
+	defer r.store.lock(ctx)()
+
+	reset, ok := r.store.resets[tokenHash]
+	if !ok {
+		return model.PasswordReset{}, sql.ErrNoRows
+	}
+
+	delete(r.store.resets, tokenHash)
+
+	return reset, nil
+}
+
+func (r *AuthRepository) VerifyEmail(ctx context.Context, userID int, email string) error {
+	defer r.store.lock(ctx)()
+
+	user, ok := r.store.users[userID]
+	if !ok || user.Email != email {
+		return sql.ErrNoRows
+	}
+
+	user.Verified = true
+	r.store.users[userID] = user
+
+	return nil
+}
+
+func (r *AuthRepository) ConfirmEmail(ctx context.Context, userID int, email string) error {
+	defer r.store.lock(ctx)()
+
+	user, ok := r.store.users[userID]
+	if !ok || user.PendingEmail == "" || user.PendingEmail != email {
+		return sql.ErrNoRows
+	}
+
+	for id, u := range r.store.users {
+		if id != userID && u.Email == email {
+			return errEmailAlreadyExists
+		}
+	}
+
+	user.Email = user.PendingEmail
+	user.PendingEmail = ""
+	user.Verified = true
+	r.store.users[userID] = user
+
+	for hash, reset := range r.store.resets {
+		if reset.UserID == userID {
+			delete(r.store.resets, hash)
+		}
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) RecordFailedLogin(ctx context.Context, userID int) (int, error) {
+	defer r.store.lock(ctx)()
+
+	user, ok := r.store.users[userID]
+	if !ok {
+		return 0, sql.ErrNoRows
+	}
+
+	user.FailedLogins++
+	r.store.users[userID] = user
+
+	return user.FailedLogins, nil
+}
+
+func (r *AuthRepository) LockUser(ctx context.Context, userID int, until time.Time) error {
+	defer r.store.lock(ctx)()
+
+	user, ok := r.store.users[userID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	user.FailedLogins = 0
+	user.Lockouts++
+	user.LockedUntil = &until
+	r.store.users[userID] = user
+
+	return nil
+}
+
+func (r *AuthRepository) ResetLockout(ctx context.Context, userID int) error {
+	defer r.store.lock(ctx)()
+
+	user, ok := r.store.users[userID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	user.FailedLogins = 0
+	user.Lockouts = 0
+	user.LockedUntil = nil
+	r.store.users[userID] = user
+
+	return nil
+}
+
+func (r *AuthRepository) GetLockouts(ctx context.Context) ([]model.Lockout, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	lockouts := make([]model.Lockout, 0)
+	for _, user := range r.store.users {
+		if user.FailedLogins == 0 && user.Lockouts == 0 {
+			continue
+		}
+
+		lockouts = append(lockouts, model.Lockout{
+			UserID:       user.ID,
+			Email:        user.Email,
+			FailedLogins: user.FailedLogins,
+			Lockouts:     user.Lockouts,
+			LockedUntil:  user.LockedUntil,
+		})
+	}
+
+	sort.Slice(lockouts, func(i, j int) bool {
+		return lockouts[i].UserID < lockouts[j].UserID
+	})
+
+	return lockouts, nil
+}
+
+func (r *AuthRepository) SetTOTPSecret(ctx context.Context, userID int, secret string) error {
+	defer r.store.lock(ctx)()
+
+	user, ok := r.store.users[userID]
+	if !ok || user.TOTPEnabled {
+		return sql.ErrNoRows
+	}
+
+	user.TOTPSecret = secret
+	r.store.users[userID] = user
+
+	return nil
+}
+
+func (r *AuthRepository) EnableTOTP(ctx context.Context, userID int, step int64, codeHashes []string) error {
+	defer r.store.lock(ctx)()
+
+	user, ok := r.store.users[userID]
+	if !ok || user.TOTPEnabled || user.TOTPSecret == "" {
+		return sql.ErrNoRows
+	}
+
+	user.TOTPEnabled = true
+	user.TOTPLastStep = step
+	r.store.users[userID] = user
+	r.store.recoveryCodes[userID] = append([]string(nil), codeHashes...)
+
+	return nil
+}
+
+func (r *AuthRepository) UseTOTPStep(ctx context.Context, userID int, step int64) error {
+	defer r.store.lock(ctx)()
+
+	user, ok := r.store.users[userID]
+	if !ok || user.TOTPLastStep >= step {
+		return sql.ErrNoRows
+	}
+
+	user.TOTPLastStep = step
+	r.store.users[userID] = user
+
+	return nil
+}
+
+func (r *AuthRepository) UseRecoveryCode(ctx context.Context, userID int, codeHash string) error {
+	defer r.store.lock(ctx)()
+
+	hashes := r.store.recoveryCodes[userID]
+	for i, hash := range hashes {
+		if hash == codeHash {
+			r.store.recoveryCodes[userID] = append(append([]string(nil), hashes[:i]...), hashes[i+1:]...)
+			return nil
+		}
+	}
+
+	return sql.ErrNoRows
+}
+
+func (r *AuthRepository) GetUsers(ctx context.Context, search model.SearchUsers) ([]model.UserSummary, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	query := strings.ToLower(search.Query)
+
+	users := make([]model.UserSummary, 0)
+	for _, user := range r.store.users {
+		if !strings.Contains(strings.ToLower(user.Name), query) && !strings.Contains(strings.ToLower(user.Email), query) {
+			continue
+		}
+
+		users = append(users, model.UserSummary{
+			ID:               user.ID,
+			Name:             user.Name,
+			Email:            user.Email,
+			Role:             user.Role,
+			EmailVerified:    user.Verified,
+			TwoFactorEnabled: user.TOTPEnabled,
+			Disabled:         user.Disabled,
+		})
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ID < users[j].ID
+	})
+
+	if search.Offset >= len(users) {
+		return make([]model.UserSummary, 0), nil
+	}
+
+	users = users[search.Offset:]
+	if len(users) > search.Limit {
+		users = users[:search.Limit]
+	}
+
+	return users, nil
+}
+
+func (r *AuthRepository) SetRole(ctx context.Context, userID int, role string) error {
+	defer r.store.lock(ctx)()
+
+	user, ok := r.store.users[userID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	user.Role = role
+	user.TokenVersion++
+	r.store.users[userID] = user
+
+	return nil
+}
+
+func (r *AuthRepository) SetDisabled(ctx context.Context, userID int, disabled bool) error {
+	defer r.store.lock(ctx)()
+
+	user, ok := r.store.users[userID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	user.Disabled = disabled
+	user.TokenVersion++
+	r.store.users[userID] = user
+
+	return nil
+}
+
+func (r *AuthRepository) RevokeSessions(ctx context.Context, userID int) error {
+	defer r.store.lock(ctx)()
+
+	user, ok := r.store.users[userID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	user.TokenVersion++
+	r.store.users[userID] = user
+
+	return nil
+}
+
+func (r *AuthRepository) GetStats(ctx context.Context) (model.Stats, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	stats := model.Stats{
+		Users:        len(r.store.users),
+		Lists:        len(r.store.lists),
+		Items:        len(r.store.items),
+		AccessTokens: len(r.store.tokens),
+	}
+
+	for _, user := range r.store.users {
+		if user.Role == model.RoleAdmin {
+			stats.Admins++
+		}
+		if user.Disabled {
+			stats.DisabledUsers++
+		}
+		if user.Verified {
+			stats.VerifiedUsers++
+		}
+		if user.TOTPEnabled {
+			stats.TwoFactorUsers++
+		}
+	}
+
+	for _, item := range r.store.items {
+		if item.Done {
+			stats.DoneItems++
+		}
+	}
+
+	return stats, nil
+}
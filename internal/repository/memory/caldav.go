@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+)
+
+type CalDAVRepository struct {
+	store *Store
+}
+
+func NewCalDAVRepository(store *Store) *CalDAVRepository {
+	return &CalDAVRepository{store: store}
+}
+
+func (r *CalDAVRepository) GetObjects(ctx context.Context, userID, listID int) ([]model.CalendarObject, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if !r.store.ownsList(userID, listID) {
+		return nil, nil
+	}
+
+	var objects []model.CalendarObject
+	for itemID, object := range r.store.objects {
+		if r.store.items[itemID].ListID == listID {
+			objects = append(objects, object)
+		}
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].ItemID < objects[j].ItemID
+	})
+
+	return objects, nil
+}
+
+func (r *CalDAVRepository) GetObjectByName(ctx context.Context, userID, listID int, name string) (model.CalendarObject, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if r.store.ownsList(userID, listID) {
+		for itemID, object := range r.store.objects {
+			if object.Name == name && r.store.items[itemID].ListID == listID {
+				return object, nil
+			}
+		}
+	}
+
+	return model.CalendarObject{}, sql.ErrNoRows
+}
+
+func (r *CalDAVRepository) SaveObject(ctx context.Context, object model.CalendarObject) error {
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.items[object.ItemID]; !ok {
+		return errItemNotFound
+	}
+
+	r.store.objects[object.ItemID] = object
+
+	return nil
+}
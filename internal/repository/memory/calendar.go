@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/repository/repoerr"
+)
+
+type CalendarRepository struct {
+	store *Store
+}
+
+func NewCalendarRepository(store *Store) *CalendarRepository {
+	return &CalendarRepository{store: store}
+}
+
+// CreateFeedToken returns repoerr.ErrFeedTokenExists when the user has a feed already.
+func (r *CalendarRepository) CreateFeedToken(ctx context.Context, userID int, tokenHash string) error {
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.feeds[userID]; ok {
+		return repoerr.ErrFeedTokenExists
+	}
+
+	if r.tokenExists(tokenHash) {
+		return errTokenAlreadyExists
+	}
+
+	r.store.feeds[userID] = tokenHash
+
+	return nil
+}
+
+func (r *CalendarRepository) UpdateFeedToken(ctx context.Context, userID int, tokenHash string) error {
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.feeds[userID]; !ok {
+		return sql.ErrNoRows
+	}
+
+	if r.tokenExists(tokenHash) {
+		return errTokenAlreadyExists
+	}
+
+	r.store.feeds[userID] = tokenHash
+
+	return nil
+}
+
+// GetUserIDByFeedToken skips the feeds of the disabled users.
+func (r *CalendarRepository) GetUserIDByFeedToken(ctx context.Context, tokenHash string) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for userID, t := range r.store.feeds {
+		if t == tokenHash && !r.store.users[userID].Disabled {
+			return userID, nil
+		}
+	}
+
+	return 0, sql.ErrNoRows
+}
+
+func (r *CalendarRepository) GetAllItems(ctx context.Context, userID int) ([]model.TodoItem, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	workspaceID := r.store.personalWorkspace(userID)
+
+	var items []model.TodoItem
+	for _, item := range r.store.items {
+		if r.store.inWorkspace(workspaceID, item.ListID) {
+			items = append(items, item)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].CompletionDate != items[j].CompletionDate {
+			return items[i].CompletionDate < items[j].CompletionDate
+		}
+
+		return items[i].ID < items[j].ID
+	})
+
+	return items, nil
+}
+
+// tokenExists must be called with the lock held.
+func (r *CalendarRepository) tokenExists(token string) bool {
+	for _, t := range r.store.feeds {
+		if t == token {
+			return true
+		}
+	}
+
+	return false
+}
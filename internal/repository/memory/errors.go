@@ -0,0 +1,15 @@
+package memory
+
+import "errors"
+
+var (
+	errEmailAlreadyExists       = errors.New("user with this email already exists")
+	errUserNotFound             = errors.New("user not found")
+	errTitleIsEmpty             = errors.New("title is empty")
+	errListNotFound             = errors.New("list not found")
+	errItemNotFound             = errors.New("item not found")
+	errTokenAlreadyExists       = errors.New("calendar feed token already exists")
+	errIdentityAlreadyLinked    = errors.New("identity is already linked")
+	errAccessTokenAlreadyExists = errors.New("access token already exists")
+	errWorkspaceNotFound        = errors.New("workspace not found")
+)
@@ -0,0 +1,151 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+)
+
+// Store keeps all the data of the in-memory backend. The repositories share a single
+// store the same way the postgres repositories share a connection pool.
+type Store struct {
+	mu sync.RWMutex
+	// txMu is held by the running transaction, the writes outside of it wait for it to end.
+	txMu sync.Mutex
+
+	users   map[int]model.User
+	lists   map[int]model.TodoList
+	items   map[int]model.TodoItem
+	feeds   map[int]string
+	objects map[int]model.CalendarObject
+	// resets are keyed by the token hash.
+	resets map[string]model.PasswordReset
+	// recoveryCodes are the code hashes by the user id.
+	recoveryCodes map[int][]string
+	// identities are the user ids by the issuer and the subject.
+	identities map[identityKey]int
+	// tokens are the personal access tokens by their id.
+	tokens map[int]model.AccessToken
+	// workspaces are keyed by their id, members are the roles by the workspace and the user.
+	workspaces map[int]workspace
+	members    map[memberKey]string
+
+	lastUserID      int
+	lastListID      int
+	lastItemID      int
+	lastTokenID     int
+	lastWorkspaceID int
+}
+
+type identityKey struct {
+	issuer  string
+	subject string
+}
+
+// workspace has no personal user unless it's the personal workspace of the user.
+type workspace struct {
+	name           string
+	personalUserID int
+}
+
+type memberKey struct {
+	workspaceID int
+	userID      int
+}
+
+func NewStore() *Store {
+	return &Store{
+		users:   make(map[int]model.User),
+		lists:   make(map[int]model.TodoList),
+		items:   make(map[int]model.TodoItem),
+		feeds:   make(map[int]string),
+		objects: make(map[int]model.CalendarObject),
+		resets:  make(map[string]model.PasswordReset),
+
+		recoveryCodes: make(map[int][]string),
+		identities:    make(map[identityKey]int),
+		tokens:        make(map[int]model.AccessToken),
+		workspaces:    make(map[int]workspace),
+		members:       make(map[memberKey]string),
+	}
+}
+
+// ownsList reports whether the list is in the personal workspace of the user, it must be called
+// with the lock held.
+func (s *Store) ownsList(userID, listID int) bool {
+	list, ok := s.lists[listID]
+
+	return ok && list.WorkspaceID == s.personalWorkspace(userID)
+}
+
+// inWorkspace must be called with the lock held.
+func (s *Store) inWorkspace(workspaceID, listID int) bool {
+	list, ok := s.lists[listID]
+
+	return ok && list.WorkspaceID == workspaceID
+}
+
+// createWorkspace must be called with the write lock held.
+func (s *Store) createWorkspace(name string, personalUserID, ownerID int) int {
+	s.lastWorkspaceID++
+	s.workspaces[s.lastWorkspaceID] = workspace{name: name, personalUserID: personalUserID}
+	s.members[memberKey{workspaceID: s.lastWorkspaceID, userID: ownerID}] = model.WorkspaceOwner
+
+	return s.lastWorkspaceID
+}
+
+// personalWorkspace must be called with the lock held, it returns 0 when the user has none.
+func (s *Store) personalWorkspace(userID int) int {
+	for id, w := range s.workspaces {
+		if w.personalUserID == userID {
+			return id
+		}
+	}
+
+	return 0
+}
+
+// otherOwner must be called with the lock held, it returns the owner of the workspace with
+// the lowest id besides the user, or 0 when there is none.
+func (s *Store) otherOwner(workspaceID, userID int) int {
+	var ownerID int
+	for key, role := range s.members {
+		if key.workspaceID != workspaceID || key.userID == userID || role != model.WorkspaceOwner {
+			continue
+		}
+
+		if ownerID == 0 || key.userID < ownerID {
+			ownerID = key.userID
+		}
+	}
+
+	return ownerID
+}
+
+// deleteItem must be called with the write lock held.
+func (s *Store) deleteItem(itemID int) {
+	delete(s.items, itemID)
+	delete(s.objects, itemID)
+}
+
+func now() string {
+	return time.Now().Format(model.CompletionDateLayout)
+}
+
+func sortLists(lists []model.TodoList) {
+	sort.Slice(lists, func(i, j int) bool {
+		return lists[i].ID < lists[j].ID
+	})
+}
+
+func sortItems(items []model.TodoItem) {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].ListID != items[j].ListID {
+			return items[i].ListID < items[j].ListID
+		}
+
+		return items[i].ID < items[j].ID
+	})
+}
@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+)
+
+type TodoItemRepository struct {
+	store *Store
+}
+
+func NewTodoItemRepository(store *Store) *TodoItemRepository {
+	return &TodoItemRepository{store: store}
+}
+
+func (r *TodoItemRepository) Create(ctx context.Context, listID int, item model.TodoItem) (int, error) {
+	if item.Title == "" {
+		return 0, errTitleIsEmpty
+	}
+
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.lists[listID]; !ok {
+		return 0, errListNotFound
+	}
+
+	if item.CompletionDate == "" {
+		item.CompletionDate = now()
+	}
+
+	r.store.lastItemID++
+	item.ID = r.store.lastItemID
+	item.ListID = listID
+	r.store.items[item.ID] = item
+
+	return item.ID, nil
+}
+
+func (r *TodoItemRepository) GetAll(ctx context.Context, listID int) ([]model.TodoItem, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var items []model.TodoItem
+	for _, item := range r.store.items {
+		if item.ListID == listID {
+			items = append(items, item)
+		}
+	}
+
+	sortItems(items)
+
+	return items, nil
+}
+
+func (r *TodoItemRepository) GetAllByLists(ctx context.Context, workspaceID int, listIDs []int) ([]model.TodoItem, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	requested := make(map[int]bool, len(listIDs))
+	for _, id := range listIDs {
+		requested[id] = r.store.inWorkspace(workspaceID, id)
+	}
+
+	var items []model.TodoItem
+	for _, item := range r.store.items {
+		if requested[item.ListID] {
+			items = append(items, item)
+		}
+	}
+
+	sortItems(items)
+
+	return items, nil
+}
+
+func (r *TodoItemRepository) GetAllByWorkspace(ctx context.Context, workspaceID int) ([]model.TodoItem, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var items []model.TodoItem
+	for _, item := range r.store.items {
+		if r.store.inWorkspace(workspaceID, item.ListID) {
+			items = append(items, item)
+		}
+	}
+
+	sortItems(items)
+
+	return items, nil
+}
+
+func (r *TodoItemRepository) GetByID(ctx context.Context, workspaceID, itemID int) (model.TodoItem, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	item, ok := r.store.items[itemID]
+	if !ok || !r.store.inWorkspace(workspaceID, item.ListID) {
+		return model.TodoItem{}, sql.ErrNoRows
+	}
+
+	return item, nil
+}
+
+func (r *TodoItemRepository) Update(ctx context.Context, itemID int, update model.UpdateTodoItem) error {
+	defer r.store.lock(ctx)()
+
+	item, ok := r.store.items[itemID]
+	if !ok {
+		return nil
+	}
+
+	if update.Title != nil {
+		item.Title = *update.Title
+	}
+
+	if update.Description != nil {
+		item.Description = *update.Description
+	}
+
+	if update.CompletionDate != nil {
+		item.CompletionDate = *update.CompletionDate
+	}
+
+	if update.Done != nil {
+		item.Done = *update.Done
+	}
+
+	r.store.items[itemID] = item
+
+	return nil
+}
+
+func (r *TodoItemRepository) Delete(ctx context.Context, itemID int) error {
+	defer r.store.lock(ctx)()
+
+	r.store.deleteItem(itemID)
+
+	return nil
+}
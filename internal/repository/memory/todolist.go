@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+)
+
+type TodoListRepository struct {
+	store *Store
+}
+
+func NewTodoListRepository(store *Store) *TodoListRepository {
+	return &TodoListRepository{store: store}
+}
+
+func (r *TodoListRepository) Create(ctx context.Context, workspaceID, userID int, list model.TodoList) (int, error) {
+	if list.Title == "" {
+		return 0, errTitleIsEmpty
+	}
+
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.workspaces[workspaceID]; !ok {
+		return 0, errWorkspaceNotFound
+	}
+
+	if list.CompletionDate == "" {
+		list.CompletionDate = now()
+	}
+
+	r.store.lastListID++
+	list.ID = r.store.lastListID
+	list.UserID = userID
+	list.WorkspaceID = workspaceID
+	r.store.lists[list.ID] = list
+
+	return list.ID, nil
+}
+
+func (r *TodoListRepository) GetAll(ctx context.Context, workspaceID int) ([]model.TodoList, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var lists []model.TodoList
+	for _, list := range r.store.lists {
+		if list.WorkspaceID == workspaceID {
+			lists = append(lists, list)
+		}
+	}
+
+	sortLists(lists)
+
+	return lists, nil
+}
+
+func (r *TodoListRepository) GetByID(ctx context.Context, workspaceID, listID int) (model.TodoList, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if !r.store.inWorkspace(workspaceID, listID) {
+		return model.TodoList{}, sql.ErrNoRows
+	}
+
+	return r.store.lists[listID], nil
+}
+
+func (r *TodoListRepository) Update(ctx context.Context, workspaceID, listID int, update model.UpdateTodoList) error {
+	defer r.store.lock(ctx)()
+
+	if !r.store.inWorkspace(workspaceID, listID) {
+		return nil
+	}
+
+	list := r.store.lists[listID]
+
+	if update.Title != nil {
+		list.Title = *update.Title
+	}
+
+	if update.Description != nil {
+		list.Description = *update.Description
+	}
+
+	if update.CompletionDate != nil {
+		list.CompletionDate = *update.CompletionDate
+	}
+
+	r.store.lists[listID] = list
+
+	return nil
+}
+
+func (r *TodoListRepository) Delete(ctx context.Context, listID int) error {
+	defer r.store.lock(ctx)()
+
+	for id, item := range r.store.items {
+		if item.ListID == listID {
+			r.store.deleteItem(id)
+		}
+	}
+
+	delete(r.store.lists, listID)
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+)
+
+type AccessTokenRepository struct {
+	store *Store
+}
+
+func NewAccessTokenRepository(store *Store) *AccessTokenRepository {
+	return &AccessTokenRepository{store: store}
+}
+
+func (r *AccessTokenRepository) Create(ctx context.Context, token model.AccessToken) (int, error) {
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.users[token.UserID]; !ok {
+		return 0, errUserNotFound
+	}
+
+	for _, t := range r.store.tokens {
+		if t.TokenHash == token.TokenHash {
+			return 0, errAccessTokenAlreadyExists
+		}
+	}
+
+	r.store.lastTokenID++
+	token.ID = r.store.lastTokenID
+	token.Scopes = append(model.Scopes(nil), token.Scopes...)
+	r.store.tokens[token.ID] = token
+
+	return token.ID, nil
+}
+
+func (r *AccessTokenRepository) GetAll(ctx context.Context, userID int) ([]model.AccessToken, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var tokens []model.AccessToken
+	for _, token := range r.store.tokens {
+		if token.UserID == userID {
+			tokens = append(tokens, token)
+		}
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].ID < tokens[j].ID
+	})
+
+	return tokens, nil
+}
+
+func (r *AccessTokenRepository) GetByID(ctx context.Context, userID, tokenID int) (model.AccessToken, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	token, ok := r.store.tokens[tokenID]
+	if !ok || token.UserID != userID {
+		return model.AccessToken{}, sql.ErrNoRows
+	}
+
+	return token, nil
+}
+
+// GetByHash skips the tokens of the disabled users.
+func (r *AccessTokenRepository) GetByHash(ctx context.Context, tokenHash string) (model.AccessToken, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, token := range r.store.tokens {
+		if token.TokenHash == tokenHash && !r.store.users[token.UserID].Disabled {
+			return token, nil
+		}
+	}
+
+	return model.AccessToken{}, sql.ErrNoRows
+}
+
+func (r *AccessTokenRepository) UpdateLastUsed(ctx context.Context, tokenID int, usedAt time.Time) error {
+	defer r.store.lock(ctx)()
+
+	token, ok := r.store.tokens[tokenID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	token.LastUsedAt = &usedAt
+	r.store.tokens[tokenID] = token
+
+	return nil
+}
+
+func (r *AccessTokenRepository) Delete(ctx context.Context, userID, tokenID int) error {
+	defer r.store.lock(ctx)()
+
+	token, ok := r.store.tokens[tokenID]
+	if !ok || token.UserID != userID {
+		return sql.ErrNoRows
+	}
+
+	delete(r.store.tokens, tokenID)
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+)
+
+type txKey struct{}
+
+// snapshot holds a copy of the data, the id counters are left out of it
+// the same way sequences aren't rolled back in postgres.
+type snapshot struct {
+	users   map[int]model.User
+	lists   map[int]model.TodoList
+	items   map[int]model.TodoItem
+	feeds   map[int]string
+	objects map[int]model.CalendarObject
+	resets  map[string]model.PasswordReset
+
+	recoveryCodes map[int][]string
+	identities    map[identityKey]int
+	tokens        map[int]model.AccessToken
+	workspaces    map[int]workspace
+	members       map[memberKey]string
+}
+
+// TxManager emulates transactions by restoring a snapshot of the store on failure.
+// Transactions are serialized with each other and with the writes made outside of them,
+// so the restore only drops the changes of the transaction. The reads outside of the
+// transactions aren't isolated from them.
+type TxManager struct {
+	store *Store
+}
+
+func NewTxManager(store *Store) *TxManager {
+	return &TxManager{store: store}
+}
+
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(txKey{}) == nil {
+		m.store.txMu.Lock()
+		defer m.store.txMu.Unlock()
+
+		ctx = context.WithValue(ctx, txKey{}, true)
+	}
+
+	saved := m.store.snapshot()
+
+	defer func() {
+		if p := recover(); p != nil {
+			m.store.restore(saved)
+			panic(p)
+		}
+	}()
+
+	if err := fn(ctx); err != nil {
+		m.store.restore(saved)
+		return err
+	}
+
+	return nil
+}
+
+// lock locks the store for a write and returns the unlock. The writes outside of a transaction
+// wait for the running one to end, the writes in the transaction already hold txMu.
+func (s *Store) lock(ctx context.Context) func() {
+	if ctx.Value(txKey{}) != nil {
+		s.mu.Lock()
+		return s.mu.Unlock
+	}
+
+	s.txMu.Lock()
+	s.mu.Lock()
+
+	return func() {
+		s.mu.Unlock()
+		s.txMu.Unlock()
+	}
+}
+
+func (s *Store) snapshot() snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	saved := snapshot{
+		users:   make(map[int]model.User, len(s.users)),
+		lists:   make(map[int]model.TodoList, len(s.lists)),
+		items:   make(map[int]model.TodoItem, len(s.items)),
+		feeds:   make(map[int]string, len(s.feeds)),
+		objects: make(map[int]model.CalendarObject, len(s.objects)),
+		resets:  make(map[string]model.PasswordReset, len(s.resets)),
+
+		recoveryCodes: make(map[int][]string, len(s.recoveryCodes)),
+		identities:    make(map[identityKey]int, len(s.identities)),
+		tokens:        make(map[int]model.AccessToken, len(s.tokens)),
+		workspaces:    make(map[int]workspace, len(s.workspaces)),
+		members:       make(map[memberKey]string, len(s.members)),
+	}
+
+	for id, user := range s.users {
+		saved.users[id] = user
+	}
+	for id, list := range s.lists {
+		saved.lists[id] = list
+	}
+	for id, item := range s.items {
+		saved.items[id] = item
+	}
+	for id, feed := range s.feeds {
+		saved.feeds[id] = feed
+	}
+	for id, object := range s.objects {
+		saved.objects[id] = object
+	}
+	for hash, reset := range s.resets {
+		saved.resets[hash] = reset
+	}
+	for id, hashes := range s.recoveryCodes {
+		saved.recoveryCodes[id] = append([]string(nil), hashes...)
+	}
+	for key, id := range s.identities {
+		saved.identities[key] = id
+	}
+	for id, token := range s.tokens {
+		saved.tokens[id] = token
+	}
+	for id, w := range s.workspaces {
+		saved.workspaces[id] = w
+	}
+	for key, role := range s.members {
+		saved.members[key] = role
+	}
+
+	return saved
+}
+
+func (s *Store) restore(saved snapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users = saved.users
+	s.lists = saved.lists
+	s.items = saved.items
+	s.feeds = saved.feeds
+	s.objects = saved.objects
+	s.resets = saved.resets
+	s.recoveryCodes = saved.recoveryCodes
+	s.identities = saved.identities
+	s.tokens = saved.tokens
+	s.workspaces = saved.workspaces
+	s.members = saved.members
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTxManager_WithinTx checks the rollback of a transaction keeps the writes made
+// outside of it in the meantime.
+func TestTxManager_WithinTx(t *testing.T) {
+	store := NewStore()
+	manager := NewTxManager(store)
+	users := NewAuthRepository(store)
+	ctx := context.Background()
+
+	errFn := errors.New("fn failed")
+	started, done := make(chan struct{}), make(chan error)
+
+	err := manager.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := users.CreateUser(ctx, model.User{Name: "inside", Email: "inside@mail.ru"}); err != nil {
+			return err
+		}
+
+		go func() {
+			close(started)
+			_, err := users.CreateUser(context.Background(), model.User{Name: "outside", Email: "outside@mail.ru"})
+			done <- err
+		}()
+
+		<-started
+		// Gives the write outside of the transaction the time to run into it.
+		time.Sleep(10 * time.Millisecond)
+
+		return errFn
+	})
+	assert.ErrorIs(t, err, errFn)
+	require.NoError(t, <-done)
+
+	_, err = users.GetUser(ctx, "inside@mail.ru")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = users.GetUser(ctx, "outside@mail.ru")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,174 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+)
+
+type WorkspaceRepository struct {
+	store *Store
+}
+
+func NewWorkspaceRepository(store *Store) *WorkspaceRepository {
+	return &WorkspaceRepository{store: store}
+}
+
+func (r *WorkspaceRepository) Create(ctx context.Context, userID int, name string) (int, error) {
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.users[userID]; !ok {
+		return 0, errUserNotFound
+	}
+
+	return r.store.createWorkspace(name, 0, userID), nil
+}
+
+func (r *WorkspaceRepository) GetAll(ctx context.Context, userID int) ([]model.Workspace, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var workspaces []model.Workspace
+	for key, role := range r.store.members {
+		if key.userID == userID {
+			workspaces = append(workspaces, r.workspace(key.workspaceID, role))
+		}
+	}
+
+	sort.Slice(workspaces, func(i, j int) bool {
+		return workspaces[i].ID < workspaces[j].ID
+	})
+
+	return workspaces, nil
+}
+
+func (r *WorkspaceRepository) GetByID(ctx context.Context, userID, workspaceID int) (model.Workspace, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	role, ok := r.store.members[memberKey{workspaceID: workspaceID, userID: userID}]
+	if !ok {
+		return model.Workspace{}, sql.ErrNoRows
+	}
+
+	return r.workspace(workspaceID, role), nil
+}
+
+func (r *WorkspaceRepository) GetPersonal(ctx context.Context, userID int) (model.Workspace, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	workspaceID := r.store.personalWorkspace(userID)
+
+	role, ok := r.store.members[memberKey{workspaceID: workspaceID, userID: userID}]
+	if !ok {
+		return model.Workspace{}, sql.ErrNoRows
+	}
+
+	return r.workspace(workspaceID, role), nil
+}
+
+func (r *WorkspaceRepository) Update(ctx context.Context, workspaceID int, name string) error {
+	defer r.store.lock(ctx)()
+
+	w, ok := r.store.workspaces[workspaceID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	w.name = name
+	r.store.workspaces[workspaceID] = w
+
+	return nil
+}
+
+func (r *WorkspaceRepository) Delete(ctx context.Context, workspaceID int) error {
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.workspaces[workspaceID]; !ok {
+		return sql.ErrNoRows
+	}
+
+	for id, list := range r.store.lists {
+		if list.WorkspaceID != workspaceID {
+			continue
+		}
+
+		for itemID, item := range r.store.items {
+			if item.ListID == id {
+				r.store.deleteItem(itemID)
+			}
+		}
+
+		delete(r.store.lists, id)
+	}
+
+	for key := range r.store.members {
+		if key.workspaceID == workspaceID {
+			delete(r.store.members, key)
+		}
+	}
+
+	delete(r.store.workspaces, workspaceID)
+
+	return nil
+}
+
+func (r *WorkspaceRepository) GetMembers(ctx context.Context, workspaceID int) ([]model.WorkspaceMember, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var members []model.WorkspaceMember
+	for key, role := range r.store.members {
+		if key.workspaceID != workspaceID {
+			continue
+		}
+
+		user := r.store.users[key.userID]
+		members = append(members, model.WorkspaceMember{UserID: user.ID, Name: user.Name, Email: user.Email, Role: role})
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].UserID < members[j].UserID
+	})
+
+	return members, nil
+}
+
+func (r *WorkspaceRepository) SetMember(ctx context.Context, workspaceID, userID int, role string) error {
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.workspaces[workspaceID]; !ok {
+		return errWorkspaceNotFound
+	}
+
+	if _, ok := r.store.users[userID]; !ok {
+		return errUserNotFound
+	}
+
+	r.store.members[memberKey{workspaceID: workspaceID, userID: userID}] = role
+
+	return nil
+}
+
+func (r *WorkspaceRepository) DeleteMember(ctx context.Context, workspaceID, userID int) error {
+	defer r.store.lock(ctx)()
+
+	key := memberKey{workspaceID: workspaceID, userID: userID}
+	if _, ok := r.store.members[key]; !ok {
+		return sql.ErrNoRows
+	}
+
+	delete(r.store.members, key)
+
+	return nil
+}
+
+// workspace must be called with the lock held.
+func (r *WorkspaceRepository) workspace(workspaceID int, role string) model.Workspace {
+	w := r.store.workspaces[workspaceID]
+
+	return model.Workspace{ID: workspaceID, Name: w.name, Personal: w.personalUserID != 0, Role: role}
+}
@@ -1,37 +1,606 @@
 package postgres
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"time"
 
+	"github.com/Lapp-coder/todo-app/internal/metrics"
 	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/repository/sqltx"
 	"github.com/jmoiron/sqlx"
 )
 
 type AuthRepository struct {
-	db *sqlx.DB
+	db           *sqlx.DB
+	queryTimeout time.Duration
 }
 
-func NewAuthRepository(db *sqlx.DB) *AuthRepository {
-	return &AuthRepository{db: db}
+func NewAuthRepository(db *sqlx.DB, queryTimeout time.Duration) *AuthRepository {
+	return &AuthRepository{db: db, queryTimeout: queryTimeout}
 }
 
-func (r *AuthRepository) CreateUser(user model.User) (int, error) {
-	if err := r.db.QueryRow(fmt.Sprintf(
-		"INSERT INTO %s (name, email, password_hash) VALUES ($1, $2, $3) RETURNING id", usersTable),
-		user.Name, user.Email, user.Password).Scan(&user.ID); err != nil {
+func (r *AuthRepository) CreateUser(ctx context.Context, user model.User) (int, error) {
+	defer metrics.ObserveQuery("auth", "CreateUser", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	// The personal workspace is created in the same statement, so there are no users without one.
+	query := fmt.Sprintf(`WITH u AS (INSERT INTO %s (name, email, password_hash, verified) VALUES ($1, $2, $3, $4) RETURNING id),
+			w AS (INSERT INTO %s (name, personal_user_id) VALUES ($5, (SELECT id FROM u)) RETURNING id, personal_user_id)
+			INSERT INTO %s (workspace_id, user_id, role) VALUES ((SELECT id FROM w), (SELECT personal_user_id FROM w), $6)
+			RETURNING user_id`,
+		usersTable, workspaceTable, membersTable)
+	if err := from(ctx, r.db).QueryRowContext(ctx, query, user.Name, user.Email, user.Password, user.Verified,
+		model.PersonalWorkspaceName, model.WorkspaceOwner).Scan(&user.ID); err != nil {
 		return 0, err
 	}
 
 	return user.ID, nil
 }
 
-func (r *AuthRepository) GetUser(email string) (model.User, error) {
+// userColumns are scanned by scanUser.
+const userColumns = "id, name, email, password_hash, verified, token_version, failed_logins, lockouts, locked_until, " +
+	"totp_secret, totp_enabled, totp_last_step, timezone, locale, role, disabled, pending_email"
+
+func scanUser(row *tracedRow) (model.User, error) {
 	var user model.User
-	if err := r.db.QueryRow(fmt.Sprintf(
-		"SELECT id, name, email, password_hash FROM %s WHERE email = $1", usersTable),
-		email).Scan(&user.ID, &user.Name, &user.Email, &user.Password); err != nil {
+	if err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.Verified, &user.TokenVersion,
+		&user.FailedLogins, &user.Lockouts, &user.LockedUntil, &user.TOTPSecret, &user.TOTPEnabled, &user.TOTPLastStep,
+		&user.Timezone, &user.Locale, &user.Role, &user.Disabled, &user.PendingEmail); err != nil {
 		return model.User{}, err
 	}
 
 	return user, nil
 }
+
+func (r *AuthRepository) GetUser(ctx context.Context, email string) (model.User, error) {
+	defer metrics.ObserveQuery("auth", "GetUser", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	return scanUser(from(ctx, r.db).QueryRowContext(ctx, fmt.Sprintf(
+		"SELECT %s FROM %s WHERE email = $1", userColumns, usersTable), email))
+}
+
+func (r *AuthRepository) GetUserByID(ctx context.Context, userID int) (model.User, error) {
+	defer metrics.ObserveQuery("auth", "GetUserByID", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	return scanUser(from(ctx, r.db).QueryRowContext(ctx, fmt.Sprintf(
+		"SELECT %s FROM %s WHERE id = $1", userColumns, usersTable), userID))
+}
+
+func (r *AuthRepository) GetUserByIdentity(ctx context.Context, issuer, subject string) (model.User, error) {
+	defer metrics.ObserveQuery("auth", "GetUserByIdentity", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	return scanUser(from(ctx, r.db).QueryRowContext(ctx, fmt.Sprintf(
+		"SELECT %s FROM %s WHERE id = (SELECT user_id FROM %s WHERE issuer = $1 AND subject = $2)",
+		userColumns, usersTable, identityTable), issuer, subject))
+}
+
+func (r *AuthRepository) LinkIdentity(ctx context.Context, identity model.Identity) error {
+	defer metrics.ObserveQuery("auth", "LinkIdentity", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf("INSERT INTO %s (issuer, subject, user_id) VALUES ($1, $2, $3)", identityTable)
+	_, err := from(ctx, r.db).ExecContext(ctx, query, identity.Issuer, identity.Subject, identity.UserID)
+
+	return err
+}
+
+// UpdatePassword also signs out all the sessions of the user and drops their reset tokens.
+func (r *AuthRepository) UpdatePassword(ctx context.Context, userID int, passwordHash string) error {
+	defer metrics.ObserveQuery("auth", "UpdatePassword", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	return sqltx.NewManager(r.db).WithinTx(ctx, func(ctx context.Context) error {
+		query1 := fmt.Sprintf(
+			"UPDATE %s SET password_hash = $1, token_version = token_version + 1 WHERE id = $2", usersTable)
+		result, err := from(ctx, r.db).ExecContext(ctx, query1, passwordHash, userID)
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+
+		query2 := fmt.Sprintf("DELETE FROM %s WHERE user_id = $1", resetsTable)
+		if _, err = from(ctx, r.db).ExecContext(ctx, query2, userID); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (r *AuthRepository) UpdateProfile(ctx context.Context, user model.User) error {
+	defer metrics.ObserveQuery("auth", "UpdateProfile", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf(
+		"UPDATE %s SET name = $1, email = $2, verified = $3, pending_email = $4, timezone = $5, locale = $6 WHERE id = $7",
+		usersTable)
+	result, err := from(ctx, r.db).ExecContext(ctx, query,
+		user.Name, user.Email, user.Verified, user.PendingEmail, user.Timezone, user.Locale, user.ID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// DeleteUser deletes the items and the lists of the personal workspace and the calendar feed
+// of the user before the user, the lists they created in the other workspaces are handed to
+// an owner of the workspace. The rest of their data is deleted by the cascades of the foreign keys.
+func (r *AuthRepository) DeleteUser(ctx context.Context, userID int) error {
+	defer metrics.ObserveQuery("auth", "DeleteUser", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	return sqltx.NewManager(r.db).WithinTx(ctx, func(ctx context.Context) error {
+		query1 := fmt.Sprintf(
+			`DELETE FROM %s WHERE list_id IN (SELECT tl.id FROM %s tl
+				INNER JOIN %s w ON w.id = tl.workspace_id WHERE w.personal_user_id = $1)`,
+			todoItemsTable, todoListsTable, workspaceTable)
+		if _, err := from(ctx, r.db).ExecContext(ctx, query1, userID); err != nil {
+			return err
+		}
+
+		query2 := fmt.Sprintf("DELETE FROM %s WHERE workspace_id IN (SELECT id FROM %s WHERE personal_user_id = $1)",
+			todoListsTable, workspaceTable)
+		if _, err := from(ctx, r.db).ExecContext(ctx, query2, userID); err != nil {
+			return err
+		}
+
+		query3 := fmt.Sprintf(
+			`UPDATE %s tl SET user_id = (SELECT m.user_id FROM %s m
+				WHERE m.workspace_id = tl.workspace_id AND m.role = $2 AND m.user_id <> $1 ORDER BY m.user_id LIMIT 1)
+				WHERE tl.user_id = $1`,
+			todoListsTable, membersTable)
+		if _, err := from(ctx, r.db).ExecContext(ctx, query3, userID, model.WorkspaceOwner); err != nil {
+			return err
+		}
+
+		query4 := fmt.Sprintf("DELETE FROM %s WHERE user_id = $1", calendarsTable)
+		if _, err := from(ctx, r.db).ExecContext(ctx, query4, userID); err != nil {
+			return err
+		}
+
+		query5 := fmt.Sprintf("DELETE FROM %s WHERE id = $1", usersTable)
+		result, err := from(ctx, r.db).ExecContext(ctx, query5, userID)
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+
+		return nil
+	})
+}
+
+func (r *AuthRepository) CreatePasswordReset(ctx context.Context, reset model.PasswordReset) error {
+	defer metrics.ObserveQuery("auth", "CreatePasswordReset", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf("INSERT INTO %s (token_hash, user_id, expires_at) VALUES ($1, $2, $3)", resetsTable)
+	_, err := from(ctx, r.db).ExecContext(ctx, query, reset.TokenHash, reset.UserID, reset.ExpiresAt)
+
+	return err
+}
+
+// TakePasswordReset deletes the reset token so it can't be used twice, expired or not.
+func (r *AuthRepository) TakePasswordReset(ctx context.Context, tokenHash string) (model.PasswordReset, error) {
+	defer metrics.ObserveQuery("auth", "TakePasswordReset", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	reset := model.PasswordReset{TokenHash: tokenHash}
+	if err := from(ctx, r.db).QueryRowContext(ctx, fmt.Sprintf(
+		"DELETE FROM %s WHERE token_hash = $1 RETURNING user_id, expires_at", resetsTable),
+		tokenHash).Scan(&reset.UserID, &reset.ExpiresAt); err != nil {
+		return model.PasswordReset{}, err
+	}
+
+	return reset, nil
+}
+
+func (r *AuthRepository) VerifyEmail(ctx context.Context, userID int, email string) error {
+	defer metrics.ObserveQuery("auth", "VerifyEmail", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf("UPDATE %s SET verified = TRUE WHERE id = $1 AND email = $2", usersTable)
+	result, err := from(ctx, r.db).ExecContext(ctx, query, userID, email)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// ConfirmEmail also drops the reset tokens of the user, they were mailed to the old email.
+func (r *AuthRepository) ConfirmEmail(ctx context.Context, userID int, email string) error {
+	defer metrics.ObserveQuery("auth", "ConfirmEmail", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	return sqltx.NewManager(r.db).WithinTx(ctx, func(ctx context.Context) error {
+		query1 := fmt.Sprintf(
+			"UPDATE %s SET email = pending_email, pending_email = '', verified = TRUE WHERE id = $1 AND pending_email = $2",
+			usersTable)
+		result, err := from(ctx, r.db).ExecContext(ctx, query1, userID, email)
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+
+		query2 := fmt.Sprintf("DELETE FROM %s WHERE user_id = $1", resetsTable)
+		if _, err = from(ctx, r.db).ExecContext(ctx, query2, userID); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (r *AuthRepository) RecordFailedLogin(ctx context.Context, userID int) (int, error) {
+	defer metrics.ObserveQuery("auth", "RecordFailedLogin", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var failedLogins int
+	if err := from(ctx, r.db).QueryRowContext(ctx, fmt.Sprintf(
+		"UPDATE %s SET failed_logins = failed_logins + 1 WHERE id = $1 RETURNING failed_logins", usersTable),
+		userID).Scan(&failedLogins); err != nil {
+		return 0, err
+	}
+
+	return failedLogins, nil
+}
+
+func (r *AuthRepository) LockUser(ctx context.Context, userID int, until time.Time) error {
+	defer metrics.ObserveQuery("auth", "LockUser", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf(
+		"UPDATE %s SET failed_logins = 0, lockouts = lockouts + 1, locked_until = $1 WHERE id = $2", usersTable)
+	result, err := from(ctx, r.db).ExecContext(ctx, query, until, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) ResetLockout(ctx context.Context, userID int) error {
+	defer metrics.ObserveQuery("auth", "ResetLockout", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf(
+		"UPDATE %s SET failed_logins = 0, lockouts = 0, locked_until = NULL WHERE id = $1", usersTable)
+	result, err := from(ctx, r.db).ExecContext(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) GetLockouts(ctx context.Context) ([]model.Lockout, error) {
+	defer metrics.ObserveQuery("auth", "GetLockouts", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var lockouts []model.Lockout
+	if err := from(ctx, r.db).SelectContext(ctx, &lockouts, fmt.Sprintf(
+		"SELECT id AS user_id, email, failed_logins, lockouts, locked_until FROM %s WHERE failed_logins > 0 OR lockouts > 0 ORDER BY id",
+		usersTable)); err != nil {
+		return nil, err
+	}
+
+	return lockouts, nil
+}
+
+func (r *AuthRepository) SetTOTPSecret(ctx context.Context, userID int, secret string) error {
+	defer metrics.ObserveQuery("auth", "SetTOTPSecret", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf("UPDATE %s SET totp_secret = $1 WHERE id = $2 AND NOT totp_enabled", usersTable)
+	result, err := from(ctx, r.db).ExecContext(ctx, query, secret, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) EnableTOTP(ctx context.Context, userID int, step int64, codeHashes []string) error {
+	defer metrics.ObserveQuery("auth", "EnableTOTP", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	return sqltx.NewManager(r.db).WithinTx(ctx, func(ctx context.Context) error {
+		query1 := fmt.Sprintf(
+			"UPDATE %s SET totp_enabled = TRUE, totp_last_step = $1 WHERE id = $2 AND NOT totp_enabled AND totp_secret <> ''",
+			usersTable)
+		result, err := from(ctx, r.db).ExecContext(ctx, query1, step, userID)
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+
+		query2 := fmt.Sprintf("DELETE FROM %s WHERE user_id = $1", recoveryTable)
+		if _, err = from(ctx, r.db).ExecContext(ctx, query2, userID); err != nil {
+			return err
+		}
+
+		query3 := fmt.Sprintf("INSERT INTO %s (user_id, code_hash) VALUES ($1, $2)", recoveryTable)
+		for _, hash := range codeHashes {
+			if _, err = from(ctx, r.db).ExecContext(ctx, query3, userID, hash); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *AuthRepository) UseTOTPStep(ctx context.Context, userID int, step int64) error {
+	defer metrics.ObserveQuery("auth", "UseTOTPStep", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf("UPDATE %s SET totp_last_step = $1 WHERE id = $2 AND totp_last_step < $1", usersTable)
+	result, err := from(ctx, r.db).ExecContext(ctx, query, step, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) UseRecoveryCode(ctx context.Context, userID int, codeHash string) error {
+	defer metrics.ObserveQuery("auth", "UseRecoveryCode", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_id = $1 AND code_hash = $2", recoveryTable)
+	result, err := from(ctx, r.db).ExecContext(ctx, query, userID, codeHash)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) GetUsers(ctx context.Context, search model.SearchUsers) ([]model.UserSummary, error) {
+	defer metrics.ObserveQuery("auth", "GetUsers", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	users := make([]model.UserSummary, 0)
+	if err := from(ctx, r.db).SelectContext(ctx, &users, fmt.Sprintf(
+		"SELECT id, name, email, role, verified, totp_enabled, disabled FROM %s "+
+			"WHERE strpos(lower(name), lower($1)) > 0 OR strpos(lower(email), lower($1)) > 0 ORDER BY id LIMIT $2 OFFSET $3",
+		usersTable), search.Query, search.Limit, search.Offset); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *AuthRepository) SetRole(ctx context.Context, userID int, role string) error {
+	defer metrics.ObserveQuery("auth", "SetRole", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf("UPDATE %s SET role = $1, token_version = token_version + 1 WHERE id = $2", usersTable)
+	result, err := from(ctx, r.db).ExecContext(ctx, query, role, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) SetDisabled(ctx context.Context, userID int, disabled bool) error {
+	defer metrics.ObserveQuery("auth", "SetDisabled", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf("UPDATE %s SET disabled = $1, token_version = token_version + 1 WHERE id = $2", usersTable)
+	result, err := from(ctx, r.db).ExecContext(ctx, query, disabled, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) RevokeSessions(ctx context.Context, userID int) error {
+	defer metrics.ObserveQuery("auth", "RevokeSessions", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf("UPDATE %s SET token_version = token_version + 1 WHERE id = $1", usersTable)
+	result, err := from(ctx, r.db).ExecContext(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) GetStats(ctx context.Context) (model.Stats, error) {
+	defer metrics.ObserveQuery("auth", "GetStats", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var stats model.Stats
+	if err := from(ctx, r.db).GetContext(ctx, &stats, fmt.Sprintf(
+		"SELECT COUNT(*) AS users, COUNT(*) FILTER (WHERE role = $1) AS admins, COUNT(*) FILTER (WHERE disabled) AS disabled_users, "+
+			"COUNT(*) FILTER (WHERE verified) AS verified_users, COUNT(*) FILTER (WHERE totp_enabled) AS two_factor_users, "+
+			"(SELECT COUNT(*) FROM %s) AS lists, (SELECT COUNT(*) FROM %s) AS items, "+
+			"(SELECT COUNT(*) FROM %s WHERE done) AS done_items, (SELECT COUNT(*) FROM %s) AS access_tokens FROM %s",
+		todoListsTable, todoItemsTable, todoItemsTable, tokensTable, usersTable), model.RoleAdmin); err != nil {
+		return model.Stats{}, err
+	}
+
+	return stats, nil
+}
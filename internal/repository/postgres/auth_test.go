@@ -1,8 +1,11 @@
 package postgres
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/Lapp-coder/todo-app/internal/model"
@@ -17,7 +20,7 @@ func TestAuthPostgres_CreateUser(t *testing.T) {
 	}
 
 	db := sqlx.NewDb(mockDB, "sqlmock")
-	repos := NewAuthRepository(db)
+	repos := NewAuthRepository(db, 0)
 
 	type args struct {
 		user model.User
@@ -40,7 +43,8 @@ func TestAuthPostgres_CreateUser(t *testing.T) {
 			mockBehavior: func(input args) {
 				rows := mock.NewRows([]string{"id"}).AddRow(1)
 				query := fmt.Sprintf("INSERT INTO %s (.+) VALUES (.+) RETURNING id", usersTable)
-				mock.ExpectQuery(query).WithArgs(input.user.Name, input.user.Email, input.user.Password).WillReturnRows(rows)
+				mock.ExpectQuery(query).WithArgs(input.user.Name, input.user.Email, input.user.Password, input.user.Verified,
+					model.PersonalWorkspaceName, model.WorkspaceOwner).WillReturnRows(rows)
 			},
 			expectedId: 1,
 			wantErr:    false,
@@ -50,7 +54,8 @@ func TestAuthPostgres_CreateUser(t *testing.T) {
 			mockBehavior: func(input args) {
 				rows := mock.NewRows([]string{"id"})
 				query := fmt.Sprintf("INSERT INTO %s (.+) VALUES (.+) RETURNING id", usersTable)
-				mock.ExpectQuery(query).WithArgs(input.user.Name, input.user.Email, input.user.Password).WillReturnRows(rows)
+				mock.ExpectQuery(query).WithArgs(input.user.Name, input.user.Email, input.user.Password, input.user.Verified,
+					model.PersonalWorkspaceName, model.WorkspaceOwner).WillReturnRows(rows)
 			},
 			wantErr: true,
 		},
@@ -60,7 +65,7 @@ func TestAuthPostgres_CreateUser(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.mockBehavior(tc.input)
 
-			got, err := repos.CreateUser(tc.input.user)
+			got, err := repos.CreateUser(context.Background(), tc.input.user)
 			if tc.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -81,7 +86,7 @@ func TestAuthPostgres_GetUser(t *testing.T) {
 
 	db := sqlx.NewDb(mockDB, "sqlmock")
 
-	repos := NewAuthRepository(db)
+	repos := NewAuthRepository(db, 0)
 
 	type args struct {
 		email string
@@ -100,13 +105,15 @@ func TestAuthPostgres_GetUser(t *testing.T) {
 			name:  "OK",
 			input: args{email: "user@gmail.com"},
 			mockBehavior: func(input args) {
-				rows := mock.NewRows([]string{"id", "name", "email", "password_hash"}).
-					AddRow(1, "user", "user@gmail.com", "user")
+				rows := mock.NewRows([]string{"id", "name", "email", "password_hash", "verified", "token_version", "failed_logins", "lockouts", "locked_until",
+					"totp_secret", "totp_enabled", "totp_last_step", "timezone", "locale", "role", "disabled", "pending_email"}).
+					AddRow(1, "user", "user@gmail.com", "user", true, 3, 2, 0, nil, "SECRET", true, 7, "Europe/Moscow", "ru", "admin", false, "")
 				query := fmt.Sprintf("SELECT (.+) FROM %s WHERE (.+)", usersTable)
 				mock.ExpectQuery(query).WithArgs(input.email).WillReturnRows(rows)
 			},
-			expectedUser: model.User{ID: 1, Name: "user", Email: "user@gmail.com", Password: "user"},
-			wantErr:      false,
+			expectedUser: model.User{ID: 1, Name: "user", Email: "user@gmail.com", Password: "user", Verified: true, TokenVersion: 3, FailedLogins: 2,
+				TOTPSecret: "SECRET", TOTPEnabled: true, TOTPLastStep: 7, Timezone: "Europe/Moscow", Locale: "ru", Role: "admin"},
+			wantErr: false,
 		},
 		{
 			name: "Empty field",
@@ -122,7 +129,7 @@ func TestAuthPostgres_GetUser(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.mockBehavior(tc.input)
 
-			got, err := repos.GetUser(tc.input.email)
+			got, err := repos.GetUser(context.Background(), tc.input.email)
 			if tc.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -134,3 +141,901 @@ func TestAuthPostgres_GetUser(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthPostgres_GetUserByID(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAuthRepository(db, 0)
+
+	rows := mock.NewRows([]string{"id", "name", "email", "password_hash", "verified", "token_version", "failed_logins", "lockouts", "locked_until",
+		"totp_secret", "totp_enabled", "totp_last_step", "timezone", "locale", "role", "disabled", "pending_email"}).
+		AddRow(1, "user", "user@gmail.com", "user", false, 0, 0, 0, nil, "", false, 0, "UTC", "en", "user", false, "")
+	query := fmt.Sprintf("SELECT (.+) FROM %s WHERE id = (.+)", usersTable)
+	mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
+
+	got, err := repos.GetUserByID(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, model.User{ID: 1, Name: "user", Email: "user@gmail.com", Password: "user", Timezone: "UTC", Locale: "en", Role: "user"}, got)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthPostgres_UpdatePassword(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAuthRepository(db, 0)
+
+	type mockBehavior func(userID int, hash string)
+
+	testCases := []struct {
+		name         string
+		userID       int
+		hash         string
+		mockBehavior mockBehavior
+		wantErr      error
+	}{
+		{
+			name:   "OK",
+			userID: 1,
+			hash:   "hash",
+			mockBehavior: func(userID int, hash string) {
+				mock.ExpectBegin()
+				query1 := fmt.Sprintf("UPDATE %s SET password_hash = (.+), token_version = token_version \\+ 1 WHERE (.+)", usersTable)
+				mock.ExpectExec(query1).WithArgs(hash, userID).WillReturnResult(sqlmock.NewResult(0, 1))
+				query2 := fmt.Sprintf("DELETE FROM %s WHERE user_id = (.+)", resetsTable)
+				mock.ExpectExec(query2).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:   "Not found",
+			userID: 2,
+			hash:   "hash",
+			mockBehavior: func(userID int, hash string) {
+				mock.ExpectBegin()
+				query := fmt.Sprintf("UPDATE %s SET (.+) WHERE (.+)", usersTable)
+				mock.ExpectExec(query).WithArgs(hash, userID).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.userID, tc.hash)
+
+			err := repos.UpdatePassword(context.Background(), tc.userID, tc.hash)
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthPostgres_UpdateProfile(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAuthRepository(db, 0)
+	user := model.User{ID: 1, Name: "user", Email: "user@gmail.com", PendingEmail: "new@gmail.com", Timezone: "Europe/Moscow", Locale: "ru"}
+
+	query := fmt.Sprintf(
+		"UPDATE %s SET name = (.+), email = (.+), verified = (.+), pending_email = (.+), timezone = (.+), locale = (.+) WHERE id = (.+)", usersTable)
+	mock.ExpectExec(query).WithArgs(user.Name, user.Email, false, user.PendingEmail, user.Timezone, user.Locale, user.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(query).WithArgs(user.Name, user.Email, false, user.PendingEmail, user.Timezone, user.Locale, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repos.UpdateProfile(context.Background(), user))
+	user.ID = 2
+	assert.ErrorIs(t, repos.UpdateProfile(context.Background(), user), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthPostgres_DeleteUser(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAuthRepository(db, 0)
+
+	type mockBehavior func(userID int)
+
+	testCases := []struct {
+		name         string
+		userID       int
+		mockBehavior mockBehavior
+		wantErr      error
+	}{
+		{
+			name:   "OK",
+			userID: 1,
+			mockBehavior: func(userID int) {
+				mock.ExpectBegin()
+				query1 := fmt.Sprintf("DELETE FROM %s WHERE list_id IN \\(SELECT (.+) FROM %s tl INNER JOIN %s w (.+) WHERE w.personal_user_id = (.+)\\)",
+					todoItemsTable, todoListsTable, workspaceTable)
+				mock.ExpectExec(query1).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 3))
+				query2 := fmt.Sprintf("DELETE FROM %s WHERE workspace_id IN \\(SELECT id FROM %s WHERE personal_user_id = (.+)\\)",
+					todoListsTable, workspaceTable)
+				mock.ExpectExec(query2).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 2))
+				query3 := fmt.Sprintf("UPDATE %s tl SET user_id = \\(SELECT (.+) FROM %s m (.+)\\) WHERE tl.user_id = (.+)",
+					todoListsTable, membersTable)
+				mock.ExpectExec(query3).WithArgs(userID, model.WorkspaceOwner).WillReturnResult(sqlmock.NewResult(0, 1))
+				query4 := fmt.Sprintf("DELETE FROM %s WHERE user_id = (.+)", calendarsTable)
+				mock.ExpectExec(query4).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 1))
+				query5 := fmt.Sprintf("DELETE FROM %s WHERE id = (.+)", usersTable)
+				mock.ExpectExec(query5).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:   "Not found",
+			userID: 2,
+			mockBehavior: func(userID int) {
+				mock.ExpectBegin()
+				mock.ExpectExec(fmt.Sprintf("DELETE FROM %s", todoItemsTable)).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(fmt.Sprintf("DELETE FROM %s", todoListsTable)).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(fmt.Sprintf("UPDATE %s", todoListsTable)).WithArgs(userID, model.WorkspaceOwner).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(fmt.Sprintf("DELETE FROM %s", calendarsTable)).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(fmt.Sprintf("DELETE FROM %s", usersTable)).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name:   "Failure",
+			userID: 1,
+			mockBehavior: func(userID int) {
+				mock.ExpectBegin()
+				mock.ExpectExec(fmt.Sprintf("DELETE FROM %s", todoItemsTable)).WithArgs(userID).WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.userID)
+
+			err := repos.DeleteUser(context.Background(), tc.userID)
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthPostgres_CreatePasswordReset(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAuthRepository(db, 0)
+	reset := model.PasswordReset{TokenHash: "hash", UserID: 1, ExpiresAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	query := fmt.Sprintf("INSERT INTO %s (.+) VALUES (.+)", resetsTable)
+	mock.ExpectExec(query).WithArgs(reset.TokenHash, reset.UserID, reset.ExpiresAt).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repos.CreatePasswordReset(context.Background(), reset))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthPostgres_TakePasswordReset(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAuthRepository(db, 0)
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type mockBehavior func(hash string)
+
+	testCases := []struct {
+		name          string
+		hash          string
+		mockBehavior  mockBehavior
+		expectedReset model.PasswordReset
+		wantErr       error
+	}{
+		{
+			name: "OK",
+			hash: "hash",
+			mockBehavior: func(hash string) {
+				rows := mock.NewRows([]string{"user_id", "expires_at"}).AddRow(1, expiresAt)
+				query := fmt.Sprintf("DELETE FROM %s WHERE token_hash = (.+) RETURNING user_id, expires_at", resetsTable)
+				mock.ExpectQuery(query).WithArgs(hash).WillReturnRows(rows)
+			},
+			expectedReset: model.PasswordReset{TokenHash: "hash", UserID: 1, ExpiresAt: expiresAt},
+		},
+		{
+			name: "Used",
+			hash: "used",
+			mockBehavior: func(hash string) {
+				rows := mock.NewRows([]string{"user_id", "expires_at"})
+				query := fmt.Sprintf("DELETE FROM %s (.+) RETURNING (.+)", resetsTable)
+				mock.ExpectQuery(query).WithArgs(hash).WillReturnRows(rows)
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.hash)
+
+			got, err := repos.TakePasswordReset(context.Background(), tc.hash)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.expectedReset, got)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthPostgres_VerifyEmail(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAuthRepository(db, 0)
+
+	type mockBehavior func(userID int, email string)
+
+	testCases := []struct {
+		name         string
+		userID       int
+		email        string
+		mockBehavior mockBehavior
+		wantErr      error
+	}{
+		{
+			name:   "OK",
+			userID: 1,
+			email:  "user@gmail.com",
+			mockBehavior: func(userID int, email string) {
+				query := fmt.Sprintf("UPDATE %s SET verified = TRUE WHERE (.+)", usersTable)
+				mock.ExpectExec(query).WithArgs(userID, email).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:   "Email changed",
+			userID: 1,
+			email:  "old@gmail.com",
+			mockBehavior: func(userID int, email string) {
+				query := fmt.Sprintf("UPDATE %s SET verified = TRUE WHERE (.+)", usersTable)
+				mock.ExpectExec(query).WithArgs(userID, email).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.userID, tc.email)
+
+			err := repos.VerifyEmail(context.Background(), tc.userID, tc.email)
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthPostgres_ConfirmEmail(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAuthRepository(db, 0)
+
+	type mockBehavior func(userID int, email string)
+
+	testCases := []struct {
+		name         string
+		userID       int
+		email        string
+		mockBehavior mockBehavior
+		wantErr      error
+	}{
+		{
+			name:   "OK",
+			userID: 1,
+			email:  "new@gmail.com",
+			mockBehavior: func(userID int, email string) {
+				mock.ExpectBegin()
+				query1 := fmt.Sprintf("UPDATE %s SET email = pending_email, pending_email = '', verified = TRUE WHERE (.+)", usersTable)
+				mock.ExpectExec(query1).WithArgs(userID, email).WillReturnResult(sqlmock.NewResult(0, 1))
+				query2 := fmt.Sprintf("DELETE FROM %s WHERE user_id = (.+)", resetsTable)
+				mock.ExpectExec(query2).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:   "Pending email changed",
+			userID: 1,
+			email:  "old@gmail.com",
+			mockBehavior: func(userID int, email string) {
+				mock.ExpectBegin()
+				query := fmt.Sprintf("UPDATE %s SET email = pending_email, (.+) WHERE (.+)", usersTable)
+				mock.ExpectExec(query).WithArgs(userID, email).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.userID, tc.email)
+
+			err := repos.ConfirmEmail(context.Background(), tc.userID, tc.email)
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthPostgres_RecordFailedLogin(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAuthRepository(db, 0)
+
+	type mockBehavior func(userID int)
+
+	testCases := []struct {
+		name                 string
+		userID               int
+		mockBehavior         mockBehavior
+		expectedFailedLogins int
+		wantErr              bool
+	}{
+		{
+			name:   "OK",
+			userID: 1,
+			mockBehavior: func(userID int) {
+				rows := mock.NewRows([]string{"failed_logins"}).AddRow(3)
+				query := fmt.Sprintf("UPDATE %s SET failed_logins = failed_logins \\+ 1 WHERE (.+) RETURNING failed_logins", usersTable)
+				mock.ExpectQuery(query).WithArgs(userID).WillReturnRows(rows)
+			},
+			expectedFailedLogins: 3,
+		},
+		{
+			name:   "Not found",
+			userID: 2,
+			mockBehavior: func(userID int) {
+				rows := mock.NewRows([]string{"failed_logins"})
+				query := fmt.Sprintf("UPDATE %s SET (.+) RETURNING failed_logins", usersTable)
+				mock.ExpectQuery(query).WithArgs(userID).WillReturnRows(rows)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.userID)
+
+			got, err := repos.RecordFailedLogin(context.Background(), tc.userID)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedFailedLogins, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthPostgres_LockUser(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAuthRepository(db, 0)
+	until := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type mockBehavior func(userID int)
+
+	testCases := []struct {
+		name         string
+		userID       int
+		mockBehavior mockBehavior
+		wantErr      error
+	}{
+		{
+			name:   "OK",
+			userID: 1,
+			mockBehavior: func(userID int) {
+				query := fmt.Sprintf("UPDATE %s SET failed_logins = 0, lockouts = lockouts \\+ 1, locked_until = (.+) WHERE (.+)", usersTable)
+				mock.ExpectExec(query).WithArgs(until, userID).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:   "Not found",
+			userID: 2,
+			mockBehavior: func(userID int) {
+				query := fmt.Sprintf("UPDATE %s SET (.+) WHERE (.+)", usersTable)
+				mock.ExpectExec(query).WithArgs(until, userID).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.userID)
+
+			err := repos.LockUser(context.Background(), tc.userID, until)
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthPostgres_ResetLockout(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAuthRepository(db, 0)
+
+	type mockBehavior func(userID int)
+
+	testCases := []struct {
+		name         string
+		userID       int
+		mockBehavior mockBehavior
+		wantErr      error
+	}{
+		{
+			name:   "OK",
+			userID: 1,
+			mockBehavior: func(userID int) {
+				query := fmt.Sprintf("UPDATE %s SET failed_logins = 0, lockouts = 0, locked_until = NULL WHERE (.+)", usersTable)
+				mock.ExpectExec(query).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:   "Not found",
+			userID: 2,
+			mockBehavior: func(userID int) {
+				query := fmt.Sprintf("UPDATE %s SET (.+) WHERE (.+)", usersTable)
+				mock.ExpectExec(query).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.userID)
+
+			err := repos.ResetLockout(context.Background(), tc.userID)
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthPostgres_GetLockouts(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAuthRepository(db, 0)
+	until := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rows := mock.NewRows([]string{"user_id", "email", "failed_logins", "lockouts", "locked_until"}).
+		AddRow(1, "first@gmail.com", 2, 0, nil).
+		AddRow(2, "second@gmail.com", 0, 1, until)
+	query := fmt.Sprintf("SELECT (.+) FROM %s WHERE failed_logins > 0 OR lockouts > 0", usersTable)
+	mock.ExpectQuery(query).WillReturnRows(rows)
+
+	got, err := repos.GetLockouts(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Lockout{
+		{UserID: 1, Email: "first@gmail.com", FailedLogins: 2},
+		{UserID: 2, Email: "second@gmail.com", Lockouts: 1, LockedUntil: &until},
+	}, got)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthPostgres_GetUsers(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAuthRepository(db, 0)
+
+	rows := mock.NewRows([]string{"id", "name", "email", "role", "verified", "totp_enabled", "disabled"}).
+		AddRow(1, "admin", "admin@gmail.com", "admin", true, true, false).
+		AddRow(2, "user", "user@gmail.com", "user", false, false, true)
+	query := fmt.Sprintf("SELECT (.+) FROM %s WHERE (.+) ORDER BY id LIMIT (.+) OFFSET (.+)", usersTable)
+	mock.ExpectQuery(query).WithArgs("gmail", 10, 20).WillReturnRows(rows)
+
+	got, err := repos.GetUsers(context.Background(), model.SearchUsers{Query: "gmail", Limit: 10, Offset: 20})
+	assert.NoError(t, err)
+	assert.Equal(t, []model.UserSummary{
+		{ID: 1, Name: "admin", Email: "admin@gmail.com", Role: "admin", EmailVerified: true, TwoFactorEnabled: true},
+		{ID: 2, Name: "user", Email: "user@gmail.com", Role: "user", Disabled: true},
+	}, got)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthPostgres_SetRole(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAuthRepository(db, 0)
+
+	type mockBehavior func(userID int)
+
+	testCases := []struct {
+		name         string
+		userID       int
+		mockBehavior mockBehavior
+		wantErr      error
+	}{
+		{
+			name:   "OK",
+			userID: 1,
+			mockBehavior: func(userID int) {
+				query := fmt.Sprintf("UPDATE %s SET role = (.+), token_version = token_version \\+ 1 WHERE (.+)", usersTable)
+				mock.ExpectExec(query).WithArgs("admin", userID).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:   "Not found",
+			userID: 2,
+			mockBehavior: func(userID int) {
+				query := fmt.Sprintf("UPDATE %s SET (.+) WHERE (.+)", usersTable)
+				mock.ExpectExec(query).WithArgs("admin", userID).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.userID)
+
+			err := repos.SetRole(context.Background(), tc.userID, "admin")
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthPostgres_SetDisabled(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAuthRepository(db, 0)
+
+	type mockBehavior func(userID int)
+
+	testCases := []struct {
+		name         string
+		userID       int
+		mockBehavior mockBehavior
+		wantErr      error
+	}{
+		{
+			name:   "OK",
+			userID: 1,
+			mockBehavior: func(userID int) {
+				query := fmt.Sprintf("UPDATE %s SET disabled = (.+), token_version = token_version \\+ 1 WHERE (.+)", usersTable)
+				mock.ExpectExec(query).WithArgs(true, userID).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:   "Not found",
+			userID: 2,
+			mockBehavior: func(userID int) {
+				query := fmt.Sprintf("UPDATE %s SET (.+) WHERE (.+)", usersTable)
+				mock.ExpectExec(query).WithArgs(true, userID).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.userID)
+
+			err := repos.SetDisabled(context.Background(), tc.userID, true)
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthPostgres_RevokeSessions(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAuthRepository(db, 0)
+
+	type mockBehavior func(userID int)
+
+	testCases := []struct {
+		name         string
+		userID       int
+		mockBehavior mockBehavior
+		wantErr      error
+	}{
+		{
+			name:   "OK",
+			userID: 1,
+			mockBehavior: func(userID int) {
+				query := fmt.Sprintf("UPDATE %s SET token_version = token_version \\+ 1 WHERE (.+)", usersTable)
+				mock.ExpectExec(query).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:   "Not found",
+			userID: 2,
+			mockBehavior: func(userID int) {
+				query := fmt.Sprintf("UPDATE %s SET (.+) WHERE (.+)", usersTable)
+				mock.ExpectExec(query).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.userID)
+
+			err := repos.RevokeSessions(context.Background(), tc.userID)
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthPostgres_GetStats(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAuthRepository(db, 0)
+
+	rows := mock.NewRows([]string{"users", "admins", "disabled_users", "verified_users", "two_factor_users",
+		"lists", "items", "done_items", "access_tokens"}).
+		AddRow(10, 1, 2, 8, 3, 20, 50, 30, 4)
+	query := fmt.Sprintf("SELECT (.+) FROM %s", usersTable)
+	mock.ExpectQuery(query).WithArgs("admin").WillReturnRows(rows)
+
+	got, err := repos.GetStats(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, model.Stats{
+		Users: 10, Admins: 1, DisabledUsers: 2, VerifiedUsers: 8, TwoFactorUsers: 3,
+		Lists: 20, Items: 50, DoneItems: 30, AccessTokens: 4,
+	}, got)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthPostgres_EnableTOTP(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAuthRepository(db, 0)
+
+	type mockBehavior func(userID int)
+
+	testCases := []struct {
+		name         string
+		userID       int
+		mockBehavior mockBehavior
+		wantErr      error
+	}{
+		{
+			name:   "OK",
+			userID: 1,
+			mockBehavior: func(userID int) {
+				mock.ExpectBegin()
+				query1 := fmt.Sprintf("UPDATE %s SET totp_enabled = TRUE, totp_last_step = (.+) WHERE (.+)", usersTable)
+				mock.ExpectExec(query1).WithArgs(int64(10), userID).WillReturnResult(sqlmock.NewResult(0, 1))
+				query2 := fmt.Sprintf("DELETE FROM %s WHERE user_id = (.+)", recoveryTable)
+				mock.ExpectExec(query2).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 0))
+				query3 := fmt.Sprintf("INSERT INTO %s (.+) VALUES (.+)", recoveryTable)
+				mock.ExpectExec(query3).WithArgs(userID, "first").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(query3).WithArgs(userID, "second").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:   "Not set up",
+			userID: 2,
+			mockBehavior: func(userID int) {
+				mock.ExpectBegin()
+				query := fmt.Sprintf("UPDATE %s SET (.+) WHERE (.+)", usersTable)
+				mock.ExpectExec(query).WithArgs(int64(10), userID).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.userID)
+
+			err := repos.EnableTOTP(context.Background(), tc.userID, 10, []string{"first", "second"})
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthPostgres_UseTOTPStep(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAuthRepository(db, 0)
+
+	type mockBehavior func(userID int, step int64)
+
+	testCases := []struct {
+		name         string
+		userID       int
+		step         int64
+		mockBehavior mockBehavior
+		wantErr      error
+	}{
+		{
+			name:   "OK",
+			userID: 1,
+			step:   11,
+			mockBehavior: func(userID int, step int64) {
+				query := fmt.Sprintf("UPDATE %s SET totp_last_step = (.+) WHERE id = (.+) AND totp_last_step < (.+)", usersTable)
+				mock.ExpectExec(query).WithArgs(step, userID).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:   "Used",
+			userID: 1,
+			step:   10,
+			mockBehavior: func(userID int, step int64) {
+				query := fmt.Sprintf("UPDATE %s SET (.+) WHERE (.+)", usersTable)
+				mock.ExpectExec(query).WithArgs(step, userID).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.userID, tc.step)
+
+			err := repos.UseTOTPStep(context.Background(), tc.userID, tc.step)
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthPostgres_UseRecoveryCode(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAuthRepository(db, 0)
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_id = (.+) AND code_hash = (.+)", recoveryTable)
+	mock.ExpectExec(query).WithArgs(1, "hash").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(query).WithArgs(1, "hash").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repos.UseRecoveryCode(context.Background(), 1, "hash"))
+	assert.ErrorIs(t, repos.UseRecoveryCode(context.Background(), 1, "hash"), sql.ErrNoRows)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthPostgres_GetUserByIdentity(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAuthRepository(db, 0)
+
+	rows := mock.NewRows([]string{"id", "name", "email", "password_hash", "verified", "token_version", "failed_logins", "lockouts", "locked_until",
+		"totp_secret", "totp_enabled", "totp_last_step", "timezone", "locale", "role", "disabled", "pending_email"}).
+		AddRow(1, "user", "user@gmail.com", "user", true, 0, 0, 0, nil, "", false, 0, "UTC", "en", "user", false, "")
+	query := fmt.Sprintf("SELECT (.+) FROM %s WHERE id = \\(SELECT user_id FROM %s WHERE issuer = (.+) AND subject = (.+)\\)", usersTable, identityTable)
+	mock.ExpectQuery(query).WithArgs("https://idp.example.com", "42").WillReturnRows(rows)
+
+	got, err := repos.GetUserByIdentity(context.Background(), "https://idp.example.com", "42")
+	assert.NoError(t, err)
+	assert.Equal(t, model.User{ID: 1, Name: "user", Email: "user@gmail.com", Password: "user", Verified: true, Timezone: "UTC", Locale: "en", Role: "user"}, got)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthPostgres_LinkIdentity(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAuthRepository(db, 0)
+	identity := model.Identity{Issuer: "https://idp.example.com", Subject: "42", UserID: 1}
+
+	query := fmt.Sprintf("INSERT INTO %s (.+) VALUES (.+)", identityTable)
+	mock.ExpectExec(query).WithArgs(identity.Issuer, identity.Subject, identity.UserID).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repos.LinkIdentity(context.Background(), identity))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/metrics"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+type CalDAVRepository struct {
+	db           *sqlx.DB
+	queryTimeout time.Duration
+}
+
+func NewCalDAVRepository(db *sqlx.DB, queryTimeout time.Duration) *CalDAVRepository {
+	return &CalDAVRepository{db: db, queryTimeout: queryTimeout}
+}
+
+func (r *CalDAVRepository) GetObjects(ctx context.Context, userID, listID int) ([]model.CalendarObject, error) {
+	defer metrics.ObserveQuery("caldav", "GetObjects", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var objects []model.CalendarObject
+
+	query := fmt.Sprintf(
+		`SELECT co.item_id, co.name, co.uid FROM %s co
+				INNER JOIN %s ti ON ti.id = co.item_id
+				INNER JOIN %s tl ON tl.id = ti.list_id
+				INNER JOIN %s w ON w.id = tl.workspace_id WHERE w.personal_user_id = $1 AND tl.id = $2`,
+		caldavTable, todoItemsTable, todoListsTable, workspaceTable)
+	if err := from(ctx, r.db).SelectContext(ctx, &objects, query, userID, listID); err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+func (r *CalDAVRepository) GetObjectByName(ctx context.Context, userID, listID int, name string) (model.CalendarObject, error) {
+	defer metrics.ObserveQuery("caldav", "GetObjectByName", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var object model.CalendarObject
+
+	query := fmt.Sprintf(
+		`SELECT co.item_id, co.name, co.uid FROM %s co
+				INNER JOIN %s ti ON ti.id = co.item_id
+				INNER JOIN %s tl ON tl.id = ti.list_id
+				INNER JOIN %s w ON w.id = tl.workspace_id WHERE w.personal_user_id = $1 AND tl.id = $2 AND co.name = $3`,
+		caldavTable, todoItemsTable, todoListsTable, workspaceTable)
+	if err := from(ctx, r.db).GetContext(ctx, &object, query, userID, listID, name); err != nil {
+		return model.CalendarObject{}, err
+	}
+
+	return object, nil
+}
+
+func (r *CalDAVRepository) SaveObject(ctx context.Context, object model.CalendarObject) error {
+	defer metrics.ObserveQuery("caldav", "SaveObject", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (item_id, name, uid) VALUES ($1, $2, $3)
+				ON CONFLICT (item_id) DO UPDATE SET name = EXCLUDED.name, uid = EXCLUDED.uid`, caldavTable)
+	if _, err := from(ctx, r.db).ExecContext(ctx, query, object.ItemID, object.Name, object.UID); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,206 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalDAVPostgres_GetObjects(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewCalDAVRepository(db, 0)
+
+	type args struct {
+		userID int
+		listID int
+	}
+
+	type mockBehavior func(input args)
+
+	testCases := []struct {
+		name            string
+		input           args
+		mockBehavior    mockBehavior
+		expectedObjects []model.CalendarObject
+		wantErr         bool
+	}{
+		{
+			name:  "OK",
+			input: args{userID: 1, listID: 1},
+			mockBehavior: func(input args) {
+				rows := mock.NewRows([]string{"item_id", "name", "uid"}).
+					AddRow(1, "client.ics", "client-uid").
+					AddRow(2, "other.ics", "other-uid")
+
+				query := fmt.Sprintf("SELECT (.+) FROM %s co INNER JOIN %s ti ON (.+) INNER JOIN %s tl ON (.+) WHERE (.+)",
+					caldavTable, todoItemsTable, todoListsTable)
+				mock.ExpectQuery(query).WithArgs(input.userID, input.listID).WillReturnRows(rows)
+			},
+			expectedObjects: []model.CalendarObject{
+				{ItemID: 1, Name: "client.ics", UID: "client-uid"},
+				{ItemID: 2, Name: "other.ics", UID: "other-uid"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Empty fields",
+			mockBehavior: func(input args) {
+				query := fmt.Sprintf("SELECT (.+) FROM %s co INNER JOIN %s ti ON (.+) INNER JOIN %s tl ON (.+) WHERE (.+)",
+					caldavTable, todoItemsTable, todoListsTable)
+				mock.ExpectQuery(query).WithArgs(0, 0)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.input)
+
+			got, err := repos.GetObjects(context.Background(), tc.input.userID, tc.input.listID)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedObjects, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCalDAVPostgres_GetObjectByName(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewCalDAVRepository(db, 0)
+
+	type args struct {
+		userID int
+		listID int
+		name   string
+	}
+
+	type mockBehavior func(input args)
+
+	testCases := []struct {
+		name           string
+		input          args
+		mockBehavior   mockBehavior
+		expectedObject model.CalendarObject
+		wantErr        bool
+	}{
+		{
+			name:  "OK",
+			input: args{userID: 1, listID: 1, name: "client.ics"},
+			mockBehavior: func(input args) {
+				rows := mock.NewRows([]string{"item_id", "name", "uid"}).AddRow(1, "client.ics", "client-uid")
+
+				query := fmt.Sprintf("SELECT (.+) FROM %s co (.+) WHERE (.+)", caldavTable)
+				mock.ExpectQuery(query).WithArgs(input.userID, input.listID, input.name).WillReturnRows(rows)
+			},
+			expectedObject: model.CalendarObject{ItemID: 1, Name: "client.ics", UID: "client-uid"},
+			wantErr:        false,
+		},
+		{
+			name:  "Not found",
+			input: args{userID: 1, listID: 1, name: "unknown.ics"},
+			mockBehavior: func(input args) {
+				rows := mock.NewRows([]string{"item_id", "name", "uid"})
+
+				query := fmt.Sprintf("SELECT (.+) FROM %s co (.+) WHERE (.+)", caldavTable)
+				mock.ExpectQuery(query).WithArgs(input.userID, input.listID, input.name).WillReturnRows(rows)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.input)
+
+			got, err := repos.GetObjectByName(context.Background(), tc.input.userID, tc.input.listID, tc.input.name)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedObject, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCalDAVPostgres_SaveObject(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewCalDAVRepository(db, 0)
+
+	type args struct {
+		object model.CalendarObject
+	}
+
+	type mockBehavior func(input args)
+
+	testCases := []struct {
+		name         string
+		input        args
+		mockBehavior mockBehavior
+		wantErr      bool
+	}{
+		{
+			name:  "OK",
+			input: args{object: model.CalendarObject{ItemID: 1, Name: "client.ics", UID: "client-uid"}},
+			mockBehavior: func(input args) {
+				query := fmt.Sprintf("INSERT INTO %s (.+) VALUES (.+) ON CONFLICT (.+) DO UPDATE SET (.+)", caldavTable)
+				mock.ExpectExec(query).WithArgs(input.object.ItemID, input.object.Name, input.object.UID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantErr: false,
+		},
+		{
+			name:  "Unknown item",
+			input: args{object: model.CalendarObject{ItemID: 5, Name: "client.ics", UID: "client-uid"}},
+			mockBehavior: func(input args) {
+				query := fmt.Sprintf("INSERT INTO %s (.+) VALUES (.+) ON CONFLICT (.+) DO UPDATE SET (.+)", caldavTable)
+				mock.ExpectExec(query).WithArgs(input.object.ItemID, input.object.Name, input.object.UID).
+					WillReturnError(fmt.Errorf("foreign key violation"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.input)
+
+			err := repos.SaveObject(context.Background(), tc.input.object)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/metrics"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/repository/repoerr"
+	"github.com/jmoiron/sqlx"
+)
+
+type CalendarRepository struct {
+	db           *sqlx.DB
+	queryTimeout time.Duration
+}
+
+func NewCalendarRepository(db *sqlx.DB, queryTimeout time.Duration) *CalendarRepository {
+	return &CalendarRepository{db: db, queryTimeout: queryTimeout}
+}
+
+// CreateFeedToken returns repoerr.ErrFeedTokenExists when the user has a feed already.
+func (r *CalendarRepository) CreateFeedToken(ctx context.Context, userID int, tokenHash string) error {
+	defer metrics.ObserveQuery("calendar", "CreateFeedToken", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf("INSERT INTO %s (user_id, token_hash) VALUES ($1, $2) ON CONFLICT (user_id) DO NOTHING", calendarsTable)
+	result, err := from(ctx, r.db).ExecContext(ctx, query, userID, tokenHash)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return repoerr.ErrFeedTokenExists
+	}
+
+	return nil
+}
+
+func (r *CalendarRepository) UpdateFeedToken(ctx context.Context, userID int, tokenHash string) error {
+	defer metrics.ObserveQuery("calendar", "UpdateFeedToken", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf("UPDATE %s cf SET token_hash = $1 WHERE cf.user_id = $2", calendarsTable)
+	result, err := from(ctx, r.db).ExecContext(ctx, query, tokenHash, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetUserIDByFeedToken skips the feeds of the disabled users.
+func (r *CalendarRepository) GetUserIDByFeedToken(ctx context.Context, tokenHash string) (int, error) {
+	defer metrics.ObserveQuery("calendar", "GetUserIDByFeedToken", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var userID int
+
+	query := fmt.Sprintf("SELECT cf.user_id FROM %s cf WHERE cf.token_hash = $1 AND cf.user_id IN (SELECT id FROM %s WHERE NOT disabled)",
+		calendarsTable, usersTable)
+	if err := from(ctx, r.db).GetContext(ctx, &userID, query, tokenHash); err != nil {
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+func (r *CalendarRepository) GetAllItems(ctx context.Context, userID int) ([]model.TodoItem, error) {
+	defer metrics.ObserveQuery("calendar", "GetAllItems", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var items []model.TodoItem
+
+	query := fmt.Sprintf(
+		`SELECT ti.id, ti.list_id, ti.title, ti.description, ti.completion_date, ti.done FROM %s ti
+				INNER JOIN %s tl ON tl.id = ti.list_id INNER JOIN %s w ON w.id = tl.workspace_id
+				WHERE w.personal_user_id = $1 ORDER BY ti.completion_date`,
+		todoItemsTable, todoListsTable, workspaceTable)
+	if err := from(ctx, r.db).SelectContext(ctx, &items, query, userID); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
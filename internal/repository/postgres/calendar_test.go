@@ -0,0 +1,269 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendarPostgres_CreateFeedToken(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewCalendarRepository(db, 0)
+
+	type args struct {
+		userID int
+		token  string
+	}
+
+	type mockBehavior func(input args)
+
+	testCases := []struct {
+		name         string
+		input        args
+		mockBehavior mockBehavior
+		wantErr      bool
+	}{
+		{
+			name:  "OK",
+			input: args{userID: 1, token: "token"},
+			mockBehavior: func(input args) {
+				query := fmt.Sprintf("INSERT INTO %s \\(user_id, token_hash\\) VALUES (.+) ON CONFLICT \\(user_id\\) DO NOTHING", calendarsTable)
+				mock.ExpectExec(query).WithArgs(input.userID, input.token).WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			wantErr: false,
+		},
+		{
+			name:  "Feed already exists",
+			input: args{userID: 1, token: "token"},
+			mockBehavior: func(input args) {
+				query := fmt.Sprintf("INSERT INTO %s \\(user_id, token_hash\\) VALUES (.+) ON CONFLICT \\(user_id\\) DO NOTHING", calendarsTable)
+				mock.ExpectExec(query).WithArgs(input.userID, input.token).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+		},
+		{
+			name:  "Database error",
+			input: args{userID: 1, token: "token"},
+			mockBehavior: func(input args) {
+				query := fmt.Sprintf("INSERT INTO %s (.+) VALUES (.+)", calendarsTable)
+				mock.ExpectExec(query).WithArgs(input.userID, input.token).WillReturnError(fmt.Errorf("connection refused"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.input)
+
+			err := repos.CreateFeedToken(context.Background(), tc.input.userID, tc.input.token)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCalendarPostgres_UpdateFeedToken(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewCalendarRepository(db, 0)
+
+	type args struct {
+		userID int
+		token  string
+	}
+
+	type mockBehavior func(input args)
+
+	testCases := []struct {
+		name         string
+		input        args
+		mockBehavior mockBehavior
+		wantErr      bool
+	}{
+		{
+			name:  "OK",
+			input: args{userID: 1, token: "token"},
+			mockBehavior: func(input args) {
+				query := fmt.Sprintf("UPDATE %s cf SET token_hash = (.+) WHERE (.+)", calendarsTable)
+				mock.ExpectExec(query).WithArgs(input.token, input.userID).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantErr: false,
+		},
+		{
+			name:  "Not found",
+			input: args{userID: 1, token: "token"},
+			mockBehavior: func(input args) {
+				query := fmt.Sprintf("UPDATE %s cf SET token_hash = (.+) WHERE (.+)", calendarsTable)
+				mock.ExpectExec(query).WithArgs(input.token, input.userID).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.input)
+
+			err := repos.UpdateFeedToken(context.Background(), tc.input.userID, tc.input.token)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCalendarPostgres_GetUserIDByFeedToken(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewCalendarRepository(db, 0)
+
+	type args struct {
+		token string
+	}
+
+	type mockBehavior func(input args)
+
+	testCases := []struct {
+		name           string
+		input          args
+		mockBehavior   mockBehavior
+		expectedUserID int
+		wantErr        bool
+	}{
+		{
+			name:  "OK",
+			input: args{token: "token"},
+			mockBehavior: func(input args) {
+				rows := mock.NewRows([]string{"user_id"}).AddRow(1)
+
+				query := fmt.Sprintf("SELECT (.+) FROM %s cf WHERE cf.token_hash = (.+) AND cf.user_id IN \\(SELECT id FROM %s WHERE NOT disabled\\)",
+					calendarsTable, usersTable)
+				mock.ExpectQuery(query).WithArgs(input.token).WillReturnRows(rows)
+			},
+			expectedUserID: 1,
+			wantErr:        false,
+		},
+		{
+			name:  "Not found",
+			input: args{token: "unknown"},
+			mockBehavior: func(input args) {
+				rows := mock.NewRows([]string{"user_id"})
+
+				query := fmt.Sprintf("SELECT (.+) FROM %s cf WHERE cf.token_hash = (.+) AND cf.user_id IN \\(SELECT id FROM %s WHERE NOT disabled\\)",
+					calendarsTable, usersTable)
+				mock.ExpectQuery(query).WithArgs(input.token).WillReturnRows(rows)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.input)
+
+			got, err := repos.GetUserIDByFeedToken(context.Background(), tc.input.token)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedUserID, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCalendarPostgres_GetAllItems(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewCalendarRepository(db, 0)
+
+	type args struct {
+		userID int
+	}
+
+	type mockBehavior func(input args)
+
+	testCases := []struct {
+		name          string
+		input         args
+		mockBehavior  mockBehavior
+		expectedItems []model.TodoItem
+		wantErr       bool
+	}{
+		{
+			name:  "OK",
+			input: args{userID: 1},
+			mockBehavior: func(input args) {
+				rows := mock.NewRows([]string{"id", "list_id", "title", "description", "completion_date", "done"}).
+					AddRow(1, 1, "test", "testing", "2021-11-21 00:00:00", true).
+					AddRow(2, 2, "test2", "testing2", "2021-11-22 00:00:00", false)
+
+				query := fmt.Sprintf("SELECT (.+) FROM %s ti INNER JOIN %s tl ON (.+) WHERE (.+)", todoItemsTable, todoListsTable)
+				mock.ExpectQuery(query).WithArgs(input.userID).WillReturnRows(rows)
+			},
+			expectedItems: []model.TodoItem{
+				{ID: 1, ListID: 1, Title: "test", Description: "testing", CompletionDate: "2021-11-21 00:00:00", Done: true},
+				{ID: 2, ListID: 2, Title: "test2", Description: "testing2", CompletionDate: "2021-11-22 00:00:00", Done: false},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Empty field",
+			mockBehavior: func(input args) {
+				query := fmt.Sprintf("SELECT (.+) FROM %s ti INNER JOIN %s tl ON (.+) WHERE (.+)", todoItemsTable, todoListsTable)
+				mock.ExpectQuery(query).WithArgs(0)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.input)
+
+			got, err := repos.GetAllItems(context.Background(), tc.input.userID)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedItems, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
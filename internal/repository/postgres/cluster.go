@@ -0,0 +1,253 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/config"
+	"github.com/Lapp-coder/todo-app/internal/repository/routing"
+	"github.com/Lapp-coder/todo-app/internal/repository/sqltx"
+	"github.com/jmoiron/sqlx"
+)
+
+// Cluster routes the queries between the primary and its read replicas. Writes,
+// transactions and the reads of a user who has written something within the sticky
+// window go to the primary, the other reads are spread over the healthy replicas.
+type Cluster struct {
+	primary      *sqlx.DB
+	replicas     []*replica
+	next         uint32
+	stickyWindow time.Duration
+	maxIdleConns int
+
+	mu         sync.Mutex
+	lastWrites map[int]time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// defaultMaxIdleConns is the limit database/sql uses when it isn't set.
+const defaultMaxIdleConns = 2
+
+type replica struct {
+	db      *sqlx.DB
+	healthy int32
+}
+
+// NewCluster creates a cluster of already opened databases, the replicas are considered
+// healthy until Check says otherwise.
+func NewCluster(primary *sqlx.DB, replicas []*sqlx.DB, stickyWindow time.Duration) *Cluster {
+	c := &Cluster{
+		primary:      primary,
+		replicas:     make([]*replica, 0, len(replicas)),
+		stickyWindow: stickyWindow,
+		lastWrites:   make(map[int]time.Time),
+	}
+
+	for _, db := range replicas {
+		c.replicas = append(c.replicas, &replica{db: db, healthy: 1})
+	}
+
+	return c
+}
+
+// OpenCluster connects to the primary and the replicas from the config and starts
+// checking the health of the databases. An unavailable replica doesn't prevent
+// the start, it's skipped until it comes back.
+func OpenCluster(cfg config.PostgresDB) (*Cluster, error) {
+	primary, err := NewDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	replicas := make([]*sqlx.DB, 0, len(cfg.Replicas))
+	for _, r := range cfg.Replicas {
+		db, err := sqlx.Open("postgres", dataSourceName(cfg, r.Host, r.Port))
+		if err != nil {
+			primary.Close()
+			for _, db := range replicas {
+				db.Close()
+			}
+
+			return nil, err
+		}
+
+		configurePool(db, cfg)
+		replicas = append(replicas, db)
+	}
+
+	c := NewCluster(primary, replicas, time.Duration(cfg.StickyWindow)*time.Second)
+	c.maxIdleConns = cfg.MaxIdleConns
+
+	if interval := time.Duration(cfg.HealthCheckInterval) * time.Second; interval > 0 {
+		c.Check(context.Background(), interval)
+		c.startChecks(interval)
+	}
+
+	return c, nil
+}
+
+// Primary returns the database the writes are sent to.
+func (c *Cluster) Primary() *sqlx.DB {
+	return c.primary
+}
+
+// Check pings the databases and marks the replicas that don't answer within the timeout
+// as unhealthy. The idle connections of a database that failed the check are dropped,
+// since they're most likely broken by its restart, so the pool reconnects on the next query.
+func (c *Cluster) Check(ctx context.Context, timeout time.Duration) {
+	var wg sync.WaitGroup
+	for _, r := range c.replicas {
+		wg.Add(1)
+		go func(r *replica) {
+			defer wg.Done()
+
+			if !c.ping(ctx, r.db, timeout) {
+				atomic.StoreInt32(&r.healthy, 0)
+				return
+			}
+
+			atomic.StoreInt32(&r.healthy, 1)
+		}(r)
+	}
+
+	c.ping(ctx, c.primary, timeout)
+	wg.Wait()
+
+	c.forgetWrites()
+}
+
+func (c *Cluster) ping(ctx context.Context, db *sqlx.DB, timeout time.Duration) bool {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		c.dropIdleConns(db)
+		return false
+	}
+
+	return true
+}
+
+func (c *Cluster) dropIdleConns(db *sqlx.DB) {
+	maxIdleConns := c.maxIdleConns
+	if maxIdleConns <= 0 {
+		maxIdleConns = defaultMaxIdleConns
+	}
+
+	db.SetMaxIdleConns(-1)
+	db.SetMaxIdleConns(maxIdleConns)
+}
+
+// PingContext checks the connection to the primary, the replicas aren't required
+// for the app to work.
+func (c *Cluster) PingContext(ctx context.Context) error {
+	return c.primary.PingContext(ctx)
+}
+
+// PoolStats returns the connection pool statistics of the primary and the replicas.
+func (c *Cluster) PoolStats() map[string]sql.DBStats {
+	stats := map[string]sql.DBStats{"primary": c.primary.Stats()}
+	for i, r := range c.replicas {
+		stats[fmt.Sprintf("replica_%d", i)] = r.db.Stats()
+	}
+
+	return stats
+}
+
+func (c *Cluster) startChecks(interval time.Duration) {
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+
+	go func() {
+		defer close(c.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-c.stop:
+				return
+			case <-ticker.C:
+				c.Check(context.Background(), interval)
+			}
+		}
+	}()
+}
+
+// Close stops the health checks and closes the connections to all the databases.
+func (c *Cluster) Close() error {
+	if c.stop != nil {
+		close(c.stop)
+		<-c.done
+	}
+
+	err := c.primary.Close()
+	for _, r := range c.replicas {
+		if closeErr := r.db.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
+
+// writer returns the executor for a write and remembers the user who made it.
+func (c *Cluster) writer(ctx context.Context) sqltx.Executor {
+	if userID, ok := routing.UserFrom(ctx); ok && c.stickyWindow > 0 && len(c.replicas) > 0 {
+		c.mu.Lock()
+		c.lastWrites[userID] = time.Now()
+		c.mu.Unlock()
+	}
+
+	return sqltx.From(ctx, c.primary)
+}
+
+// reader returns the executor for a read that may be served by a replica.
+func (c *Cluster) reader(ctx context.Context) sqltx.Executor {
+	if len(c.replicas) == 0 || sqltx.InTx(ctx) || c.recentlyWrote(ctx) {
+		return sqltx.From(ctx, c.primary)
+	}
+
+	start := atomic.AddUint32(&c.next, 1)
+	for i := range c.replicas {
+		r := c.replicas[(int(start)+i)%len(c.replicas)]
+		if atomic.LoadInt32(&r.healthy) == 1 {
+			return r.db
+		}
+	}
+
+	return c.primary
+}
+
+func (c *Cluster) recentlyWrote(ctx context.Context) bool {
+	userID, ok := routing.UserFrom(ctx)
+	if !ok {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	lastWrite, ok := c.lastWrites[userID]
+
+	return ok && time.Since(lastWrite) < c.stickyWindow
+}
+
+// forgetWrites drops the writes that are out of the sticky window.
+func (c *Cluster) forgetWrites() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for userID, lastWrite := range c.lastWrites {
+		if time.Since(lastWrite) >= c.stickyWindow {
+			delete(c.lastWrites, userID)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Lapp-coder/todo-app/internal/repository/routing"
+	"github.com/Lapp-coder/todo-app/internal/repository/sqltx"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMockDB(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
+	mockDB, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	return sqlx.NewDb(mockDB, "sqlmock"), mock
+}
+
+func TestCluster_reader(t *testing.T) {
+	primary, _ := newMockDB(t)
+	replica, _ := newMockDB(t)
+
+	testTable := []struct {
+		name     string
+		cluster  func() *Cluster
+		ctx      func(c *Cluster) context.Context
+		expected *sqlx.DB
+	}{
+		{
+			name:     "Replica",
+			cluster:  func() *Cluster { return NewCluster(primary, []*sqlx.DB{replica}, time.Minute) },
+			ctx:      func(c *Cluster) context.Context { return routing.WithUser(context.Background(), 1) },
+			expected: replica,
+		},
+		{
+			name:     "Without replicas",
+			cluster:  func() *Cluster { return NewCluster(primary, nil, time.Minute) },
+			ctx:      func(c *Cluster) context.Context { return context.Background() },
+			expected: primary,
+		},
+		{
+			name:    "After write of the user",
+			cluster: func() *Cluster { return NewCluster(primary, []*sqlx.DB{replica}, time.Minute) },
+			ctx: func(c *Cluster) context.Context {
+				ctx := routing.WithUser(context.Background(), 1)
+				c.writer(ctx)
+				return ctx
+			},
+			expected: primary,
+		},
+		{
+			name:    "After write of another user",
+			cluster: func() *Cluster { return NewCluster(primary, []*sqlx.DB{replica}, time.Minute) },
+			ctx: func(c *Cluster) context.Context {
+				c.writer(routing.WithUser(context.Background(), 2))
+				return routing.WithUser(context.Background(), 1)
+			},
+			expected: replica,
+		},
+		{
+			name:    "After the sticky window",
+			cluster: func() *Cluster { return NewCluster(primary, []*sqlx.DB{replica}, time.Nanosecond) },
+			ctx: func(c *Cluster) context.Context {
+				ctx := routing.WithUser(context.Background(), 1)
+				c.writer(ctx)
+				time.Sleep(time.Millisecond)
+				return ctx
+			},
+			expected: replica,
+		},
+		{
+			name:    "Unhealthy replica",
+			cluster: func() *Cluster { return NewCluster(primary, []*sqlx.DB{replica}, time.Minute) },
+			ctx: func(c *Cluster) context.Context {
+				c.replicas[0].healthy = 0
+				return context.Background()
+			},
+			expected: primary,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			c := tc.cluster()
+
+			assert.Equal(t, tc.expected, c.reader(tc.ctx(c)))
+		})
+	}
+}
+
+func TestCluster_readerInTx(t *testing.T) {
+	primary, mock := newMockDB(t)
+	replica, _ := newMockDB(t)
+	c := NewCluster(primary, []*sqlx.DB{replica}, time.Minute)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	err := sqltx.NewManager(primary).WithinTx(context.Background(), func(ctx context.Context) error {
+		assert.NotEqual(t, replica, c.reader(ctx))
+		assert.NotEqual(t, primary, c.reader(ctx))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCluster_Check(t *testing.T) {
+	primary, primaryMock := newMockDB(t)
+	healthy, healthyMock := newMockDB(t)
+	unhealthy, unhealthyMock := newMockDB(t)
+	recovered, recoveredMock := newMockDB(t)
+	c := NewCluster(primary, []*sqlx.DB{unhealthy, healthy, recovered}, time.Minute)
+	c.replicas[2].healthy = 0
+
+	primaryMock.ExpectPing()
+	healthyMock.ExpectPing()
+	unhealthyMock.ExpectPing().WillReturnError(errors.New("connection refused"))
+	recoveredMock.ExpectPing()
+
+	c.Check(context.Background(), time.Second)
+
+	readers := make([]sqltx.Executor, 0, len(c.replicas))
+	for i := 0; i < len(c.replicas)-1; i++ {
+		readers = append(readers, c.reader(context.Background()))
+	}
+
+	assert.ElementsMatch(t, []sqltx.Executor{healthy, recovered}, readers)
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+	assert.NoError(t, healthyMock.ExpectationsWereMet())
+	assert.NoError(t, unhealthyMock.ExpectationsWereMet())
+	assert.NoError(t, recoveredMock.ExpectationsWereMet())
+}
+
+func TestCluster_PoolStats(t *testing.T) {
+	primary, _ := newMockDB(t)
+	replica, _ := newMockDB(t)
+	c := NewCluster(primary, []*sqlx.DB{replica}, 0)
+
+	stats := c.PoolStats()
+
+	assert.Len(t, stats, 2)
+	assert.Contains(t, stats, "primary")
+	assert.Contains(t, stats, "replica_0")
+}
@@ -1,7 +1,9 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/Lapp-coder/todo-app/internal/config"
 	"github.com/jmoiron/sqlx"
@@ -11,15 +13,79 @@ const (
 	usersTable     string = "users"
 	todoListsTable string = "todo_lists"
 	todoItemsTable string = "todo_items"
+	calendarsTable string = "calendar_feeds"
+	caldavTable    string = "caldav_objects"
+	resetsTable    string = "password_resets"
+	recoveryTable  string = "recovery_codes"
+	identityTable  string = "user_identities"
+	tokensTable    string = "access_tokens"
+	workspaceTable string = "workspaces"
+	membersTable   string = "workspace_members"
 )
 
+// maxConnectBackoff caps the pause between the attempts to connect.
+const maxConnectBackoff = 30 * time.Second
+
+// NewDB connects to the primary, retrying with a growing pause while the database
+// isn't up yet, and applies the pool settings from the config.
 func NewDB(cfg config.PostgresDB) (*sqlx.DB, error) {
-	db, err := sqlx.Connect("postgres", fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.DBName, cfg.SSLMode))
+	db, err := connectWithRetry(func() (*sqlx.DB, error) {
+		return sqlx.Connect("postgres", dataSourceName(cfg, cfg.Host, cfg.Port))
+	}, cfg.ConnectRetries, time.Duration(cfg.ConnectBackoff)*time.Second, time.Sleep)
 	if err != nil {
 		return nil, err
 	}
 
+	configurePool(db, cfg)
+
 	return db, nil
 }
+
+func connectWithRetry(connect func() (*sqlx.DB, error), retries int, backoff time.Duration, sleep func(time.Duration)) (*sqlx.DB, error) {
+	attempts := retries
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		var db *sqlx.DB
+		if db, err = connect(); err == nil {
+			return db, nil
+		}
+
+		if attempt == attempts {
+			return nil, fmt.Errorf("failed to connect after %d attempts: %w", attempts, err)
+		}
+
+		sleep(backoff)
+		if backoff *= 2; backoff > maxConnectBackoff {
+			backoff = maxConnectBackoff
+		}
+	}
+}
+
+func configurePool(db *sqlx.DB, cfg config.PostgresDB) {
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	if cfg.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	db.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second)
+	db.SetConnMaxIdleTime(time.Duration(cfg.ConnMaxIdleTime) * time.Second)
+}
+
+func dataSourceName(cfg config.PostgresDB, host, port string) string {
+	return fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		host, port, cfg.Username, cfg.Password, cfg.DBName, cfg.SSLMode)
+}
+
+// withTimeout limits the time a query may take, zero timeout means no limit
+// besides the deadline of the request context.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectWithRetry(t *testing.T) {
+	errRefused := errors.New("connection refused")
+
+	testTable := []struct {
+		name             string
+		failures         int
+		retries          int
+		backoff          time.Duration
+		expectedAttempts int
+		expectedPauses   []time.Duration
+		wantErr          bool
+	}{
+		{
+			name:             "OK",
+			retries:          3,
+			backoff:          time.Second,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "OK after failures",
+			failures:         2,
+			retries:          3,
+			backoff:          time.Second,
+			expectedAttempts: 3,
+			expectedPauses:   []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:             "Out of retries",
+			failures:         5,
+			retries:          3,
+			backoff:          time.Second,
+			expectedAttempts: 3,
+			expectedPauses:   []time.Duration{time.Second, 2 * time.Second},
+			wantErr:          true,
+		},
+		{
+			name:             "Without retries",
+			failures:         1,
+			expectedAttempts: 1,
+			wantErr:          true,
+		},
+		{
+			name:             "Capped backoff",
+			failures:         3,
+			retries:          4,
+			backoff:          20 * time.Second,
+			expectedAttempts: 4,
+			expectedPauses:   []time.Duration{20 * time.Second, maxConnectBackoff, maxConnectBackoff},
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts int
+			var pauses []time.Duration
+
+			db, err := connectWithRetry(func() (*sqlx.DB, error) {
+				attempts++
+				if attempts <= tc.failures {
+					return nil, errRefused
+				}
+
+				return &sqlx.DB{}, nil
+			}, tc.retries, tc.backoff, func(d time.Duration) { pauses = append(pauses, d) })
+
+			if tc.wantErr {
+				assert.ErrorIs(t, err, errRefused)
+				assert.Nil(t, db)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, db)
+			}
+
+			assert.Equal(t, tc.expectedAttempts, attempts)
+			assert.Equal(t, tc.expectedPauses, pauses)
+		})
+	}
+}
@@ -1,22 +1,31 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/Lapp-coder/todo-app/internal/metrics"
 	"github.com/Lapp-coder/todo-app/internal/model"
-	TodoItemRepository "github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type TodoItem struct {
-	db *TodoItemRepository.DB
+	db           *Cluster
+	queryTimeout time.Duration
 }
 
-func NewTodoItemRepository(db *TodoItemRepository.DB) *TodoItem {
-	return &TodoItem{db: db}
+func NewTodoItemRepository(db *Cluster, queryTimeout time.Duration) *TodoItem {
+	return &TodoItem{db: db, queryTimeout: queryTimeout}
 }
 
-func (r *TodoItem) Create(listID int, item model.TodoItem) (int, error) {
+func (r *TodoItem) Create(ctx context.Context, listID int, item model.TodoItem) (int, error) {
+	defer metrics.ObserveQuery("todo_item", "Create", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	var fields = make([]string, 0)
 	var values = make([]interface{}, 0)
 	var placeHolderID int
@@ -48,44 +57,105 @@ func (r *TodoItem) Create(listID int, item model.TodoItem) (int, error) {
 		placeHolderIDs = append(placeHolderIDs, fmt.Sprintf("$%d", placeHolderID))
 	}
 
+	if item.Done {
+		fields = append(fields, "done")
+		values = append(values, item.Done)
+		placeHolderID++
+		placeHolderIDs = append(placeHolderIDs, fmt.Sprintf("$%d", placeHolderID))
+	}
+
 	query := fmt.Sprintf(
 		"INSERT INTO %s (%s) VALUES (%s) RETURNING id",
 		todoItemsTable, strings.Join(fields, ","), strings.Join(placeHolderIDs, ","),
 	)
-	if err := r.db.QueryRow(query, values...).Scan(&item.ID); err != nil {
+	if err := traced(r.db.writer(ctx)).QueryRowContext(ctx, query, values...).Scan(&item.ID); err != nil {
 		return 0, err
 	}
 
 	return item.ID, nil
 }
 
-func (r *TodoItem) GetAll(listID int) ([]model.TodoItem, error) {
+func (r *TodoItem) GetAll(ctx context.Context, listID int) ([]model.TodoItem, error) {
+	defer metrics.ObserveQuery("todo_item", "GetAll", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	var items []model.TodoItem
 
 	query := fmt.Sprintf(
 		`SELECT ti.id, ti.list_id, ti.title, ti.description, ti.completion_date, ti.done FROM %s ti 
 			WHERE ti.list_id = $1`, todoItemsTable)
-	if err := r.db.Select(&items, query, listID); err != nil {
+	if err := traced(r.db.reader(ctx)).SelectContext(ctx, &items, query, listID); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// GetAllByLists returns the items of several lists of the workspace with a single query.
+func (r *TodoItem) GetAllByLists(ctx context.Context, workspaceID int, listIDs []int) ([]model.TodoItem, error) {
+	defer metrics.ObserveQuery("todo_item", "GetAllByLists", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var items []model.TodoItem
+
+	query := fmt.Sprintf(
+		`SELECT ti.id, ti.list_id, ti.title, ti.description, ti.completion_date, ti.done FROM %s ti
+				INNER JOIN %s tl ON tl.id = ti.list_id WHERE tl.workspace_id = $1 AND ti.list_id = ANY($2)
+				ORDER BY ti.list_id, ti.id`, todoItemsTable, todoListsTable)
+	if err := traced(r.db.reader(ctx)).SelectContext(ctx, &items, query, workspaceID, pq.Array(listIDs)); err != nil {
 		return nil, err
 	}
 
 	return items, nil
 }
 
-func (r *TodoItem) GetByID(userID, itemID int) (model.TodoItem, error) {
+func (r *TodoItem) GetAllByWorkspace(ctx context.Context, workspaceID int) ([]model.TodoItem, error) {
+	defer metrics.ObserveQuery("todo_item", "GetAllByWorkspace", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var items []model.TodoItem
+
+	query := fmt.Sprintf(
+		`SELECT ti.id, ti.list_id, ti.title, ti.description, ti.completion_date, ti.done FROM %s ti
+				INNER JOIN %s tl ON tl.id = ti.list_id WHERE tl.workspace_id = $1
+				ORDER BY ti.list_id, ti.id`, todoItemsTable, todoListsTable)
+	if err := traced(r.db.reader(ctx)).SelectContext(ctx, &items, query, workspaceID); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *TodoItem) GetByID(ctx context.Context, workspaceID, itemID int) (model.TodoItem, error) {
+	defer metrics.ObserveQuery("todo_item", "GetByID", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	var item model.TodoItem
 
 	query := fmt.Sprintf(
 		`SELECT ti.id, ti.list_id, ti.title, ti.description, ti.completion_date, ti.done FROM %s ti
-				INNER JOIN %s tl ON tl.id = ti.list_id WHERE tl.user_id = $1 AND ti.id = $2`, todoItemsTable, todoListsTable)
-	if err := r.db.Get(&item, query, userID, itemID); err != nil {
+				INNER JOIN %s tl ON tl.id = ti.list_id WHERE tl.workspace_id = $1 AND ti.id = $2`, todoItemsTable, todoListsTable)
+	if err := traced(r.db.reader(ctx)).GetContext(ctx, &item, query, workspaceID, itemID); err != nil {
 		return model.TodoItem{}, err
 	}
 
 	return item, nil
 }
 
-func (r *TodoItem) Update(itemID int, update model.UpdateTodoItem) error {
+func (r *TodoItem) Update(ctx context.Context, itemID int, update model.UpdateTodoItem) error {
+	defer metrics.ObserveQuery("todo_item", "Update", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	setValues := make([]string, 0)
 	args := make([]interface{}, 0)
 	placeHolderID := 1
@@ -119,16 +189,21 @@ func (r *TodoItem) Update(itemID int, update model.UpdateTodoItem) error {
 	query := fmt.Sprintf(
 		"UPDATE %s ti SET %s WHERE ti.id = $%d",
 		todoItemsTable, strings.Join(setValues, ", "), placeHolderID)
-	if _, err := r.db.Exec(query, args...); err != nil {
+	if _, err := traced(r.db.writer(ctx)).ExecContext(ctx, query, args...); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (r *TodoItem) Delete(itemID int) error {
+func (r *TodoItem) Delete(ctx context.Context, itemID int) error {
+	defer metrics.ObserveQuery("todo_item", "Delete", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	query := fmt.Sprintf("DELETE FROM %s ti WHERE ti.id = $1", todoItemsTable)
-	if _, err := r.db.Exec(query, itemID); err != nil {
+	if _, err := traced(r.db.writer(ctx)).ExecContext(ctx, query, itemID); err != nil {
 		return err
 	}
 
@@ -1,6 +1,8 @@
 package postgres
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"testing"
 
@@ -8,6 +10,7 @@ import (
 	"github.com/Lapp-coder/todo-app/internal/model"
 	"github.com/Lapp-coder/todo-app/test"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,7 +21,7 @@ func TestTodoItemPostgres_Create(t *testing.T) {
 	}
 
 	db := sqlx.NewDb(mockDB, "sqlmock")
-	repos := NewTodoItemRepository(db)
+	repos := NewTodoItemRepository(NewCluster(db, nil, 0), 0)
 
 	type args struct {
 		listID int
@@ -48,6 +51,20 @@ func TestTodoItemPostgres_Create(t *testing.T) {
 			expectedID: 3,
 			wantErr:    false,
 		},
+		{
+			name: "OK_Done",
+			input: args{
+				listID: 1,
+				item:   model.TodoItem{Title: "test", CompletionDate: "2021-11-21 00:00:00", Done: true},
+			},
+			mockBehavior: func(input args) {
+				rows := mock.NewRows([]string{"id"}).AddRow(3)
+				query := fmt.Sprintf("INSERT INTO %s", todoItemsTable)
+				mock.ExpectQuery(query).WithArgs(input.listID, input.item.Title, input.item.CompletionDate, input.item.Done).WillReturnRows(rows)
+			},
+			expectedID: 3,
+			wantErr:    false,
+		},
 		{
 			name: "OK_WithoutDescription",
 			input: args{
@@ -97,7 +114,7 @@ func TestTodoItemPostgres_Create(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.mockBehavior(tc.input)
 
-			got, err := repos.Create(tc.input.listID, tc.input.item)
+			got, err := repos.Create(context.Background(), tc.input.listID, tc.input.item)
 			if tc.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -117,7 +134,7 @@ func TestTodoItemPostgres_GetAll(t *testing.T) {
 	}
 
 	db := sqlx.NewDb(mockDB, "sqlmock")
-	repos := NewTodoItemRepository(db)
+	repos := NewTodoItemRepository(NewCluster(db, nil, 0), 0)
 
 	type args struct {
 		listID int
@@ -165,7 +182,138 @@ func TestTodoItemPostgres_GetAll(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.mockBehavior(tc.input)
 
-			got, err := repos.GetAll(tc.input.listID)
+			got, err := repos.GetAll(context.Background(), tc.input.listID)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedItems, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTodoItemPostgres_GetAllByLists(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewTodoItemRepository(NewCluster(db, nil, 0), 0)
+
+	type args struct {
+		workspaceID int
+		listIDs     []int
+	}
+
+	type mockBehavior func(input args)
+
+	testCases := []struct {
+		name          string
+		input         args
+		mockBehavior  mockBehavior
+		expectedItems []model.TodoItem
+		wantErr       bool
+	}{
+		{
+			name:  "OK",
+			input: args{workspaceID: 1, listIDs: []int{1, 2}},
+			mockBehavior: func(input args) {
+				rows := mock.NewRows([]string{"id", "list_id", "title", "description", "completion_date", "done"}).
+					AddRow(1, 1, "test", "testing", "2021-11-21 00:00:00", true).
+					AddRow(2, 2, "test2", "testing2", "2021-11-21 00:00:00", false)
+
+				query := fmt.Sprintf("SELECT (.+) FROM %s ti INNER JOIN %s tl ON (.+) WHERE (.+)", todoItemsTable, todoListsTable)
+				mock.ExpectQuery(query).WithArgs(input.workspaceID, pq.Array(input.listIDs)).WillReturnRows(rows)
+			},
+			expectedItems: []model.TodoItem{
+				{ID: 1, ListID: 1, Title: "test", Description: "testing", CompletionDate: "2021-11-21 00:00:00", Done: true},
+				{ID: 2, ListID: 2, Title: "test2", Description: "testing2", CompletionDate: "2021-11-21 00:00:00", Done: false},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "Failure",
+			input: args{workspaceID: 1, listIDs: []int{1}},
+			mockBehavior: func(input args) {
+				query := fmt.Sprintf("SELECT (.+) FROM %s ti INNER JOIN %s tl ON (.+) WHERE (.+)", todoItemsTable, todoListsTable)
+				mock.ExpectQuery(query).WithArgs(input.workspaceID, pq.Array(input.listIDs)).WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.input)
+
+			got, err := repos.GetAllByLists(context.Background(), tc.input.workspaceID, tc.input.listIDs)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedItems, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTodoItemPostgres_GetAllByWorkspace(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewTodoItemRepository(NewCluster(db, nil, 0), 0)
+
+	type mockBehavior func(workspaceID int)
+
+	testCases := []struct {
+		name          string
+		workspaceID   int
+		mockBehavior  mockBehavior
+		expectedItems []model.TodoItem
+		wantErr       bool
+	}{
+		{
+			name:        "OK",
+			workspaceID: 1,
+			mockBehavior: func(workspaceID int) {
+				rows := mock.NewRows([]string{"id", "list_id", "title", "description", "completion_date", "done"}).
+					AddRow(1, 1, "test", "testing", "2021-11-21 00:00:00", true).
+					AddRow(2, 3, "test2", "testing2", "2021-11-21 00:00:00", false)
+
+				query := fmt.Sprintf("SELECT (.+) FROM %s ti INNER JOIN %s tl ON (.+) WHERE (.+)", todoItemsTable, todoListsTable)
+				mock.ExpectQuery(query).WithArgs(workspaceID).WillReturnRows(rows)
+			},
+			expectedItems: []model.TodoItem{
+				{ID: 1, ListID: 1, Title: "test", Description: "testing", CompletionDate: "2021-11-21 00:00:00", Done: true},
+				{ID: 2, ListID: 3, Title: "test2", Description: "testing2", CompletionDate: "2021-11-21 00:00:00", Done: false},
+			},
+			wantErr: false,
+		},
+		{
+			name:        "Failure",
+			workspaceID: 1,
+			mockBehavior: func(workspaceID int) {
+				query := fmt.Sprintf("SELECT (.+) FROM %s ti INNER JOIN %s tl ON (.+) WHERE (.+)", todoItemsTable, todoListsTable)
+				mock.ExpectQuery(query).WithArgs(workspaceID).WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.workspaceID)
+
+			got, err := repos.GetAllByWorkspace(context.Background(), tc.workspaceID)
 			if tc.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -185,11 +333,11 @@ func TestTodoItemPostgres_GetByID(t *testing.T) {
 	}
 
 	db := sqlx.NewDb(mockDB, "sqlmock")
-	repos := NewTodoItemRepository(db)
+	repos := NewTodoItemRepository(NewCluster(db, nil, 0), 0)
 
 	type args struct {
-		userID int
-		listID int
+		workspaceID int
+		listID      int
 	}
 
 	type mockBehavior func(input args)
@@ -204,15 +352,15 @@ func TestTodoItemPostgres_GetByID(t *testing.T) {
 		{
 			name: "OK",
 			input: args{
-				userID: 1,
-				listID: 3,
+				workspaceID: 1,
+				listID:      3,
 			},
 			mockBehavior: func(input args) {
 				rows := mock.NewRows([]string{"id", "list_id", "title", "description", "completion_date", "done"}).
 					AddRow(1, 3, "test", "testing", "2021-11-21 00:00:00", true)
 
 				query := fmt.Sprintf("SELECT (.+) FROM %s ti INNER JOIN %s tl ON (.+) WHERE (.+)", todoItemsTable, todoListsTable)
-				mock.ExpectQuery(query).WithArgs(input.userID, input.listID).WillReturnRows(rows)
+				mock.ExpectQuery(query).WithArgs(input.workspaceID, input.listID).WillReturnRows(rows)
 			},
 			expectedItem: model.TodoItem{
 				ID:             1,
@@ -240,7 +388,7 @@ func TestTodoItemPostgres_GetByID(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.mockBehavior(tc.input)
 
-			got, err := repos.GetByID(tc.input.userID, tc.input.listID)
+			got, err := repos.GetByID(context.Background(), tc.input.workspaceID, tc.input.listID)
 			if tc.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -260,7 +408,7 @@ func TestTodoItemPostgres_Update(t *testing.T) {
 	}
 
 	db := sqlx.NewDb(mockDB, "sqlmock")
-	repos := NewTodoItemRepository(db)
+	repos := NewTodoItemRepository(NewCluster(db, nil, 0), 0)
 
 	type args struct {
 		itemID int
@@ -426,7 +574,7 @@ func TestTodoItemPostgres_Update(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.mockBehavior(tc.input)
 
-			err := repos.Update(tc.input.itemID, tc.input.update)
+			err := repos.Update(context.Background(), tc.input.itemID, tc.input.update)
 			if tc.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -445,7 +593,7 @@ func TestTodoItemPostgres_Delete(t *testing.T) {
 	}
 
 	db := sqlx.NewDb(mockDB, "sqlmock")
-	repos := NewTodoItemRepository(db)
+	repos := NewTodoItemRepository(NewCluster(db, nil, 0), 0)
 
 	type args struct {
 		itemID int
@@ -482,7 +630,7 @@ func TestTodoItemPostgres_Delete(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.mockBehavior(tc.input)
 
-			err := repos.Delete(tc.input.itemID)
+			err := repos.Delete(context.Background(), tc.input.itemID)
 			if tc.wantErr {
 				assert.Error(t, err)
 			} else {
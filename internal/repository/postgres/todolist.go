@@ -1,22 +1,31 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/Lapp-coder/todo-app/internal/metrics"
 	"github.com/Lapp-coder/todo-app/internal/model"
-	"github.com/jmoiron/sqlx"
+	"github.com/Lapp-coder/todo-app/internal/repository/sqltx"
 )
 
 type TodoListRepository struct {
-	db *sqlx.DB
+	db           *Cluster
+	queryTimeout time.Duration
 }
 
-func NewTodoListRepository(db *sqlx.DB) *TodoListRepository {
-	return &TodoListRepository{db: db}
+func NewTodoListRepository(db *Cluster, queryTimeout time.Duration) *TodoListRepository {
+	return &TodoListRepository{db: db, queryTimeout: queryTimeout}
 }
 
-func (r *TodoListRepository) Create(userID int, list model.TodoList) (int, error) {
+func (r *TodoListRepository) Create(ctx context.Context, workspaceID, userID int, list model.TodoList) (int, error) {
+	defer metrics.ObserveQuery("todo_list", "Create", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	var fields = make([]string, 0)
 	var values = make([]interface{}, 0)
 	var placeHolderID int
@@ -27,6 +36,11 @@ func (r *TodoListRepository) Create(userID int, list model.TodoList) (int, error
 	placeHolderID++
 	placeHolderIDs = append(placeHolderIDs, fmt.Sprintf("$%d", placeHolderID))
 
+	fields = append(fields, "workspace_id")
+	values = append(values, workspaceID)
+	placeHolderID++
+	placeHolderIDs = append(placeHolderIDs, fmt.Sprintf("$%d", placeHolderID))
+
 	if list.Title != "" {
 		fields = append(fields, "title")
 		values = append(values, list.Title)
@@ -52,38 +66,55 @@ func (r *TodoListRepository) Create(userID int, list model.TodoList) (int, error
 		"INSERT INTO %s (%s) VALUES (%s) RETURNING id",
 		todoListsTable, strings.Join(fields, ","), strings.Join(placeHolderIDs, ","),
 	)
-	if err := r.db.QueryRow(query, values...).Scan(&list.ID); err != nil {
+	if err := traced(r.db.writer(ctx)).QueryRowContext(ctx, query, values...).Scan(&list.ID); err != nil {
 		return 0, err
 	}
 
 	return list.ID, nil
 }
 
-func (r *TodoListRepository) GetAll(userID int) ([]model.TodoList, error) {
+func (r *TodoListRepository) GetAll(ctx context.Context, workspaceID int) ([]model.TodoList, error) {
+	defer metrics.ObserveQuery("todo_list", "GetAll", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	var lists []model.TodoList
 
 	query := fmt.Sprintf(
-		"SELECT tl.id, tl.user_id, tl.title, tl.description, tl.completion_date FROM %s tl WHERE tl.user_id = $1", todoListsTable)
-	if err := r.db.Select(&lists, query, userID); err != nil {
+		`SELECT tl.id, tl.user_id, tl.workspace_id, tl.title, tl.description, tl.completion_date FROM %s tl
+				WHERE tl.workspace_id = $1`, todoListsTable)
+	if err := traced(r.db.reader(ctx)).SelectContext(ctx, &lists, query, workspaceID); err != nil {
 		return nil, err
 	}
 
 	return lists, nil
 }
 
-func (r *TodoListRepository) GetByID(userID, listID int) (model.TodoList, error) {
+func (r *TodoListRepository) GetByID(ctx context.Context, workspaceID, listID int) (model.TodoList, error) {
+	defer metrics.ObserveQuery("todo_list", "GetByID", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	var list model.TodoList
 
 	query := fmt.Sprintf(
-		"SELECT tl.id, tl.user_id, tl.title, tl.description, tl.completion_date FROM %s tl WHERE tl.user_id = $1 AND tl.list_id = $2", todoListsTable)
-	if err := r.db.Get(&list, query, userID, listID); err != nil {
+		`SELECT tl.id, tl.user_id, tl.workspace_id, tl.title, tl.description, tl.completion_date FROM %s tl
+				WHERE tl.workspace_id = $1 AND tl.id = $2`, todoListsTable)
+	if err := traced(r.db.reader(ctx)).GetContext(ctx, &list, query, workspaceID, listID); err != nil {
 		return list, err
 	}
 
 	return list, nil
 }
 
-func (r *TodoListRepository) Update(listID int, update model.UpdateTodoList) error {
+func (r *TodoListRepository) Update(ctx context.Context, workspaceID, listID int, update model.UpdateTodoList) error {
+	defer metrics.ObserveQuery("todo_list", "Update", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	setValues := make([]string, 0)
 	args := make([]interface{}, 0)
 	placeHolderID := 1
@@ -106,37 +137,34 @@ func (r *TodoListRepository) Update(listID int, update model.UpdateTodoList) err
 		placeHolderID++
 	}
 
-	args = append(args, listID)
+	args = append(args, listID, workspaceID)
 
-	query := fmt.Sprintf("UPDATE %s tl SET %s WHERE tl.id = $%d", todoListsTable, strings.Join(setValues, ", "), placeHolderID)
-	if _, err := r.db.Exec(query, args...); err != nil {
+	query := fmt.Sprintf("UPDATE %s tl SET %s WHERE tl.id = $%d AND tl.workspace_id = $%d",
+		todoListsTable, strings.Join(setValues, ", "), placeHolderID, placeHolderID+1)
+	if _, err := traced(r.db.writer(ctx)).ExecContext(ctx, query, args...); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (r *TodoListRepository) Delete(listID int) error {
-	tx, err := r.db.Begin()
-	if err != nil {
-		return err
-	}
+func (r *TodoListRepository) Delete(ctx context.Context, listID int) error {
+	defer metrics.ObserveQuery("todo_list", "Delete", time.Now())
 
-	query1 := fmt.Sprintf("DELETE FROM %s ti WHERE ti.list_id = $1", todoItemsTable)
-	if _, err = tx.Exec(query1, listID); err != nil {
-		tx.Rollback()
-		return err
-	}
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
 
-	query2 := fmt.Sprintf("DELETE FROM %s tl WHERE tl.id = $1", todoListsTable)
-	if _, err = tx.Exec(query2, listID); err != nil {
-		tx.Rollback()
-		return err
-	}
+	return sqltx.NewManager(r.db.Primary()).WithinTx(ctx, func(ctx context.Context) error {
+		query1 := fmt.Sprintf("DELETE FROM %s ti WHERE ti.list_id = $1", todoItemsTable)
+		if _, err := traced(r.db.writer(ctx)).ExecContext(ctx, query1, listID); err != nil {
+			return err
+		}
 
-	if err = tx.Commit(); err != nil {
-		return err
-	}
+		query2 := fmt.Sprintf("DELETE FROM %s tl WHERE tl.id = $1", todoListsTable)
+		if _, err := traced(r.db.writer(ctx)).ExecContext(ctx, query2, listID); err != nil {
+			return err
+		}
 
-	return nil
+		return nil
+	})
 }
@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -19,11 +20,12 @@ func TestTodoListPostgres_Create(t *testing.T) {
 	}
 
 	db := sqlx.NewDb(mockDB, "sqlmock")
-	repos := NewTodoListRepository(db)
+	repos := NewTodoListRepository(NewCluster(db, nil, 0), 0)
 
 	type args struct {
-		userID int
-		list   model.TodoList
+		workspaceID int
+		userID      int
+		list        model.TodoList
 	}
 
 	type mockBehavior func(input args)
@@ -38,14 +40,15 @@ func TestTodoListPostgres_Create(t *testing.T) {
 		{
 			name: "OK_AllFields",
 			input: args{
-				userID: 1,
-				list:   model.TodoList{UserID: 1, Title: "test", Description: "testing", CompletionDate: "2021-11-21 00:00:00"},
+				workspaceID: 2,
+				userID:      1,
+				list:        model.TodoList{UserID: 1, Title: "test", Description: "testing", CompletionDate: "2021-11-21 00:00:00"},
 			},
 			mockBehavior: func(input args) {
 				rows := mock.NewRows([]string{"id"}).AddRow(1)
 
 				query := fmt.Sprintf("INSERT INTO %s", todoListsTable)
-				mock.ExpectQuery(query).WithArgs(input.userID, input.list.Title, input.list.Description, input.list.CompletionDate).WillReturnRows(rows)
+				mock.ExpectQuery(query).WithArgs(input.userID, input.workspaceID, input.list.Title, input.list.Description, input.list.CompletionDate).WillReturnRows(rows)
 			},
 			expectedListID: 1,
 			wantErr:        false,
@@ -53,14 +56,15 @@ func TestTodoListPostgres_Create(t *testing.T) {
 		{
 			name: "OK_WithoutDescription",
 			input: args{
-				userID: 1,
-				list:   model.TodoList{Title: "test", CompletionDate: "2021-11-21 00:00:00"},
+				workspaceID: 2,
+				userID:      1,
+				list:        model.TodoList{Title: "test", CompletionDate: "2021-11-21 00:00:00"},
 			},
 			mockBehavior: func(input args) {
 				rows := mock.NewRows([]string{"id"}).AddRow(1)
 
 				query := fmt.Sprintf("INSERT INTO %s", todoListsTable)
-				mock.ExpectQuery(query).WithArgs(input.userID, input.list.Title, input.list.CompletionDate).WillReturnRows(rows)
+				mock.ExpectQuery(query).WithArgs(input.userID, input.workspaceID, input.list.Title, input.list.CompletionDate).WillReturnRows(rows)
 			},
 			expectedListID: 1,
 			wantErr:        false,
@@ -68,14 +72,15 @@ func TestTodoListPostgres_Create(t *testing.T) {
 		{
 			name: "OK_WithoutCompletionDate",
 			input: args{
-				userID: 1,
-				list:   model.TodoList{Title: "test", Description: "testing"},
+				workspaceID: 2,
+				userID:      1,
+				list:        model.TodoList{Title: "test", Description: "testing"},
 			},
 			mockBehavior: func(input args) {
 				rows := mock.NewRows([]string{"id"}).AddRow(1)
 
 				query := fmt.Sprintf("INSERT INTO %s", todoListsTable)
-				mock.ExpectQuery(query).WithArgs(input.userID, input.list.Title, input.list.Description).WillReturnRows(rows)
+				mock.ExpectQuery(query).WithArgs(input.userID, input.workspaceID, input.list.Title, input.list.Description).WillReturnRows(rows)
 			},
 			expectedListID: 1,
 			wantErr:        false,
@@ -84,7 +89,7 @@ func TestTodoListPostgres_Create(t *testing.T) {
 			name: "Empty fields",
 			mockBehavior: func(input args) {
 				query := fmt.Sprintf("INSERT INTO %s", todoListsTable)
-				mock.ExpectQuery(query).WithArgs(input.userID)
+				mock.ExpectQuery(query).WithArgs(input.userID, input.workspaceID)
 			},
 			wantErr: true,
 		},
@@ -94,7 +99,7 @@ func TestTodoListPostgres_Create(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.mockBehavior(tc.input)
 
-			got, err := repos.Create(tc.input.userID, tc.input.list)
+			got, err := repos.Create(context.Background(), tc.input.workspaceID, tc.input.userID, tc.input.list)
 			if tc.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -114,10 +119,10 @@ func TestTodoListPostgres_GetAll(t *testing.T) {
 	}
 
 	db := sqlx.NewDb(mockDB, "sqlmock")
-	repos := NewTodoListRepository(db)
+	repos := NewTodoListRepository(NewCluster(db, nil, 0), 0)
 
 	type args struct {
-		userID int
+		workspaceID int
 	}
 
 	type mockBehavior func(input args)
@@ -131,20 +136,20 @@ func TestTodoListPostgres_GetAll(t *testing.T) {
 	}{
 		{
 			name:  "OK",
-			input: args{userID: 1},
+			input: args{workspaceID: 2},
 			mockBehavior: func(input args) {
-				rows := mock.NewRows([]string{"id", "user_id", "title", "description", "completion_date"}).
-					AddRow(1, 1, "test1", "testing1", "2021-11-12 00:00:00").
-					AddRow(2, 1, "test2", "testing2", "2021-11-12 00:00:00").
-					AddRow(3, 1, "test3", "testing3", "2021-11-12 00:00:00")
+				rows := mock.NewRows([]string{"id", "user_id", "workspace_id", "title", "description", "completion_date"}).
+					AddRow(1, 1, 2, "test1", "testing1", "2021-11-12 00:00:00").
+					AddRow(2, 1, 2, "test2", "testing2", "2021-11-12 00:00:00").
+					AddRow(3, 1, 2, "test3", "testing3", "2021-11-12 00:00:00")
 
 				query := fmt.Sprintf("SELECT (.+) FROM %s tl WHERE (.+)", todoListsTable)
-				mock.ExpectQuery(query).WithArgs(input.userID).WillReturnRows(rows)
+				mock.ExpectQuery(query).WithArgs(input.workspaceID).WillReturnRows(rows)
 			},
 			expectedLists: []model.TodoList{
-				{ID: 1, UserID: 1, Title: "test1", Description: "testing1", CompletionDate: "2021-11-12 00:00:00"},
-				{ID: 2, UserID: 1, Title: "test2", Description: "testing2", CompletionDate: "2021-11-12 00:00:00"},
-				{ID: 3, UserID: 1, Title: "test3", Description: "testing3", CompletionDate: "2021-11-12 00:00:00"},
+				{ID: 1, UserID: 1, WorkspaceID: 2, Title: "test1", Description: "testing1", CompletionDate: "2021-11-12 00:00:00"},
+				{ID: 2, UserID: 1, WorkspaceID: 2, Title: "test2", Description: "testing2", CompletionDate: "2021-11-12 00:00:00"},
+				{ID: 3, UserID: 1, WorkspaceID: 2, Title: "test3", Description: "testing3", CompletionDate: "2021-11-12 00:00:00"},
 			},
 			wantErr: false,
 		},
@@ -162,7 +167,7 @@ func TestTodoListPostgres_GetAll(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.mockBehavior(tc.input)
 
-			got, err := repos.GetAll(tc.input.userID)
+			got, err := repos.GetAll(context.Background(), tc.input.workspaceID)
 			if tc.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -182,11 +187,11 @@ func TestTodoListPostgres_GetByID(t *testing.T) {
 	}
 
 	db := sqlx.NewDb(mockDB, "sqlmock")
-	repos := NewTodoListRepository(db)
+	repos := NewTodoListRepository(NewCluster(db, nil, 0), 0)
 
 	type args struct {
-		userID int
-		listID int
+		workspaceID int
+		listID      int
 	}
 
 	type mockBehavior func(input args)
@@ -201,16 +206,16 @@ func TestTodoListPostgres_GetByID(t *testing.T) {
 		{
 			name: "OK",
 			input: args{
-				userID: 1,
-				listID: 3,
+				workspaceID: 2,
+				listID:      3,
 			},
 			mockBehavior: func(input args) {
-				rows := mock.NewRows([]string{"id", "user_id", "title", "description", "completion_date"}).
-					AddRow(3, 1, "test", "testing", "2021-11-12 00:00:00")
+				rows := mock.NewRows([]string{"id", "user_id", "workspace_id", "title", "description", "completion_date"}).
+					AddRow(3, 1, 2, "test", "testing", "2021-11-12 00:00:00")
 				query := fmt.Sprintf("SELECT (.+) FROM %s tl WHERE (.+)", todoListsTable)
-				mock.ExpectQuery(query).WithArgs(input.userID, input.listID).WillReturnRows(rows)
+				mock.ExpectQuery(query).WithArgs(input.workspaceID, input.listID).WillReturnRows(rows)
 			},
-			expectedList: model.TodoList{ID: 3, UserID: 1, Title: "test", Description: "testing", CompletionDate: "2021-11-12 00:00:00"},
+			expectedList: model.TodoList{ID: 3, UserID: 1, WorkspaceID: 2, Title: "test", Description: "testing", CompletionDate: "2021-11-12 00:00:00"},
 			wantErr:      false,
 		},
 		{
@@ -222,7 +227,7 @@ func TestTodoListPostgres_GetByID(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name:  "EmptyField_UserId",
+			name:  "EmptyField_WorkspaceId",
 			input: args{listID: 3},
 			mockBehavior: func(input args) {
 				query := fmt.Sprintf("SELECT (.+) FROM %s tl WHERE (.+)", todoListsTable)
@@ -232,10 +237,10 @@ func TestTodoListPostgres_GetByID(t *testing.T) {
 		},
 		{
 			name:  "EmptyField_ListId",
-			input: args{userID: 1},
+			input: args{workspaceID: 2},
 			mockBehavior: func(input args) {
 				query := fmt.Sprintf("SELECT (.+) FROM %s tl WHERE (.+)", todoListsTable)
-				mock.ExpectQuery(query).WithArgs(1, 0)
+				mock.ExpectQuery(query).WithArgs(2, 0)
 			},
 			wantErr: true,
 		},
@@ -245,7 +250,7 @@ func TestTodoListPostgres_GetByID(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.mockBehavior(tc.input)
 
-			got, err := repos.GetByID(tc.input.userID, tc.input.listID)
+			got, err := repos.GetByID(context.Background(), tc.input.workspaceID, tc.input.listID)
 			if tc.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -265,11 +270,12 @@ func TestTodoListPostgres_Update(t *testing.T) {
 	}
 
 	db := sqlx.NewDb(mockDB, "sqlmock")
-	repos := NewTodoListRepository(db)
+	repos := NewTodoListRepository(NewCluster(db, nil, 0), 0)
 
 	type args struct {
-		listID int
-		update model.UpdateTodoList
+		workspaceID int
+		listID      int
+		update      model.UpdateTodoList
 	}
 
 	type mockBehavior func(input args)
@@ -283,7 +289,8 @@ func TestTodoListPostgres_Update(t *testing.T) {
 		{
 			name: "OK_AllFields",
 			input: args{
-				listID: 1,
+				workspaceID: 2,
+				listID:      1,
 				update: model.UpdateTodoList{
 					Title:          test.StringPointer("test"),
 					Description:    test.StringPointer("testing"),
@@ -293,14 +300,15 @@ func TestTodoListPostgres_Update(t *testing.T) {
 			mockBehavior: func(input args) {
 				query := fmt.Sprintf("UPDATE %s tl SET (.+) WHERE (.+)", todoListsTable)
 				mock.ExpectExec(query).WithArgs(
-					*input.update.Title, *input.update.Description, *input.update.CompletionDate, input.listID).WillReturnResult(sqlmock.NewResult(1, 1))
+					*input.update.Title, *input.update.Description, *input.update.CompletionDate, input.listID, input.workspaceID).WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 			wantErr: false,
 		},
 		{
 			name: "OK_WithoutDescription",
 			input: args{
-				listID: 1,
+				workspaceID: 2,
+				listID:      1,
 				update: model.UpdateTodoList{
 					Title:          test.StringPointer("test"),
 					CompletionDate: test.StringPointer("2021-11-21 00:00:00"),
@@ -309,14 +317,15 @@ func TestTodoListPostgres_Update(t *testing.T) {
 			mockBehavior: func(input args) {
 				query := fmt.Sprintf("UPDATE %s tl SET (.+) WHERE (.+)", todoListsTable)
 				mock.ExpectExec(query).WithArgs(
-					*input.update.Title, *input.update.CompletionDate, input.listID).WillReturnResult(sqlmock.NewResult(1, 1))
+					*input.update.Title, *input.update.CompletionDate, input.listID, input.workspaceID).WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 			wantErr: false,
 		},
 		{
 			name: "OK_WithoutTitle",
 			input: args{
-				listID: 1,
+				workspaceID: 2,
+				listID:      1,
 				update: model.UpdateTodoList{
 					Description:    test.StringPointer("testing"),
 					CompletionDate: test.StringPointer("2021-11-21 00:00:00"),
@@ -325,14 +334,15 @@ func TestTodoListPostgres_Update(t *testing.T) {
 			mockBehavior: func(input args) {
 				query := fmt.Sprintf("UPDATE %s tl SET (.+) WHERE (.+)", todoListsTable)
 				mock.ExpectExec(query).WithArgs(
-					*input.update.Description, *input.update.CompletionDate, input.listID).WillReturnResult(sqlmock.NewResult(1, 1))
+					*input.update.Description, *input.update.CompletionDate, input.listID, input.workspaceID).WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 			wantErr: false,
 		},
 		{
 			name: "OK_WithoutCompletionDate",
 			input: args{
-				listID: 1,
+				workspaceID: 2,
+				listID:      1,
 				update: model.UpdateTodoList{
 					Title:       test.StringPointer("test"),
 					Description: test.StringPointer("testing"),
@@ -341,18 +351,19 @@ func TestTodoListPostgres_Update(t *testing.T) {
 			mockBehavior: func(input args) {
 				query := fmt.Sprintf("UPDATE %s tl SET (.+) WHERE (.+)", todoListsTable)
 				mock.ExpectExec(query).WithArgs(
-					*input.update.Title, *input.update.Description, input.listID).WillReturnResult(sqlmock.NewResult(1, 1))
+					*input.update.Title, *input.update.Description, input.listID, input.workspaceID).WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 			wantErr: false,
 		},
 		{
 			name: "OK_NoUpdateFields",
 			input: args{
-				listID: 1,
+				workspaceID: 2,
+				listID:      1,
 			},
 			mockBehavior: func(input args) {
 				query := fmt.Sprintf("UPDATE %s tl SET WHERE (.+)", todoListsTable)
-				mock.ExpectExec(query).WithArgs(input.listID).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(query).WithArgs(input.listID, input.workspaceID).WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 			wantErr: false,
 		},
@@ -362,7 +373,7 @@ func TestTodoListPostgres_Update(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.mockBehavior(tc.input)
 
-			err := repos.Update(tc.input.listID, tc.input.update)
+			err := repos.Update(context.Background(), tc.input.workspaceID, tc.input.listID, tc.input.update)
 			if tc.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -381,7 +392,7 @@ func TestTodoListPostgres_Delete(t *testing.T) {
 	}
 
 	db := sqlx.NewDb(mockDB, "sqlmock")
-	repos := NewTodoListRepository(db)
+	repos := NewTodoListRepository(NewCluster(db, nil, 0), 0)
 
 	type args struct {
 		listID int
@@ -429,7 +440,7 @@ func TestTodoListPostgres_Delete(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.mockBehavior(tc.input)
 
-			err := repos.Delete(tc.input.listID)
+			err := repos.Delete(context.Background(), tc.input.listID)
 			if tc.wantErr {
 				assert.Error(t, err)
 			} else {
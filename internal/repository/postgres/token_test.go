@@ -0,0 +1,207 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessTokenPostgres_Create(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAccessTokenRepository(db, 0)
+
+	createdAt := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	type mockBehavior func(input model.AccessToken)
+
+	testCases := []struct {
+		name         string
+		input        model.AccessToken
+		mockBehavior mockBehavior
+		expectedID   int
+		wantErr      bool
+	}{
+		{
+			name: "OK",
+			input: model.AccessToken{
+				UserID: 1, Name: "ci", TokenHash: "hash", Scopes: model.Scopes{"lists:read", "items:write"}, CreatedAt: createdAt,
+			},
+			mockBehavior: func(input model.AccessToken) {
+				rows := mock.NewRows([]string{"id"}).AddRow(1)
+
+				query := fmt.Sprintf("INSERT INTO %s (.+) VALUES (.+) RETURNING id", tokensTable)
+				mock.ExpectQuery(query).
+					WithArgs(input.UserID, input.Name, input.TokenHash, "lists:read items:write", input.ExpiresAt, input.CreatedAt).
+					WillReturnRows(rows)
+			},
+			expectedID: 1,
+			wantErr:    false,
+		},
+		{
+			name:  "User not found",
+			input: model.AccessToken{UserID: 2, Name: "ci", TokenHash: "hash", Scopes: model.Scopes{"lists:read"}, CreatedAt: createdAt},
+			mockBehavior: func(input model.AccessToken) {
+				query := fmt.Sprintf("INSERT INTO %s (.+) VALUES (.+) RETURNING id", tokensTable)
+				mock.ExpectQuery(query).
+					WithArgs(input.UserID, input.Name, input.TokenHash, "lists:read", input.ExpiresAt, input.CreatedAt).
+					WillReturnError(fmt.Errorf("foreign key violation"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.input)
+
+			got, err := repos.Create(context.Background(), tc.input)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedID, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAccessTokenPostgres_GetByHash(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAccessTokenRepository(db, 0)
+
+	createdAt := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	columns := []string{"id", "user_id", "name", "token_hash", "scopes", "expires_at", "last_used_at", "created_at"}
+
+	type args struct {
+		tokenHash string
+	}
+
+	type mockBehavior func(input args)
+
+	testCases := []struct {
+		name          string
+		input         args
+		mockBehavior  mockBehavior
+		expectedToken model.AccessToken
+		wantErr       bool
+	}{
+		{
+			name:  "OK",
+			input: args{tokenHash: "hash"},
+			mockBehavior: func(input args) {
+				rows := mock.NewRows(columns).AddRow(1, 1, "ci", "hash", "lists:read items:write", nil, nil, createdAt)
+
+				query := fmt.Sprintf("SELECT (.+) FROM %s WHERE token_hash = (.+)", tokensTable)
+				mock.ExpectQuery(query).WithArgs(input.tokenHash).WillReturnRows(rows)
+			},
+			expectedToken: model.AccessToken{
+				ID: 1, UserID: 1, Name: "ci", TokenHash: "hash", Scopes: model.Scopes{"lists:read", "items:write"}, CreatedAt: createdAt,
+			},
+			wantErr: false,
+		},
+		{
+			name:  "Not found",
+			input: args{tokenHash: "unknown"},
+			mockBehavior: func(input args) {
+				rows := mock.NewRows(columns)
+
+				query := fmt.Sprintf("SELECT (.+) FROM %s WHERE token_hash = (.+)", tokensTable)
+				mock.ExpectQuery(query).WithArgs(input.tokenHash).WillReturnRows(rows)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.input)
+
+			got, err := repos.GetByHash(context.Background(), tc.input.tokenHash)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, sql.ErrNoRows)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedToken, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAccessTokenPostgres_Delete(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewAccessTokenRepository(db, 0)
+
+	type args struct {
+		userID  int
+		tokenID int
+	}
+
+	type mockBehavior func(input args)
+
+	testCases := []struct {
+		name         string
+		input        args
+		mockBehavior mockBehavior
+		wantErr      bool
+	}{
+		{
+			name:  "OK",
+			input: args{userID: 1, tokenID: 1},
+			mockBehavior: func(input args) {
+				query := fmt.Sprintf("DELETE FROM %s WHERE (.+)", tokensTable)
+				mock.ExpectExec(query).WithArgs(input.tokenID, input.userID).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantErr: false,
+		},
+		{
+			name:  "Not found",
+			input: args{userID: 2, tokenID: 1},
+			mockBehavior: func(input args) {
+				query := fmt.Sprintf("DELETE FROM %s WHERE (.+)", tokensTable)
+				mock.ExpectExec(query).WithArgs(input.tokenID, input.userID).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.input)
+
+			err := repos.Delete(context.Background(), tc.input.userID, tc.input.tokenID)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, sql.ErrNoRows)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/Lapp-coder/todo-app/internal/repository/sqltx"
+	"github.com/Lapp-coder/todo-app/internal/tracing"
+	"github.com/jmoiron/sqlx"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedExecutor starts a span for every query with the statement as an attribute.
+type tracedExecutor struct {
+	next sqltx.Executor
+}
+
+func traced(executor sqltx.Executor) tracedExecutor {
+	return tracedExecutor{next: executor}
+}
+
+// from is sqltx.From with the queries traced.
+func from(ctx context.Context, db *sqlx.DB) tracedExecutor {
+	return traced(sqltx.From(ctx, db))
+}
+
+func startQuery(ctx context.Context, query string) (context.Context, trace.Span) {
+	operation := query
+	if i := strings.IndexByte(query, ' '); i > 0 {
+		operation = query[:i]
+	}
+
+	return tracing.Start(ctx, "postgres "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperationKey.String(operation),
+			semconv.DBStatementKey.String(query),
+		))
+}
+
+func (e tracedExecutor) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := startQuery(ctx, query)
+	result, err := e.next.ExecContext(ctx, query, args...)
+	tracing.End(span, err)
+
+	return result, err
+}
+
+// QueryRowContext leaves the span open until the row is scanned, the query isn't done before.
+func (e tracedExecutor) QueryRowContext(ctx context.Context, query string, args ...interface{}) *tracedRow {
+	ctx, span := startQuery(ctx, query)
+
+	return &tracedRow{row: e.next.QueryRowContext(ctx, query, args...), span: span}
+}
+
+func (e tracedExecutor) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	ctx, span := startQuery(ctx, query)
+	err := e.next.GetContext(ctx, dest, query, args...)
+	tracing.End(span, err)
+
+	return err
+}
+
+func (e tracedExecutor) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	ctx, span := startQuery(ctx, query)
+	err := e.next.SelectContext(ctx, dest, query, args...)
+	tracing.End(span, err)
+
+	return err
+}
+
+// tracedRow ends the span of its query when it's scanned.
+type tracedRow struct {
+	row  *sql.Row
+	span trace.Span
+}
+
+func (r *tracedRow) Scan(dest ...interface{}) error {
+	err := r.row.Scan(dest...)
+	tracing.End(r.span, err)
+
+	return err
+}
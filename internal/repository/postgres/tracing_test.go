@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Lapp-coder/todo-app/internal/tracing/tracingtest"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+)
+
+func TestTracedExecutor(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+
+	testTable := []struct {
+		name           string
+		query          string
+		mockBehavior   func()
+		expectedName   string
+		expectedStatus codes.Code
+	}{
+		{
+			name:  "OK",
+			query: "DELETE FROM todo_items WHERE id = $1",
+			mockBehavior: func() {
+				mock.ExpectExec("DELETE FROM todo_items").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedName:   "postgres DELETE",
+			expectedStatus: codes.Unset,
+		},
+		{
+			name:  "Query failure",
+			query: "DELETE FROM todo_items WHERE id = $1",
+			mockBehavior: func() {
+				mock.ExpectExec("DELETE FROM todo_items").WithArgs(1).WillReturnError(errors.New("connection reset"))
+			},
+			expectedName:   "postgres DELETE",
+			expectedStatus: codes.Error,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			exporter := tracingtest.Record(t)
+			tc.mockBehavior()
+
+			from(context.Background(), db).ExecContext(context.Background(), tc.query, 1)
+
+			spans := exporter.GetSpans()
+			if assert.Len(t, spans, 1) {
+				assert.Equal(t, tc.expectedName, spans[0].Name)
+				assert.Equal(t, tc.expectedStatus, spans[0].Status.Code)
+				assert.Contains(t, spans[0].Attributes, semconv.DBStatementKey.String(tc.query))
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTracedExecutor_QueryRowContext(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+
+	testTable := []struct {
+		name           string
+		mockBehavior   func()
+		expectedStatus codes.Code
+	}{
+		{
+			name: "OK",
+			mockBehavior: func() {
+				mock.ExpectQuery("SELECT id FROM users").WithArgs(1).WillReturnRows(mock.NewRows([]string{"id"}).AddRow(1))
+			},
+			expectedStatus: codes.Unset,
+		},
+		{
+			name: "Scan failure",
+			mockBehavior: func() {
+				mock.ExpectQuery("SELECT id FROM users").WithArgs(1).WillReturnRows(mock.NewRows([]string{"id"}))
+			},
+			expectedStatus: codes.Error,
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			exporter := tracingtest.Record(t)
+			tc.mockBehavior()
+
+			row := from(context.Background(), db).QueryRowContext(context.Background(), "SELECT id FROM users WHERE id = $1", 1)
+			assert.Empty(t, exporter.GetSpans())
+
+			var id int
+			row.Scan(&id)
+
+			spans := exporter.GetSpans()
+			if assert.Len(t, spans, 1) {
+				assert.Equal(t, "postgres SELECT", spans[0].Name)
+				assert.Equal(t, tc.expectedStatus, spans[0].Status.Code)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
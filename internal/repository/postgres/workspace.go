@@ -0,0 +1,211 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/metrics"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/repository/sqltx"
+	"github.com/jmoiron/sqlx"
+)
+
+// workspaceColumns are selected from the workspaces joined with the membership of the user.
+const workspaceColumns = "w.id, w.name, w.personal_user_id IS NOT NULL AS personal, m.role"
+
+type WorkspaceRepository struct {
+	db           *sqlx.DB
+	queryTimeout time.Duration
+}
+
+func NewWorkspaceRepository(db *sqlx.DB, queryTimeout time.Duration) *WorkspaceRepository {
+	return &WorkspaceRepository{db: db, queryTimeout: queryTimeout}
+}
+
+func (r *WorkspaceRepository) Create(ctx context.Context, userID int, name string) (int, error) {
+	defer metrics.ObserveQuery("workspace", "Create", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var id int
+
+	query := fmt.Sprintf(`WITH w AS (INSERT INTO %s (name) VALUES ($1) RETURNING id)
+			INSERT INTO %s (workspace_id, user_id, role) VALUES ((SELECT id FROM w), $2, $3) RETURNING workspace_id`,
+		workspaceTable, membersTable)
+	if err := from(ctx, r.db).QueryRowContext(ctx, query, name, userID, model.WorkspaceOwner).Scan(&id); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *WorkspaceRepository) GetAll(ctx context.Context, userID int) ([]model.Workspace, error) {
+	defer metrics.ObserveQuery("workspace", "GetAll", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var workspaces []model.Workspace
+
+	query := fmt.Sprintf(`SELECT %s FROM %s w INNER JOIN %s m ON m.workspace_id = w.id
+			WHERE m.user_id = $1 ORDER BY w.id`, workspaceColumns, workspaceTable, membersTable)
+	if err := from(ctx, r.db).SelectContext(ctx, &workspaces, query, userID); err != nil {
+		return nil, err
+	}
+
+	return workspaces, nil
+}
+
+func (r *WorkspaceRepository) GetByID(ctx context.Context, userID, workspaceID int) (model.Workspace, error) {
+	defer metrics.ObserveQuery("workspace", "GetByID", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var workspace model.Workspace
+
+	query := fmt.Sprintf(`SELECT %s FROM %s w INNER JOIN %s m ON m.workspace_id = w.id
+			WHERE m.user_id = $1 AND w.id = $2`, workspaceColumns, workspaceTable, membersTable)
+	if err := from(ctx, r.db).GetContext(ctx, &workspace, query, userID, workspaceID); err != nil {
+		return model.Workspace{}, err
+	}
+
+	return workspace, nil
+}
+
+func (r *WorkspaceRepository) GetPersonal(ctx context.Context, userID int) (model.Workspace, error) {
+	defer metrics.ObserveQuery("workspace", "GetPersonal", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var workspace model.Workspace
+
+	query := fmt.Sprintf(`SELECT %s FROM %s w INNER JOIN %s m ON m.workspace_id = w.id
+			WHERE m.user_id = $1 AND w.personal_user_id = m.user_id`, workspaceColumns, workspaceTable, membersTable)
+	if err := from(ctx, r.db).GetContext(ctx, &workspace, query, userID); err != nil {
+		return model.Workspace{}, err
+	}
+
+	return workspace, nil
+}
+
+func (r *WorkspaceRepository) Update(ctx context.Context, workspaceID int, name string) error {
+	defer metrics.ObserveQuery("workspace", "Update", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf("UPDATE %s SET name = $1 WHERE id = $2", workspaceTable)
+	result, err := from(ctx, r.db).ExecContext(ctx, query, name, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *WorkspaceRepository) Delete(ctx context.Context, workspaceID int) error {
+	defer metrics.ObserveQuery("workspace", "Delete", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	return sqltx.NewManager(r.db).WithinTx(ctx, func(ctx context.Context) error {
+		query1 := fmt.Sprintf("DELETE FROM %s WHERE list_id IN (SELECT id FROM %s WHERE workspace_id = $1)",
+			todoItemsTable, todoListsTable)
+		if _, err := from(ctx, r.db).ExecContext(ctx, query1, workspaceID); err != nil {
+			return err
+		}
+
+		query2 := fmt.Sprintf("DELETE FROM %s WHERE workspace_id = $1", todoListsTable)
+		if _, err := from(ctx, r.db).ExecContext(ctx, query2, workspaceID); err != nil {
+			return err
+		}
+
+		query3 := fmt.Sprintf("DELETE FROM %s WHERE id = $1", workspaceTable)
+		result, err := from(ctx, r.db).ExecContext(ctx, query3, workspaceID)
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+
+		return nil
+	})
+}
+
+func (r *WorkspaceRepository) GetMembers(ctx context.Context, workspaceID int) ([]model.WorkspaceMember, error) {
+	defer metrics.ObserveQuery("workspace", "GetMembers", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var members []model.WorkspaceMember
+
+	query := fmt.Sprintf(`SELECT m.user_id, u.name, u.email, m.role FROM %s m INNER JOIN %s u ON u.id = m.user_id
+			WHERE m.workspace_id = $1 ORDER BY m.user_id`, membersTable, usersTable)
+	if err := from(ctx, r.db).SelectContext(ctx, &members, query, workspaceID); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (r *WorkspaceRepository) SetMember(ctx context.Context, workspaceID, userID int, role string) error {
+	defer metrics.ObserveQuery("workspace", "SetMember", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf(`INSERT INTO %s (workspace_id, user_id, role) VALUES ($1, $2, $3)
+			ON CONFLICT (workspace_id, user_id) DO UPDATE SET role = EXCLUDED.role`, membersTable)
+	if _, err := from(ctx, r.db).ExecContext(ctx, query, workspaceID, userID, role); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *WorkspaceRepository) DeleteMember(ctx context.Context, workspaceID, userID int) error {
+	defer metrics.ObserveQuery("workspace", "DeleteMember", time.Now())
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE workspace_id = $1 AND user_id = $2", membersTable)
+	result, err := from(ctx, r.db).ExecContext(ctx, query, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,371 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkspacePostgres_Create(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewWorkspaceRepository(db, 0)
+
+	type args struct {
+		userID int
+		name   string
+	}
+
+	type mockBehavior func(input args)
+
+	testCases := []struct {
+		name         string
+		input        args
+		mockBehavior mockBehavior
+		expectedID   int
+		wantErr      bool
+	}{
+		{
+			name:  "OK",
+			input: args{userID: 1, name: "team"},
+			mockBehavior: func(input args) {
+				rows := mock.NewRows([]string{"workspace_id"}).AddRow(2)
+
+				query := fmt.Sprintf("INSERT INTO %s (.+) INSERT INTO %s (.+) RETURNING workspace_id", workspaceTable, membersTable)
+				mock.ExpectQuery(query).WithArgs(input.name, input.userID, model.WorkspaceOwner).WillReturnRows(rows)
+			},
+			expectedID: 2,
+		},
+		{
+			name:  "Failure",
+			input: args{userID: 1, name: "team"},
+			mockBehavior: func(input args) {
+				query := fmt.Sprintf("INSERT INTO %s", workspaceTable)
+				mock.ExpectQuery(query).WithArgs(input.name, input.userID, model.WorkspaceOwner).WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.input)
+
+			got, err := repos.Create(context.Background(), tc.input.userID, tc.input.name)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedID, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWorkspacePostgres_GetAll(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewWorkspaceRepository(db, 0)
+
+	rows := mock.NewRows([]string{"id", "name", "personal", "role"}).
+		AddRow(1, model.PersonalWorkspaceName, true, model.WorkspaceOwner).
+		AddRow(2, "team", false, model.WorkspaceViewer)
+
+	query := fmt.Sprintf("SELECT (.+) FROM %s w INNER JOIN %s m ON (.+) WHERE m.user_id = (.+) ORDER BY w.id", workspaceTable, membersTable)
+	mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
+
+	got, err := repos.GetAll(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Workspace{
+		{ID: 1, Name: model.PersonalWorkspaceName, Personal: true, Role: model.WorkspaceOwner},
+		{ID: 2, Name: "team", Role: model.WorkspaceViewer},
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWorkspacePostgres_GetByID(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewWorkspaceRepository(db, 0)
+
+	type args struct {
+		userID      int
+		workspaceID int
+	}
+
+	type mockBehavior func(input args)
+
+	testCases := []struct {
+		name              string
+		input             args
+		mockBehavior      mockBehavior
+		expectedWorkspace model.Workspace
+		wantErr           error
+	}{
+		{
+			name:  "OK",
+			input: args{userID: 1, workspaceID: 2},
+			mockBehavior: func(input args) {
+				rows := mock.NewRows([]string{"id", "name", "personal", "role"}).AddRow(2, "team", false, model.WorkspaceEditor)
+
+				query := fmt.Sprintf("SELECT (.+) FROM %s w INNER JOIN %s m ON (.+) WHERE (.+)", workspaceTable, membersTable)
+				mock.ExpectQuery(query).WithArgs(input.userID, input.workspaceID).WillReturnRows(rows)
+			},
+			expectedWorkspace: model.Workspace{ID: 2, Name: "team", Role: model.WorkspaceEditor},
+		},
+		{
+			name:  "Not a member",
+			input: args{userID: 3, workspaceID: 2},
+			mockBehavior: func(input args) {
+				rows := mock.NewRows([]string{"id", "name", "personal", "role"})
+
+				query := fmt.Sprintf("SELECT (.+) FROM %s w INNER JOIN %s m ON (.+) WHERE (.+)", workspaceTable, membersTable)
+				mock.ExpectQuery(query).WithArgs(input.userID, input.workspaceID).WillReturnRows(rows)
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.input)
+
+			got, err := repos.GetByID(context.Background(), tc.input.userID, tc.input.workspaceID)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.expectedWorkspace, got)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWorkspacePostgres_GetPersonal(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewWorkspaceRepository(db, 0)
+
+	rows := mock.NewRows([]string{"id", "name", "personal", "role"}).
+		AddRow(1, model.PersonalWorkspaceName, true, model.WorkspaceOwner)
+
+	query := fmt.Sprintf("SELECT (.+) FROM %s w INNER JOIN %s m ON (.+) WHERE (.+) AND w.personal_user_id = m.user_id",
+		workspaceTable, membersTable)
+	mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
+
+	got, err := repos.GetPersonal(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.Workspace{ID: 1, Name: model.PersonalWorkspaceName, Personal: true, Role: model.WorkspaceOwner}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWorkspacePostgres_Update(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewWorkspaceRepository(db, 0)
+
+	testCases := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{
+			name:     "OK",
+			affected: 1,
+		},
+		{
+			name:    "Not found",
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			query := fmt.Sprintf("UPDATE %s SET name = (.+) WHERE id = (.+)", workspaceTable)
+			mock.ExpectExec(query).WithArgs("renamed", 2).WillReturnResult(sqlmock.NewResult(0, tc.affected))
+
+			err := repos.Update(context.Background(), 2, "renamed")
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWorkspacePostgres_Delete(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewWorkspaceRepository(db, 0)
+
+	type mockBehavior func(workspaceID int)
+
+	testCases := []struct {
+		name         string
+		workspaceID  int
+		mockBehavior mockBehavior
+		wantErr      error
+	}{
+		{
+			name:        "OK",
+			workspaceID: 2,
+			mockBehavior: func(workspaceID int) {
+				mock.ExpectBegin()
+				query1 := fmt.Sprintf("DELETE FROM %s WHERE list_id IN \\(SELECT id FROM %s WHERE workspace_id = (.+)\\)",
+					todoItemsTable, todoListsTable)
+				mock.ExpectExec(query1).WithArgs(workspaceID).WillReturnResult(sqlmock.NewResult(0, 3))
+				query2 := fmt.Sprintf("DELETE FROM %s WHERE workspace_id = (.+)", todoListsTable)
+				mock.ExpectExec(query2).WithArgs(workspaceID).WillReturnResult(sqlmock.NewResult(0, 2))
+				query3 := fmt.Sprintf("DELETE FROM %s WHERE id = (.+)", workspaceTable)
+				mock.ExpectExec(query3).WithArgs(workspaceID).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:        "Not found",
+			workspaceID: 3,
+			mockBehavior: func(workspaceID int) {
+				mock.ExpectBegin()
+				mock.ExpectExec(fmt.Sprintf("DELETE FROM %s", todoItemsTable)).WithArgs(workspaceID).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(fmt.Sprintf("DELETE FROM %s", todoListsTable)).WithArgs(workspaceID).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(fmt.Sprintf("DELETE FROM %s", workspaceTable)).WithArgs(workspaceID).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name:        "Failure",
+			workspaceID: 2,
+			mockBehavior: func(workspaceID int) {
+				mock.ExpectBegin()
+				mock.ExpectExec(fmt.Sprintf("DELETE FROM %s", todoItemsTable)).WithArgs(workspaceID).WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior(tc.workspaceID)
+
+			err := repos.Delete(context.Background(), tc.workspaceID)
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWorkspacePostgres_GetMembers(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewWorkspaceRepository(db, 0)
+
+	rows := mock.NewRows([]string{"user_id", "name", "email", "role"}).
+		AddRow(1, "owner", "owner@example.com", model.WorkspaceOwner).
+		AddRow(3, "viewer", "viewer@example.com", model.WorkspaceViewer)
+
+	query := fmt.Sprintf("SELECT (.+) FROM %s m INNER JOIN %s u ON (.+) WHERE m.workspace_id = (.+) ORDER BY m.user_id",
+		membersTable, usersTable)
+	mock.ExpectQuery(query).WithArgs(2).WillReturnRows(rows)
+
+	got, err := repos.GetMembers(context.Background(), 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.WorkspaceMember{
+		{UserID: 1, Name: "owner", Email: "owner@example.com", Role: model.WorkspaceOwner},
+		{UserID: 3, Name: "viewer", Email: "viewer@example.com", Role: model.WorkspaceViewer},
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWorkspacePostgres_SetMember(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewWorkspaceRepository(db, 0)
+
+	query := fmt.Sprintf("INSERT INTO %s (.+) VALUES (.+) ON CONFLICT (.+) DO UPDATE SET role = EXCLUDED.role", membersTable)
+	mock.ExpectExec(query).WithArgs(2, 3, model.WorkspaceEditor).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repos.SetMember(context.Background(), 2, 3, model.WorkspaceEditor)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWorkspacePostgres_DeleteMember(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repos := NewWorkspaceRepository(db, 0)
+
+	testCases := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{
+			name:     "OK",
+			affected: 1,
+		},
+		{
+			name:    "Not a member",
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			query := fmt.Sprintf("DELETE FROM %s WHERE workspace_id = (.+) AND user_id = (.+)", membersTable)
+			mock.ExpectExec(query).WithArgs(2, 3).WillReturnResult(sqlmock.NewResult(0, tc.affected))
+
+			err := repos.DeleteMember(context.Background(), 2, 3)
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,7 @@
+// Package repoerr holds the errors the storage backends return for the conditions
+// sql.ErrNoRows doesn't describe, the repository package re-exports them.
+package repoerr
+
+import "errors"
+
+var ErrFeedTokenExists = errors.New("calendar feed already exists")
@@ -1,46 +1,225 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/repository/memory"
 	"github.com/Lapp-coder/todo-app/internal/repository/postgres"
+	"github.com/Lapp-coder/todo-app/internal/repository/routing"
+	"github.com/Lapp-coder/todo-app/internal/repository/sqlite"
+	"github.com/Lapp-coder/todo-app/internal/repository/sqltx"
 	"github.com/jmoiron/sqlx"
 )
 
+var _ TxManager = (*sqltx.Manager)(nil)
+var _ TxManager = (*memory.TxManager)(nil)
+
 var _ Authorization = (*postgres.AuthRepository)(nil)
 var _ TodoList = (*postgres.TodoListRepository)(nil)
 var _ TodoItem = (*postgres.TodoItem)(nil)
+var _ Calendar = (*postgres.CalendarRepository)(nil)
+var _ CalDAV = (*postgres.CalDAVRepository)(nil)
+var _ AccessToken = (*postgres.AccessTokenRepository)(nil)
+var _ Workspace = (*postgres.WorkspaceRepository)(nil)
+
+var _ Authorization = (*sqlite.AuthRepository)(nil)
+var _ TodoList = (*sqlite.TodoListRepository)(nil)
+var _ TodoItem = (*sqlite.TodoItemRepository)(nil)
+var _ Calendar = (*sqlite.CalendarRepository)(nil)
+var _ CalDAV = (*sqlite.CalDAVRepository)(nil)
+var _ AccessToken = (*sqlite.AccessTokenRepository)(nil)
+var _ Workspace = (*sqlite.WorkspaceRepository)(nil)
+
+var _ Authorization = (*memory.AuthRepository)(nil)
+var _ TodoList = (*memory.TodoListRepository)(nil)
+var _ TodoItem = (*memory.TodoItemRepository)(nil)
+var _ Calendar = (*memory.CalendarRepository)(nil)
+var _ CalDAV = (*memory.CalDAVRepository)(nil)
+var _ AccessToken = (*memory.AccessTokenRepository)(nil)
+var _ Workspace = (*memory.WorkspaceRepository)(nil)
+
+// TxManager runs several repository calls in one transaction. The repositories
+// called with the context passed to fn take part in it, nested calls use savepoints.
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
 
 type Authorization interface {
-	CreateUser(user model.User) (int, error)
-	GetUser(email string) (model.User, error)
+	// CreateUser creates the user together with their personal workspace.
+	CreateUser(ctx context.Context, user model.User) (int, error)
+	GetUser(ctx context.Context, email string) (model.User, error)
+	GetUserByID(ctx context.Context, userID int) (model.User, error)
+	// GetUserByIdentity returns the user linked to the account at the identity provider.
+	GetUserByIdentity(ctx context.Context, issuer, subject string) (model.User, error)
+	LinkIdentity(ctx context.Context, identity model.Identity) error
+	// UpdateProfile sets the name, the email, whether it's verified, the pending email, the timezone
+	// and the locale of the user.
+	UpdateProfile(ctx context.Context, user model.User) error
+	// DeleteUser deletes the user with their personal workspace, its lists and items, and the rest
+	// of their data in one transaction. The lists the user created in the other workspaces are
+	// handed to an owner of the workspace, the workspaces must have another owner.
+	DeleteUser(ctx context.Context, userID int) error
+	// UpdatePassword sets the password hash and signs out all the sessions of the user.
+	UpdatePassword(ctx context.Context, userID int, passwordHash string) error
+	CreatePasswordReset(ctx context.Context, reset model.PasswordReset) error
+	// TakePasswordReset returns and deletes the reset with the token hash.
+	TakePasswordReset(ctx context.Context, tokenHash string) (model.PasswordReset, error)
+	// VerifyEmail marks the email of the user as verified, unless the user has changed it since.
+	VerifyEmail(ctx context.Context, userID int, email string) error
+	// ConfirmEmail replaces the email of the user with the verified pending one and drops their
+	// reset tokens, unless the user has changed the pending email since.
+	ConfirmEmail(ctx context.Context, userID int, email string) error
+	// RecordFailedLogin counts a failed sign-in of the user and returns the failures so far.
+	RecordFailedLogin(ctx context.Context, userID int) (int, error)
+	// LockUser locks the user until the time, counts the lockout and resets the failures.
+	LockUser(ctx context.Context, userID int, until time.Time) error
+	ResetLockout(ctx context.Context, userID int) error
+	// GetLockouts returns the users with failed sign-ins or lockouts.
+	GetLockouts(ctx context.Context) ([]model.Lockout, error)
+	// GetUsers returns a page of the users whose name or email contains the query, ignoring the case.
+	GetUsers(ctx context.Context, search model.SearchUsers) ([]model.UserSummary, error)
+	// SetRole sets the role of the user and signs out their sessions, which carry the old role.
+	SetRole(ctx context.Context, userID int, role string) error
+	// SetDisabled disables or enables the user and signs out their sessions.
+	SetDisabled(ctx context.Context, userID int, disabled bool) error
+	// RevokeSessions signs out all the sessions of the user.
+	RevokeSessions(ctx context.Context, userID int) error
+	GetStats(ctx context.Context) (model.Stats, error)
+	// SetTOTPSecret sets the secret of the user, unless the two-factor authentication is enabled.
+	SetTOTPSecret(ctx context.Context, userID int, secret string) error
+	// EnableTOTP enables the two-factor authentication with the secret set, accepting the step
+	// of the code that confirmed it, and replaces the recovery codes of the user.
+	EnableTOTP(ctx context.Context, userID int, step int64, codeHashes []string) error
+	// UseTOTPStep accepts the step of a code, unless a code of the step or a later one was accepted.
+	UseTOTPStep(ctx context.Context, userID int, step int64) error
+	// UseRecoveryCode deletes the recovery code of the user.
+	UseRecoveryCode(ctx context.Context, userID int, codeHash string) error
 }
 
+// Workspace keeps the workspaces and their members, the methods taking the user id only
+// reach the workspaces the user is a member of.
+type Workspace interface {
+	// Create creates the workspace with the user as its owner.
+	Create(ctx context.Context, userID int, name string) (int, error)
+	GetAll(ctx context.Context, userID int) ([]model.Workspace, error)
+	GetByID(ctx context.Context, userID, workspaceID int) (model.Workspace, error)
+	GetPersonal(ctx context.Context, userID int) (model.Workspace, error)
+	Update(ctx context.Context, workspaceID int, name string) error
+	// Delete deletes the workspace with its members, lists and items in one transaction.
+	Delete(ctx context.Context, workspaceID int) error
+	GetMembers(ctx context.Context, workspaceID int) ([]model.WorkspaceMember, error)
+	// SetMember adds the user to the workspace, or changes their role if they are a member.
+	SetMember(ctx context.Context, workspaceID, userID int, role string) error
+	DeleteMember(ctx context.Context, workspaceID, userID int) error
+}
+
+// TodoList keeps the lists, the methods taking the workspace id only reach the lists of the workspace.
 type TodoList interface {
-	Create(userID int, list model.TodoList) (int, error)
-	GetAll(userID int) ([]model.TodoList, error)
-	GetByID(userID, listID int) (model.TodoList, error)
-	Update(listID int, update model.UpdateTodoList) error
-	Delete(listID int) error
+	Create(ctx context.Context, workspaceID, userID int, list model.TodoList) (int, error)
+	GetAll(ctx context.Context, workspaceID int) ([]model.TodoList, error)
+	GetByID(ctx context.Context, workspaceID, listID int) (model.TodoList, error)
+	Update(ctx context.Context, workspaceID, listID int, update model.UpdateTodoList) error
+	Delete(ctx context.Context, listID int) error
 }
 
+// TodoItem keeps the items, the methods taking the workspace id only reach the items
+// of the lists of the workspace.
 type TodoItem interface {
-	Create(listID int, item model.TodoItem) (int, error)
-	GetAll(listID int) ([]model.TodoItem, error)
-	GetByID(userID, itemID int) (model.TodoItem, error)
-	Update(itemID int, update model.UpdateTodoItem) error
-	Delete(itemID int) error
+	Create(ctx context.Context, listID int, item model.TodoItem) (int, error)
+	GetAll(ctx context.Context, listID int) ([]model.TodoItem, error)
+	GetAllByLists(ctx context.Context, workspaceID int, listIDs []int) ([]model.TodoItem, error)
+	GetAllByWorkspace(ctx context.Context, workspaceID int) ([]model.TodoItem, error)
+	GetByID(ctx context.Context, workspaceID, itemID int) (model.TodoItem, error)
+	Update(ctx context.Context, itemID int, update model.UpdateTodoItem) error
+	Delete(ctx context.Context, itemID int) error
+}
+
+// Calendar keeps the feed tokens hashed, the tokens themselves are only shown to the user.
+type Calendar interface {
+	// CreateFeedToken creates the feed of the user, ErrFeedTokenExists means the user has one already.
+	CreateFeedToken(ctx context.Context, userID int, tokenHash string) error
+	UpdateFeedToken(ctx context.Context, userID int, tokenHash string) error
+	// GetUserIDByFeedToken returns the owner of the feed, the feeds of the disabled users aren't found.
+	GetUserIDByFeedToken(ctx context.Context, tokenHash string) (int, error)
+	// GetAllItems returns the items of the personal workspace of the user.
+	GetAllItems(ctx context.Context, userID int) ([]model.TodoItem, error)
+}
+
+type CalDAV interface {
+	GetObjects(ctx context.Context, userID, listID int) ([]model.CalendarObject, error)
+	GetObjectByName(ctx context.Context, userID, listID int, name string) (model.CalendarObject, error)
+	SaveObject(ctx context.Context, object model.CalendarObject) error
+}
+
+// AccessToken keeps the personal access tokens, the methods taking the user id only
+// reach the tokens of the user.
+type AccessToken interface {
+	Create(ctx context.Context, token model.AccessToken) (int, error)
+	GetAll(ctx context.Context, userID int) ([]model.AccessToken, error)
+	GetByID(ctx context.Context, userID, tokenID int) (model.AccessToken, error)
+	GetByHash(ctx context.Context, tokenHash string) (model.AccessToken, error)
+	UpdateLastUsed(ctx context.Context, tokenID int, usedAt time.Time) error
+	Delete(ctx context.Context, userID, tokenID int) error
 }
 
 type Repository struct {
+	TxManager
 	Authorization
 	TodoList
 	TodoItem
+	Calendar
+	CalDAV
+	AccessToken
+	Workspace
+}
+
+// New creates the postgres repositories. The lists and the items read from the replicas
+// of the cluster, the rest of the repositories only use the primary.
+func New(cluster *postgres.Cluster, queryTimeout time.Duration) *Repository {
+	db := cluster.Primary()
+
+	return &Repository{
+		TxManager:     sqltx.NewManager(db),
+		Authorization: postgres.NewAuthRepository(db, queryTimeout),
+		TodoList:      postgres.NewTodoListRepository(cluster, queryTimeout),
+		TodoItem:      postgres.NewTodoItemRepository(cluster, queryTimeout),
+		Calendar:      postgres.NewCalendarRepository(db, queryTimeout),
+		CalDAV:        postgres.NewCalDAVRepository(db, queryTimeout),
+		AccessToken:   postgres.NewAccessTokenRepository(db, queryTimeout),
+		Workspace:     postgres.NewWorkspaceRepository(db, queryTimeout),
+	}
+}
+
+// WithUser marks the calls made with ctx as made on behalf of the user, which lets
+// the backends with read replicas give the user their own writes back.
+func WithUser(ctx context.Context, userID int) context.Context {
+	return routing.WithUser(ctx, userID)
+}
+
+func NewSQLite(db *sqlx.DB) *Repository {
+	return &Repository{
+		TxManager:     sqltx.NewManager(db),
+		Authorization: sqlite.NewAuthRepository(db),
+		TodoList:      sqlite.NewTodoListRepository(db),
+		TodoItem:      sqlite.NewTodoItemRepository(db),
+		Calendar:      sqlite.NewCalendarRepository(db),
+		CalDAV:        sqlite.NewCalDAVRepository(db),
+		AccessToken:   sqlite.NewAccessTokenRepository(db),
+		Workspace:     sqlite.NewWorkspaceRepository(db),
+	}
 }
 
-func New(db *sqlx.DB) *Repository {
+func NewMemory(store *memory.Store) *Repository {
 	return &Repository{
-		Authorization: postgres.NewAuthRepository(db),
-		TodoList:      postgres.NewTodoListRepository(db),
-		TodoItem:      postgres.NewTodoItemRepository(db),
+		TxManager:     memory.NewTxManager(store),
+		Authorization: memory.NewAuthRepository(store),
+		TodoList:      memory.NewTodoListRepository(store),
+		TodoItem:      memory.NewTodoItemRepository(store),
+		Calendar:      memory.NewCalendarRepository(store),
+		CalDAV:        memory.NewCalDAVRepository(store),
+		AccessToken:   memory.NewAccessTokenRepository(store),
+		Workspace:     memory.NewWorkspaceRepository(store),
 	}
 }
@@ -0,0 +1,20 @@
+// Package routing carries the hints the storage backends use to pick the database
+// a query is sent to.
+package routing
+
+import "context"
+
+type userKey struct{}
+
+// WithUser marks the queries made with ctx as made on behalf of the user, so the
+// backends with read replicas can send the reads of a user who has just written
+// something to the primary.
+func WithUser(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, userKey{}, userID)
+}
+
+// UserFrom returns the user set by WithUser.
+func UserFrom(ctx context.Context) (int, bool) {
+	userID, ok := ctx.Value(userKey{}).(int)
+	return userID, ok
+}
@@ -0,0 +1,552 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/metrics"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/repository/sqltx"
+	"github.com/jmoiron/sqlx"
+)
+
+type AuthRepository struct {
+	db *sqlx.DB
+}
+
+func NewAuthRepository(db *sqlx.DB) *AuthRepository {
+	return &AuthRepository{db: db}
+}
+
+func (r *AuthRepository) CreateUser(ctx context.Context, user model.User) (int, error) {
+	defer metrics.ObserveQuery("auth", "CreateUser", time.Now())
+
+	if err := sqltx.NewManager(r.db).WithinTx(ctx, func(ctx context.Context) error {
+		result, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+			"INSERT INTO %s (name, email, password_hash, verified) VALUES (?, ?, ?, ?)", usersTable),
+			user.Name, user.Email, user.Password, user.Verified)
+		if err != nil {
+			return err
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		user.ID = int(id)
+
+		var workspaceID int
+		if err = sqltx.From(ctx, r.db).QueryRowContext(ctx, fmt.Sprintf(
+			"INSERT INTO %s (name, personal_user_id) VALUES (?, ?) RETURNING id", workspaceTable),
+			model.PersonalWorkspaceName, user.ID).Scan(&workspaceID); err != nil {
+			return err
+		}
+
+		_, err = sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+			"INSERT INTO %s (workspace_id, user_id, role) VALUES (?, ?, ?)", membersTable),
+			workspaceID, user.ID, model.WorkspaceOwner)
+		return err
+	}); err != nil {
+		return 0, err
+	}
+
+	return user.ID, nil
+}
+
+// userColumns are scanned by scanUser.
+const userColumns = "id, name, email, password_hash, verified, token_version, failed_logins, lockouts, locked_until, " +
+	"totp_secret, totp_enabled, totp_last_step, timezone, locale, role, disabled, pending_email"
+
+func scanUser(row *sql.Row) (model.User, error) {
+	var user model.User
+	if err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.Verified, &user.TokenVersion,
+		&user.FailedLogins, &user.Lockouts, &user.LockedUntil, &user.TOTPSecret, &user.TOTPEnabled, &user.TOTPLastStep,
+		&user.Timezone, &user.Locale, &user.Role, &user.Disabled, &user.PendingEmail); err != nil {
+		return model.User{}, err
+	}
+
+	return user, nil
+}
+
+func (r *AuthRepository) GetUser(ctx context.Context, email string) (model.User, error) {
+	defer metrics.ObserveQuery("auth", "GetUser", time.Now())
+
+	return scanUser(sqltx.From(ctx, r.db).QueryRowContext(ctx, fmt.Sprintf(
+		"SELECT %s FROM %s WHERE email = ?", userColumns, usersTable), email))
+}
+
+func (r *AuthRepository) GetUserByID(ctx context.Context, userID int) (model.User, error) {
+	defer metrics.ObserveQuery("auth", "GetUserByID", time.Now())
+
+	return scanUser(sqltx.From(ctx, r.db).QueryRowContext(ctx, fmt.Sprintf(
+		"SELECT %s FROM %s WHERE id = ?", userColumns, usersTable), userID))
+}
+
+func (r *AuthRepository) GetUserByIdentity(ctx context.Context, issuer, subject string) (model.User, error) {
+	defer metrics.ObserveQuery("auth", "GetUserByIdentity", time.Now())
+
+	return scanUser(sqltx.From(ctx, r.db).QueryRowContext(ctx, fmt.Sprintf(
+		"SELECT %s FROM %s WHERE id = (SELECT user_id FROM %s WHERE issuer = ? AND subject = ?)",
+		userColumns, usersTable, identityTable), issuer, subject))
+}
+
+func (r *AuthRepository) LinkIdentity(ctx context.Context, identity model.Identity) error {
+	defer metrics.ObserveQuery("auth", "LinkIdentity", time.Now())
+
+	_, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+		"INSERT INTO %s (issuer, subject, user_id) VALUES (?, ?, ?)", identityTable),
+		identity.Issuer, identity.Subject, identity.UserID)
+
+	return err
+}
+
+// UpdatePassword also signs out all the sessions of the user and drops their reset tokens.
+func (r *AuthRepository) UpdatePassword(ctx context.Context, userID int, passwordHash string) error {
+	defer metrics.ObserveQuery("auth", "UpdatePassword", time.Now())
+
+	return sqltx.NewManager(r.db).WithinTx(ctx, func(ctx context.Context) error {
+		result, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+			"UPDATE %s SET password_hash = ?, token_version = token_version + 1 WHERE id = ?", usersTable),
+			passwordHash, userID)
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+
+		if _, err = sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+			"DELETE FROM %s WHERE user_id = ?", resetsTable), userID); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (r *AuthRepository) UpdateProfile(ctx context.Context, user model.User) error {
+	defer metrics.ObserveQuery("auth", "UpdateProfile", time.Now())
+
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+		"UPDATE %s SET name = ?, email = ?, verified = ?, pending_email = ?, timezone = ?, locale = ? WHERE id = ?",
+		usersTable), user.Name, user.Email, user.Verified, user.PendingEmail, user.Timezone, user.Locale, user.ID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// DeleteUser deletes the items and the lists of the personal workspace and the calendar feed
+// of the user before the user, the lists they created in the other workspaces are handed to
+// an owner of the workspace. The rest of their data is deleted by the cascades of the foreign keys.
+func (r *AuthRepository) DeleteUser(ctx context.Context, userID int) error {
+	defer metrics.ObserveQuery("auth", "DeleteUser", time.Now())
+
+	return sqltx.NewManager(r.db).WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+			`DELETE FROM %s WHERE list_id IN (SELECT tl.id FROM %s tl
+				INNER JOIN %s w ON w.id = tl.workspace_id WHERE w.personal_user_id = ?)`,
+			todoItemsTable, todoListsTable, workspaceTable), userID); err != nil {
+			return err
+		}
+
+		if _, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+			"DELETE FROM %s WHERE workspace_id IN (SELECT id FROM %s WHERE personal_user_id = ?)",
+			todoListsTable, workspaceTable), userID); err != nil {
+			return err
+		}
+
+		if _, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+			`UPDATE %s SET user_id = (SELECT m.user_id FROM %s m
+				WHERE m.workspace_id = %s.workspace_id AND m.role = ? AND m.user_id <> ? ORDER BY m.user_id LIMIT 1)
+				WHERE user_id = ?`,
+			todoListsTable, membersTable, todoListsTable), model.WorkspaceOwner, userID, userID); err != nil {
+			return err
+		}
+
+		if _, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+			"DELETE FROM %s WHERE user_id = ?", calendarsTable), userID); err != nil {
+			return err
+		}
+
+		result, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+			"DELETE FROM %s WHERE id = ?", usersTable), userID)
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+
+		return nil
+	})
+}
+
+func (r *AuthRepository) CreatePasswordReset(ctx context.Context, reset model.PasswordReset) error {
+	defer metrics.ObserveQuery("auth", "CreatePasswordReset", time.Now())
+
+	_, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+		"INSERT INTO %s (token_hash, user_id, expires_at) VALUES (?, ?, ?)", resetsTable),
+		reset.TokenHash, reset.UserID, reset.ExpiresAt)
+
+	return err
+}
+
+// TakePasswordReset deletes the reset token so it can't be used twice, expired or not.
+func (r *AuthRepository) TakePasswordReset(ctx context.Context, tokenHash string) (model.PasswordReset, error) {
+	defer metrics.ObserveQuery("auth", "TakePasswordReset", time.Now())
+
+	reset := model.PasswordReset{TokenHash: tokenHash}
+	if err := sqltx.From(ctx, r.db).QueryRowContext(ctx, fmt.Sprintf(
+		"DELETE FROM %s WHERE token_hash = ? RETURNING user_id, expires_at", resetsTable),
+		tokenHash).Scan(&reset.UserID, &reset.ExpiresAt); err != nil {
+		return model.PasswordReset{}, err
+	}
+
+	return reset, nil
+}
+
+func (r *AuthRepository) VerifyEmail(ctx context.Context, userID int, email string) error {
+	defer metrics.ObserveQuery("auth", "VerifyEmail", time.Now())
+
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+		"UPDATE %s SET verified = TRUE WHERE id = ? AND email = ?", usersTable),
+		userID, email)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// ConfirmEmail also drops the reset tokens of the user, they were mailed to the old email.
+func (r *AuthRepository) ConfirmEmail(ctx context.Context, userID int, email string) error {
+	defer metrics.ObserveQuery("auth", "ConfirmEmail", time.Now())
+
+	return sqltx.NewManager(r.db).WithinTx(ctx, func(ctx context.Context) error {
+		result, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+			"UPDATE %s SET email = pending_email, pending_email = '', verified = TRUE WHERE id = ? AND pending_email = ?",
+			usersTable), userID, email)
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+
+		if _, err = sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+			"DELETE FROM %s WHERE user_id = ?", resetsTable), userID); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (r *AuthRepository) RecordFailedLogin(ctx context.Context, userID int) (int, error) {
+	defer metrics.ObserveQuery("auth", "RecordFailedLogin", time.Now())
+
+	var failedLogins int
+	if err := sqltx.From(ctx, r.db).QueryRowContext(ctx, fmt.Sprintf(
+		"UPDATE %s SET failed_logins = failed_logins + 1 WHERE id = ? RETURNING failed_logins", usersTable),
+		userID).Scan(&failedLogins); err != nil {
+		return 0, err
+	}
+
+	return failedLogins, nil
+}
+
+func (r *AuthRepository) LockUser(ctx context.Context, userID int, until time.Time) error {
+	defer metrics.ObserveQuery("auth", "LockUser", time.Now())
+
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+		"UPDATE %s SET failed_logins = 0, lockouts = lockouts + 1, locked_until = ? WHERE id = ?", usersTable),
+		until, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) ResetLockout(ctx context.Context, userID int) error {
+	defer metrics.ObserveQuery("auth", "ResetLockout", time.Now())
+
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+		"UPDATE %s SET failed_logins = 0, lockouts = 0, locked_until = NULL WHERE id = ?", usersTable),
+		userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) GetLockouts(ctx context.Context) ([]model.Lockout, error) {
+	defer metrics.ObserveQuery("auth", "GetLockouts", time.Now())
+
+	var lockouts []model.Lockout
+	if err := sqltx.From(ctx, r.db).SelectContext(ctx, &lockouts, fmt.Sprintf(
+		"SELECT id AS user_id, email, failed_logins, lockouts, locked_until FROM %s WHERE failed_logins > 0 OR lockouts > 0 ORDER BY id",
+		usersTable)); err != nil {
+		return nil, err
+	}
+
+	return lockouts, nil
+}
+
+func (r *AuthRepository) SetTOTPSecret(ctx context.Context, userID int, secret string) error {
+	defer metrics.ObserveQuery("auth", "SetTOTPSecret", time.Now())
+
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+		"UPDATE %s SET totp_secret = ? WHERE id = ? AND NOT totp_enabled", usersTable),
+		secret, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) EnableTOTP(ctx context.Context, userID int, step int64, codeHashes []string) error {
+	defer metrics.ObserveQuery("auth", "EnableTOTP", time.Now())
+
+	return sqltx.NewManager(r.db).WithinTx(ctx, func(ctx context.Context) error {
+		result, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+			"UPDATE %s SET totp_enabled = TRUE, totp_last_step = ? WHERE id = ? AND NOT totp_enabled AND totp_secret <> ''",
+			usersTable), step, userID)
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+
+		if _, err = sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+			"DELETE FROM %s WHERE user_id = ?", recoveryTable), userID); err != nil {
+			return err
+		}
+
+		for _, hash := range codeHashes {
+			if _, err = sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+				"INSERT INTO %s (user_id, code_hash) VALUES (?, ?)", recoveryTable), userID, hash); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *AuthRepository) UseTOTPStep(ctx context.Context, userID int, step int64) error {
+	defer metrics.ObserveQuery("auth", "UseTOTPStep", time.Now())
+
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+		"UPDATE %s SET totp_last_step = ? WHERE id = ? AND totp_last_step < ?", usersTable),
+		step, userID, step)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) UseRecoveryCode(ctx context.Context, userID int, codeHash string) error {
+	defer metrics.ObserveQuery("auth", "UseRecoveryCode", time.Now())
+
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+		"DELETE FROM %s WHERE user_id = ? AND code_hash = ?", recoveryTable),
+		userID, codeHash)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) GetUsers(ctx context.Context, search model.SearchUsers) ([]model.UserSummary, error) {
+	defer metrics.ObserveQuery("auth", "GetUsers", time.Now())
+
+	users := make([]model.UserSummary, 0)
+	if err := sqltx.From(ctx, r.db).SelectContext(ctx, &users, fmt.Sprintf(
+		"SELECT id, name, email, role, verified, totp_enabled, disabled FROM %s "+
+			"WHERE instr(lower(name), lower(?)) > 0 OR instr(lower(email), lower(?)) > 0 ORDER BY id LIMIT ? OFFSET ?",
+		usersTable), search.Query, search.Query, search.Limit, search.Offset); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *AuthRepository) SetRole(ctx context.Context, userID int, role string) error {
+	defer metrics.ObserveQuery("auth", "SetRole", time.Now())
+
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+		"UPDATE %s SET role = ?, token_version = token_version + 1 WHERE id = ?", usersTable),
+		role, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) SetDisabled(ctx context.Context, userID int, disabled bool) error {
+	defer metrics.ObserveQuery("auth", "SetDisabled", time.Now())
+
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+		"UPDATE %s SET disabled = ?, token_version = token_version + 1 WHERE id = ?", usersTable),
+		disabled, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) RevokeSessions(ctx context.Context, userID int) error {
+	defer metrics.ObserveQuery("auth", "RevokeSessions", time.Now())
+
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+		"UPDATE %s SET token_version = token_version + 1 WHERE id = ?", usersTable),
+		userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) GetStats(ctx context.Context) (model.Stats, error) {
+	defer metrics.ObserveQuery("auth", "GetStats", time.Now())
+
+	var stats model.Stats
+	if err := sqltx.From(ctx, r.db).GetContext(ctx, &stats, fmt.Sprintf(
+		"SELECT COUNT(*) AS users, COUNT(*) FILTER (WHERE role = ?) AS admins, COUNT(*) FILTER (WHERE disabled) AS disabled_users, "+
+			"COUNT(*) FILTER (WHERE verified) AS verified_users, COUNT(*) FILTER (WHERE totp_enabled) AS two_factor_users, "+
+			"(SELECT COUNT(*) FROM %s) AS lists, (SELECT COUNT(*) FROM %s) AS items, "+
+			"(SELECT COUNT(*) FROM %s WHERE done) AS done_items, (SELECT COUNT(*) FROM %s) AS access_tokens FROM %s",
+		todoListsTable, todoItemsTable, todoItemsTable, tokensTable, usersTable), model.RoleAdmin); err != nil {
+		return model.Stats{}, err
+	}
+
+	return stats, nil
+}
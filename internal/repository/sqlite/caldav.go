@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/metrics"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/repository/sqltx"
+	"github.com/jmoiron/sqlx"
+)
+
+type CalDAVRepository struct {
+	db *sqlx.DB
+}
+
+func NewCalDAVRepository(db *sqlx.DB) *CalDAVRepository {
+	return &CalDAVRepository{db: db}
+}
+
+func (r *CalDAVRepository) GetObjects(ctx context.Context, userID, listID int) ([]model.CalendarObject, error) {
+	defer metrics.ObserveQuery("caldav", "GetObjects", time.Now())
+
+	var objects []model.CalendarObject
+
+	query := fmt.Sprintf(
+		`SELECT co.item_id, co.name, co.uid FROM %s co
+				INNER JOIN %s ti ON ti.id = co.item_id
+				INNER JOIN %s tl ON tl.id = ti.list_id
+				INNER JOIN %s w ON w.id = tl.workspace_id WHERE w.personal_user_id = ? AND tl.id = ? ORDER BY co.item_id`,
+		caldavTable, todoItemsTable, todoListsTable, workspaceTable)
+	if err := sqltx.From(ctx, r.db).SelectContext(ctx, &objects, query, userID, listID); err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+func (r *CalDAVRepository) GetObjectByName(ctx context.Context, userID, listID int, name string) (model.CalendarObject, error) {
+	defer metrics.ObserveQuery("caldav", "GetObjectByName", time.Now())
+
+	var object model.CalendarObject
+
+	query := fmt.Sprintf(
+		`SELECT co.item_id, co.name, co.uid FROM %s co
+				INNER JOIN %s ti ON ti.id = co.item_id
+				INNER JOIN %s tl ON tl.id = ti.list_id
+				INNER JOIN %s w ON w.id = tl.workspace_id WHERE w.personal_user_id = ? AND tl.id = ? AND co.name = ?`,
+		caldavTable, todoItemsTable, todoListsTable, workspaceTable)
+	if err := sqltx.From(ctx, r.db).GetContext(ctx, &object, query, userID, listID, name); err != nil {
+		return model.CalendarObject{}, err
+	}
+
+	return object, nil
+}
+
+func (r *CalDAVRepository) SaveObject(ctx context.Context, object model.CalendarObject) error {
+	defer metrics.ObserveQuery("caldav", "SaveObject", time.Now())
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (item_id, name, uid) VALUES (?, ?, ?)
+				ON CONFLICT (item_id) DO UPDATE SET name = excluded.name, uid = excluded.uid`, caldavTable)
+	if _, err := sqltx.From(ctx, r.db).ExecContext(ctx, query, object.ItemID, object.Name, object.UID); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/metrics"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/repository/repoerr"
+	"github.com/Lapp-coder/todo-app/internal/repository/sqltx"
+	"github.com/jmoiron/sqlx"
+)
+
+type CalendarRepository struct {
+	db *sqlx.DB
+}
+
+func NewCalendarRepository(db *sqlx.DB) *CalendarRepository {
+	return &CalendarRepository{db: db}
+}
+
+// CreateFeedToken returns repoerr.ErrFeedTokenExists when the user has a feed already.
+func (r *CalendarRepository) CreateFeedToken(ctx context.Context, userID int, tokenHash string) error {
+	defer metrics.ObserveQuery("calendar", "CreateFeedToken", time.Now())
+
+	query := fmt.Sprintf("INSERT INTO %s (user_id, token_hash) VALUES (?, ?) ON CONFLICT (user_id) DO NOTHING", calendarsTable)
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, query, userID, tokenHash)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return repoerr.ErrFeedTokenExists
+	}
+
+	return nil
+}
+
+func (r *CalendarRepository) UpdateFeedToken(ctx context.Context, userID int, tokenHash string) error {
+	defer metrics.ObserveQuery("calendar", "UpdateFeedToken", time.Now())
+
+	query := fmt.Sprintf("UPDATE %s SET token_hash = ? WHERE user_id = ?", calendarsTable)
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, query, tokenHash, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetUserIDByFeedToken skips the feeds of the disabled users.
+func (r *CalendarRepository) GetUserIDByFeedToken(ctx context.Context, tokenHash string) (int, error) {
+	defer metrics.ObserveQuery("calendar", "GetUserIDByFeedToken", time.Now())
+
+	var userID int
+
+	query := fmt.Sprintf("SELECT cf.user_id FROM %s cf WHERE cf.token_hash = ? AND cf.user_id IN (SELECT id FROM %s WHERE NOT disabled)",
+		calendarsTable, usersTable)
+	if err := sqltx.From(ctx, r.db).GetContext(ctx, &userID, query, tokenHash); err != nil {
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+func (r *CalendarRepository) GetAllItems(ctx context.Context, userID int) ([]model.TodoItem, error) {
+	defer metrics.ObserveQuery("calendar", "GetAllItems", time.Now())
+
+	var items []model.TodoItem
+
+	query := fmt.Sprintf(
+		`SELECT ti.id, ti.list_id, ti.title, ti.description, ti.completion_date, ti.done FROM %s ti
+				INNER JOIN %s tl ON tl.id = ti.list_id INNER JOIN %s w ON w.id = tl.workspace_id
+				WHERE w.personal_user_id = ? ORDER BY ti.completion_date, ti.id`,
+		todoItemsTable, todoListsTable, workspaceTable)
+	if err := sqltx.From(ctx, r.db).SelectContext(ctx, &items, query, userID); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
@@ -0,0 +1,104 @@
+CREATE TABLE IF NOT EXISTS users
+(
+    id             INTEGER      NOT NULL PRIMARY KEY AUTOINCREMENT,
+    name           VARCHAR(30)  NOT NULL,
+    email          VARCHAR(255) NOT NULL UNIQUE,
+    password_hash  VARCHAR(255) NOT NULL,
+    failed_logins  INT          NOT NULL DEFAULT 0,
+    lockouts       INT          NOT NULL DEFAULT 0,
+    locked_until   TIMESTAMP,
+    verified       BOOLEAN      NOT NULL DEFAULT FALSE,
+    token_version  INT          NOT NULL DEFAULT 0,
+    totp_secret    VARCHAR(64)  NOT NULL DEFAULT '',
+    totp_enabled   BOOLEAN      NOT NULL DEFAULT FALSE,
+    totp_last_step BIGINT       NOT NULL DEFAULT 0,
+    timezone       VARCHAR(64)  NOT NULL DEFAULT 'UTC',
+    locale         VARCHAR(35)  NOT NULL DEFAULT 'en',
+    role           VARCHAR(20)  NOT NULL DEFAULT 'user',
+    disabled       BOOLEAN      NOT NULL DEFAULT FALSE,
+    pending_email  VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS workspaces
+(
+    id               INTEGER                                     NOT NULL PRIMARY KEY AUTOINCREMENT,
+    name             VARCHAR(40)                                 NOT NULL,
+    personal_user_id INT REFERENCES users (id) ON DELETE CASCADE UNIQUE,
+    created_at       TIMESTAMP                                   NOT NULL DEFAULT (DATETIME('now', 'localtime'))
+);
+
+CREATE TABLE IF NOT EXISTS workspace_members
+(
+    workspace_id INT REFERENCES workspaces (id) ON DELETE CASCADE NOT NULL,
+    user_id      INT REFERENCES users (id) ON DELETE CASCADE      NOT NULL,
+    role         VARCHAR(20)                                      NOT NULL,
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS todo_lists
+(
+    id              INTEGER                        NOT NULL PRIMARY KEY AUTOINCREMENT,
+    user_id         INT REFERENCES users (id)      NOT NULL,
+    workspace_id    INT REFERENCES workspaces (id) NOT NULL,
+    title           VARCHAR(40)                    NOT NULL,
+    description     VARCHAR(100)                   NOT NULL DEFAULT '',
+    completion_date TIMESTAMP                      NOT NULL DEFAULT (DATETIME('now', 'localtime'))
+);
+
+CREATE TABLE IF NOT EXISTS todo_items
+(
+    id              INTEGER                        NOT NULL PRIMARY KEY AUTOINCREMENT,
+    list_id         INT REFERENCES todo_lists (id) NOT NULL,
+    title           VARCHAR(40)                    NOT NULL,
+    description     VARCHAR(100)                   NOT NULL DEFAULT '',
+    completion_date TIMESTAMP                      NOT NULL DEFAULT (DATETIME('now', 'localtime')),
+    done            BOOLEAN                        NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE IF NOT EXISTS calendar_feeds
+(
+    id         INTEGER                   NOT NULL PRIMARY KEY AUTOINCREMENT,
+    user_id    INT REFERENCES users (id) NOT NULL UNIQUE,
+    token_hash VARCHAR(64)               NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS caldav_objects
+(
+    item_id INT REFERENCES todo_items (id) ON DELETE CASCADE NOT NULL UNIQUE,
+    name    VARCHAR(255)                                    NOT NULL,
+    uid     VARCHAR(255)                                    NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS password_resets
+(
+    token_hash VARCHAR(64)                                NOT NULL PRIMARY KEY,
+    user_id    INT REFERENCES users (id) ON DELETE CASCADE NOT NULL,
+    expires_at TIMESTAMP                                  NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS recovery_codes
+(
+    user_id   INT REFERENCES users (id) ON DELETE CASCADE NOT NULL,
+    code_hash VARCHAR(64)                                 NOT NULL,
+    PRIMARY KEY (user_id, code_hash)
+);
+
+CREATE TABLE IF NOT EXISTS user_identities
+(
+    issuer  VARCHAR(255)                                NOT NULL,
+    subject VARCHAR(255)                                NOT NULL,
+    user_id INT REFERENCES users (id) ON DELETE CASCADE NOT NULL,
+    PRIMARY KEY (issuer, subject)
+);
+
+CREATE TABLE IF NOT EXISTS access_tokens
+(
+    id           INTEGER                                     NOT NULL PRIMARY KEY AUTOINCREMENT,
+    user_id      INT REFERENCES users (id) ON DELETE CASCADE NOT NULL,
+    name         VARCHAR(100)                                NOT NULL,
+    token_hash   VARCHAR(64)                                 NOT NULL UNIQUE,
+    scopes       VARCHAR(255)                                NOT NULL,
+    expires_at   TIMESTAMP,
+    last_used_at TIMESTAMP,
+    created_at   TIMESTAMP                                   NOT NULL
+);
@@ -0,0 +1,178 @@
+package sqlite
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/Lapp-coder/todo-app/internal/config"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/jmoiron/sqlx"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	usersTable     string = "users"
+	todoListsTable string = "todo_lists"
+	todoItemsTable string = "todo_items"
+	calendarsTable string = "calendar_feeds"
+	caldavTable    string = "caldav_objects"
+	resetsTable    string = "password_resets"
+	recoveryTable  string = "recovery_codes"
+	identityTable  string = "user_identities"
+	tokensTable    string = "access_tokens"
+	workspaceTable string = "workspaces"
+	membersTable   string = "workspace_members"
+)
+
+//go:embed schema.sql
+var schema string
+
+// addedColumns are the columns added to the tables after they were first created, the
+// schema only creates the missing tables, so the databases created before get them here.
+var addedColumns = []struct {
+	table      string
+	column     string
+	definition string
+}{
+	{usersTable, "failed_logins", "INT NOT NULL DEFAULT 0"},
+	{usersTable, "lockouts", "INT NOT NULL DEFAULT 0"},
+	{usersTable, "locked_until", "TIMESTAMP"},
+	// The accounts created before the verification was introduced count as verified.
+	{usersTable, "verified", "BOOLEAN NOT NULL DEFAULT TRUE"},
+	{usersTable, "token_version", "INT NOT NULL DEFAULT 0"},
+	{usersTable, "totp_secret", "VARCHAR(64) NOT NULL DEFAULT ''"},
+	{usersTable, "totp_enabled", "BOOLEAN NOT NULL DEFAULT FALSE"},
+	{usersTable, "totp_last_step", "BIGINT NOT NULL DEFAULT 0"},
+	{usersTable, "timezone", "VARCHAR(64) NOT NULL DEFAULT 'UTC'"},
+	{usersTable, "locale", "VARCHAR(35) NOT NULL DEFAULT 'en'"},
+	{usersTable, "role", "VARCHAR(20) NOT NULL DEFAULT 'user'"},
+	{usersTable, "disabled", "BOOLEAN NOT NULL DEFAULT FALSE"},
+	{usersTable, "pending_email", "VARCHAR(255) NOT NULL DEFAULT ''"},
+	// The lists of the databases created before the workspaces get one in addPersonalWorkspaces.
+	{todoListsTable, "workspace_id", "INT REFERENCES workspaces (id)"},
+}
+
+// NewDB opens the database file and creates the tables if they don't exist yet.
+// SQLite allows a single writer, so the pool is limited to one connection,
+// which also keeps ":memory:" databases alive between queries.
+func NewDB(cfg config.Storage) (*sqlx.DB, error) {
+	db, err := sqlx.Connect("sqlite", cfg.SQLitePath)
+	if err != nil {
+		return nil, err
+	}
+
+	db.SetMaxOpenConns(1)
+
+	if _, err = db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if _, err = db.Exec(schema); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if err = addColumns(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if err = addPersonalWorkspaces(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if err = hashFeedTokens(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func addColumns(db *sqlx.DB) error {
+	for _, c := range addedColumns {
+		var exists bool
+		if err := db.Get(&exists, "SELECT COUNT(*) > 0 FROM pragma_table_info(?) WHERE name = ?", c.table, c.column); err != nil {
+			return err
+		}
+
+		if exists {
+			continue
+		}
+
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.column, c.definition)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addPersonalWorkspaces creates the personal workspaces of the users created before the
+// workspaces were introduced and moves their lists there.
+func addPersonalWorkspaces(db *sqlx.DB) error {
+	queries := []string{
+		fmt.Sprintf(`INSERT INTO %s (name, personal_user_id) SELECT ?, u.id FROM %s u
+				WHERE u.id NOT IN (SELECT personal_user_id FROM %s WHERE personal_user_id IS NOT NULL)`,
+			workspaceTable, usersTable, workspaceTable),
+		fmt.Sprintf(`INSERT OR IGNORE INTO %s (workspace_id, user_id, role)
+				SELECT id, personal_user_id, ? FROM %s WHERE personal_user_id IS NOT NULL`,
+			membersTable, workspaceTable),
+		fmt.Sprintf(`UPDATE %s SET workspace_id = (SELECT w.id FROM %s w WHERE w.personal_user_id = %s.user_id)
+				WHERE workspace_id IS NULL`, todoListsTable, workspaceTable, todoListsTable),
+	}
+	args := [][]interface{}{{model.PersonalWorkspaceName}, {model.WorkspaceOwner}, nil}
+
+	for i, query := range queries {
+		if _, err := db.Exec(query, args[i]...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hashFeedTokens replaces the feed tokens of the databases created before they were hashed
+// with their SHA-256 hashes.
+func hashFeedTokens(db *sqlx.DB) error {
+	var exists bool
+	if err := db.Get(&exists, "SELECT COUNT(*) > 0 FROM pragma_table_info(?) WHERE name = 'token'", calendarsTable); err != nil {
+		return err
+	}
+
+	if !exists {
+		return nil
+	}
+
+	var feeds []struct {
+		ID    int    `db:"id"`
+		Token string `db:"token"`
+	}
+	if err := db.Select(&feeds, fmt.Sprintf("SELECT id, token FROM %s", calendarsTable)); err != nil {
+		return err
+	}
+
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec(fmt.Sprintf("ALTER TABLE %s RENAME COLUMN token TO token_hash", calendarsTable)); err != nil {
+		return err
+	}
+
+	for _, feed := range feeds {
+		sum := sha256.Sum256([]byte(feed.Token))
+		if _, err = tx.Exec(fmt.Sprintf("UPDATE %s SET token_hash = ? WHERE id = ?", calendarsTable),
+			hex.EncodeToString(sum[:]), feed.ID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
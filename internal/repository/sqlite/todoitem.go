@@ -0,0 +1,180 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/metrics"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/repository/sqltx"
+	"github.com/jmoiron/sqlx"
+)
+
+type TodoItemRepository struct {
+	db *sqlx.DB
+}
+
+func NewTodoItemRepository(db *sqlx.DB) *TodoItemRepository {
+	return &TodoItemRepository{db: db}
+}
+
+func (r *TodoItemRepository) Create(ctx context.Context, listID int, item model.TodoItem) (int, error) {
+	defer metrics.ObserveQuery("todo_item", "Create", time.Now())
+
+	fields := []string{"list_id"}
+	values := []interface{}{listID}
+
+	if item.Title != "" {
+		fields = append(fields, "title")
+		values = append(values, item.Title)
+	}
+
+	if item.Description != "" {
+		fields = append(fields, "description")
+		values = append(values, item.Description)
+	}
+
+	if item.CompletionDate != "" {
+		fields = append(fields, "completion_date")
+		values = append(values, item.CompletionDate)
+	}
+
+	if item.Done {
+		fields = append(fields, "done")
+		values = append(values, item.Done)
+	}
+
+	query := fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (%s)",
+		todoItemsTable, strings.Join(fields, ","), placeholders(len(fields)),
+	)
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, query, values...)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+func (r *TodoItemRepository) GetAll(ctx context.Context, listID int) ([]model.TodoItem, error) {
+	defer metrics.ObserveQuery("todo_item", "GetAll", time.Now())
+
+	var items []model.TodoItem
+
+	query := fmt.Sprintf(
+		`SELECT ti.id, ti.list_id, ti.title, ti.description, ti.completion_date, ti.done FROM %s ti
+			WHERE ti.list_id = ? ORDER BY ti.id`, todoItemsTable)
+	if err := sqltx.From(ctx, r.db).SelectContext(ctx, &items, query, listID); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *TodoItemRepository) GetAllByLists(ctx context.Context, workspaceID int, listIDs []int) ([]model.TodoItem, error) {
+	defer metrics.ObserveQuery("todo_item", "GetAllByLists", time.Now())
+
+	var items []model.TodoItem
+	if len(listIDs) == 0 {
+		return items, nil
+	}
+
+	query, args, err := sqlx.In(fmt.Sprintf(
+		`SELECT ti.id, ti.list_id, ti.title, ti.description, ti.completion_date, ti.done FROM %s ti
+				INNER JOIN %s tl ON tl.id = ti.list_id WHERE tl.workspace_id = ? AND ti.list_id IN (?)
+				ORDER BY ti.list_id, ti.id`, todoItemsTable, todoListsTable), workspaceID, listIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = sqltx.From(ctx, r.db).SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *TodoItemRepository) GetAllByWorkspace(ctx context.Context, workspaceID int) ([]model.TodoItem, error) {
+	defer metrics.ObserveQuery("todo_item", "GetAllByWorkspace", time.Now())
+
+	var items []model.TodoItem
+
+	query := fmt.Sprintf(
+		`SELECT ti.id, ti.list_id, ti.title, ti.description, ti.completion_date, ti.done FROM %s ti
+				INNER JOIN %s tl ON tl.id = ti.list_id WHERE tl.workspace_id = ?
+				ORDER BY ti.list_id, ti.id`, todoItemsTable, todoListsTable)
+	if err := sqltx.From(ctx, r.db).SelectContext(ctx, &items, query, workspaceID); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *TodoItemRepository) GetByID(ctx context.Context, workspaceID, itemID int) (model.TodoItem, error) {
+	defer metrics.ObserveQuery("todo_item", "GetByID", time.Now())
+
+	var item model.TodoItem
+
+	query := fmt.Sprintf(
+		`SELECT ti.id, ti.list_id, ti.title, ti.description, ti.completion_date, ti.done FROM %s ti
+				INNER JOIN %s tl ON tl.id = ti.list_id WHERE tl.workspace_id = ? AND ti.id = ?`, todoItemsTable, todoListsTable)
+	if err := sqltx.From(ctx, r.db).GetContext(ctx, &item, query, workspaceID, itemID); err != nil {
+		return model.TodoItem{}, err
+	}
+
+	return item, nil
+}
+
+func (r *TodoItemRepository) Update(ctx context.Context, itemID int, update model.UpdateTodoItem) error {
+	defer metrics.ObserveQuery("todo_item", "Update", time.Now())
+
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+
+	if update.Title != nil {
+		setValues = append(setValues, "title=?")
+		args = append(args, *update.Title)
+	}
+
+	if update.Description != nil {
+		setValues = append(setValues, "description=?")
+		args = append(args, *update.Description)
+	}
+
+	if update.CompletionDate != nil {
+		setValues = append(setValues, "completion_date=?")
+		args = append(args, *update.CompletionDate)
+	}
+
+	if update.Done != nil {
+		setValues = append(setValues, "done=?")
+		args = append(args, *update.Done)
+	}
+
+	args = append(args, itemID)
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = ?", todoItemsTable, strings.Join(setValues, ", "))
+	if _, err := sqltx.From(ctx, r.db).ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *TodoItemRepository) Delete(ctx context.Context, itemID int) error {
+	defer metrics.ObserveQuery("todo_item", "Delete", time.Now())
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = ?", todoItemsTable)
+	if _, err := sqltx.From(ctx, r.db).ExecContext(ctx, query, itemID); err != nil {
+		return err
+	}
+
+	return nil
+}
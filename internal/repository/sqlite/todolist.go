@@ -0,0 +1,142 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/metrics"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/repository/sqltx"
+	"github.com/jmoiron/sqlx"
+)
+
+type TodoListRepository struct {
+	db *sqlx.DB
+}
+
+func NewTodoListRepository(db *sqlx.DB) *TodoListRepository {
+	return &TodoListRepository{db: db}
+}
+
+func (r *TodoListRepository) Create(ctx context.Context, workspaceID, userID int, list model.TodoList) (int, error) {
+	defer metrics.ObserveQuery("todo_list", "Create", time.Now())
+
+	fields := []string{"user_id", "workspace_id"}
+	values := []interface{}{userID, workspaceID}
+
+	if list.Title != "" {
+		fields = append(fields, "title")
+		values = append(values, list.Title)
+	}
+
+	if list.Description != "" {
+		fields = append(fields, "description")
+		values = append(values, list.Description)
+	}
+
+	if list.CompletionDate != "" {
+		fields = append(fields, "completion_date")
+		values = append(values, list.CompletionDate)
+	}
+
+	query := fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (%s)",
+		todoListsTable, strings.Join(fields, ","), placeholders(len(fields)),
+	)
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, query, values...)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+func (r *TodoListRepository) GetAll(ctx context.Context, workspaceID int) ([]model.TodoList, error) {
+	defer metrics.ObserveQuery("todo_list", "GetAll", time.Now())
+
+	var lists []model.TodoList
+
+	query := fmt.Sprintf(
+		`SELECT tl.id, tl.user_id, tl.workspace_id, tl.title, tl.description, tl.completion_date FROM %s tl
+				WHERE tl.workspace_id = ? ORDER BY tl.id`, todoListsTable)
+	if err := sqltx.From(ctx, r.db).SelectContext(ctx, &lists, query, workspaceID); err != nil {
+		return nil, err
+	}
+
+	return lists, nil
+}
+
+func (r *TodoListRepository) GetByID(ctx context.Context, workspaceID, listID int) (model.TodoList, error) {
+	defer metrics.ObserveQuery("todo_list", "GetByID", time.Now())
+
+	var list model.TodoList
+
+	query := fmt.Sprintf(
+		`SELECT tl.id, tl.user_id, tl.workspace_id, tl.title, tl.description, tl.completion_date FROM %s tl
+				WHERE tl.workspace_id = ? AND tl.id = ?`, todoListsTable)
+	if err := sqltx.From(ctx, r.db).GetContext(ctx, &list, query, workspaceID, listID); err != nil {
+		return list, err
+	}
+
+	return list, nil
+}
+
+func (r *TodoListRepository) Update(ctx context.Context, workspaceID, listID int, update model.UpdateTodoList) error {
+	defer metrics.ObserveQuery("todo_list", "Update", time.Now())
+
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+
+	if update.Title != nil {
+		setValues = append(setValues, "title=?")
+		args = append(args, *update.Title)
+	}
+
+	if update.Description != nil {
+		setValues = append(setValues, "description=?")
+		args = append(args, *update.Description)
+	}
+
+	if update.CompletionDate != nil {
+		setValues = append(setValues, "completion_date=?")
+		args = append(args, *update.CompletionDate)
+	}
+
+	args = append(args, listID, workspaceID)
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = ? AND workspace_id = ?", todoListsTable, strings.Join(setValues, ", "))
+	if _, err := sqltx.From(ctx, r.db).ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *TodoListRepository) Delete(ctx context.Context, listID int) error {
+	defer metrics.ObserveQuery("todo_list", "Delete", time.Now())
+
+	return sqltx.NewManager(r.db).WithinTx(ctx, func(ctx context.Context) error {
+		query1 := fmt.Sprintf("DELETE FROM %s WHERE list_id = ?", todoItemsTable)
+		if _, err := sqltx.From(ctx, r.db).ExecContext(ctx, query1, listID); err != nil {
+			return err
+		}
+
+		query2 := fmt.Sprintf("DELETE FROM %s WHERE id = ?", todoListsTable)
+		if _, err := sqltx.From(ctx, r.db).ExecContext(ctx, query2, listID); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
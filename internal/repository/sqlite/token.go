@@ -0,0 +1,121 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/metrics"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/repository/sqltx"
+	"github.com/jmoiron/sqlx"
+)
+
+const tokenColumns = "id, user_id, name, token_hash, scopes, expires_at, last_used_at, created_at"
+
+type AccessTokenRepository struct {
+	db *sqlx.DB
+}
+
+func NewAccessTokenRepository(db *sqlx.DB) *AccessTokenRepository {
+	return &AccessTokenRepository{db: db}
+}
+
+func (r *AccessTokenRepository) Create(ctx context.Context, token model.AccessToken) (int, error) {
+	defer metrics.ObserveQuery("token", "Create", time.Now())
+
+	var id int
+
+	query := fmt.Sprintf("INSERT INTO %s (user_id, name, token_hash, scopes, expires_at, created_at) "+
+		"VALUES (?, ?, ?, ?, ?, ?) RETURNING id", tokensTable)
+	if err := sqltx.From(ctx, r.db).QueryRowContext(ctx, query, token.UserID, token.Name, token.TokenHash, token.Scopes,
+		token.ExpiresAt, token.CreatedAt).Scan(&id); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *AccessTokenRepository) GetAll(ctx context.Context, userID int) ([]model.AccessToken, error) {
+	defer metrics.ObserveQuery("token", "GetAll", time.Now())
+
+	var tokens []model.AccessToken
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE user_id = ? ORDER BY id", tokenColumns, tokensTable)
+	if err := sqltx.From(ctx, r.db).SelectContext(ctx, &tokens, query, userID); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (r *AccessTokenRepository) GetByID(ctx context.Context, userID, tokenID int) (model.AccessToken, error) {
+	defer metrics.ObserveQuery("token", "GetByID", time.Now())
+
+	var token model.AccessToken
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = ? AND user_id = ?", tokenColumns, tokensTable)
+	if err := sqltx.From(ctx, r.db).GetContext(ctx, &token, query, tokenID, userID); err != nil {
+		return model.AccessToken{}, err
+	}
+
+	return token, nil
+}
+
+// GetByHash skips the tokens of the disabled users.
+func (r *AccessTokenRepository) GetByHash(ctx context.Context, tokenHash string) (model.AccessToken, error) {
+	defer metrics.ObserveQuery("token", "GetByHash", time.Now())
+
+	var token model.AccessToken
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE token_hash = ? AND user_id IN (SELECT id FROM %s WHERE NOT disabled)",
+		tokenColumns, tokensTable, usersTable)
+	if err := sqltx.From(ctx, r.db).GetContext(ctx, &token, query, tokenHash); err != nil {
+		return model.AccessToken{}, err
+	}
+
+	return token, nil
+}
+
+func (r *AccessTokenRepository) UpdateLastUsed(ctx context.Context, tokenID int, usedAt time.Time) error {
+	defer metrics.ObserveQuery("token", "UpdateLastUsed", time.Now())
+
+	query := fmt.Sprintf("UPDATE %s SET last_used_at = ? WHERE id = ?", tokensTable)
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, query, usedAt, tokenID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *AccessTokenRepository) Delete(ctx context.Context, userID, tokenID int) error {
+	defer metrics.ObserveQuery("token", "Delete", time.Now())
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = ? AND user_id = ?", tokensTable)
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, query, tokenID, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/metrics"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/repository/sqltx"
+	"github.com/jmoiron/sqlx"
+)
+
+// workspaceColumns are selected from the workspaces joined with the membership of the user.
+const workspaceColumns = "w.id, w.name, w.personal_user_id IS NOT NULL AS personal, m.role"
+
+type WorkspaceRepository struct {
+	db *sqlx.DB
+}
+
+func NewWorkspaceRepository(db *sqlx.DB) *WorkspaceRepository {
+	return &WorkspaceRepository{db: db}
+}
+
+func (r *WorkspaceRepository) Create(ctx context.Context, userID int, name string) (int, error) {
+	defer metrics.ObserveQuery("workspace", "Create", time.Now())
+
+	var id int
+
+	if err := sqltx.NewManager(r.db).WithinTx(ctx, func(ctx context.Context) error {
+		if err := sqltx.From(ctx, r.db).QueryRowContext(ctx, fmt.Sprintf(
+			"INSERT INTO %s (name) VALUES (?) RETURNING id", workspaceTable), name).Scan(&id); err != nil {
+			return err
+		}
+
+		_, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+			"INSERT INTO %s (workspace_id, user_id, role) VALUES (?, ?, ?)", membersTable),
+			id, userID, model.WorkspaceOwner)
+		return err
+	}); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *WorkspaceRepository) GetAll(ctx context.Context, userID int) ([]model.Workspace, error) {
+	defer metrics.ObserveQuery("workspace", "GetAll", time.Now())
+
+	var workspaces []model.Workspace
+
+	query := fmt.Sprintf(`SELECT %s FROM %s w INNER JOIN %s m ON m.workspace_id = w.id
+			WHERE m.user_id = ? ORDER BY w.id`, workspaceColumns, workspaceTable, membersTable)
+	if err := sqltx.From(ctx, r.db).SelectContext(ctx, &workspaces, query, userID); err != nil {
+		return nil, err
+	}
+
+	return workspaces, nil
+}
+
+func (r *WorkspaceRepository) GetByID(ctx context.Context, userID, workspaceID int) (model.Workspace, error) {
+	defer metrics.ObserveQuery("workspace", "GetByID", time.Now())
+
+	var workspace model.Workspace
+
+	query := fmt.Sprintf(`SELECT %s FROM %s w INNER JOIN %s m ON m.workspace_id = w.id
+			WHERE m.user_id = ? AND w.id = ?`, workspaceColumns, workspaceTable, membersTable)
+	if err := sqltx.From(ctx, r.db).GetContext(ctx, &workspace, query, userID, workspaceID); err != nil {
+		return model.Workspace{}, err
+	}
+
+	return workspace, nil
+}
+
+func (r *WorkspaceRepository) GetPersonal(ctx context.Context, userID int) (model.Workspace, error) {
+	defer metrics.ObserveQuery("workspace", "GetPersonal", time.Now())
+
+	var workspace model.Workspace
+
+	query := fmt.Sprintf(`SELECT %s FROM %s w INNER JOIN %s m ON m.workspace_id = w.id
+			WHERE m.user_id = ? AND w.personal_user_id = m.user_id`, workspaceColumns, workspaceTable, membersTable)
+	if err := sqltx.From(ctx, r.db).GetContext(ctx, &workspace, query, userID); err != nil {
+		return model.Workspace{}, err
+	}
+
+	return workspace, nil
+}
+
+func (r *WorkspaceRepository) Update(ctx context.Context, workspaceID int, name string) error {
+	defer metrics.ObserveQuery("workspace", "Update", time.Now())
+
+	query := fmt.Sprintf("UPDATE %s SET name = ? WHERE id = ?", workspaceTable)
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, query, name, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *WorkspaceRepository) Delete(ctx context.Context, workspaceID int) error {
+	defer metrics.ObserveQuery("workspace", "Delete", time.Now())
+
+	return sqltx.NewManager(r.db).WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+			"DELETE FROM %s WHERE list_id IN (SELECT id FROM %s WHERE workspace_id = ?)", todoItemsTable, todoListsTable),
+			workspaceID); err != nil {
+			return err
+		}
+
+		if _, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+			"DELETE FROM %s WHERE workspace_id = ?", todoListsTable), workspaceID); err != nil {
+			return err
+		}
+
+		result, err := sqltx.From(ctx, r.db).ExecContext(ctx, fmt.Sprintf(
+			"DELETE FROM %s WHERE id = ?", workspaceTable), workspaceID)
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+
+		return nil
+	})
+}
+
+func (r *WorkspaceRepository) GetMembers(ctx context.Context, workspaceID int) ([]model.WorkspaceMember, error) {
+	defer metrics.ObserveQuery("workspace", "GetMembers", time.Now())
+
+	var members []model.WorkspaceMember
+
+	query := fmt.Sprintf(`SELECT m.user_id, u.name, u.email, m.role FROM %s m INNER JOIN %s u ON u.id = m.user_id
+			WHERE m.workspace_id = ? ORDER BY m.user_id`, membersTable, usersTable)
+	if err := sqltx.From(ctx, r.db).SelectContext(ctx, &members, query, workspaceID); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (r *WorkspaceRepository) SetMember(ctx context.Context, workspaceID, userID int, role string) error {
+	defer metrics.ObserveQuery("workspace", "SetMember", time.Now())
+
+	query := fmt.Sprintf(`INSERT INTO %s (workspace_id, user_id, role) VALUES (?, ?, ?)
+			ON CONFLICT (workspace_id, user_id) DO UPDATE SET role = excluded.role`, membersTable)
+	if _, err := sqltx.From(ctx, r.db).ExecContext(ctx, query, workspaceID, userID, role); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *WorkspaceRepository) DeleteMember(ctx context.Context, workspaceID, userID int) error {
+	defer metrics.ObserveQuery("workspace", "DeleteMember", time.Now())
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE workspace_id = ? AND user_id = ?", membersTable)
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, query, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
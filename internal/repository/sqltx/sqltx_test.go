@@ -0,0 +1,158 @@
+package sqltx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_WithinTx(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	manager := NewManager(db)
+
+	errFn := errors.New("fn failed")
+
+	type mockBehavior func()
+
+	testTable := []struct {
+		name         string
+		mockBehavior mockBehavior
+		fn           func(ctx context.Context) error
+		expectedErr  error
+		wantPanic    bool
+	}{
+		{
+			name: "Commit",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO lists").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			fn: func(ctx context.Context) error {
+				_, err := From(ctx, db).ExecContext(ctx, "INSERT INTO lists")
+				return err
+			},
+		},
+		{
+			name: "Rollback on error",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO lists").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectRollback()
+			},
+			fn: func(ctx context.Context) error {
+				if _, err := From(ctx, db).ExecContext(ctx, "INSERT INTO lists"); err != nil {
+					return err
+				}
+
+				return errFn
+			},
+			expectedErr: errFn,
+		},
+		{
+			name: "Rollback on panic",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			fn: func(ctx context.Context) error {
+				panic("fn panicked")
+			},
+			wantPanic: true,
+		},
+		{
+			name: "Failed to begin",
+			mockBehavior: func() {
+				mock.ExpectBegin().WillReturnError(errFn)
+			},
+			fn: func(ctx context.Context) error {
+				return nil
+			},
+			expectedErr: errFn,
+		},
+		{
+			name: "Nested rollback to savepoint",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO lists").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO items").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			fn: func(ctx context.Context) error {
+				if _, err := From(ctx, db).ExecContext(ctx, "INSERT INTO lists"); err != nil {
+					return err
+				}
+
+				err := manager.WithinTx(ctx, func(ctx context.Context) error {
+					if _, err := From(ctx, db).ExecContext(ctx, "INSERT INTO items"); err != nil {
+						return err
+					}
+
+					return errFn
+				})
+				if !errors.Is(err, errFn) {
+					return errors.New("unexpected error of the nested transaction")
+				}
+
+				return nil
+			},
+		},
+		{
+			name: "Nested release savepoint",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO items").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("RELEASE SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			fn: func(ctx context.Context) error {
+				return manager.WithinTx(ctx, func(ctx context.Context) error {
+					_, err := From(ctx, db).ExecContext(ctx, "INSERT INTO items")
+					return err
+				})
+			},
+		},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockBehavior()
+
+			if tc.wantPanic {
+				assert.Panics(t, func() { manager.WithinTx(context.Background(), tc.fn) })
+			} else {
+				err := manager.WithinTx(context.Background(), tc.fn)
+				if tc.expectedErr != nil {
+					assert.ErrorIs(t, err, tc.expectedErr)
+				} else {
+					assert.NoError(t, err)
+				}
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFrom(t *testing.T) {
+	mockDB, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occurred when opening a connection to the stub database: %s", err.Error())
+	}
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+
+	assert.Equal(t, db, From(context.Background(), db))
+}
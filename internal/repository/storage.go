@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/config"
+	"github.com/Lapp-coder/todo-app/internal/repository/memory"
+	"github.com/Lapp-coder/todo-app/internal/repository/postgres"
+	"github.com/Lapp-coder/todo-app/internal/repository/sqlite"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
+// Database is the connection to the storage backend.
+type Database interface {
+	io.Closer
+	PingContext(ctx context.Context) error
+	// PoolStats returns the statistics of the connection pools by their names.
+	PoolStats() map[string]sql.DBStats
+}
+
+type sqliteDB struct {
+	*sqlx.DB
+}
+
+func (db sqliteDB) PoolStats() map[string]sql.DBStats {
+	return map[string]sql.DBStats{DriverSQLite: db.Stats()}
+}
+
+type memoryDB struct{}
+
+func (memoryDB) Close() error { return nil }
+
+func (memoryDB) PingContext(ctx context.Context) error { return nil }
+
+func (memoryDB) PoolStats() map[string]sql.DBStats { return nil }
+
+// Open creates the repositories of the storage backend selected by the driver,
+// the returned database has to be closed to release the connections.
+func Open(cfg config.Storage, postgresCfg config.PostgresDB) (*Repository, Database, error) {
+	switch cfg.Driver {
+	case DriverPostgres:
+		cluster, err := postgres.OpenCluster(postgresCfg)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return New(cluster, time.Duration(postgresCfg.QueryTimeout)*time.Second), cluster, nil
+	case DriverSQLite:
+		db, err := sqlite.NewDB(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return NewSQLite(db), sqliteDB{db}, nil
+	case DriverMemory:
+		return NewMemory(memory.NewStore()), memoryDB{}, nil
+	default:
+		return nil, nil, ErrUnknownDriver
+	}
+}
@@ -0,0 +1,77 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/ratelimit"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	todov1 "github.com/Lapp-coder/todo-app/pkg/api/todo/v1"
+)
+
+type authServer struct {
+	todov1.UnimplementedAuthServiceServer
+	service *service.Service
+	limiter *ratelimit.Limiter
+}
+
+func (s *authServer) SignUp(ctx context.Context, req *todov1.SignUpRequest) (*todov1.SignUpResponse, error) {
+	if !lengthBetween(req.GetName(), minNameLength, maxNameLength) || !validEmail(req.GetEmail()) || !validPassword(req.GetPassword()) {
+		return nil, invalidArgument(errInvalidInput)
+	}
+
+	if err := allow(ctx, s.limiter, ratelimit.PolicyAuthIP, clientIP(ctx)); err != nil {
+		return nil, err
+	}
+
+	user := model.User{Name: req.GetName(), Email: req.GetEmail(), Password: req.GetPassword()}
+	id, err := s.service.Authorization.CreateUser(ctx, user)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &todov1.SignUpResponse{Id: int64(id)}, nil
+}
+
+func (s *authServer) SignIn(ctx context.Context, req *todov1.SignInRequest) (*todov1.SignInResponse, error) {
+	if !validEmail(req.GetEmail()) || !validPassword(req.GetPassword()) {
+		return nil, invalidArgument(errInvalidInput)
+	}
+
+	if err := allow(ctx, s.limiter, ratelimit.PolicyAuthIP, clientIP(ctx)); err != nil {
+		return nil, err
+	}
+
+	if err := allow(ctx, s.limiter, ratelimit.PolicyAuthEmail, ratelimit.EmailKey(req.GetEmail())); err != nil {
+		return nil, err
+	}
+
+	result, err := s.service.Authorization.GenerateToken(ctx, req.GetEmail(), req.GetPassword())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &todov1.SignInResponse{Token: result.Token, ChallengeToken: result.ChallengeToken}, nil
+}
+
+func (s *authServer) CompleteSignIn(ctx context.Context, req *todov1.CompleteSignInRequest) (*todov1.CompleteSignInResponse, error) {
+	if req.GetChallengeToken() == "" || req.GetCode() == "" {
+		return nil, invalidArgument(errInvalidInput)
+	}
+
+	if err := allow(ctx, s.limiter, ratelimit.PolicyAuthIP, clientIP(ctx)); err != nil {
+		return nil, err
+	}
+
+	// The codes are short, so the guesses are limited for every challenge too.
+	if err := allow(ctx, s.limiter, ratelimit.PolicyAuthEmail, ratelimit.TokenKey(req.GetChallengeToken())); err != nil {
+		return nil, err
+	}
+
+	token, err := s.service.Authorization.CompleteSignIn(ctx, req.GetChallengeToken(), req.GetCode())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &todov1.CompleteSignInResponse{Token: token}, nil
+}
@@ -0,0 +1,79 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/ratelimit"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	mockService "github.com/Lapp-coder/todo-app/internal/service/mocks"
+	todov1 "github.com/Lapp-coder/todo-app/pkg/api/todo/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthServer_SignIn(t *testing.T) {
+	// Arrange
+	policies := map[string]ratelimit.Policy{
+		ratelimit.PolicyAuthIP:    {Requests: 3, Period: time.Minute},
+		ratelimit.PolicyAuthEmail: {Requests: 1, Period: time.Minute},
+	}
+
+	// Init dependency
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	auth := mockService.NewMockAuthorization(c)
+	auth.EXPECT().GenerateToken(gomock.Any(), "test@mail.ru", "qwerty").Return(model.SignInResult{}, service.ErrIncorrectEmailOrPassword)
+	auth.EXPECT().GenerateToken(gomock.Any(), "other@mail.ru", "qwerty").Return(model.SignInResult{Token: "token"}, nil)
+
+	server := &authServer{
+		service: &service.Service{Authorization: auth},
+		limiter: ratelimit.New(ratelimit.NewMemoryStore(), policies),
+	}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 50000}})
+
+	signIn := func(email string) codes.Code {
+		_, err := server.SignIn(ctx, &todov1.SignInRequest{Email: email, Password: "qwerty"})
+		return status.Code(err)
+	}
+
+	// Perform request & Assert
+	assert.Equal(t, codes.Unauthenticated, signIn("test@mail.ru"))
+	assert.Equal(t, codes.ResourceExhausted, signIn("TEST@mail.ru"))
+	assert.Equal(t, codes.OK, signIn("other@mail.ru"))
+	// The client IP is out of tokens as well by now.
+	assert.Equal(t, codes.ResourceExhausted, signIn("third@mail.ru"))
+}
+
+func TestAuthServer_CompleteSignIn(t *testing.T) {
+	// Arrange
+	policies := map[string]ratelimit.Policy{ratelimit.PolicyAuthEmail: {Requests: 1, Period: time.Minute}}
+
+	// Init dependency
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	auth := mockService.NewMockAuthorization(c)
+	auth.EXPECT().CompleteSignIn(gomock.Any(), "challenge", "000000").Return("", service.ErrInvalidTwoFactorCode)
+
+	server := &authServer{
+		service: &service.Service{Authorization: auth},
+		limiter: ratelimit.New(ratelimit.NewMemoryStore(), policies),
+	}
+
+	completeSignIn := func(challenge string) codes.Code {
+		_, err := server.CompleteSignIn(context.Background(), &todov1.CompleteSignInRequest{ChallengeToken: challenge, Code: "000000"})
+		return status.Code(err)
+	}
+
+	// Perform request & Assert
+	assert.Equal(t, codes.Unauthenticated, completeSignIn("challenge"))
+	assert.Equal(t, codes.ResourceExhausted, completeSignIn("challenge"))
+}
@@ -0,0 +1,48 @@
+package rpc
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/Lapp-coder/todo-app/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errInvalidInput       = errors.New("invalid input")
+	errEmptyUpdate        = errors.New("update has no fields")
+	errInvalidID          = errors.New("invalid id")
+	errInvalidWorkspaceID = errors.New("invalid workspace id")
+	errFailedToGetUserID  = errors.New("failed to get user id")
+	errEmptyAuthMetadata  = errors.New("empty authorization metadata")
+	errInvalidAuthHeader  = errors.New("invalid auth header")
+	errEmptyToken         = errors.New("token is empty")
+	errTooManyRequests    = errors.New("too many requests")
+)
+
+// toStatus maps errors returned by the services to gRPC status errors.
+func toStatus(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, service.ErrIncorrectEmailOrPassword),
+		errors.Is(err, service.ErrInvalidChallengeToken),
+		errors.Is(err, service.ErrInvalidTwoFactorCode):
+		code = codes.Unauthenticated
+	case errors.Is(err, service.ErrAccountLocked),
+		errors.Is(err, service.ErrAccountDisabled),
+		errors.Is(err, service.ErrWorkspaceReadOnly):
+		code = codes.PermissionDenied
+	case errors.Is(err, service.ErrFailedToGetListByID),
+		errors.Is(err, service.ErrFailedToGetItemByID),
+		errors.Is(err, service.ErrWorkspaceNotFound),
+		errors.Is(err, sql.ErrNoRows):
+		code = codes.NotFound
+	}
+
+	return status.Error(code, err.Error())
+}
+
+func invalidArgument(err error) error {
+	return status.Error(codes.InvalidArgument, err.Error())
+}
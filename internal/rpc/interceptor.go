@@ -0,0 +1,107 @@
+package rpc
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/Lapp-coder/todo-app/internal/logger"
+	"github.com/Lapp-coder/todo-app/internal/ratelimit"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	authorizationMetadata = "authorization"
+	workspaceMetadata     = "x-workspace-id"
+	publicServicePrefix   = "/todo.v1.AuthService/"
+	indexBearer           = 0
+	indexToken            = 1
+)
+
+type userIDKey struct{}
+
+// userAuthentication is the gRPC counterpart of the userAuthentication middleware
+// of the REST API, it's skipped for the methods of AuthService.
+func (h Handler) userAuthentication(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if strings.HasPrefix(info.FullMethod, publicServicePrefix) {
+		return handler(ctx, req)
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(authorizationMetadata)
+	if len(values) == 0 || values[0] == "" {
+		return nil, status.Error(codes.Unauthenticated, errEmptyAuthMetadata.Error())
+	}
+
+	headerParts := strings.Split(values[0], " ")
+	if len(headerParts) != 2 || headerParts[indexBearer] != "Bearer" {
+		return nil, status.Error(codes.Unauthenticated, errInvalidAuthHeader.Error())
+	}
+
+	token := headerParts[indexToken]
+	if token == "" {
+		return nil, status.Error(codes.Unauthenticated, errEmptyToken.Error())
+	}
+
+	session, err := h.service.Authorization.ParseToken(ctx, token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	ctx = logger.WithField(ctx, logger.UserIDField, session.UserID)
+
+	return handler(context.WithValue(ctx, userIDKey{}, session.UserID), req)
+}
+
+// limitByUser is the gRPC counterpart of the limitByUser middleware of the REST API, sharing
+// its buckets, the methods of AuthService are limited by themselves.
+func (h Handler) limitByUser(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	userID := getUserID(ctx)
+	if userID == 0 {
+		return handler(ctx, req)
+	}
+
+	if err := allow(ctx, h.limiter, ratelimit.PolicyAPIUser, strconv.Itoa(userID)); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// selectWorkspace is the gRPC counterpart of the selectWorkspace middleware of the REST API,
+// it scopes the lists and the items to the workspace from the x-workspace-id metadata.
+// Without it the personal workspace of the user is used.
+func (h Handler) selectWorkspace(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	userID := getUserID(ctx)
+	if userID == 0 {
+		return handler(ctx, req)
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(workspaceMetadata)
+	if len(values) == 0 {
+		return handler(ctx, req)
+	}
+
+	workspaceID, err := strconv.Atoi(values[0])
+	if err != nil || workspaceID <= 0 {
+		return nil, invalidArgument(errInvalidWorkspaceID)
+	}
+
+	workspace, err := h.service.Workspace.GetByID(ctx, userID, workspaceID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return handler(service.WithWorkspace(ctx, userID, workspace), req)
+}
+
+func getUserID(ctx context.Context) int {
+	id, _ := ctx.Value(userIDKey{}).(int)
+
+	return id
+}
@@ -0,0 +1,237 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/config"
+	"github.com/Lapp-coder/todo-app/internal/mail/mailtest"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/ratelimit"
+	"github.com/Lapp-coder/todo-app/internal/repository"
+	"github.com/Lapp-coder/todo-app/internal/repository/memory"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	mockService "github.com/Lapp-coder/todo-app/internal/service/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestHandler_userAuthentication(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockAuthorization, token string)
+
+	testTable := []struct {
+		name           string
+		method         string
+		authorization  string
+		token          string
+		mockBehavior   mockBehavior
+		expectedCode   codes.Code
+		expectedUserID int
+	}{
+		{
+			name:          "OK",
+			method:        "/todo.v1.ListService/GetAllLists",
+			authorization: "Bearer token",
+			token:         "token",
+			mockBehavior: func(s *mockService.MockAuthorization, token string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(model.Session{UserID: 1}, nil)
+			},
+			expectedCode:   codes.OK,
+			expectedUserID: 1,
+		},
+		{
+			name:           "Public method",
+			method:         "/todo.v1.AuthService/SignIn",
+			mockBehavior:   func(s *mockService.MockAuthorization, token string) {},
+			expectedCode:   codes.OK,
+			expectedUserID: 0,
+		},
+		{
+			name:         "Empty metadata",
+			method:       "/todo.v1.ListService/GetAllLists",
+			mockBehavior: func(s *mockService.MockAuthorization, token string) {},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:          "Invalid bearer",
+			method:        "/todo.v1.ListService/GetAllLists",
+			authorization: "NoBearer token",
+			mockBehavior:  func(s *mockService.MockAuthorization, token string) {},
+			expectedCode:  codes.Unauthenticated,
+		},
+		{
+			name:          "Empty token",
+			method:        "/todo.v1.ListService/GetAllLists",
+			authorization: "Bearer ",
+			mockBehavior:  func(s *mockService.MockAuthorization, token string) {},
+			expectedCode:  codes.Unauthenticated,
+		},
+		{
+			name:          "Service failure",
+			method:        "/todo.v1.ListService/GetAllLists",
+			authorization: "Bearer token",
+			token:         "token",
+			mockBehavior: func(s *mockService.MockAuthorization, token string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(model.Session{}, errors.New("failed to parse token"))
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+	}
+
+	// Act
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mockService.NewMockAuthorization(c)
+			tc.mockBehavior(auth, tc.token)
+
+			handler := New(&service.Service{Authorization: auth}, nil)
+
+			ctx := context.Background()
+			if tc.authorization != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(authorizationMetadata, tc.authorization))
+			}
+
+			// Perform request
+			var userID int
+			_, err := handler.userAuthentication(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tc.method},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					userID = getUserID(ctx)
+					return nil, nil
+				})
+
+			// Assert
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+			assert.Equal(t, tc.expectedUserID, userID)
+		})
+	}
+}
+
+func TestHandler_limitByUser(t *testing.T) {
+	// Arrange
+	policies := map[string]ratelimit.Policy{ratelimit.PolicyAPIUser: {Requests: 1, Period: time.Minute}}
+
+	// Init dependency
+	handler := New(&service.Service{}, ratelimit.New(ratelimit.NewMemoryStore(), policies))
+	info := &grpc.UnaryServerInfo{FullMethod: "/todo.v1.ListService/GetAllLists"}
+
+	call := func(userID int) codes.Code {
+		ctx := context.WithValue(context.Background(), userIDKey{}, userID)
+		_, err := handler.limitByUser(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		})
+		return status.Code(err)
+	}
+
+	// Perform request & Assert
+	assert.Equal(t, codes.OK, call(1))
+	assert.Equal(t, codes.ResourceExhausted, call(1))
+	// The users have their own buckets.
+	assert.Equal(t, codes.OK, call(2))
+	// The methods of AuthService have no user and are limited by themselves.
+	assert.Equal(t, codes.OK, call(0))
+	assert.Equal(t, codes.OK, call(0))
+}
+
+// TestHandler_selectWorkspace shares a workspace with a member and checks the lists reached
+// through the interceptor are scoped to the workspace from the metadata.
+func TestHandler_selectWorkspace(t *testing.T) {
+	// Init dependency
+	cfg := config.Service{SigningKey: "key", Salt: "salt", TokenTTL: 60}
+	services := service.New(repository.NewMemory(memory.NewStore()), cfg, nil, service.LogNotifier{}, &mailtest.Mailer{}, nil)
+	handler := New(services, nil)
+	ctx := context.Background()
+
+	createUser := func(name string) int {
+		id, err := services.Authorization.CreateUser(ctx, model.User{Name: name, Email: name + "@mail.ru", Password: "testing"})
+		require.NoError(t, err)
+		return id
+	}
+
+	owner, member, stranger := createUser("owner"), createUser("member"), createUser("stranger")
+
+	_, err := services.TodoList.Create(ctx, member, model.TodoList{Title: "personal"})
+	require.NoError(t, err)
+
+	workspaceID, err := services.Workspace.Create(ctx, owner, model.CreateWorkspace{Name: "team"})
+	require.NoError(t, err)
+	require.NoError(t, services.Workspace.AddMember(ctx, owner, workspaceID,
+		model.AddWorkspaceMember{Email: "member@mail.ru", Role: model.WorkspaceViewer}))
+
+	workspace, err := services.Workspace.GetByID(ctx, owner, workspaceID)
+	require.NoError(t, err)
+	_, err = services.TodoList.Create(service.WithWorkspace(ctx, owner, workspace), owner, model.TodoList{Title: "shared"})
+	require.NoError(t, err)
+
+	// Arrange
+	testTable := []struct {
+		name           string
+		userID         int
+		workspace      string
+		expectedCode   codes.Code
+		expectedTitles []string
+	}{
+		{
+			name:           "OK",
+			userID:         member,
+			workspace:      strconv.Itoa(workspaceID),
+			expectedCode:   codes.OK,
+			expectedTitles: []string{"shared"},
+		},
+		{
+			name:           "Personal workspace",
+			userID:         member,
+			expectedCode:   codes.OK,
+			expectedTitles: []string{"personal"},
+		},
+		{
+			name:         "Not a member",
+			userID:       stranger,
+			workspace:    strconv.Itoa(workspaceID),
+			expectedCode: codes.NotFound,
+		},
+		{
+			name:         "Invalid workspace id",
+			userID:       member,
+			workspace:    "invalid",
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	// Act
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			reqCtx := context.WithValue(ctx, userIDKey{}, tc.userID)
+			if tc.workspace != "" {
+				reqCtx = metadata.NewIncomingContext(reqCtx, metadata.Pairs(workspaceMetadata, tc.workspace))
+			}
+
+			// Perform request
+			var titles []string
+			_, err := handler.selectWorkspace(reqCtx, nil, &grpc.UnaryServerInfo{FullMethod: "/todo.v1.ListService/GetAllLists"},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					lists, err := services.TodoList.GetAll(ctx, getUserID(ctx))
+					for _, list := range lists {
+						titles = append(titles, list.Title)
+					}
+					return nil, err
+				})
+
+			// Assert
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+			assert.Equal(t, tc.expectedTitles, titles)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	todov1 "github.com/Lapp-coder/todo-app/pkg/api/todo/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type itemServer struct {
+	todov1.UnimplementedItemServiceServer
+	service *service.Service
+}
+
+func (s *itemServer) CreateItem(ctx context.Context, req *todov1.CreateItemRequest) (*todov1.CreateItemResponse, error) {
+	userID := getUserID(ctx)
+	if userID == 0 {
+		return nil, status.Error(codes.Internal, errFailedToGetUserID.Error())
+	}
+
+	if req.GetListId() <= 0 {
+		return nil, invalidArgument(errInvalidID)
+	}
+
+	if !validTitle(req.GetTitle()) || !validDescription(req.GetDescription()) {
+		return nil, invalidArgument(errInvalidInput)
+	}
+
+	item := model.TodoItem{Title: req.GetTitle(), Description: req.GetDescription(), CompletionDate: req.GetCompletionDate(), Done: req.GetDone()}
+	itemID, err := s.service.TodoItem.Create(ctx, userID, int(req.GetListId()), item)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &todov1.CreateItemResponse{Id: int64(itemID)}, nil
+}
+
+func (s *itemServer) GetAllItems(ctx context.Context, req *todov1.GetAllItemsRequest) (*todov1.GetAllItemsResponse, error) {
+	userID := getUserID(ctx)
+	if userID == 0 {
+		return nil, status.Error(codes.Internal, errFailedToGetUserID.Error())
+	}
+
+	if req.GetListId() <= 0 {
+		return nil, invalidArgument(errInvalidID)
+	}
+
+	items, err := s.service.TodoItem.GetAll(ctx, userID, int(req.GetListId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &todov1.GetAllItemsResponse{Items: make([]*todov1.TodoItem, 0, len(items))}
+	for _, item := range items {
+		resp.Items = append(resp.Items, itemToProto(item))
+	}
+
+	return resp, nil
+}
+
+func (s *itemServer) GetItem(ctx context.Context, req *todov1.GetItemRequest) (*todov1.GetItemResponse, error) {
+	userID := getUserID(ctx)
+	if userID == 0 {
+		return nil, status.Error(codes.Internal, errFailedToGetUserID.Error())
+	}
+
+	if req.GetId() <= 0 {
+		return nil, invalidArgument(errInvalidID)
+	}
+
+	item, err := s.service.TodoItem.GetByID(ctx, userID, int(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &todov1.GetItemResponse{Item: itemToProto(item)}, nil
+}
+
+func (s *itemServer) UpdateItem(ctx context.Context, req *todov1.UpdateItemRequest) (*todov1.UpdateItemResponse, error) {
+	userID := getUserID(ctx)
+	if userID == 0 {
+		return nil, status.Error(codes.Internal, errFailedToGetUserID.Error())
+	}
+
+	if req.GetId() <= 0 {
+		return nil, invalidArgument(errInvalidID)
+	}
+
+	update := model.UpdateTodoItem{Title: req.Title, Description: req.Description, CompletionDate: req.CompletionDate, Done: req.Done}
+	if update.IsNilAllFields() {
+		return nil, invalidArgument(errEmptyUpdate)
+	}
+
+	if (update.Title != nil && !validTitle(*update.Title)) || (update.Description != nil && !validDescription(*update.Description)) {
+		return nil, invalidArgument(errInvalidInput)
+	}
+
+	if err := s.service.TodoItem.Update(ctx, userID, int(req.GetId()), update); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &todov1.UpdateItemResponse{}, nil
+}
+
+func (s *itemServer) DeleteItem(ctx context.Context, req *todov1.DeleteItemRequest) (*todov1.DeleteItemResponse, error) {
+	userID := getUserID(ctx)
+	if userID == 0 {
+		return nil, status.Error(codes.Internal, errFailedToGetUserID.Error())
+	}
+
+	if req.GetId() <= 0 {
+		return nil, invalidArgument(errInvalidID)
+	}
+
+	if err := s.service.TodoItem.Delete(ctx, userID, int(req.GetId())); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &todov1.DeleteItemResponse{}, nil
+}
+
+func itemToProto(item model.TodoItem) *todov1.TodoItem {
+	return &todov1.TodoItem{
+		Id:             int64(item.ID),
+		ListId:         int64(item.ListID),
+		Title:          item.Title,
+		Description:    item.Description,
+		CompletionDate: item.CompletionDate,
+		Done:           item.Done,
+	}
+}
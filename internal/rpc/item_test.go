@@ -0,0 +1,133 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	mockService "github.com/Lapp-coder/todo-app/internal/service/mocks"
+	todov1 "github.com/Lapp-coder/todo-app/pkg/api/todo/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestItemServer_CreateItem(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockTodoItem, item model.TodoItem)
+
+	testTable := []struct {
+		name         string
+		request      *todov1.CreateItemRequest
+		item         model.TodoItem
+		mockBehavior mockBehavior
+		expectedCode codes.Code
+		expectedID   int64
+	}{
+		{
+			name:    "OK",
+			request: &todov1.CreateItemRequest{ListId: 1, Title: "test title", Done: true},
+			item:    model.TodoItem{Title: "test title", Done: true},
+			mockBehavior: func(s *mockService.MockTodoItem, item model.TodoItem) {
+				s.EXPECT().Create(gomock.Any(), 1, 1, item).Return(1, nil)
+			},
+			expectedCode: codes.OK,
+			expectedID:   1,
+		},
+		{
+			name:         "Invalid list id",
+			request:      &todov1.CreateItemRequest{Title: "test title"},
+			mockBehavior: func(s *mockService.MockTodoItem, item model.TodoItem) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Long description",
+			request:      &todov1.CreateItemRequest{ListId: 1, Title: "test title", Description: string(make([]byte, 51))},
+			mockBehavior: func(s *mockService.MockTodoItem, item model.TodoItem) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "Service failure",
+			request: &todov1.CreateItemRequest{ListId: 1, Title: "test title"},
+			item:    model.TodoItem{Title: "test title"},
+			mockBehavior: func(s *mockService.MockTodoItem, item model.TodoItem) {
+				s.EXPECT().Create(gomock.Any(), 1, 1, item).Return(0, service.ErrFailedToCreateItem)
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	// Act
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			todoItem := mockService.NewMockTodoItem(c)
+			tc.mockBehavior(todoItem, tc.item)
+
+			server := &itemServer{service: &service.Service{TodoItem: todoItem}}
+			ctx := context.WithValue(context.Background(), userIDKey{}, 1)
+
+			// Perform request
+			resp, err := server.CreateItem(ctx, tc.request)
+
+			// Assert
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+			assert.Equal(t, tc.expectedID, resp.GetId())
+		})
+	}
+}
+
+func TestItemServer_DeleteItem(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockTodoItem, itemID int)
+
+	testTable := []struct {
+		name         string
+		itemID       int64
+		mockBehavior mockBehavior
+		expectedCode codes.Code
+	}{
+		{
+			name:   "OK",
+			itemID: 1,
+			mockBehavior: func(s *mockService.MockTodoItem, itemID int) {
+				s.EXPECT().Delete(gomock.Any(), 1, itemID).Return(nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:   "Service failure",
+			itemID: 1,
+			mockBehavior: func(s *mockService.MockTodoItem, itemID int) {
+				s.EXPECT().Delete(gomock.Any(), 1, itemID).Return(service.ErrFailedToDeleteItem)
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	// Act
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			todoItem := mockService.NewMockTodoItem(c)
+			tc.mockBehavior(todoItem, int(tc.itemID))
+
+			server := &itemServer{service: &service.Service{TodoItem: todoItem}}
+			ctx := context.WithValue(context.Background(), userIDKey{}, 1)
+
+			// Perform request
+			_, err := server.DeleteItem(ctx, &todov1.DeleteItemRequest{Id: tc.itemID})
+
+			// Assert
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	todov1 "github.com/Lapp-coder/todo-app/pkg/api/todo/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type listServer struct {
+	todov1.UnimplementedListServiceServer
+	service *service.Service
+}
+
+func (s *listServer) CreateList(ctx context.Context, req *todov1.CreateListRequest) (*todov1.CreateListResponse, error) {
+	userID := getUserID(ctx)
+	if userID == 0 {
+		return nil, status.Error(codes.Internal, errFailedToGetUserID.Error())
+	}
+
+	if !validTitle(req.GetTitle()) || !validDescription(req.GetDescription()) {
+		return nil, invalidArgument(errInvalidInput)
+	}
+
+	list := model.TodoList{Title: req.GetTitle(), Description: req.GetDescription(), CompletionDate: req.GetCompletionDate()}
+	listID, err := s.service.TodoList.Create(ctx, userID, list)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &todov1.CreateListResponse{Id: int64(listID)}, nil
+}
+
+func (s *listServer) GetAllLists(ctx context.Context, req *todov1.GetAllListsRequest) (*todov1.GetAllListsResponse, error) {
+	userID := getUserID(ctx)
+	if userID == 0 {
+		return nil, status.Error(codes.Internal, errFailedToGetUserID.Error())
+	}
+
+	lists, err := s.service.TodoList.GetAll(ctx, userID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &todov1.GetAllListsResponse{Lists: make([]*todov1.TodoList, 0, len(lists))}
+	for _, list := range lists {
+		resp.Lists = append(resp.Lists, listToProto(list))
+	}
+
+	return resp, nil
+}
+
+func (s *listServer) GetList(ctx context.Context, req *todov1.GetListRequest) (*todov1.GetListResponse, error) {
+	userID := getUserID(ctx)
+	if userID == 0 {
+		return nil, status.Error(codes.Internal, errFailedToGetUserID.Error())
+	}
+
+	if req.GetId() <= 0 {
+		return nil, invalidArgument(errInvalidID)
+	}
+
+	list, err := s.service.TodoList.GetByID(ctx, userID, int(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &todov1.GetListResponse{List: listToProto(list)}, nil
+}
+
+func (s *listServer) UpdateList(ctx context.Context, req *todov1.UpdateListRequest) (*todov1.UpdateListResponse, error) {
+	userID := getUserID(ctx)
+	if userID == 0 {
+		return nil, status.Error(codes.Internal, errFailedToGetUserID.Error())
+	}
+
+	if req.GetId() <= 0 {
+		return nil, invalidArgument(errInvalidID)
+	}
+
+	update := model.UpdateTodoList{Title: req.Title, Description: req.Description, CompletionDate: req.CompletionDate}
+	if update.IsNilAllFields() {
+		return nil, invalidArgument(errEmptyUpdate)
+	}
+
+	if (update.Title != nil && !validTitle(*update.Title)) || (update.Description != nil && !validDescription(*update.Description)) {
+		return nil, invalidArgument(errInvalidInput)
+	}
+
+	if err := s.service.TodoList.Update(ctx, userID, int(req.GetId()), update); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &todov1.UpdateListResponse{}, nil
+}
+
+func (s *listServer) DeleteList(ctx context.Context, req *todov1.DeleteListRequest) (*todov1.DeleteListResponse, error) {
+	userID := getUserID(ctx)
+	if userID == 0 {
+		return nil, status.Error(codes.Internal, errFailedToGetUserID.Error())
+	}
+
+	if req.GetId() <= 0 {
+		return nil, invalidArgument(errInvalidID)
+	}
+
+	if err := s.service.TodoList.Delete(ctx, userID, int(req.GetId())); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &todov1.DeleteListResponse{}, nil
+}
+
+func listToProto(list model.TodoList) *todov1.TodoList {
+	return &todov1.TodoList{
+		Id:             int64(list.ID),
+		UserId:         int64(list.UserID),
+		Title:          list.Title,
+		Description:    list.Description,
+		CompletionDate: list.CompletionDate,
+	}
+}
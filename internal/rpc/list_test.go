@@ -0,0 +1,189 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	mockService "github.com/Lapp-coder/todo-app/internal/service/mocks"
+	todov1 "github.com/Lapp-coder/todo-app/pkg/api/todo/v1"
+	"github.com/Lapp-coder/todo-app/test"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListServer_CreateList(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockTodoList, list model.TodoList)
+
+	testTable := []struct {
+		name         string
+		request      *todov1.CreateListRequest
+		list         model.TodoList
+		mockBehavior mockBehavior
+		expectedCode codes.Code
+		expectedID   int64
+	}{
+		{
+			name:    "OK",
+			request: &todov1.CreateListRequest{Title: "test title", Description: "test description"},
+			list:    model.TodoList{Title: "test title", Description: "test description"},
+			mockBehavior: func(s *mockService.MockTodoList, list model.TodoList) {
+				s.EXPECT().Create(gomock.Any(), 1, list).Return(1, nil)
+			},
+			expectedCode: codes.OK,
+			expectedID:   1,
+		},
+		{
+			name:         "Short title",
+			request:      &todov1.CreateListRequest{Title: "ab"},
+			mockBehavior: func(s *mockService.MockTodoList, list model.TodoList) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "Service failure",
+			request: &todov1.CreateListRequest{Title: "test title"},
+			list:    model.TodoList{Title: "test title"},
+			mockBehavior: func(s *mockService.MockTodoList, list model.TodoList) {
+				s.EXPECT().Create(gomock.Any(), 1, list).Return(0, service.ErrFailedToCreateList)
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	// Act
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			todoList := mockService.NewMockTodoList(c)
+			tc.mockBehavior(todoList, tc.list)
+
+			server := &listServer{service: &service.Service{TodoList: todoList}}
+			ctx := context.WithValue(context.Background(), userIDKey{}, 1)
+
+			// Perform request
+			resp, err := server.CreateList(ctx, tc.request)
+
+			// Assert
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+			assert.Equal(t, tc.expectedID, resp.GetId())
+		})
+	}
+}
+
+func TestListServer_GetList(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockTodoList, listID int)
+
+	testTable := []struct {
+		name         string
+		listID       int64
+		mockBehavior mockBehavior
+		expectedCode codes.Code
+		expectedList *todov1.TodoList
+	}{
+		{
+			name:   "OK",
+			listID: 1,
+			mockBehavior: func(s *mockService.MockTodoList, listID int) {
+				s.EXPECT().GetByID(gomock.Any(), 1, listID).Return(model.TodoList{ID: listID, UserID: 1, Title: "test title"}, nil)
+			},
+			expectedCode: codes.OK,
+			expectedList: &todov1.TodoList{Id: 1, UserId: 1, Title: "test title"},
+		},
+		{
+			name:         "Invalid id",
+			listID:       0,
+			mockBehavior: func(s *mockService.MockTodoList, listID int) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:   "Not found",
+			listID: 2,
+			mockBehavior: func(s *mockService.MockTodoList, listID int) {
+				s.EXPECT().GetByID(gomock.Any(), 1, listID).Return(model.TodoList{}, service.ErrFailedToGetListByID)
+			},
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	// Act
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			todoList := mockService.NewMockTodoList(c)
+			tc.mockBehavior(todoList, int(tc.listID))
+
+			server := &listServer{service: &service.Service{TodoList: todoList}}
+			ctx := context.WithValue(context.Background(), userIDKey{}, 1)
+
+			// Perform request
+			resp, err := server.GetList(ctx, &todov1.GetListRequest{Id: tc.listID})
+
+			// Assert
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+			if tc.expectedList != nil {
+				assert.Equal(t, tc.expectedList.String(), resp.GetList().String())
+			}
+		})
+	}
+}
+
+func TestListServer_UpdateList(t *testing.T) {
+	// Arrange
+	type mockBehavior func(s *mockService.MockTodoList, update model.UpdateTodoList)
+
+	testTable := []struct {
+		name         string
+		request      *todov1.UpdateListRequest
+		update       model.UpdateTodoList
+		mockBehavior mockBehavior
+		expectedCode codes.Code
+	}{
+		{
+			name:    "OK",
+			request: &todov1.UpdateListRequest{Id: 1, Title: test.StringPointer("new title")},
+			update:  model.UpdateTodoList{Title: test.StringPointer("new title")},
+			mockBehavior: func(s *mockService.MockTodoList, update model.UpdateTodoList) {
+				s.EXPECT().Update(gomock.Any(), 1, 1, update).Return(nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Empty update",
+			request:      &todov1.UpdateListRequest{Id: 1},
+			mockBehavior: func(s *mockService.MockTodoList, update model.UpdateTodoList) {},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	// Act
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			// Init dependency
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			todoList := mockService.NewMockTodoList(c)
+			tc.mockBehavior(todoList, tc.update)
+
+			server := &listServer{service: &service.Service{TodoList: todoList}}
+			ctx := context.WithValue(context.Background(), userIDKey{}, 1)
+
+			// Perform request
+			_, err := server.UpdateList(ctx, tc.request)
+
+			// Assert
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+		})
+	}
+}
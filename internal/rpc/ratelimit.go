@@ -0,0 +1,55 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	"github.com/Lapp-coder/todo-app/internal/logger"
+	"github.com/Lapp-coder/todo-app/internal/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const retryAfterMetadata = "retry-after"
+
+// allow is the gRPC counterpart of the rate limiting of the REST API, sharing its buckets.
+// The exceeded limits are returned as ResourceExhausted with the retry-after header, and the
+// requests are let through when the store fails.
+func allow(ctx context.Context, limiter *ratelimit.Limiter, policy, key string) error {
+	if limiter == nil {
+		return nil
+	}
+
+	result, err := limiter.Allow(ctx, policy, key)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).WithField("policy", policy).Error("failed to check rate limit")
+		return nil
+	}
+
+	if result.Allowed {
+		return nil
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(retryAfterMetadata, strconv.Itoa(ratelimit.Seconds(result.RetryAfter))))
+
+	return status.Error(codes.ResourceExhausted, errTooManyRequests.Error())
+}
+
+// clientIP is the address of the peer without the port.
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
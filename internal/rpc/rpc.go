@@ -0,0 +1,28 @@
+package rpc
+
+import (
+	"github.com/Lapp-coder/todo-app/internal/ratelimit"
+	"github.com/Lapp-coder/todo-app/internal/service"
+	todov1 "github.com/Lapp-coder/todo-app/pkg/api/todo/v1"
+	"google.golang.org/grpc"
+)
+
+type Handler struct {
+	service *service.Service
+	// limiter is nil when the rate limiting is disabled.
+	limiter *ratelimit.Limiter
+}
+
+func New(service *service.Service, limiter *ratelimit.Limiter) *Handler {
+	return &Handler{service: service, limiter: limiter}
+}
+
+func (h Handler) InitServer() *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(h.userAuthentication, h.limitByUser, h.selectWorkspace))
+
+	todov1.RegisterAuthServiceServer(server, &authServer{service: h.service, limiter: h.limiter})
+	todov1.RegisterListServiceServer(server, &listServer{service: h.service})
+	todov1.RegisterItemServiceServer(server, &itemServer{service: h.service})
+
+	return server
+}
@@ -0,0 +1,46 @@
+package rpc
+
+import (
+	"net/mail"
+	"unicode/utf8"
+)
+
+// The limits are the same as the binding tags of the REST API requests in the model package.
+const (
+	minNameLength        = 3
+	maxNameLength        = 20
+	maxEmailLength       = 50
+	minPasswordLength    = 6
+	maxPasswordLength    = 50
+	minTitleLength       = 3
+	maxTitleLength       = 30
+	maxDescriptionLength = 50
+)
+
+func lengthBetween(s string, min, max int) bool {
+	length := utf8.RuneCountInString(s)
+
+	return length >= min && length <= max
+}
+
+func validEmail(email string) bool {
+	if !lengthBetween(email, 1, maxEmailLength) {
+		return false
+	}
+
+	_, err := mail.ParseAddress(email)
+
+	return err == nil
+}
+
+func validPassword(password string) bool {
+	return lengthBetween(password, minPasswordLength, maxPasswordLength)
+}
+
+func validTitle(title string) bool {
+	return lengthBetween(title, minTitleLength, maxTitleLength)
+}
+
+func validDescription(description string) bool {
+	return utf8.RuneCountInString(description) <= maxDescriptionLength
+}
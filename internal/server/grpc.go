@@ -0,0 +1,48 @@
+package server
+
+import (
+	"context"
+	"net"
+
+	"github.com/Lapp-coder/todo-app/internal/config"
+	"google.golang.org/grpc"
+)
+
+type GRPCServer struct {
+	grpcServer *grpc.Server
+	addr       string
+}
+
+func NewGRPCServer(cfg config.GRPCServer, server *grpc.Server) *GRPCServer {
+	return &GRPCServer{
+		grpcServer: server,
+		addr:       cfg.Host + ":" + cfg.Port,
+	}
+}
+
+func (s *GRPCServer) Run() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	return s.grpcServer.Serve(listener)
+}
+
+// Shutdown waits for the pending RPCs to finish and stops them forcibly
+// when the context is done first.
+func (s *GRPCServer) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		return ctx.Err()
+	}
+}
@@ -13,17 +13,34 @@ type Server struct {
 }
 
 func NewServer(cfg config.Server) *Server {
+	writeTimeout := time.Second * time.Duration(cfg.WriteTimeout)
+
 	return &Server{
 		httpServer: &http.Server{
 			Addr:           cfg.Host + ":" + cfg.Port,
-			Handler:        cfg.Handler,
+			Handler:        withDeadline(cfg.Handler, writeTimeout),
 			MaxHeaderBytes: cfg.MaxHeaderBytes << 20, // MB
 			ReadTimeout:    time.Second * time.Duration(cfg.ReadTimeout),
-			WriteTimeout:   time.Second * time.Duration(cfg.WriteTimeout),
+			WriteTimeout:   writeTimeout,
 		},
 	}
 }
 
+// withDeadline cancels the request context when the write timeout expires,
+// since the response can't be written after that anyway.
+func withDeadline(handler http.Handler, timeout time.Duration) http.Handler {
+	if timeout <= 0 {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 func (s *Server) Run() error {
 	return s.httpServer.ListenAndServe()
 }
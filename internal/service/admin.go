@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+)
+
+// defaultUsersLimit is the page size of the users when the admin doesn't ask for one.
+const defaultUsersLimit = 50
+
+func (s AuthService) GetUsers(ctx context.Context, search model.SearchUsers) ([]model.UserSummary, error) {
+	if search.Limit == 0 {
+		search.Limit = defaultUsersLimit
+	}
+
+	users, err := s.repos.GetUsers(ctx, search)
+	if err != nil {
+		logError(ctx, err, ErrFailedToGetUsers)
+		return nil, ErrFailedToGetUsers
+	}
+
+	return users, nil
+}
+
+// SetRole signs the user out, so the tokens carrying the old role stop working.
+func (s AuthService) SetRole(ctx context.Context, userID int, role string) error {
+	return s.updateUser(ctx, s.repos.SetRole(ctx, userID, role), ErrFailedToSetRole)
+}
+
+// DisableUser signs the user out and keeps them from signing in or using their access tokens.
+func (s AuthService) DisableUser(ctx context.Context, userID int) error {
+	return s.updateUser(ctx, s.repos.SetDisabled(ctx, userID, true), ErrFailedToDisableUser)
+}
+
+func (s AuthService) EnableUser(ctx context.Context, userID int) error {
+	return s.updateUser(ctx, s.repos.SetDisabled(ctx, userID, false), ErrFailedToEnableUser)
+}
+
+// RevokeSessions signs the user out everywhere, their access tokens keep working.
+func (s AuthService) RevokeSessions(ctx context.Context, userID int) error {
+	return s.updateUser(ctx, s.repos.RevokeSessions(ctx, userID), ErrFailedToRevokeSessions)
+}
+
+// updateUser maps the error of an update of the user to the one returned to the admin.
+func (s AuthService) updateUser(ctx context.Context, err, reason error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound
+	}
+
+	logError(ctx, err, reason)
+	return reason
+}
+
+func (s AuthService) GetStats(ctx context.Context) (model.Stats, error) {
+	stats, err := s.repos.GetStats(ctx)
+	if err != nil {
+		logError(ctx, err, ErrFailedToGetStats)
+		return model.Stats{}, ErrFailedToGetStats
+	}
+
+	return stats, nil
+}
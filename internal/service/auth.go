@@ -1,52 +1,181 @@
 package service
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"time"
 
 	"github.com/Lapp-coder/todo-app/internal/config"
+	"github.com/Lapp-coder/todo-app/internal/mail"
 	"github.com/Lapp-coder/todo-app/internal/model"
 	"github.com/Lapp-coder/todo-app/internal/repository"
 	"github.com/dgrijalva/jwt-go"
 )
 
 type AuthService struct {
-	repos repository.Authorization
-	cfg   config.Service
+	repos          repository.Authorization
+	reposWorkspace repository.Workspace
+	tx             repository.TxManager
+	cfg            config.Service
+	notifier       LockoutNotifier
+	mailer         mail.Mailer
 }
 
-func NewAuthService(repos repository.Authorization, cfg config.Service) *AuthService {
-	return &AuthService{repos: repos, cfg: cfg}
+func NewAuthService(repos *repository.Repository, cfg config.Service, notifier LockoutNotifier, mailer mail.Mailer) *AuthService {
+	return &AuthService{
+		repos: repos.Authorization, reposWorkspace: repos.Workspace, tx: repos.TxManager,
+		cfg: cfg, notifier: notifier, mailer: mailer,
+	}
 }
 
-func (s AuthService) CreateUser(user model.User) (int, error) {
+// CreateUser creates an unverified account and mails the verification link. The account
+// is kept when the mail can't be sent, the link can be requested again.
+func (s AuthService) CreateUser(ctx context.Context, user model.User) (int, error) {
 	user.Password = generatePasswordHash(user.Password, s.cfg.Salt)
+	user.Verified = false
+
+	id, err := s.repos.CreateUser(ctx, user)
+	if err != nil {
+		return 0, err
+	}
+
+	user.ID = id
+	if err = s.sendVerification(ctx, user); err != nil {
+		logError(ctx, err, ErrFailedToSendVerification)
+	}
 
-	return s.repos.CreateUser(user)
+	return id, nil
 }
 
 type tokenClaims struct {
 	jwt.StandardClaims
 	UserID int `json:"user_id"`
+	// Version must match the token version of the user, the tokens issued before
+	// their sessions were revoked carry an older one.
+	Version int `json:"ver,omitempty"`
+	// Role can be trusted as long as the version matches, changing the role revokes the sessions.
+	Role string `json:"role,omitempty"`
+}
+
+// Authenticate checks the password alone, so it refuses the accounts with the two-factor
+// authentication, their users sign in with GenerateToken and CompleteSignIn.
+func (s AuthService) Authenticate(ctx context.Context, email, password string) (int, error) {
+	user, err := s.signIn(ctx, email, password)
+	if err != nil {
+		return 0, err
+	}
+
+	if user.TOTPEnabled {
+		return 0, ErrTwoFactorRequired
+	}
+
+	return user.ID, nil
+}
+
+// signIn makes the failed sign-ins take at least failedSignInDuration.
+func (s AuthService) signIn(ctx context.Context, email, password string) (model.User, error) {
+	start := time.Now()
+
+	user, err := s.authenticate(ctx, email, password)
+	if err != nil {
+		waitUntil(ctx, start.Add(failedSignInDuration))
+		return model.User{}, err
+	}
+
+	return user, nil
 }
 
-func (s AuthService) GenerateToken(email, password string) (string, error) {
-	user, err := s.repos.GetUser(email)
-	if err != nil || !compareHashAndPassword(user.Password, password, s.cfg.Salt) {
-		return "", ErrIncorrectEmailOrPassword
+// authenticate tells the locked, the disabled and the unverified accounts only to those who
+// know the password, the rest get the same error as for a wrong password.
+func (s AuthService) authenticate(ctx context.Context, email, password string) (model.User, error) {
+	user, err := s.repos.GetUser(ctx, email)
+	if err != nil {
+		compareHashAndPassword(dummyPasswordHash, password, s.cfg.Salt)
+		logError(ctx, err, ErrIncorrectEmailOrPassword)
+		return model.User{}, ErrIncorrectEmailOrPassword
+	}
+
+	valid := compareHashAndPassword(user.Password, password, s.cfg.Salt)
+
+	if s.locked(user) || user.Disabled {
+		if !valid {
+			return model.User{}, ErrIncorrectEmailOrPassword
+		}
+
+		if user.Disabled {
+			return model.User{}, ErrAccountDisabled
+		}
+
+		return model.User{}, ErrAccountLocked
+	}
+
+	if !valid {
+		s.recordFailedSignIn(ctx, user)
+		return model.User{}, ErrIncorrectEmailOrPassword
 	}
 
+	// The failures of the accounts with the two-factor authentication are reset once the
+	// code is checked too, or the wrong codes could be tried without ever locking the account.
+	if !user.TOTPEnabled && (user.FailedLogins > 0 || user.Lockouts > 0) {
+		if err = s.repos.ResetLockout(ctx, user.ID); err != nil {
+			logError(ctx, err, ErrFailedToUnlockUser)
+		}
+	}
+
+	if s.cfg.RequireVerification && !user.Verified {
+		return model.User{}, ErrEmailNotVerified
+	}
+
+	return user, nil
+}
+
+// GenerateToken returns the access token, or the challenge token when the account has
+// the two-factor authentication, see CompleteSignIn.
+func (s AuthService) GenerateToken(ctx context.Context, email, password string) (model.SignInResult, error) {
+	user, err := s.signIn(ctx, email, password)
+	if err != nil {
+		return model.SignInResult{}, err
+	}
+
+	return s.signInResult(user)
+}
+
+func (s AuthService) signInResult(user model.User) (model.SignInResult, error) {
+	if user.TOTPEnabled {
+		challenge, err := s.newChallenge(user)
+		if err != nil {
+			return model.SignInResult{}, err
+		}
+
+		return model.SignInResult{ChallengeToken: challenge}, nil
+	}
+
+	token, err := s.newToken(user)
+	if err != nil {
+		return model.SignInResult{}, err
+	}
+
+	return model.SignInResult{Token: token}, nil
+}
+
+func (s AuthService) newToken(user model.User) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, tokenClaims{
 		jwt.StandardClaims{
 			IssuedAt:  time.Now().Unix(),
 			ExpiresAt: time.Now().Add(time.Second * time.Duration(s.cfg.TokenTTL)).Unix(),
 		},
 		user.ID,
+		user.TokenVersion,
+		user.Role,
 	})
 
 	return token.SignedString([]byte(s.cfg.SigningKey))
 }
 
-func (s AuthService) ParseToken(accessToken string) (int, error) {
+// ParseToken checks the token against the current token version of the user,
+// so the sessions revoked by a password change stop working before they expire.
+func (s AuthService) ParseToken(ctx context.Context, accessToken string) (model.Session, error) {
 	token, err := jwt.ParseWithClaims(accessToken, &tokenClaims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, ErrInvalidSigningMethod
@@ -55,13 +184,31 @@ func (s AuthService) ParseToken(accessToken string) (int, error) {
 		return []byte(s.cfg.SigningKey), nil
 	})
 	if err != nil {
-		return 0, err
+		return model.Session{}, err
 	}
 
 	claims, ok := token.Claims.(*tokenClaims)
 	if !ok {
-		return 0, err
+		return model.Session{}, ErrInvalidTokenClaims
+	}
+
+	user, err := s.repos.GetUserByID(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Session{}, ErrSessionRevoked
+		}
+
+		logError(ctx, err, ErrFailedToCheckSession)
+		return model.Session{}, ErrFailedToCheckSession
+	}
+
+	if user.TokenVersion != claims.Version {
+		return model.Session{}, ErrSessionRevoked
+	}
+
+	if user.Disabled {
+		return model.Session{}, ErrAccountDisabled
 	}
 
-	return claims.UserID, nil
+	return model.Session{UserID: claims.UserID, Role: claims.Role}, nil
 }
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Lapp-coder/todo-app/internal/caldav"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/repository"
+)
+
+type CalDAVService struct {
+	repos repository.CalDAV
+	items *TodoItemService
+	tx    repository.TxManager
+}
+
+func NewCalDAVService(repos *repository.Repository) *CalDAVService {
+	return &CalDAVService{repos: repos.CalDAV, items: NewTodoItemService(repos), tx: repos.TxManager}
+}
+
+func (s CalDAVService) GetObjects(ctx context.Context, userID, listID int) ([]model.CalendarObject, error) {
+	objects, err := s.repos.GetObjects(ctx, userID, listID)
+	if err != nil {
+		logError(ctx, err, ErrFailedToGetCalendarObjects)
+		return nil, ErrFailedToGetCalendarObjects
+	}
+
+	return objects, nil
+}
+
+func (s CalDAVService) GetObjectByName(ctx context.Context, userID, listID int, name string) (model.CalendarObject, error) {
+	object, err := s.repos.GetObjectByName(ctx, userID, listID, name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.CalendarObject{}, ErrCalendarObjectNotFound
+		}
+
+		logError(ctx, err, ErrFailedToGetCalendarObjects)
+		return model.CalendarObject{}, ErrFailedToGetCalendarObjects
+	}
+
+	return object, nil
+}
+
+// PutItem creates the item in the list, or updates the item of the object when its ItemID is set,
+// and saves the object in one transaction, so a failed save leaves no item without its object behind.
+// The object without a uid gets the default one.
+func (s CalDAVService) PutItem(ctx context.Context, userID, listID int, object model.CalendarObject,
+	item model.TodoItem) (model.CalendarObject, error) {
+	if err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if object.ItemID == 0 {
+			itemID, err := s.items.Create(ctx, userID, listID, item)
+			if err != nil {
+				return err
+			}
+
+			object.ItemID = itemID
+		} else {
+			update := model.UpdateTodoItem{Title: &item.Title, Description: &item.Description, Done: &item.Done}
+			if item.CompletionDate != "" {
+				update.CompletionDate = &item.CompletionDate
+			}
+
+			if err := s.items.Update(ctx, userID, object.ItemID, update); err != nil {
+				return err
+			}
+		}
+
+		if object.UID == "" {
+			object.UID = caldav.DefaultUID(object.ItemID)
+		}
+
+		if err := s.repos.SaveObject(ctx, object); err != nil {
+			logError(ctx, err, ErrFailedToSaveCalendarObject)
+			return ErrFailedToSaveCalendarObject
+		}
+
+		return nil
+	}); err != nil {
+		return model.CalendarObject{}, err
+	}
+
+	return object, nil
+}
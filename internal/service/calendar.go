@@ -0,0 +1,159 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/ical"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/repository"
+)
+
+const (
+	feedTokenBytes = 20
+	feedProductID  = "-//Lapp-coder//todo-app//EN"
+	feedName       = "todo-app"
+)
+
+type CalendarService struct {
+	repos repository.Calendar
+}
+
+func NewCalendarService(repos repository.Calendar) *CalendarService {
+	return &CalendarService{repos: repos}
+}
+
+// CreateFeedToken returns the token of the new feed, which is stored hashed, so a lost token
+// is replaced with RotateFeedToken.
+func (s CalendarService) CreateFeedToken(ctx context.Context, userID int) (string, error) {
+	token, err := generateFeedToken()
+	if err != nil {
+		logError(ctx, err, ErrFailedToCreateFeedToken)
+		return "", ErrFailedToCreateFeedToken
+	}
+
+	if err = s.repos.CreateFeedToken(ctx, userID, hashToken(token)); err != nil {
+		if errors.Is(err, repository.ErrFeedTokenExists) {
+			return "", ErrFeedAlreadyExists
+		}
+
+		logError(ctx, err, ErrFailedToCreateFeedToken)
+		return "", ErrFailedToCreateFeedToken
+	}
+
+	return token, nil
+}
+
+func (s CalendarService) RotateFeedToken(ctx context.Context, userID int) (string, error) {
+	token, err := generateFeedToken()
+	if err != nil {
+		logError(ctx, err, ErrFailedToRotateFeedToken)
+		return "", ErrFailedToRotateFeedToken
+	}
+
+	if err = s.repos.UpdateFeedToken(ctx, userID, hashToken(token)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrFeedNotFound
+		}
+
+		logError(ctx, err, ErrFailedToRotateFeedToken)
+		return "", ErrFailedToRotateFeedToken
+	}
+
+	return token, nil
+}
+
+func (s CalendarService) GetFeed(ctx context.Context, token string, events, todos bool) ([]byte, error) {
+	userID, err := s.repos.GetUserIDByFeedToken(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrFeedNotFound
+		}
+
+		logError(ctx, err, ErrFailedToGetFeed)
+		return nil, ErrFailedToGetFeed
+	}
+
+	items, err := s.repos.GetAllItems(ctx, userID)
+	if err != nil {
+		logError(ctx, err, ErrFailedToGetFeed)
+		return nil, ErrFailedToGetFeed
+	}
+
+	calendar := ical.NewComponent("VCALENDAR")
+	calendar.Add("VERSION", "2.0")
+	calendar.Add("PRODID", feedProductID)
+	calendar.Add("CALSCALE", "GREGORIAN")
+	calendar.AddText("X-WR-CALNAME", feedName)
+
+	stamp := ical.FormatUTCDateTime(time.Now())
+	for _, item := range items {
+		due, ok := model.ParseCompletionDate(item.CompletionDate)
+		if !ok {
+			continue
+		}
+
+		if todos {
+			calendar.AddComponent(itemToTodo(item, due, stamp))
+		}
+
+		if events {
+			calendar.AddComponent(itemToEvent(item, due, stamp))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err = ical.Encode(&buf, calendar); err != nil {
+		logError(ctx, err, ErrFailedToGetFeed)
+		return nil, ErrFailedToGetFeed
+	}
+
+	return buf.Bytes(), nil
+}
+
+func itemToTodo(item model.TodoItem, due time.Time, stamp string) ical.Component {
+	todo := ical.NewComponent("VTODO")
+	todo.Add("UID", fmt.Sprintf("item-%d@%s", item.ID, feedName))
+	todo.Add("DTSTAMP", stamp)
+	todo.AddText("SUMMARY", item.Title)
+	if item.Description != "" {
+		todo.AddText("DESCRIPTION", item.Description)
+	}
+	todo.Add("DUE", ical.FormatDateTime(due))
+	if item.Done {
+		todo.Add("STATUS", "COMPLETED")
+	} else {
+		todo.Add("STATUS", "NEEDS-ACTION")
+	}
+
+	return todo
+}
+
+func itemToEvent(item model.TodoItem, due time.Time, stamp string) ical.Component {
+	event := ical.NewComponent("VEVENT")
+	event.Add("UID", fmt.Sprintf("item-%d-event@%s", item.ID, feedName))
+	event.Add("DTSTAMP", stamp)
+	event.AddText("SUMMARY", item.Title)
+	if item.Description != "" {
+		event.AddText("DESCRIPTION", item.Description)
+	}
+	event.Add("DTSTART", ical.FormatDateTime(due))
+	event.Add("TRANSP", "TRANSPARENT")
+
+	return event
+}
+
+func generateFeedToken() (string, error) {
+	b := make([]byte, feedTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
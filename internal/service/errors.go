@@ -1,18 +1,116 @@
 package service
 
-import "errors"
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Lapp-coder/todo-app/internal/logger"
+)
 
 var (
-	ErrIncorrectEmailOrPassword = errors.New("incorrect email or password")
-	ErrInvalidSigningMethod     = errors.New("invalid signing method")
-	ErrFailedToCreateItem       = errors.New("failed to create item")
-	ErrFailedToGetAllItems      = errors.New("failed to get all items")
-	ErrFailedToGetItemByID      = errors.New("failed to get item by id")
-	ErrFailedToUpdateItem       = errors.New("failed to update item")
-	ErrFailedToDeleteItem       = errors.New("failed to delete item")
-	ErrFailedToCreateList       = errors.New("failed to create list")
-	ErrFailedToGetAllLists      = errors.New("failed to get all lists")
-	ErrFailedToGetListByID      = errors.New("failed to get list by id")
-	ErrFailedToUpdateList       = errors.New("failed to update list")
-	ErrFailedToDeleteList       = errors.New("failed to delete list")
+	ErrIncorrectEmailOrPassword   = errors.New("incorrect email or password")
+	ErrInvalidSigningMethod       = errors.New("invalid signing method")
+	ErrInvalidTokenClaims         = errors.New("invalid token claims")
+	ErrAccountLocked              = errors.New("account is temporarily locked after too many failed sign-ins")
+	ErrFailedToRecordFailedSignIn = errors.New("failed to record failed sign-in")
+	ErrFailedToNotifyLockout      = errors.New("failed to notify about lockout")
+	ErrFailedToGetLockouts        = errors.New("failed to get lockouts")
+	ErrFailedToUnlockUser         = errors.New("failed to unlock user")
+	ErrUserNotFound               = errors.New("user not found")
+	ErrEmailNotVerified           = errors.New("email is not verified")
+	ErrInvalidVerificationToken   = errors.New("invalid or expired verification token")
+	ErrFailedToVerifyEmail        = errors.New("failed to verify email")
+	ErrFailedToSendVerification   = errors.New("failed to send verification email")
+	ErrSessionRevoked             = errors.New("session is revoked")
+	ErrFailedToCheckSession       = errors.New("failed to check session")
+	ErrFailedToSendPasswordReset  = errors.New("failed to send password reset email")
+	ErrInvalidResetToken          = errors.New("invalid or expired reset token")
+	ErrFailedToResetPassword      = errors.New("failed to reset password")
+	ErrIncorrectPassword          = errors.New("incorrect password")
+	ErrFailedToChangePassword     = errors.New("failed to change password")
+	ErrTwoFactorRequired          = errors.New("two-factor authentication is required")
+	ErrTwoFactorEnabled           = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotSetUp          = errors.New("two-factor authentication is not set up")
+	ErrInvalidTwoFactorCode       = errors.New("invalid two-factor code")
+	ErrInvalidChallengeToken      = errors.New("invalid or expired challenge token")
+	ErrFailedToSetUpTwoFactor     = errors.New("failed to set up two-factor authentication")
+	ErrFailedToEnableTwoFactor    = errors.New("failed to enable two-factor authentication")
+	ErrFailedToCompleteSignIn     = errors.New("failed to complete sign-in")
+	ErrOIDCDisabled               = errors.New("single sign-on is not configured")
+	ErrInvalidOIDCState           = errors.New("invalid or expired single sign-on state")
+	ErrOIDCSignInFailed           = errors.New("failed to sign in with the identity provider")
+	ErrOIDCEmailNotVerified       = errors.New("email is not verified by the identity provider")
+	ErrOIDCAccountNotVerified     = errors.New("account with this email must be verified before signing in with the identity provider")
+	ErrFailedToStartOIDCLogin     = errors.New("failed to start single sign-on")
+	ErrFailedToSignInWithOIDC     = errors.New("failed to sign in with single sign-on")
+	ErrEmailAlreadyTaken          = errors.New("user with this email already exists")
+	ErrInvalidTimezone            = errors.New("invalid timezone")
+	ErrInvalidLocale              = errors.New("invalid locale")
+	ErrFailedToGetProfile         = errors.New("failed to get profile")
+	ErrFailedToUpdateProfile      = errors.New("failed to update profile")
+	ErrFailedToDeleteAccount      = errors.New("failed to delete account")
+	ErrInvalidAccessToken         = errors.New("invalid or expired access token")
+	ErrFailedToCheckAccessToken   = errors.New("failed to check access token")
+	ErrAccessTokenNotFound        = errors.New("access token not found")
+	ErrFailedToCreateAccessToken  = errors.New("failed to create access token")
+	ErrFailedToGetAccessTokens    = errors.New("failed to get access tokens")
+	ErrFailedToGetAccessToken     = errors.New("failed to get access token")
+	ErrFailedToDeleteAccessToken  = errors.New("failed to delete access token")
+	ErrAdminAPIDisabled           = errors.New("admin api is disabled")
+	ErrInvalidAdminToken          = errors.New("invalid admin token")
+	ErrAccountDisabled            = errors.New("account is disabled")
+	ErrFailedToGetUsers           = errors.New("failed to get users")
+	ErrFailedToSetRole            = errors.New("failed to set role")
+	ErrFailedToDisableUser        = errors.New("failed to disable user")
+	ErrFailedToEnableUser         = errors.New("failed to enable user")
+	ErrFailedToRevokeSessions     = errors.New("failed to revoke sessions")
+	ErrFailedToGetStats           = errors.New("failed to get stats")
+	ErrWorkspaceNotFound          = errors.New("workspace not found")
+	ErrWorkspaceReadOnly          = errors.New("not allowed for the role in the workspace")
+	ErrPersonalWorkspace          = errors.New("personal workspace can't be shared or deleted")
+	ErrLastWorkspaceOwner         = errors.New("workspace must keep an owner")
+	ErrMemberNotFound             = errors.New("workspace member not found")
+	ErrAlreadyMember              = errors.New("user is already a member of the workspace")
+	ErrFailedToGetWorkspace       = errors.New("failed to get workspace")
+	ErrFailedToGetWorkspaces      = errors.New("failed to get workspaces")
+	ErrFailedToCreateWorkspace    = errors.New("failed to create workspace")
+	ErrFailedToUpdateWorkspace    = errors.New("failed to update workspace")
+	ErrFailedToDeleteWorkspace    = errors.New("failed to delete workspace")
+	ErrFailedToGetMembers         = errors.New("failed to get workspace members")
+	ErrFailedToSetMember          = errors.New("failed to set workspace member")
+	ErrFailedToRemoveMember       = errors.New("failed to remove workspace member")
+	ErrFailedToCreateItem         = errors.New("failed to create item")
+	ErrFailedToGetAllItems        = errors.New("failed to get all items")
+	ErrFailedToGetItemByID        = errors.New("failed to get item by id")
+	ErrFailedToUpdateItem         = errors.New("failed to update item")
+	ErrFailedToDeleteItem         = errors.New("failed to delete item")
+	ErrFailedToCreateList         = errors.New("failed to create list")
+	ErrFailedToGetAllLists        = errors.New("failed to get all lists")
+	ErrFailedToGetListByID        = errors.New("failed to get list by id")
+	ErrFailedToUpdateList         = errors.New("failed to update list")
+	ErrFailedToDeleteList         = errors.New("failed to delete list")
+	ErrFailedToCreateFeedToken    = errors.New("failed to create calendar feed token")
+	ErrFailedToRotateFeedToken    = errors.New("failed to rotate calendar feed token")
+	ErrFailedToGetFeed            = errors.New("failed to get calendar feed")
+	ErrFeedNotFound               = errors.New("calendar feed not found")
+	ErrFeedAlreadyExists          = errors.New("calendar feed already exists, rotate its token to get a new one")
+	ErrFailedToGetCalendarObjects = errors.New("failed to get calendar objects")
+	ErrFailedToSaveCalendarObject = errors.New("failed to save calendar object")
+	ErrCalendarObjectNotFound     = errors.New("calendar object not found")
+	ErrNotReady                   = errors.New("not ready")
+	ErrShuttingDown               = errors.New("shutting down")
 )
+
+// logError logs the error hidden behind the reason returned to the caller. Missing rows
+// are expected, e.g. when a user asks for someone else's list, so they're only logged
+// for debugging.
+func logError(ctx context.Context, err, reason error) {
+	entry := logger.FromContext(ctx).WithError(err)
+	if errors.Is(err, sql.ErrNoRows) {
+		entry.Debug(reason.Error())
+		return
+	}
+
+	entry.Error(reason.Error())
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"crypto/sha1"
+	"crypto/subtle"
 	"fmt"
 )
 
@@ -13,5 +14,5 @@ func generatePasswordHash(password string, salt string) string {
 }
 
 func compareHashAndPassword(hash, password, salt string) bool {
-	return generatePasswordHash(password, salt) == hash
+	return subtle.ConstantTimeCompare([]byte(generatePasswordHash(password, salt)), []byte(hash)) == 1
 }
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+const healthCheckOK = "ok"
+
+// HealthCheck returns the reason a dependency of the app can't serve requests.
+type HealthCheck func(ctx context.Context) error
+
+type HealthService struct {
+	checks       map[string]HealthCheck
+	shuttingDown int32
+}
+
+func NewHealthService(checks map[string]HealthCheck) *HealthService {
+	return &HealthService{checks: checks}
+}
+
+// Ready runs the checks concurrently and returns their results by name,
+// the error is set when any of them failed or the app is shutting down.
+func (s *HealthService) Ready(ctx context.Context) (map[string]string, error) {
+	if atomic.LoadInt32(&s.shuttingDown) == 1 {
+		return nil, ErrShuttingDown
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	results := make(map[string]string, len(s.checks))
+	ready := true
+
+	for name, check := range s.checks {
+		wg.Add(1)
+		go func(name string, check HealthCheck) {
+			defer wg.Done()
+
+			result := healthCheckOK
+			err := check(ctx)
+			if err != nil {
+				result = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			results[name] = result
+			if err != nil {
+				ready = false
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	if !ready {
+		return results, ErrNotReady
+	}
+
+	return results, nil
+}
+
+// ShutDown makes the app not ready for good, so the load balancer stops sending
+// requests to it while the server is draining the ones in flight.
+func (s *HealthService) ShutDown() {
+	atomic.StoreInt32(&s.shuttingDown, 1)
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/logger"
+	"github.com/Lapp-coder/todo-app/internal/model"
+)
+
+// failedSignInDuration is the least time a failed sign-in takes, so the responses
+// for the unknown emails, the wrong passwords and the locked accounts can't be told
+// apart by their timing.
+var failedSignInDuration = 300 * time.Millisecond
+
+// dummyPasswordHash is compared with the password when the email is unknown,
+// so the hash is computed as for an existing account.
+const dummyPasswordHash = "0000000000000000000000000000000000000000"
+
+// LockoutNotifier tells the users their account was locked after the failed sign-ins.
+type LockoutNotifier interface {
+	AccountLocked(ctx context.Context, user model.User, until time.Time) error
+}
+
+// LogNotifier writes the lockouts to the log, it's used until the users can be reached another way.
+type LogNotifier struct{}
+
+func (LogNotifier) AccountLocked(ctx context.Context, user model.User, until time.Time) error {
+	logger.FromContext(ctx).WithField(logger.UserIDField, user.ID).
+		WithField("locked_until", until.Format(time.RFC3339)).Warn("account locked after failed sign-ins")
+
+	return nil
+}
+
+func (s AuthService) lockoutEnabled() bool {
+	return s.cfg.LockoutThreshold > 0
+}
+
+func (s AuthService) locked(user model.User) bool {
+	return s.lockoutEnabled() && user.LockedUntil != nil && time.Now().Before(*user.LockedUntil)
+}
+
+// lockoutDuration doubles the lockout for every lockout since the last successful sign-in.
+func (s AuthService) lockoutDuration(lockouts int) time.Duration {
+	duration := time.Duration(s.cfg.LockoutDuration) * time.Second
+	max := time.Duration(s.cfg.MaxLockoutDuration) * time.Second
+
+	for i := 0; i < lockouts && (max <= 0 || duration < max); i++ {
+		duration *= 2
+	}
+
+	if max > 0 && duration > max {
+		return max
+	}
+
+	return duration
+}
+
+// recordFailedSignIn counts the failure and locks the account once there are enough of them.
+// The errors are only logged, the caller fails the sign-in anyway.
+func (s AuthService) recordFailedSignIn(ctx context.Context, user model.User) {
+	if !s.lockoutEnabled() {
+		return
+	}
+
+	failedLogins, err := s.repos.RecordFailedLogin(ctx, user.ID)
+	if err != nil {
+		logError(ctx, err, ErrFailedToRecordFailedSignIn)
+		return
+	}
+
+	if failedLogins < s.cfg.LockoutThreshold {
+		return
+	}
+
+	until := time.Now().Add(s.lockoutDuration(user.Lockouts))
+	if err = s.repos.LockUser(ctx, user.ID, until); err != nil {
+		logError(ctx, err, ErrFailedToRecordFailedSignIn)
+		return
+	}
+
+	if err = s.notifier.AccountLocked(ctx, user, until); err != nil {
+		logError(ctx, err, ErrFailedToNotifyLockout)
+	}
+}
+
+func (s AuthService) GetLockouts(ctx context.Context) ([]model.Lockout, error) {
+	lockouts, err := s.repos.GetLockouts(ctx)
+	if err != nil {
+		logError(ctx, err, ErrFailedToGetLockouts)
+		return nil, ErrFailedToGetLockouts
+	}
+
+	now := time.Now()
+	for i := range lockouts {
+		lockouts[i].Locked = lockouts[i].LockedUntil != nil && now.Before(*lockouts[i].LockedUntil)
+	}
+
+	return lockouts, nil
+}
+
+func (s AuthService) Unlock(ctx context.Context, userID int) error {
+	if err := s.repos.ResetLockout(ctx, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+
+		logError(ctx, err, ErrFailedToUnlockUser)
+		return ErrFailedToUnlockUser
+	}
+
+	return nil
+}
+
+func (s AuthService) AuthorizeAdmin(token string) error {
+	if s.cfg.AdminToken == "" {
+		return ErrAdminAPIDisabled
+	}
+
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.cfg.AdminToken)) != 1 {
+		return ErrInvalidAdminToken
+	}
+
+	return nil
+}
+
+// waitUntil blocks until the time or the end of the context.
+func waitUntil(ctx context.Context, t time.Time) {
+	timer := time.NewTimer(time.Until(t))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
@@ -5,6 +5,7 @@
 package mock_service
 
 import (
+	context "context"
 	reflect "reflect"
 
 	model "github.com/Lapp-coder/todo-app/internal/model"
@@ -34,49 +35,353 @@ func (m *MockAuthorization) EXPECT() *MockAuthorizationMockRecorder {
 	return m.recorder
 }
 
+// Authenticate mocks base method.
+func (m *MockAuthorization) Authenticate(ctx context.Context, email, password string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, email, password)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAuthorizationMockRecorder) Authenticate(ctx, email, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAuthorization)(nil).Authenticate), ctx, email, password)
+}
+
+// AuthorizeAdmin mocks base method.
+func (m *MockAuthorization) AuthorizeAdmin(token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizeAdmin", token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AuthorizeAdmin indicates an expected call of AuthorizeAdmin.
+func (mr *MockAuthorizationMockRecorder) AuthorizeAdmin(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizeAdmin", reflect.TypeOf((*MockAuthorization)(nil).AuthorizeAdmin), token)
+}
+
+// ChangePassword mocks base method.
+func (m *MockAuthorization) ChangePassword(ctx context.Context, userID int, currentPassword, newPassword string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, userID, currentPassword, newPassword)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockAuthorizationMockRecorder) ChangePassword(ctx, userID, currentPassword, newPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockAuthorization)(nil).ChangePassword), ctx, userID, currentPassword, newPassword)
+}
+
+// CompleteSignIn mocks base method.
+func (m *MockAuthorization) CompleteSignIn(ctx context.Context, challengeToken, code string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteSignIn", ctx, challengeToken, code)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteSignIn indicates an expected call of CompleteSignIn.
+func (mr *MockAuthorizationMockRecorder) CompleteSignIn(ctx, challengeToken, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteSignIn", reflect.TypeOf((*MockAuthorization)(nil).CompleteSignIn), ctx, challengeToken, code)
+}
+
+// ConfirmTwoFactor mocks base method.
+func (m *MockAuthorization) ConfirmTwoFactor(ctx context.Context, userID int, code string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTwoFactor", ctx, userID, code)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmTwoFactor indicates an expected call of ConfirmTwoFactor.
+func (mr *MockAuthorizationMockRecorder) ConfirmTwoFactor(ctx, userID, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTwoFactor", reflect.TypeOf((*MockAuthorization)(nil).ConfirmTwoFactor), ctx, userID, code)
+}
+
 // CreateUser mocks base method.
-func (m *MockAuthorization) CreateUser(user model.User) (int, error) {
+func (m *MockAuthorization) CreateUser(ctx context.Context, user model.User) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", user)
+	ret := m.ctrl.Call(m, "CreateUser", ctx, user)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateUser indicates an expected call of CreateUser.
-func (mr *MockAuthorizationMockRecorder) CreateUser(user interface{}) *gomock.Call {
+func (mr *MockAuthorizationMockRecorder) CreateUser(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockAuthorization)(nil).CreateUser), ctx, user)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockAuthorization) DeleteAccount(ctx context.Context, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockAuthorizationMockRecorder) DeleteAccount(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockAuthorization)(nil).CreateUser), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockAuthorization)(nil).DeleteAccount), ctx, userID)
+}
+
+// DisableUser mocks base method.
+func (m *MockAuthorization) DisableUser(ctx context.Context, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableUser", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableUser indicates an expected call of DisableUser.
+func (mr *MockAuthorizationMockRecorder) DisableUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableUser", reflect.TypeOf((*MockAuthorization)(nil).DisableUser), ctx, userID)
+}
+
+// EnableUser mocks base method.
+func (m *MockAuthorization) EnableUser(ctx context.Context, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableUser", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableUser indicates an expected call of EnableUser.
+func (mr *MockAuthorizationMockRecorder) EnableUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableUser", reflect.TypeOf((*MockAuthorization)(nil).EnableUser), ctx, userID)
+}
+
+// ForgotPassword mocks base method.
+func (m *MockAuthorization) ForgotPassword(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForgotPassword", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForgotPassword indicates an expected call of ForgotPassword.
+func (mr *MockAuthorizationMockRecorder) ForgotPassword(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockAuthorization)(nil).ForgotPassword), ctx, email)
 }
 
 // GenerateToken mocks base method.
-func (m *MockAuthorization) GenerateToken(email, password string) (string, error) {
+func (m *MockAuthorization) GenerateToken(ctx context.Context, email, password string) (model.SignInResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateToken", email, password)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GenerateToken", ctx, email, password)
+	ret0, _ := ret[0].(model.SignInResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GenerateToken indicates an expected call of GenerateToken.
-func (mr *MockAuthorizationMockRecorder) GenerateToken(email, password interface{}) *gomock.Call {
+func (mr *MockAuthorizationMockRecorder) GenerateToken(ctx, email, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockAuthorization)(nil).GenerateToken), ctx, email, password)
+}
+
+// GetLockouts mocks base method.
+func (m *MockAuthorization) GetLockouts(ctx context.Context) ([]model.Lockout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLockouts", ctx)
+	ret0, _ := ret[0].([]model.Lockout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLockouts indicates an expected call of GetLockouts.
+func (mr *MockAuthorizationMockRecorder) GetLockouts(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLockouts", reflect.TypeOf((*MockAuthorization)(nil).GetLockouts), ctx)
+}
+
+// GetProfile mocks base method.
+func (m *MockAuthorization) GetProfile(ctx context.Context, userID int) (model.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfile", ctx, userID)
+	ret0, _ := ret[0].(model.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProfile indicates an expected call of GetProfile.
+func (mr *MockAuthorizationMockRecorder) GetProfile(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockAuthorization)(nil).GetProfile), ctx, userID)
+}
+
+// GetStats mocks base method.
+func (m *MockAuthorization) GetStats(ctx context.Context) (model.Stats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStats", ctx)
+	ret0, _ := ret[0].(model.Stats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStats indicates an expected call of GetStats.
+func (mr *MockAuthorizationMockRecorder) GetStats(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockAuthorization)(nil).GetStats), ctx)
+}
+
+// GetUsers mocks base method.
+func (m *MockAuthorization) GetUsers(ctx context.Context, search model.SearchUsers) ([]model.UserSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsers", ctx, search)
+	ret0, _ := ret[0].([]model.UserSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsers indicates an expected call of GetUsers.
+func (mr *MockAuthorizationMockRecorder) GetUsers(ctx, search interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockAuthorization)(nil).GenerateToken), email, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockAuthorization)(nil).GetUsers), ctx, search)
 }
 
 // ParseToken mocks base method.
-func (m *MockAuthorization) ParseToken(accessToken string) (int, error) {
+func (m *MockAuthorization) ParseToken(ctx context.Context, accessToken string) (model.Session, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ParseToken", accessToken)
-	ret0, _ := ret[0].(int)
+	ret := m.ctrl.Call(m, "ParseToken", ctx, accessToken)
+	ret0, _ := ret[0].(model.Session)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ParseToken indicates an expected call of ParseToken.
-func (mr *MockAuthorizationMockRecorder) ParseToken(accessToken interface{}) *gomock.Call {
+func (mr *MockAuthorizationMockRecorder) ParseToken(ctx, accessToken interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseToken", reflect.TypeOf((*MockAuthorization)(nil).ParseToken), accessToken)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseToken", reflect.TypeOf((*MockAuthorization)(nil).ParseToken), ctx, accessToken)
+}
+
+// ResendVerification mocks base method.
+func (m *MockAuthorization) ResendVerification(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResendVerification", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResendVerification indicates an expected call of ResendVerification.
+func (mr *MockAuthorizationMockRecorder) ResendVerification(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendVerification", reflect.TypeOf((*MockAuthorization)(nil).ResendVerification), ctx, email)
+}
+
+// ResetPassword mocks base method.
+func (m *MockAuthorization) ResetPassword(ctx context.Context, token, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, token, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockAuthorizationMockRecorder) ResetPassword(ctx, token, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockAuthorization)(nil).ResetPassword), ctx, token, password)
+}
+
+// RevokeSessions mocks base method.
+func (m *MockAuthorization) RevokeSessions(ctx context.Context, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSessions", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSessions indicates an expected call of RevokeSessions.
+func (mr *MockAuthorizationMockRecorder) RevokeSessions(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSessions", reflect.TypeOf((*MockAuthorization)(nil).RevokeSessions), ctx, userID)
+}
+
+// SetRole mocks base method.
+func (m *MockAuthorization) SetRole(ctx context.Context, userID int, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRole", ctx, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRole indicates an expected call of SetRole.
+func (mr *MockAuthorizationMockRecorder) SetRole(ctx, userID, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRole", reflect.TypeOf((*MockAuthorization)(nil).SetRole), ctx, userID, role)
+}
+
+// SetupTwoFactor mocks base method.
+func (m *MockAuthorization) SetupTwoFactor(ctx context.Context, userID int) (model.TwoFactorSetup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetupTwoFactor", ctx, userID)
+	ret0, _ := ret[0].(model.TwoFactorSetup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetupTwoFactor indicates an expected call of SetupTwoFactor.
+func (mr *MockAuthorizationMockRecorder) SetupTwoFactor(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetupTwoFactor", reflect.TypeOf((*MockAuthorization)(nil).SetupTwoFactor), ctx, userID)
+}
+
+// Unlock mocks base method.
+func (m *MockAuthorization) Unlock(ctx context.Context, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockAuthorizationMockRecorder) Unlock(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockAuthorization)(nil).Unlock), ctx, userID)
+}
+
+// UpdateProfile mocks base method.
+func (m *MockAuthorization) UpdateProfile(ctx context.Context, userID int, update model.UpdateProfile) (model.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, userID, update)
+	ret0, _ := ret[0].(model.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockAuthorizationMockRecorder) UpdateProfile(ctx, userID, update interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockAuthorization)(nil).UpdateProfile), ctx, userID, update)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockAuthorization) VerifyEmail(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockAuthorizationMockRecorder) VerifyEmail(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockAuthorization)(nil).VerifyEmail), ctx, token)
 }
 
 // MockTodoList is a mock of TodoList interface.
@@ -103,76 +408,76 @@ func (m *MockTodoList) EXPECT() *MockTodoListMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockTodoList) Create(userID int, list model.TodoList) (int, error) {
+func (m *MockTodoList) Create(ctx context.Context, userID int, list model.TodoList) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", userID, list)
+	ret := m.ctrl.Call(m, "Create", ctx, userID, list)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockTodoListMockRecorder) Create(userID, list interface{}) *gomock.Call {
+func (mr *MockTodoListMockRecorder) Create(ctx, userID, list interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTodoList)(nil).Create), userID, list)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTodoList)(nil).Create), ctx, userID, list)
 }
 
 // Delete mocks base method.
-func (m *MockTodoList) Delete(userID, listID int) error {
+func (m *MockTodoList) Delete(ctx context.Context, userID, listID int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", userID, listID)
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, listID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockTodoListMockRecorder) Delete(userID, listID interface{}) *gomock.Call {
+func (mr *MockTodoListMockRecorder) Delete(ctx, userID, listID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTodoList)(nil).Delete), userID, listID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTodoList)(nil).Delete), ctx, userID, listID)
 }
 
 // GetAll mocks base method.
-func (m *MockTodoList) GetAll(userID int) ([]model.TodoList, error) {
+func (m *MockTodoList) GetAll(ctx context.Context, userID int) ([]model.TodoList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", userID)
+	ret := m.ctrl.Call(m, "GetAll", ctx, userID)
 	ret0, _ := ret[0].([]model.TodoList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockTodoListMockRecorder) GetAll(userID interface{}) *gomock.Call {
+func (mr *MockTodoListMockRecorder) GetAll(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTodoList)(nil).GetAll), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTodoList)(nil).GetAll), ctx, userID)
 }
 
 // GetByID mocks base method.
-func (m *MockTodoList) GetByID(userID, listID int) (model.TodoList, error) {
+func (m *MockTodoList) GetByID(ctx context.Context, userID, listID int) (model.TodoList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", userID, listID)
+	ret := m.ctrl.Call(m, "GetByID", ctx, userID, listID)
 	ret0, _ := ret[0].(model.TodoList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockTodoListMockRecorder) GetByID(userID, listID interface{}) *gomock.Call {
+func (mr *MockTodoListMockRecorder) GetByID(ctx, userID, listID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTodoList)(nil).GetByID), userID, listID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTodoList)(nil).GetByID), ctx, userID, listID)
 }
 
 // Update mocks base method.
-func (m *MockTodoList) Update(userID, listID int, update model.UpdateTodoList) error {
+func (m *MockTodoList) Update(ctx context.Context, userID, listID int, update model.UpdateTodoList) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", userID, listID, update)
+	ret := m.ctrl.Call(m, "Update", ctx, userID, listID, update)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockTodoListMockRecorder) Update(userID, listID, update interface{}) *gomock.Call {
+func (mr *MockTodoListMockRecorder) Update(ctx, userID, listID, update interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTodoList)(nil).Update), userID, listID, update)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTodoList)(nil).Update), ctx, userID, listID, update)
 }
 
 // MockTodoItem is a mock of TodoItem interface.
@@ -199,74 +504,609 @@ func (m *MockTodoItem) EXPECT() *MockTodoItemMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockTodoItem) Create(userID, listID int, item model.TodoItem) (int, error) {
+func (m *MockTodoItem) Create(ctx context.Context, userID, listID int, item model.TodoItem) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", userID, listID, item)
+	ret := m.ctrl.Call(m, "Create", ctx, userID, listID, item)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockTodoItemMockRecorder) Create(userID, listID, item interface{}) *gomock.Call {
+func (mr *MockTodoItemMockRecorder) Create(ctx, userID, listID, item interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTodoItem)(nil).Create), userID, listID, item)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTodoItem)(nil).Create), ctx, userID, listID, item)
 }
 
 // Delete mocks base method.
-func (m *MockTodoItem) Delete(userID, itemID int) error {
+func (m *MockTodoItem) Delete(ctx context.Context, userID, itemID int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", userID, itemID)
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, itemID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockTodoItemMockRecorder) Delete(userID, itemID interface{}) *gomock.Call {
+func (mr *MockTodoItemMockRecorder) Delete(ctx, userID, itemID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTodoItem)(nil).Delete), userID, itemID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTodoItem)(nil).Delete), ctx, userID, itemID)
 }
 
 // GetAll mocks base method.
-func (m *MockTodoItem) GetAll(userID, listID int) ([]model.TodoItem, error) {
+func (m *MockTodoItem) GetAll(ctx context.Context, userID, listID int) ([]model.TodoItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", userID, listID)
+	ret := m.ctrl.Call(m, "GetAll", ctx, userID, listID)
 	ret0, _ := ret[0].([]model.TodoItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockTodoItemMockRecorder) GetAll(userID, listID interface{}) *gomock.Call {
+func (mr *MockTodoItemMockRecorder) GetAll(ctx, userID, listID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTodoItem)(nil).GetAll), ctx, userID, listID)
+}
+
+// GetAllByLists mocks base method.
+func (m *MockTodoItem) GetAllByLists(ctx context.Context, userID int, listIDs []int) ([]model.TodoItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByLists", ctx, userID, listIDs)
+	ret0, _ := ret[0].([]model.TodoItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllByLists indicates an expected call of GetAllByLists.
+func (mr *MockTodoItemMockRecorder) GetAllByLists(ctx, userID, listIDs interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTodoItem)(nil).GetAll), userID, listID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByLists", reflect.TypeOf((*MockTodoItem)(nil).GetAllByLists), ctx, userID, listIDs)
+}
+
+// GetAllByUser mocks base method.
+func (m *MockTodoItem) GetAllByUser(ctx context.Context, userID int) ([]model.TodoItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByUser", ctx, userID)
+	ret0, _ := ret[0].([]model.TodoItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllByUser indicates an expected call of GetAllByUser.
+func (mr *MockTodoItemMockRecorder) GetAllByUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByUser", reflect.TypeOf((*MockTodoItem)(nil).GetAllByUser), ctx, userID)
 }
 
 // GetByID mocks base method.
-func (m *MockTodoItem) GetByID(userID, itemID int) (model.TodoItem, error) {
+func (m *MockTodoItem) GetByID(ctx context.Context, userID, itemID int) (model.TodoItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", userID, itemID)
+	ret := m.ctrl.Call(m, "GetByID", ctx, userID, itemID)
 	ret0, _ := ret[0].(model.TodoItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockTodoItemMockRecorder) GetByID(userID, itemID interface{}) *gomock.Call {
+func (mr *MockTodoItemMockRecorder) GetByID(ctx, userID, itemID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTodoItem)(nil).GetByID), userID, itemID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTodoItem)(nil).GetByID), ctx, userID, itemID)
 }
 
 // Update mocks base method.
-func (m *MockTodoItem) Update(userID, itemID int, update model.UpdateTodoItem) error {
+func (m *MockTodoItem) Update(ctx context.Context, userID, itemID int, update model.UpdateTodoItem) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", userID, itemID, update)
+	ret := m.ctrl.Call(m, "Update", ctx, userID, itemID, update)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockTodoItemMockRecorder) Update(userID, itemID, update interface{}) *gomock.Call {
+func (mr *MockTodoItemMockRecorder) Update(ctx, userID, itemID, update interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTodoItem)(nil).Update), ctx, userID, itemID, update)
+}
+
+// MockCalendar is a mock of Calendar interface.
+type MockCalendar struct {
+	ctrl     *gomock.Controller
+	recorder *MockCalendarMockRecorder
+}
+
+// MockCalendarMockRecorder is the mock recorder for MockCalendar.
+type MockCalendarMockRecorder struct {
+	mock *MockCalendar
+}
+
+// NewMockCalendar creates a new mock instance.
+func NewMockCalendar(ctrl *gomock.Controller) *MockCalendar {
+	mock := &MockCalendar{ctrl: ctrl}
+	mock.recorder = &MockCalendarMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCalendar) EXPECT() *MockCalendarMockRecorder {
+	return m.recorder
+}
+
+// CreateFeedToken mocks base method.
+func (m *MockCalendar) CreateFeedToken(ctx context.Context, userID int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFeedToken", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFeedToken indicates an expected call of CreateFeedToken.
+func (mr *MockCalendarMockRecorder) CreateFeedToken(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFeedToken", reflect.TypeOf((*MockCalendar)(nil).CreateFeedToken), ctx, userID)
+}
+
+// GetFeed mocks base method.
+func (m *MockCalendar) GetFeed(ctx context.Context, token string, events, todos bool) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeed", ctx, token, events, todos)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeed indicates an expected call of GetFeed.
+func (mr *MockCalendarMockRecorder) GetFeed(ctx, token, events, todos interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeed", reflect.TypeOf((*MockCalendar)(nil).GetFeed), ctx, token, events, todos)
+}
+
+// RotateFeedToken mocks base method.
+func (m *MockCalendar) RotateFeedToken(ctx context.Context, userID int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateFeedToken", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateFeedToken indicates an expected call of RotateFeedToken.
+func (mr *MockCalendarMockRecorder) RotateFeedToken(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateFeedToken", reflect.TypeOf((*MockCalendar)(nil).RotateFeedToken), ctx, userID)
+}
+
+// MockCalDAV is a mock of CalDAV interface.
+type MockCalDAV struct {
+	ctrl     *gomock.Controller
+	recorder *MockCalDAVMockRecorder
+}
+
+// MockCalDAVMockRecorder is the mock recorder for MockCalDAV.
+type MockCalDAVMockRecorder struct {
+	mock *MockCalDAV
+}
+
+// NewMockCalDAV creates a new mock instance.
+func NewMockCalDAV(ctrl *gomock.Controller) *MockCalDAV {
+	mock := &MockCalDAV{ctrl: ctrl}
+	mock.recorder = &MockCalDAVMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCalDAV) EXPECT() *MockCalDAVMockRecorder {
+	return m.recorder
+}
+
+// GetObjectByName mocks base method.
+func (m *MockCalDAV) GetObjectByName(ctx context.Context, userID, listID int, name string) (model.CalendarObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetObjectByName", ctx, userID, listID, name)
+	ret0, _ := ret[0].(model.CalendarObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetObjectByName indicates an expected call of GetObjectByName.
+func (mr *MockCalDAVMockRecorder) GetObjectByName(ctx, userID, listID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObjectByName", reflect.TypeOf((*MockCalDAV)(nil).GetObjectByName), ctx, userID, listID, name)
+}
+
+// GetObjects mocks base method.
+func (m *MockCalDAV) GetObjects(ctx context.Context, userID, listID int) ([]model.CalendarObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetObjects", ctx, userID, listID)
+	ret0, _ := ret[0].([]model.CalendarObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetObjects indicates an expected call of GetObjects.
+func (mr *MockCalDAVMockRecorder) GetObjects(ctx, userID, listID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObjects", reflect.TypeOf((*MockCalDAV)(nil).GetObjects), ctx, userID, listID)
+}
+
+// PutItem mocks base method.
+func (m *MockCalDAV) PutItem(ctx context.Context, userID, listID int, object model.CalendarObject, item model.TodoItem) (model.CalendarObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutItem", ctx, userID, listID, object, item)
+	ret0, _ := ret[0].(model.CalendarObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutItem indicates an expected call of PutItem.
+func (mr *MockCalDAVMockRecorder) PutItem(ctx, userID, listID, object, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutItem", reflect.TypeOf((*MockCalDAV)(nil).PutItem), ctx, userID, listID, object, item)
+}
+
+// MockHealth is a mock of Health interface.
+type MockHealth struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthMockRecorder
+}
+
+// MockHealthMockRecorder is the mock recorder for MockHealth.
+type MockHealthMockRecorder struct {
+	mock *MockHealth
+}
+
+// NewMockHealth creates a new mock instance.
+func NewMockHealth(ctrl *gomock.Controller) *MockHealth {
+	mock := &MockHealth{ctrl: ctrl}
+	mock.recorder = &MockHealthMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealth) EXPECT() *MockHealthMockRecorder {
+	return m.recorder
+}
+
+// Ready mocks base method.
+func (m *MockHealth) Ready(ctx context.Context) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ready", ctx)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Ready indicates an expected call of Ready.
+func (mr *MockHealthMockRecorder) Ready(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ready", reflect.TypeOf((*MockHealth)(nil).Ready), ctx)
+}
+
+// ShutDown mocks base method.
+func (m *MockHealth) ShutDown() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ShutDown")
+}
+
+// ShutDown indicates an expected call of ShutDown.
+func (mr *MockHealthMockRecorder) ShutDown() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShutDown", reflect.TypeOf((*MockHealth)(nil).ShutDown))
+}
+
+// MockAccessToken is a mock of AccessToken interface.
+type MockAccessToken struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessTokenMockRecorder
+}
+
+// MockAccessTokenMockRecorder is the mock recorder for MockAccessToken.
+type MockAccessTokenMockRecorder struct {
+	mock *MockAccessToken
+}
+
+// NewMockAccessToken creates a new mock instance.
+func NewMockAccessToken(ctrl *gomock.Controller) *MockAccessToken {
+	mock := &MockAccessToken{ctrl: ctrl}
+	mock.recorder = &MockAccessTokenMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccessToken) EXPECT() *MockAccessTokenMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAccessToken) Authenticate(ctx context.Context, secret string) (model.AccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, secret)
+	ret0, _ := ret[0].(model.AccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAccessTokenMockRecorder) Authenticate(ctx, secret interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAccessToken)(nil).Authenticate), ctx, secret)
+}
+
+// Create mocks base method.
+func (m *MockAccessToken) Create(ctx context.Context, userID int, input model.CreateAccessToken) (model.CreatedAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, input)
+	ret0, _ := ret[0].(model.CreatedAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAccessTokenMockRecorder) Create(ctx, userID, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAccessToken)(nil).Create), ctx, userID, input)
+}
+
+// Delete mocks base method.
+func (m *MockAccessToken) Delete(ctx context.Context, userID, tokenID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, tokenID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAccessTokenMockRecorder) Delete(ctx, userID, tokenID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAccessToken)(nil).Delete), ctx, userID, tokenID)
+}
+
+// GetAll mocks base method.
+func (m *MockAccessToken) GetAll(ctx context.Context, userID int) ([]model.AccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, userID)
+	ret0, _ := ret[0].([]model.AccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockAccessTokenMockRecorder) GetAll(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAccessToken)(nil).GetAll), ctx, userID)
+}
+
+// GetByID mocks base method.
+func (m *MockAccessToken) GetByID(ctx context.Context, userID, tokenID int) (model.AccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, userID, tokenID)
+	ret0, _ := ret[0].(model.AccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockAccessTokenMockRecorder) GetByID(ctx, userID, tokenID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAccessToken)(nil).GetByID), ctx, userID, tokenID)
+}
+
+// MockWorkspace is a mock of Workspace interface.
+type MockWorkspace struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkspaceMockRecorder
+}
+
+// MockWorkspaceMockRecorder is the mock recorder for MockWorkspace.
+type MockWorkspaceMockRecorder struct {
+	mock *MockWorkspace
+}
+
+// NewMockWorkspace creates a new mock instance.
+func NewMockWorkspace(ctrl *gomock.Controller) *MockWorkspace {
+	mock := &MockWorkspace{ctrl: ctrl}
+	mock.recorder = &MockWorkspaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWorkspace) EXPECT() *MockWorkspaceMockRecorder {
+	return m.recorder
+}
+
+// AddMember mocks base method.
+func (m *MockWorkspace) AddMember(ctx context.Context, userID, workspaceID int, input model.AddWorkspaceMember) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMember", ctx, userID, workspaceID, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMember indicates an expected call of AddMember.
+func (mr *MockWorkspaceMockRecorder) AddMember(ctx, userID, workspaceID, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMember", reflect.TypeOf((*MockWorkspace)(nil).AddMember), ctx, userID, workspaceID, input)
+}
+
+// Create mocks base method.
+func (m *MockWorkspace) Create(ctx context.Context, userID int, input model.CreateWorkspace) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, input)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWorkspaceMockRecorder) Create(ctx, userID, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWorkspace)(nil).Create), ctx, userID, input)
+}
+
+// Delete mocks base method.
+func (m *MockWorkspace) Delete(ctx context.Context, userID, workspaceID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, workspaceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWorkspaceMockRecorder) Delete(ctx, userID, workspaceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWorkspace)(nil).Delete), ctx, userID, workspaceID)
+}
+
+// GetAll mocks base method.
+func (m *MockWorkspace) GetAll(ctx context.Context, userID int) ([]model.Workspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, userID)
+	ret0, _ := ret[0].([]model.Workspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockWorkspaceMockRecorder) GetAll(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockWorkspace)(nil).GetAll), ctx, userID)
+}
+
+// GetByID mocks base method.
+func (m *MockWorkspace) GetByID(ctx context.Context, userID, workspaceID int) (model.Workspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, userID, workspaceID)
+	ret0, _ := ret[0].(model.Workspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockWorkspaceMockRecorder) GetByID(ctx, userID, workspaceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockWorkspace)(nil).GetByID), ctx, userID, workspaceID)
+}
+
+// GetMembers mocks base method.
+func (m *MockWorkspace) GetMembers(ctx context.Context, userID, workspaceID int) ([]model.WorkspaceMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMembers", ctx, userID, workspaceID)
+	ret0, _ := ret[0].([]model.WorkspaceMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembers indicates an expected call of GetMembers.
+func (mr *MockWorkspaceMockRecorder) GetMembers(ctx, userID, workspaceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembers", reflect.TypeOf((*MockWorkspace)(nil).GetMembers), ctx, userID, workspaceID)
+}
+
+// GetPersonal mocks base method.
+func (m *MockWorkspace) GetPersonal(ctx context.Context, userID int) (model.Workspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPersonal", ctx, userID)
+	ret0, _ := ret[0].(model.Workspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPersonal indicates an expected call of GetPersonal.
+func (mr *MockWorkspaceMockRecorder) GetPersonal(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPersonal", reflect.TypeOf((*MockWorkspace)(nil).GetPersonal), ctx, userID)
+}
+
+// RemoveMember mocks base method.
+func (m *MockWorkspace) RemoveMember(ctx context.Context, userID, workspaceID, memberID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, userID, workspaceID, memberID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockWorkspaceMockRecorder) RemoveMember(ctx, userID, workspaceID, memberID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockWorkspace)(nil).RemoveMember), ctx, userID, workspaceID, memberID)
+}
+
+// SetMemberRole mocks base method.
+func (m *MockWorkspace) SetMemberRole(ctx context.Context, userID, workspaceID, memberID int, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMemberRole", ctx, userID, workspaceID, memberID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMemberRole indicates an expected call of SetMemberRole.
+func (mr *MockWorkspaceMockRecorder) SetMemberRole(ctx, userID, workspaceID, memberID, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMemberRole", reflect.TypeOf((*MockWorkspace)(nil).SetMemberRole), ctx, userID, workspaceID, memberID, role)
+}
+
+// Update mocks base method.
+func (m *MockWorkspace) Update(ctx context.Context, userID, workspaceID int, input model.UpdateWorkspace) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userID, workspaceID, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockWorkspaceMockRecorder) Update(ctx, userID, workspaceID, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWorkspace)(nil).Update), ctx, userID, workspaceID, input)
+}
+
+// MockSSO is a mock of SSO interface.
+type MockSSO struct {
+	ctrl     *gomock.Controller
+	recorder *MockSSOMockRecorder
+}
+
+// MockSSOMockRecorder is the mock recorder for MockSSO.
+type MockSSOMockRecorder struct {
+	mock *MockSSO
+}
+
+// NewMockSSO creates a new mock instance.
+func NewMockSSO(ctrl *gomock.Controller) *MockSSO {
+	mock := &MockSSO{ctrl: ctrl}
+	mock.recorder = &MockSSOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSSO) EXPECT() *MockSSOMockRecorder {
+	return m.recorder
+}
+
+// OIDCCallback mocks base method.
+func (m *MockSSO) OIDCCallback(ctx context.Context, state, stateParam, code string) (model.SignInResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OIDCCallback", ctx, state, stateParam, code)
+	ret0, _ := ret[0].(model.SignInResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OIDCCallback indicates an expected call of OIDCCallback.
+func (mr *MockSSOMockRecorder) OIDCCallback(ctx, state, stateParam, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OIDCCallback", reflect.TypeOf((*MockSSO)(nil).OIDCCallback), ctx, state, stateParam, code)
+}
+
+// OIDCLogin mocks base method.
+func (m *MockSSO) OIDCLogin(ctx context.Context) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OIDCLogin", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// OIDCLogin indicates an expected call of OIDCLogin.
+func (mr *MockSSOMockRecorder) OIDCLogin(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTodoItem)(nil).Update), userID, itemID, update)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OIDCLogin", reflect.TypeOf((*MockSSO)(nil).OIDCLogin), ctx)
 }
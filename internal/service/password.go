@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/mail"
+	"github.com/Lapp-coder/todo-app/internal/model"
+)
+
+const resetTokenBytes = 32
+
+// forgotPasswordDuration is the least time a password reset request takes, so the
+// unknown emails can't be told from the registered ones by the timing.
+var forgotPasswordDuration = 500 * time.Millisecond
+
+// hashToken hashes the random tokens, which are long enough not to need a salt.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// ForgotPassword mails a single-use reset token to the account with the email. The unknown
+// emails are skipped without an error, so the response doesn't reveal the accounts.
+func (s AuthService) ForgotPassword(ctx context.Context, email string) error {
+	start := time.Now()
+	defer func() { waitUntil(ctx, start.Add(forgotPasswordDuration)) }()
+
+	user, err := s.repos.GetUser(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+
+		logError(ctx, err, ErrFailedToSendPasswordReset)
+		return ErrFailedToSendPasswordReset
+	}
+
+	b := make([]byte, resetTokenBytes)
+	if _, err = rand.Read(b); err != nil {
+		logError(ctx, err, ErrFailedToSendPasswordReset)
+		return ErrFailedToSendPasswordReset
+	}
+	token := hex.EncodeToString(b)
+
+	ttl := time.Duration(s.cfg.PasswordResetTTL) * time.Second
+	reset := model.PasswordReset{TokenHash: hashToken(token), UserID: user.ID, ExpiresAt: time.Now().Add(ttl)}
+	if err = s.repos.CreatePasswordReset(ctx, reset); err != nil {
+		logError(ctx, err, ErrFailedToSendPasswordReset)
+		return ErrFailedToSendPasswordReset
+	}
+
+	if err = s.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nuse the token to set a new password with POST %s/auth/reset-password:\n%s\n\n"+
+			"It expires in %s and works once. If you didn't ask to reset your password, ignore this email.\n",
+			user.Name, s.cfg.BaseURL, token, ttl),
+	}); err != nil {
+		logError(ctx, err, ErrFailedToSendPasswordReset)
+		return ErrFailedToSendPasswordReset
+	}
+
+	return nil
+}
+
+// ResetPassword sets the password of the account the reset token was sent to and signs
+// out all its sessions. The account is unlocked as well, the token proves the owner asked.
+func (s AuthService) ResetPassword(ctx context.Context, token, password string) error {
+	reset, err := s.repos.TakePasswordReset(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidResetToken
+		}
+
+		logError(ctx, err, ErrFailedToResetPassword)
+		return ErrFailedToResetPassword
+	}
+
+	if time.Now().After(reset.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	if err = s.repos.UpdatePassword(ctx, reset.UserID, generatePasswordHash(password, s.cfg.Salt)); err != nil {
+		logError(ctx, err, ErrFailedToResetPassword)
+		return ErrFailedToResetPassword
+	}
+
+	if err = s.repos.ResetLockout(ctx, reset.UserID); err != nil {
+		logError(ctx, err, ErrFailedToUnlockUser)
+	}
+
+	return nil
+}
+
+// ChangePassword sets the new password once the current one is confirmed. All the sessions
+// of the user are signed out, the returned token replaces the one of the caller.
+func (s AuthService) ChangePassword(ctx context.Context, userID int, currentPassword, newPassword string) (string, error) {
+	user, err := s.repos.GetUserByID(ctx, userID)
+	if err != nil {
+		logError(ctx, err, ErrFailedToChangePassword)
+		return "", ErrFailedToChangePassword
+	}
+
+	if !compareHashAndPassword(user.Password, currentPassword, s.cfg.Salt) {
+		return "", ErrIncorrectPassword
+	}
+
+	if err = s.repos.UpdatePassword(ctx, userID, generatePasswordHash(newPassword, s.cfg.Salt)); err != nil {
+		logError(ctx, err, ErrFailedToChangePassword)
+		return "", ErrFailedToChangePassword
+	}
+
+	user.TokenVersion++
+
+	return s.newToken(user)
+}
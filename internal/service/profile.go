@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"golang.org/x/text/language"
+)
+
+func profileOf(user model.User) model.Profile {
+	return model.Profile{
+		ID:               user.ID,
+		Name:             user.Name,
+		Email:            user.Email,
+		EmailVerified:    user.Verified,
+		PendingEmail:     user.PendingEmail,
+		Timezone:         user.Timezone,
+		Locale:           user.Locale,
+		TwoFactorEnabled: user.TOTPEnabled,
+	}
+}
+
+func (s AuthService) GetProfile(ctx context.Context, userID int) (model.Profile, error) {
+	user, err := s.repos.GetUserByID(ctx, userID)
+	if err != nil {
+		logError(ctx, err, ErrFailedToGetProfile)
+		return model.Profile{}, ErrFailedToGetProfile
+	}
+
+	return profileOf(user), nil
+}
+
+// UpdateProfile changes the fields set in the update. A new email needs the current password and
+// stays pending until the link mailed to it is opened, the account keeps the old one until then.
+func (s AuthService) UpdateProfile(ctx context.Context, userID int, update model.UpdateProfile) (model.Profile, error) {
+	user, err := s.repos.GetUserByID(ctx, userID)
+	if err != nil {
+		logError(ctx, err, ErrFailedToUpdateProfile)
+		return model.Profile{}, ErrFailedToUpdateProfile
+	}
+
+	if update.Name != nil {
+		user.Name = *update.Name
+	}
+
+	if update.Timezone != nil {
+		// LoadLocation takes "Local" and "" too, they'd depend on the server.
+		if _, err = time.LoadLocation(*update.Timezone); err != nil || *update.Timezone == "" || *update.Timezone == "Local" {
+			return model.Profile{}, ErrInvalidTimezone
+		}
+		user.Timezone = *update.Timezone
+	}
+
+	if update.Locale != nil {
+		tag, err := language.Parse(*update.Locale)
+		if err != nil {
+			return model.Profile{}, ErrInvalidLocale
+		}
+		user.Locale = tag.String()
+	}
+
+	// Setting the email back to the current one cancels the pending change.
+	if update.Email != nil && *update.Email == user.Email {
+		user.PendingEmail = ""
+	}
+
+	emailChanged := update.Email != nil && *update.Email != user.Email
+	if emailChanged {
+		if update.CurrentPassword == nil || !compareHashAndPassword(user.Password, *update.CurrentPassword, s.cfg.Salt) {
+			return model.Profile{}, ErrIncorrectPassword
+		}
+
+		_, err = s.repos.GetUser(ctx, *update.Email)
+		if err == nil {
+			return model.Profile{}, ErrEmailAlreadyTaken
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			logError(ctx, err, ErrFailedToUpdateProfile)
+			return model.Profile{}, ErrFailedToUpdateProfile
+		}
+
+		user.PendingEmail = *update.Email
+	}
+
+	if err = s.repos.UpdateProfile(ctx, user); err != nil {
+		logError(ctx, err, ErrFailedToUpdateProfile)
+		return model.Profile{}, ErrFailedToUpdateProfile
+	}
+
+	if emailChanged {
+		pending := user
+		pending.Email = user.PendingEmail
+		if err = s.sendVerification(ctx, pending); err != nil {
+			logError(ctx, err, ErrFailedToSendVerification)
+		}
+	}
+
+	return profileOf(user), nil
+}
+
+// DeleteAccount deletes the user with their personal workspace and tokens, the lists they created
+// in the shared workspaces go to the owners. The last owner of a shared workspace has to hand it
+// over or delete it first.
+func (s AuthService) DeleteAccount(ctx context.Context, userID int) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		workspaces, err := s.reposWorkspace.GetAll(ctx, userID)
+		if err != nil {
+			logError(ctx, err, ErrFailedToDeleteAccount)
+			return ErrFailedToDeleteAccount
+		}
+
+		for _, workspace := range workspaces {
+			if workspace.Personal || workspace.Role != model.WorkspaceOwner {
+				continue
+			}
+
+			members, err := s.reposWorkspace.GetMembers(ctx, workspace.ID)
+			if err != nil {
+				logError(ctx, err, ErrFailedToDeleteAccount)
+				return ErrFailedToDeleteAccount
+			}
+
+			if countOwners(members) == 1 {
+				return ErrLastWorkspaceOwner
+			}
+		}
+
+		if err = s.repos.DeleteUser(ctx, userID); err != nil {
+			logError(ctx, err, ErrFailedToDeleteAccount)
+			return ErrFailedToDeleteAccount
+		}
+
+		return nil
+	})
+}
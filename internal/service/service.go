@@ -1,8 +1,12 @@
 package service
 
 import (
+	"context"
+
 	"github.com/Lapp-coder/todo-app/internal/config"
+	"github.com/Lapp-coder/todo-app/internal/mail"
 	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/oidc"
 	"github.com/Lapp-coder/todo-app/internal/repository"
 )
 
@@ -14,37 +18,121 @@ var (
 )
 
 type Authorization interface {
-	CreateUser(user model.User) (int, error)
-	GenerateToken(email, password string) (string, error)
-	ParseToken(accessToken string) (int, error)
+	CreateUser(ctx context.Context, user model.User) (int, error)
+	Authenticate(ctx context.Context, email, password string) (int, error)
+	GenerateToken(ctx context.Context, email, password string) (model.SignInResult, error)
+	CompleteSignIn(ctx context.Context, challengeToken, code string) (string, error)
+	ParseToken(ctx context.Context, accessToken string) (model.Session, error)
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, password string) error
+	ChangePassword(ctx context.Context, userID int, currentPassword, newPassword string) (string, error)
+	SetupTwoFactor(ctx context.Context, userID int) (model.TwoFactorSetup, error)
+	ConfirmTwoFactor(ctx context.Context, userID int, code string) ([]string, error)
+	GetProfile(ctx context.Context, userID int) (model.Profile, error)
+	UpdateProfile(ctx context.Context, userID int, update model.UpdateProfile) (model.Profile, error)
+	DeleteAccount(ctx context.Context, userID int) error
+	GetLockouts(ctx context.Context) ([]model.Lockout, error)
+	Unlock(ctx context.Context, userID int) error
+	AuthorizeAdmin(token string) error
+	GetUsers(ctx context.Context, search model.SearchUsers) ([]model.UserSummary, error)
+	SetRole(ctx context.Context, userID int, role string) error
+	DisableUser(ctx context.Context, userID int) error
+	EnableUser(ctx context.Context, userID int) error
+	RevokeSessions(ctx context.Context, userID int) error
+	GetStats(ctx context.Context) (model.Stats, error)
 }
 
 type TodoList interface {
-	Create(userID int, list model.TodoList) (int, error)
-	GetAll(userID int) ([]model.TodoList, error)
-	GetByID(userID, listID int) (model.TodoList, error)
-	Update(userID, listID int, update model.UpdateTodoList) error
-	Delete(userID, listID int) error
+	Create(ctx context.Context, userID int, list model.TodoList) (int, error)
+	GetAll(ctx context.Context, userID int) ([]model.TodoList, error)
+	GetByID(ctx context.Context, userID, listID int) (model.TodoList, error)
+	Update(ctx context.Context, userID, listID int, update model.UpdateTodoList) error
+	Delete(ctx context.Context, userID, listID int) error
 }
 
 type TodoItem interface {
-	Create(userID, listID int, item model.TodoItem) (int, error)
-	GetAll(userID, listID int) ([]model.TodoItem, error)
-	GetByID(userID, itemID int) (model.TodoItem, error)
-	Update(userID, itemID int, update model.UpdateTodoItem) error
-	Delete(userID, itemID int) error
+	Create(ctx context.Context, userID, listID int, item model.TodoItem) (int, error)
+	GetAll(ctx context.Context, userID, listID int) ([]model.TodoItem, error)
+	GetAllByLists(ctx context.Context, userID int, listIDs []int) ([]model.TodoItem, error)
+	GetAllByUser(ctx context.Context, userID int) ([]model.TodoItem, error)
+	GetByID(ctx context.Context, userID, itemID int) (model.TodoItem, error)
+	Update(ctx context.Context, userID, itemID int, update model.UpdateTodoItem) error
+	Delete(ctx context.Context, userID, itemID int) error
+}
+
+type Calendar interface {
+	CreateFeedToken(ctx context.Context, userID int) (string, error)
+	RotateFeedToken(ctx context.Context, userID int) (string, error)
+	GetFeed(ctx context.Context, token string, events, todos bool) ([]byte, error)
+}
+
+type CalDAV interface {
+	GetObjects(ctx context.Context, userID, listID int) ([]model.CalendarObject, error)
+	GetObjectByName(ctx context.Context, userID, listID int, name string) (model.CalendarObject, error)
+	PutItem(ctx context.Context, userID, listID int, object model.CalendarObject, item model.TodoItem) (model.CalendarObject, error)
+}
+
+type Health interface {
+	Ready(ctx context.Context) (map[string]string, error)
+	ShutDown()
+}
+
+// AccessToken manages the personal access tokens of the users.
+type AccessToken interface {
+	Create(ctx context.Context, userID int, input model.CreateAccessToken) (model.CreatedAccessToken, error)
+	GetAll(ctx context.Context, userID int) ([]model.AccessToken, error)
+	GetByID(ctx context.Context, userID, tokenID int) (model.AccessToken, error)
+	Delete(ctx context.Context, userID, tokenID int) error
+	Authenticate(ctx context.Context, secret string) (model.AccessToken, error)
+}
+
+// Workspace manages the workspaces owning the lists and their members.
+type Workspace interface {
+	Create(ctx context.Context, userID int, input model.CreateWorkspace) (int, error)
+	GetAll(ctx context.Context, userID int) ([]model.Workspace, error)
+	GetByID(ctx context.Context, userID, workspaceID int) (model.Workspace, error)
+	GetPersonal(ctx context.Context, userID int) (model.Workspace, error)
+	Update(ctx context.Context, userID, workspaceID int, input model.UpdateWorkspace) error
+	Delete(ctx context.Context, userID, workspaceID int) error
+	GetMembers(ctx context.Context, userID, workspaceID int) ([]model.WorkspaceMember, error)
+	AddMember(ctx context.Context, userID, workspaceID int, input model.AddWorkspaceMember) error
+	SetMemberRole(ctx context.Context, userID, workspaceID, memberID int, role string) error
+	RemoveMember(ctx context.Context, userID, workspaceID, memberID int) error
+}
+
+// SSO signs the users in with the identity provider.
+type SSO interface {
+	// OIDCLogin returns the URL of the identity provider to send the user to, and the state
+	// the client keeps until the callback.
+	OIDCLogin(ctx context.Context) (authURL, state string, err error)
+	OIDCCallback(ctx context.Context, state, stateParam, code string) (model.SignInResult, error)
 }
 
 type Service struct {
 	Authorization
+	SSO
+	AccessToken
+	Workspace
 	TodoList
 	TodoItem
+	Calendar
+	CalDAV
+	Health
 }
 
-func New(repos *repository.Repository, cfg config.Service) *Service {
+func New(repos *repository.Repository, cfg config.Service, health Health, notifier LockoutNotifier, mailer mail.Mailer,
+	provider *oidc.Provider) *Service {
 	return &Service{
-		Authorization: NewAuthService(repos.Authorization, cfg),
-		TodoList:      NewTodoListService(repos.TodoList),
-		TodoItem:      NewTodoItemService(repos),
+		Authorization: tracedAuthorization{NewAuthService(repos, cfg, notifier, mailer)},
+		SSO:           tracedSSO{NewSSOService(repos.Authorization, repos.TxManager, cfg, provider)},
+		AccessToken:   tracedAccessToken{NewAccessTokenService(repos.AccessToken)},
+		Workspace:     tracedWorkspace{NewWorkspaceService(repos)},
+		TodoList:      tracedTodoList{NewTodoListService(repos)},
+		TodoItem:      tracedTodoItem{NewTodoItemService(repos)},
+		Calendar:      tracedCalendar{NewCalendarService(repos.Calendar)},
+		CalDAV:        tracedCalDAV{NewCalDAVService(repos)},
+		Health:        health,
 	}
 }
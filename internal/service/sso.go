@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/config"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/oidc"
+	"github.com/Lapp-coder/todo-app/internal/repository"
+	"github.com/dgrijalva/jwt-go"
+)
+
+const (
+	// oidcStateKeySuffix tells the key of the state tokens from the keys of the other tokens.
+	oidcStateKeySuffix = ":oidc-state"
+	// oidcStateTTL is the time the user has to sign in at the identity provider.
+	oidcStateTTL = 10 * time.Minute
+
+	// noPasswordHash is the password hash of the accounts created by the single sign-on,
+	// no password matches it until the user sets one with a password reset.
+	noPasswordHash = "-"
+	maxNameLength  = 30
+)
+
+// oidcStateClaims keep the state, the nonce and the PKCE verifier of a sign-in with the
+// identity provider on the client until the callback, so the server keeps no state.
+type oidcStateClaims struct {
+	jwt.StandardClaims
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+type SSOService struct {
+	auth     AuthService
+	tx       repository.TxManager
+	provider *oidc.Provider
+}
+
+// NewSSOService takes a nil provider when the single sign-on is disabled.
+func NewSSOService(repos repository.Authorization, tx repository.TxManager, cfg config.Service, provider *oidc.Provider) *SSOService {
+	return &SSOService{auth: AuthService{repos: repos, cfg: cfg}, tx: tx, provider: provider}
+}
+
+func (s SSOService) stateKey() []byte {
+	return []byte(s.auth.cfg.SigningKey + oidcStateKeySuffix)
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func (s SSOService) OIDCLogin(ctx context.Context) (string, string, error) {
+	if s.provider == nil {
+		return "", "", ErrOIDCDisabled
+	}
+
+	var claims oidcStateClaims
+	for _, v := range []*string{&claims.State, &claims.Nonce, &claims.Verifier} {
+		random, err := randomString()
+		if err != nil {
+			logError(ctx, err, ErrFailedToStartOIDCLogin)
+			return "", "", ErrFailedToStartOIDCLogin
+		}
+
+		*v = random
+	}
+
+	claims.IssuedAt = time.Now().Unix()
+	claims.ExpiresAt = time.Now().Add(oidcStateTTL).Unix()
+
+	state, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.stateKey())
+	if err != nil {
+		logError(ctx, err, ErrFailedToStartOIDCLogin)
+		return "", "", ErrFailedToStartOIDCLogin
+	}
+
+	authURL, err := s.provider.AuthCodeURL(ctx, claims.State, claims.Nonce, claims.Verifier)
+	if err != nil {
+		logError(ctx, err, ErrFailedToStartOIDCLogin)
+		return "", "", ErrFailedToStartOIDCLogin
+	}
+
+	return authURL, state, nil
+}
+
+// OIDCCallback signs in the user the identity provider sent back with the code. The account
+// with their verified email is linked on the first sign-in, or created when there's none.
+func (s SSOService) OIDCCallback(ctx context.Context, state, stateParam, code string) (model.SignInResult, error) {
+	if s.provider == nil {
+		return model.SignInResult{}, ErrOIDCDisabled
+	}
+
+	parsed, err := jwt.ParseWithClaims(state, &oidcStateClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidSigningMethod
+		}
+
+		return s.stateKey(), nil
+	})
+	if err != nil {
+		return model.SignInResult{}, ErrInvalidOIDCState
+	}
+
+	claims, ok := parsed.Claims.(*oidcStateClaims)
+	if !ok || subtle.ConstantTimeCompare([]byte(claims.State), []byte(stateParam)) != 1 {
+		return model.SignInResult{}, ErrInvalidOIDCState
+	}
+
+	identity, err := s.provider.Exchange(ctx, code, claims.Verifier)
+	if err != nil {
+		logError(ctx, err, ErrOIDCSignInFailed)
+		return model.SignInResult{}, ErrOIDCSignInFailed
+	}
+
+	if subtle.ConstantTimeCompare([]byte(identity.Nonce), []byte(claims.Nonce)) != 1 {
+		return model.SignInResult{}, ErrOIDCSignInFailed
+	}
+
+	user, err := s.userFor(ctx, identity)
+	if err != nil {
+		if errors.Is(err, ErrOIDCEmailNotVerified) || errors.Is(err, ErrOIDCAccountNotVerified) {
+			return model.SignInResult{}, err
+		}
+
+		logError(ctx, err, ErrFailedToSignInWithOIDC)
+		return model.SignInResult{}, ErrFailedToSignInWithOIDC
+	}
+
+	if user.Disabled {
+		return model.SignInResult{}, ErrAccountDisabled
+	}
+
+	return s.auth.signInResult(user)
+}
+
+// userFor returns the user linked to the identity, linking or creating one by the email. Only
+// the verified accounts are linked, otherwise whoever signed up with the email first and knows
+// the password would keep the access to the account of the owner of the email.
+func (s SSOService) userFor(ctx context.Context, identity oidc.Identity) (model.User, error) {
+	repos := s.auth.repos
+
+	user, err := repos.GetUserByIdentity(ctx, identity.Issuer, identity.Subject)
+	if err == nil {
+		return user, nil
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return model.User{}, err
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return model.User{}, ErrOIDCEmailNotVerified
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		user, err = repos.GetUser(ctx, identity.Email)
+		switch {
+		case err == nil:
+			if !user.Verified {
+				return ErrOIDCAccountNotVerified
+			}
+		case errors.Is(err, sql.ErrNoRows):
+			user = model.User{Name: displayName(identity), Email: identity.Email, Password: noPasswordHash, Verified: true}
+			if user.ID, err = repos.CreateUser(ctx, user); err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+
+		return repos.LinkIdentity(ctx, model.Identity{Issuer: identity.Issuer, Subject: identity.Subject, UserID: user.ID})
+	})
+	if err != nil {
+		return model.User{}, err
+	}
+
+	return user, nil
+}
+
+// displayName falls back to the local part of the email and fits the name into the column.
+func displayName(identity oidc.Identity) string {
+	name := strings.TrimSpace(identity.Name)
+	if name == "" {
+		name = strings.SplitN(identity.Email, "@", 2)[0]
+	}
+
+	if runes := []rune(name); len(runes) > maxNameLength {
+		name = string(runes[:maxNameLength])
+	}
+
+	return name
+}
@@ -1,92 +1,206 @@
 package service
 
 import (
+	"context"
+
+	"github.com/Lapp-coder/todo-app/internal/metrics"
 	"github.com/Lapp-coder/todo-app/internal/model"
 	"github.com/Lapp-coder/todo-app/internal/repository"
 )
 
 type TodoItemService struct {
-	repos     repository.TodoItem
-	reposList repository.TodoList
+	repos          repository.TodoItem
+	reposList      repository.TodoList
+	reposWorkspace repository.Workspace
+	tx             repository.TxManager
 }
 
 func NewTodoItemService(repos *repository.Repository) *TodoItemService {
-	return &TodoItemService{repos: repos.TodoItem, reposList: repos.TodoList}
+	return &TodoItemService{repos: repos.TodoItem, reposList: repos.TodoList, reposWorkspace: repos.Workspace, tx: repos.TxManager}
 }
 
-func (s TodoItemService) Create(userID, listID int, item model.TodoItem) (int, error) {
-	if cacheList[userID] != listID {
-		if _, err := s.reposList.GetByID(userID, listID); err != nil {
-			return 0, ErrFailedToCreateItem
-		}
+func (s TodoItemService) Create(ctx context.Context, userID, listID int, item model.TodoItem) (int, error) {
+	ctx = repository.WithUser(ctx, userID)
 
-		cacheList[userID] = listID
+	workspace, err := writableWorkspace(ctx, s.reposWorkspace, userID)
+	if err != nil {
+		return 0, err
 	}
 
-	itemID, err := s.repos.Create(listID, item)
-	if err != nil {
+	var itemID int
+
+	// The list is checked in the same transaction so it can't be deleted before the item is inserted.
+	if err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		cached := cacheList[workspace.ID] == listID
+		metrics.ObserveCacheLookup("list", cached)
+		if !cached {
+			if _, err := s.reposList.GetByID(ctx, workspace.ID, listID); err != nil {
+				logError(ctx, err, ErrFailedToCreateItem)
+				return ErrFailedToCreateItem
+			}
+
+			cacheList[workspace.ID] = listID
+		}
+
+		var err error
+		if itemID, err = s.repos.Create(ctx, listID, item); err != nil {
+			logError(ctx, err, ErrFailedToCreateItem)
+			return ErrFailedToCreateItem
+		}
+
+		return nil
+	}); err != nil {
 		return 0, err
 	}
 
-	cacheItem[userID] = itemID
+	cacheItem[workspace.ID] = itemID
+	metrics.ItemsCreated.Inc()
 
 	return itemID, nil
 }
 
-func (s TodoItemService) GetAll(userID, listID int) ([]model.TodoItem, error) {
-	if cacheList[userID] != listID {
-		if _, err := s.reposList.GetByID(userID, listID); err != nil {
+func (s TodoItemService) GetAll(ctx context.Context, userID, listID int) ([]model.TodoItem, error) {
+	ctx = repository.WithUser(ctx, userID)
+
+	workspace, err := currentWorkspace(ctx, s.reposWorkspace, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cached := cacheList[workspace.ID] == listID
+	metrics.ObserveCacheLookup("list", cached)
+	if !cached {
+		if _, err := s.reposList.GetByID(ctx, workspace.ID, listID); err != nil {
 			return nil, err
 		}
 
-		cacheList[userID] = listID
+		cacheList[workspace.ID] = listID
+	}
+
+	items, err := s.repos.GetAll(ctx, listID)
+	if err != nil {
+		logError(ctx, err, ErrFailedToGetAllItems)
+		return nil, ErrFailedToGetAllItems
+	}
+
+	return items, nil
+}
+
+func (s TodoItemService) GetAllByLists(ctx context.Context, userID int, listIDs []int) ([]model.TodoItem, error) {
+	ctx = repository.WithUser(ctx, userID)
+
+	if len(listIDs) == 0 {
+		return nil, nil
+	}
+
+	workspace, err := currentWorkspace(ctx, s.reposWorkspace, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.repos.GetAllByLists(ctx, workspace.ID, listIDs)
+	if err != nil {
+		logError(ctx, err, ErrFailedToGetAllItems)
+		return nil, ErrFailedToGetAllItems
 	}
 
-	items, err := s.repos.GetAll(listID)
+	return items, nil
+}
+
+func (s TodoItemService) GetAllByUser(ctx context.Context, userID int) ([]model.TodoItem, error) {
+	ctx = repository.WithUser(ctx, userID)
+
+	workspace, err := currentWorkspace(ctx, s.reposWorkspace, userID)
 	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.repos.GetAllByWorkspace(ctx, workspace.ID)
+	if err != nil {
+		logError(ctx, err, ErrFailedToGetAllItems)
 		return nil, ErrFailedToGetAllItems
 	}
 
 	return items, nil
 }
 
-func (s TodoItemService) GetByID(userID, itemID int) (model.TodoItem, error) {
-	item, err := s.repos.GetByID(userID, itemID)
+func (s TodoItemService) GetByID(ctx context.Context, userID, itemID int) (model.TodoItem, error) {
+	ctx = repository.WithUser(ctx, userID)
+
+	workspace, err := currentWorkspace(ctx, s.reposWorkspace, userID)
 	if err != nil {
+		return model.TodoItem{}, err
+	}
+
+	item, err := s.repos.GetByID(ctx, workspace.ID, itemID)
+	if err != nil {
+		logError(ctx, err, ErrFailedToGetItemByID)
 		return model.TodoItem{}, ErrFailedToGetItemByID
 	}
 
 	return item, nil
 }
 
-func (s TodoItemService) Update(userID, itemID int, update model.UpdateTodoItem) error {
-	if cacheItem[userID] != itemID {
-		if _, err := s.repos.GetByID(userID, itemID); err != nil {
-			return err
+func (s TodoItemService) Update(ctx context.Context, userID, itemID int, update model.UpdateTodoItem) error {
+	ctx = repository.WithUser(ctx, userID)
+
+	workspace, err := writableWorkspace(ctx, s.reposWorkspace, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		cached := cacheItem[workspace.ID] == itemID
+		metrics.ObserveCacheLookup("item", cached)
+		if !cached {
+			if _, err := s.repos.GetByID(ctx, workspace.ID, itemID); err != nil {
+				return err
+			}
+
+			cacheItem[workspace.ID] = itemID
 		}
 
-		cacheItem[userID] = itemID
+		if err := s.repos.Update(ctx, itemID, update); err != nil {
+			logError(ctx, err, ErrFailedToUpdateItem)
+			return ErrFailedToUpdateItem
+		}
+
+		return nil
+	}); err != nil {
+		return err
 	}
 
-	if err := s.repos.Update(itemID, update); err != nil {
-		return ErrFailedToUpdateItem
+	if update.Done != nil && *update.Done {
+		metrics.ItemsCompleted.Inc()
 	}
 
 	return nil
 }
 
-func (s TodoItemService) Delete(userID, itemID int) error {
-	if cacheItem[userID] != itemID {
-		if _, err := s.repos.GetByID(userID, itemID); err != nil {
-			return err
-		}
+func (s TodoItemService) Delete(ctx context.Context, userID, itemID int) error {
+	ctx = repository.WithUser(ctx, userID)
+
+	workspace, err := writableWorkspace(ctx, s.reposWorkspace, userID)
+	if err != nil {
+		return err
 	}
 
-	delete(cacheItem, userID)
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		cached := cacheItem[workspace.ID] == itemID
+		metrics.ObserveCacheLookup("item", cached)
+		if !cached {
+			if _, err := s.repos.GetByID(ctx, workspace.ID, itemID); err != nil {
+				return err
+			}
+		}
 
-	if err := s.repos.Delete(itemID); err != nil {
-		return ErrFailedToDeleteItem
-	}
+		delete(cacheItem, workspace.ID)
 
-	return nil
+		if err := s.repos.Delete(ctx, itemID); err != nil {
+			logError(ctx, err, ErrFailedToDeleteItem)
+			return ErrFailedToDeleteItem
+		}
+
+		return nil
+	})
 }
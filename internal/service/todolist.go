@@ -1,76 +1,129 @@
 package service
 
 import (
+	"context"
+
+	"github.com/Lapp-coder/todo-app/internal/metrics"
 	"github.com/Lapp-coder/todo-app/internal/model"
 	"github.com/Lapp-coder/todo-app/internal/repository"
 )
 
 type TodoListService struct {
-	repos repository.TodoList
+	repos          repository.TodoList
+	reposWorkspace repository.Workspace
+	tx             repository.TxManager
 }
 
-func NewTodoListService(repos repository.TodoList) *TodoListService {
-	return &TodoListService{repos: repos}
+func NewTodoListService(repos *repository.Repository) *TodoListService {
+	return &TodoListService{repos: repos.TodoList, reposWorkspace: repos.Workspace, tx: repos.TxManager}
 }
 
-func (s TodoListService) Create(userID int, list model.TodoList) (int, error) {
-	listID, err := s.repos.Create(userID, list)
+func (s TodoListService) Create(ctx context.Context, userID int, list model.TodoList) (int, error) {
+	ctx = repository.WithUser(ctx, userID)
+
+	workspace, err := writableWorkspace(ctx, s.reposWorkspace, userID)
 	if err != nil {
+		return 0, err
+	}
+
+	listID, err := s.repos.Create(ctx, workspace.ID, userID, list)
+	if err != nil {
+		logError(ctx, err, ErrFailedToCreateList)
 		return 0, ErrFailedToCreateList
 	}
 
-	cacheList[userID] = listID
+	cacheList[workspace.ID] = listID
 
 	return listID, nil
 }
 
-func (s TodoListService) GetAll(userID int) ([]model.TodoList, error) {
-	lists, err := s.repos.GetAll(userID)
+func (s TodoListService) GetAll(ctx context.Context, userID int) ([]model.TodoList, error) {
+	ctx = repository.WithUser(ctx, userID)
+
+	workspace, err := currentWorkspace(ctx, s.reposWorkspace, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	lists, err := s.repos.GetAll(ctx, workspace.ID)
 	if err != nil {
+		logError(ctx, err, ErrFailedToGetAllLists)
 		return nil, ErrFailedToGetAllLists
 	}
 
 	return lists, nil
 }
 
-func (s TodoListService) GetByID(userID, listID int) (model.TodoList, error) {
-	list, err := s.repos.GetByID(userID, listID)
+func (s TodoListService) GetByID(ctx context.Context, userID, listID int) (model.TodoList, error) {
+	ctx = repository.WithUser(ctx, userID)
+
+	workspace, err := currentWorkspace(ctx, s.reposWorkspace, userID)
 	if err != nil {
+		return model.TodoList{}, err
+	}
+
+	list, err := s.repos.GetByID(ctx, workspace.ID, listID)
+	if err != nil {
+		logError(ctx, err, ErrFailedToGetListByID)
 		return model.TodoList{}, ErrFailedToGetListByID
 	}
 
 	return list, err
 }
 
-func (s TodoListService) Update(userID, listID int, update model.UpdateTodoList) error {
-	if cacheList[userID] != listID {
-		if _, err := s.GetByID(userID, listID); err != nil {
-			return err
-		}
+func (s TodoListService) Update(ctx context.Context, userID, listID int, update model.UpdateTodoList) error {
+	ctx = repository.WithUser(ctx, userID)
 
-		cacheList[userID] = listID
+	workspace, err := writableWorkspace(ctx, s.reposWorkspace, userID)
+	if err != nil {
+		return err
 	}
 
-	if err := s.repos.Update(userID, update); err != nil {
-		return ErrFailedToUpdateList
-	}
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		cached := cacheList[workspace.ID] == listID
+		metrics.ObserveCacheLookup("list", cached)
+		if !cached {
+			if _, err := s.GetByID(ctx, userID, listID); err != nil {
+				return err
+			}
 
-	return nil
-}
+			cacheList[workspace.ID] = listID
+		}
 
-func (s TodoListService) Delete(userID, listID int) error {
-	if cacheList[userID] != listID {
-		if _, err := s.repos.GetByID(userID, listID); err != nil {
-			return err
+		if err := s.repos.Update(ctx, workspace.ID, listID, update); err != nil {
+			logError(ctx, err, ErrFailedToUpdateList)
+			return ErrFailedToUpdateList
 		}
-	}
 
-	delete(cacheList, userID)
-	delete(cacheItem, userID)
+		return nil
+	})
+}
+
+func (s TodoListService) Delete(ctx context.Context, userID, listID int) error {
+	ctx = repository.WithUser(ctx, userID)
 
-	if err := s.repos.Delete(listID); err != nil {
-		return ErrFailedToDeleteList
+	workspace, err := writableWorkspace(ctx, s.reposWorkspace, userID)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		cached := cacheList[workspace.ID] == listID
+		metrics.ObserveCacheLookup("list", cached)
+		if !cached {
+			if _, err := s.repos.GetByID(ctx, workspace.ID, listID); err != nil {
+				return err
+			}
+		}
+
+		delete(cacheList, workspace.ID)
+		delete(cacheItem, workspace.ID)
+
+		if err := s.repos.Delete(ctx, listID); err != nil {
+			logError(ctx, err, ErrFailedToDeleteList)
+			return ErrFailedToDeleteList
+		}
+
+		return nil
+	})
 }
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/repository"
+)
+
+const (
+	// AccessTokenPrefix tells the personal access tokens from the session tokens.
+	AccessTokenPrefix = "todo_pat_"
+	accessTokenBytes  = 20
+
+	// lastUsedPrecision limits the writes of the last-used time to one a minute per token.
+	lastUsedPrecision = time.Minute
+)
+
+type AccessTokenService struct {
+	repos repository.AccessToken
+}
+
+func NewAccessTokenService(repos repository.AccessToken) *AccessTokenService {
+	return &AccessTokenService{repos: repos}
+}
+
+// Create returns the new token along with its details, the token can't be read again.
+func (s AccessTokenService) Create(ctx context.Context, userID int, input model.CreateAccessToken) (model.CreatedAccessToken, error) {
+	b := make([]byte, accessTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		logError(ctx, err, ErrFailedToCreateAccessToken)
+		return model.CreatedAccessToken{}, ErrFailedToCreateAccessToken
+	}
+	secret := AccessTokenPrefix + hex.EncodeToString(b)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	token := model.AccessToken{
+		UserID:    userID,
+		Name:      input.Name,
+		TokenHash: hashToken(secret),
+		Scopes:    uniqueScopes(input.Scopes),
+		CreatedAt: now,
+	}
+	if input.ExpiresInDays > 0 {
+		expiresAt := now.AddDate(0, 0, input.ExpiresInDays)
+		token.ExpiresAt = &expiresAt
+	}
+
+	id, err := s.repos.Create(ctx, token)
+	if err != nil {
+		logError(ctx, err, ErrFailedToCreateAccessToken)
+		return model.CreatedAccessToken{}, ErrFailedToCreateAccessToken
+	}
+	token.ID = id
+
+	return model.CreatedAccessToken{AccessToken: token, Token: secret}, nil
+}
+
+func uniqueScopes(scopes []string) model.Scopes {
+	unique := make(model.Scopes, 0, len(scopes))
+	for _, scope := range scopes {
+		if !unique.Has(scope) {
+			unique = append(unique, scope)
+		}
+	}
+
+	return unique
+}
+
+func (s AccessTokenService) GetAll(ctx context.Context, userID int) ([]model.AccessToken, error) {
+	tokens, err := s.repos.GetAll(ctx, userID)
+	if err != nil {
+		logError(ctx, err, ErrFailedToGetAccessTokens)
+		return nil, ErrFailedToGetAccessTokens
+	}
+
+	return tokens, nil
+}
+
+func (s AccessTokenService) GetByID(ctx context.Context, userID, tokenID int) (model.AccessToken, error) {
+	token, err := s.repos.GetByID(ctx, userID, tokenID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.AccessToken{}, ErrAccessTokenNotFound
+		}
+
+		logError(ctx, err, ErrFailedToGetAccessToken)
+		return model.AccessToken{}, ErrFailedToGetAccessToken
+	}
+
+	return token, nil
+}
+
+func (s AccessTokenService) Delete(ctx context.Context, userID, tokenID int) error {
+	if err := s.repos.Delete(ctx, userID, tokenID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrAccessTokenNotFound
+		}
+
+		logError(ctx, err, ErrFailedToDeleteAccessToken)
+		return ErrFailedToDeleteAccessToken
+	}
+
+	return nil
+}
+
+// Authenticate returns the token, unless it's unknown or expired, and records its use.
+func (s AccessTokenService) Authenticate(ctx context.Context, secret string) (model.AccessToken, error) {
+	if !strings.HasPrefix(secret, AccessTokenPrefix) {
+		return model.AccessToken{}, ErrInvalidAccessToken
+	}
+
+	token, err := s.repos.GetByHash(ctx, hashToken(secret))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.AccessToken{}, ErrInvalidAccessToken
+		}
+
+		logError(ctx, err, ErrFailedToCheckAccessToken)
+		return model.AccessToken{}, ErrFailedToCheckAccessToken
+	}
+
+	now := time.Now()
+	if token.ExpiresAt != nil && !now.Before(*token.ExpiresAt) {
+		return model.AccessToken{}, ErrInvalidAccessToken
+	}
+
+	// A failed write of the last-used time doesn't keep the token from working.
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedPrecision {
+		usedAt := now.UTC().Truncate(time.Second)
+		if err = s.repos.UpdateLastUsed(ctx, token.ID, usedAt); err != nil {
+			logError(ctx, err, ErrFailedToCheckAccessToken)
+		} else {
+			token.LastUsedAt = &usedAt
+		}
+	}
+
+	return token, nil
+}
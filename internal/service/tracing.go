@@ -0,0 +1,499 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// The traced services wrap every method of a service in a span named after
+// the interface and the method, e.g. TodoItem.Create.
+
+const userIDKey = attribute.Key("user.id")
+
+type tracedAuthorization struct {
+	next Authorization
+}
+
+func (s tracedAuthorization) CreateUser(ctx context.Context, user model.User) (id int, err error) {
+	ctx, span := tracing.Start(ctx, "Authorization.CreateUser")
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.CreateUser(ctx, user)
+}
+
+func (s tracedAuthorization) Authenticate(ctx context.Context, email, password string) (id int, err error) {
+	ctx, span := tracing.Start(ctx, "Authorization.Authenticate")
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Authenticate(ctx, email, password)
+}
+
+func (s tracedAuthorization) GenerateToken(ctx context.Context, email, password string) (result model.SignInResult, err error) {
+	ctx, span := tracing.Start(ctx, "Authorization.GenerateToken")
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.GenerateToken(ctx, email, password)
+}
+
+func (s tracedAuthorization) ParseToken(ctx context.Context, accessToken string) (session model.Session, err error) {
+	ctx, span := tracing.Start(ctx, "Authorization.ParseToken")
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.ParseToken(ctx, accessToken)
+}
+
+func (s tracedAuthorization) VerifyEmail(ctx context.Context, token string) (err error) {
+	ctx, span := tracing.Start(ctx, "Authorization.VerifyEmail")
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.VerifyEmail(ctx, token)
+}
+
+func (s tracedAuthorization) ResendVerification(ctx context.Context, email string) (err error) {
+	ctx, span := tracing.Start(ctx, "Authorization.ResendVerification")
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.ResendVerification(ctx, email)
+}
+
+func (s tracedAuthorization) ForgotPassword(ctx context.Context, email string) (err error) {
+	ctx, span := tracing.Start(ctx, "Authorization.ForgotPassword")
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.ForgotPassword(ctx, email)
+}
+
+func (s tracedAuthorization) ResetPassword(ctx context.Context, token, password string) (err error) {
+	ctx, span := tracing.Start(ctx, "Authorization.ResetPassword")
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.ResetPassword(ctx, token, password)
+}
+
+func (s tracedAuthorization) ChangePassword(ctx context.Context, userID int, currentPassword, newPassword string) (token string, err error) {
+	ctx, span := tracing.Start(ctx, "Authorization.ChangePassword")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.ChangePassword(ctx, userID, currentPassword, newPassword)
+}
+
+func (s tracedAuthorization) CompleteSignIn(ctx context.Context, challengeToken, code string) (token string, err error) {
+	ctx, span := tracing.Start(ctx, "Authorization.CompleteSignIn")
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.CompleteSignIn(ctx, challengeToken, code)
+}
+
+func (s tracedAuthorization) SetupTwoFactor(ctx context.Context, userID int) (setup model.TwoFactorSetup, err error) {
+	ctx, span := tracing.Start(ctx, "Authorization.SetupTwoFactor")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.SetupTwoFactor(ctx, userID)
+}
+
+func (s tracedAuthorization) ConfirmTwoFactor(ctx context.Context, userID int, code string) (codes []string, err error) {
+	ctx, span := tracing.Start(ctx, "Authorization.ConfirmTwoFactor")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.ConfirmTwoFactor(ctx, userID, code)
+}
+
+func (s tracedAuthorization) GetProfile(ctx context.Context, userID int) (profile model.Profile, err error) {
+	ctx, span := tracing.Start(ctx, "Authorization.GetProfile")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.GetProfile(ctx, userID)
+}
+
+func (s tracedAuthorization) UpdateProfile(ctx context.Context, userID int, update model.UpdateProfile) (profile model.Profile, err error) {
+	ctx, span := tracing.Start(ctx, "Authorization.UpdateProfile")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.UpdateProfile(ctx, userID, update)
+}
+
+func (s tracedAuthorization) DeleteAccount(ctx context.Context, userID int) (err error) {
+	ctx, span := tracing.Start(ctx, "Authorization.DeleteAccount")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.DeleteAccount(ctx, userID)
+}
+
+func (s tracedAuthorization) GetLockouts(ctx context.Context) (lockouts []model.Lockout, err error) {
+	ctx, span := tracing.Start(ctx, "Authorization.GetLockouts")
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.GetLockouts(ctx)
+}
+
+func (s tracedAuthorization) Unlock(ctx context.Context, userID int) (err error) {
+	ctx, span := tracing.Start(ctx, "Authorization.Unlock")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Unlock(ctx, userID)
+}
+
+func (s tracedAuthorization) AuthorizeAdmin(token string) error {
+	return s.next.AuthorizeAdmin(token)
+}
+
+func (s tracedAuthorization) GetUsers(ctx context.Context, search model.SearchUsers) (users []model.UserSummary, err error) {
+	ctx, span := tracing.Start(ctx, "Authorization.GetUsers")
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.GetUsers(ctx, search)
+}
+
+func (s tracedAuthorization) SetRole(ctx context.Context, userID int, role string) (err error) {
+	ctx, span := tracing.Start(ctx, "Authorization.SetRole")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.SetRole(ctx, userID, role)
+}
+
+func (s tracedAuthorization) DisableUser(ctx context.Context, userID int) (err error) {
+	ctx, span := tracing.Start(ctx, "Authorization.DisableUser")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.DisableUser(ctx, userID)
+}
+
+func (s tracedAuthorization) EnableUser(ctx context.Context, userID int) (err error) {
+	ctx, span := tracing.Start(ctx, "Authorization.EnableUser")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.EnableUser(ctx, userID)
+}
+
+func (s tracedAuthorization) RevokeSessions(ctx context.Context, userID int) (err error) {
+	ctx, span := tracing.Start(ctx, "Authorization.RevokeSessions")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.RevokeSessions(ctx, userID)
+}
+
+func (s tracedAuthorization) GetStats(ctx context.Context) (stats model.Stats, err error) {
+	ctx, span := tracing.Start(ctx, "Authorization.GetStats")
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.GetStats(ctx)
+}
+
+type tracedSSO struct {
+	next SSO
+}
+
+func (s tracedSSO) OIDCLogin(ctx context.Context) (authURL, state string, err error) {
+	ctx, span := tracing.Start(ctx, "SSO.OIDCLogin")
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.OIDCLogin(ctx)
+}
+
+func (s tracedSSO) OIDCCallback(ctx context.Context, state, stateParam, code string) (result model.SignInResult, err error) {
+	ctx, span := tracing.Start(ctx, "SSO.OIDCCallback")
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.OIDCCallback(ctx, state, stateParam, code)
+}
+
+type tracedAccessToken struct {
+	next AccessToken
+}
+
+func (s tracedAccessToken) Create(ctx context.Context, userID int, input model.CreateAccessToken) (token model.CreatedAccessToken, err error) {
+	ctx, span := tracing.Start(ctx, "AccessToken.Create")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Create(ctx, userID, input)
+}
+
+func (s tracedAccessToken) GetAll(ctx context.Context, userID int) (tokens []model.AccessToken, err error) {
+	ctx, span := tracing.Start(ctx, "AccessToken.GetAll")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.GetAll(ctx, userID)
+}
+
+func (s tracedAccessToken) GetByID(ctx context.Context, userID, tokenID int) (token model.AccessToken, err error) {
+	ctx, span := tracing.Start(ctx, "AccessToken.GetByID")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.GetByID(ctx, userID, tokenID)
+}
+
+func (s tracedAccessToken) Delete(ctx context.Context, userID, tokenID int) (err error) {
+	ctx, span := tracing.Start(ctx, "AccessToken.Delete")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Delete(ctx, userID, tokenID)
+}
+
+func (s tracedAccessToken) Authenticate(ctx context.Context, secret string) (token model.AccessToken, err error) {
+	ctx, span := tracing.Start(ctx, "AccessToken.Authenticate")
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Authenticate(ctx, secret)
+}
+
+type tracedWorkspace struct {
+	next Workspace
+}
+
+func (s tracedWorkspace) Create(ctx context.Context, userID int, input model.CreateWorkspace) (id int, err error) {
+	ctx, span := tracing.Start(ctx, "Workspace.Create")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Create(ctx, userID, input)
+}
+
+func (s tracedWorkspace) GetAll(ctx context.Context, userID int) (workspaces []model.Workspace, err error) {
+	ctx, span := tracing.Start(ctx, "Workspace.GetAll")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.GetAll(ctx, userID)
+}
+
+func (s tracedWorkspace) GetByID(ctx context.Context, userID, workspaceID int) (workspace model.Workspace, err error) {
+	ctx, span := tracing.Start(ctx, "Workspace.GetByID")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.GetByID(ctx, userID, workspaceID)
+}
+
+func (s tracedWorkspace) GetPersonal(ctx context.Context, userID int) (workspace model.Workspace, err error) {
+	ctx, span := tracing.Start(ctx, "Workspace.GetPersonal")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.GetPersonal(ctx, userID)
+}
+
+func (s tracedWorkspace) Update(ctx context.Context, userID, workspaceID int, input model.UpdateWorkspace) (err error) {
+	ctx, span := tracing.Start(ctx, "Workspace.Update")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Update(ctx, userID, workspaceID, input)
+}
+
+func (s tracedWorkspace) Delete(ctx context.Context, userID, workspaceID int) (err error) {
+	ctx, span := tracing.Start(ctx, "Workspace.Delete")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Delete(ctx, userID, workspaceID)
+}
+
+func (s tracedWorkspace) GetMembers(ctx context.Context, userID, workspaceID int) (members []model.WorkspaceMember, err error) {
+	ctx, span := tracing.Start(ctx, "Workspace.GetMembers")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.GetMembers(ctx, userID, workspaceID)
+}
+
+func (s tracedWorkspace) AddMember(ctx context.Context, userID, workspaceID int, input model.AddWorkspaceMember) (err error) {
+	ctx, span := tracing.Start(ctx, "Workspace.AddMember")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.AddMember(ctx, userID, workspaceID, input)
+}
+
+func (s tracedWorkspace) SetMemberRole(ctx context.Context, userID, workspaceID, memberID int, role string) (err error) {
+	ctx, span := tracing.Start(ctx, "Workspace.SetMemberRole")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.SetMemberRole(ctx, userID, workspaceID, memberID, role)
+}
+
+func (s tracedWorkspace) RemoveMember(ctx context.Context, userID, workspaceID, memberID int) (err error) {
+	ctx, span := tracing.Start(ctx, "Workspace.RemoveMember")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.RemoveMember(ctx, userID, workspaceID, memberID)
+}
+
+type tracedTodoList struct {
+	next TodoList
+}
+
+func (s tracedTodoList) Create(ctx context.Context, userID int, list model.TodoList) (id int, err error) {
+	ctx, span := tracing.Start(ctx, "TodoList.Create")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Create(ctx, userID, list)
+}
+
+func (s tracedTodoList) GetAll(ctx context.Context, userID int) (lists []model.TodoList, err error) {
+	ctx, span := tracing.Start(ctx, "TodoList.GetAll")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.GetAll(ctx, userID)
+}
+
+func (s tracedTodoList) GetByID(ctx context.Context, userID, listID int) (list model.TodoList, err error) {
+	ctx, span := tracing.Start(ctx, "TodoList.GetByID")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.GetByID(ctx, userID, listID)
+}
+
+func (s tracedTodoList) Update(ctx context.Context, userID, listID int, update model.UpdateTodoList) (err error) {
+	ctx, span := tracing.Start(ctx, "TodoList.Update")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Update(ctx, userID, listID, update)
+}
+
+func (s tracedTodoList) Delete(ctx context.Context, userID, listID int) (err error) {
+	ctx, span := tracing.Start(ctx, "TodoList.Delete")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Delete(ctx, userID, listID)
+}
+
+type tracedTodoItem struct {
+	next TodoItem
+}
+
+func (s tracedTodoItem) Create(ctx context.Context, userID, listID int, item model.TodoItem) (id int, err error) {
+	ctx, span := tracing.Start(ctx, "TodoItem.Create")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Create(ctx, userID, listID, item)
+}
+
+func (s tracedTodoItem) GetAll(ctx context.Context, userID, listID int) (items []model.TodoItem, err error) {
+	ctx, span := tracing.Start(ctx, "TodoItem.GetAll")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.GetAll(ctx, userID, listID)
+}
+
+func (s tracedTodoItem) GetAllByLists(ctx context.Context, userID int, listIDs []int) (items []model.TodoItem, err error) {
+	ctx, span := tracing.Start(ctx, "TodoItem.GetAllByLists")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.GetAllByLists(ctx, userID, listIDs)
+}
+
+func (s tracedTodoItem) GetAllByUser(ctx context.Context, userID int) (items []model.TodoItem, err error) {
+	ctx, span := tracing.Start(ctx, "TodoItem.GetAllByUser")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.GetAllByUser(ctx, userID)
+}
+
+func (s tracedTodoItem) GetByID(ctx context.Context, userID, itemID int) (item model.TodoItem, err error) {
+	ctx, span := tracing.Start(ctx, "TodoItem.GetByID")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.GetByID(ctx, userID, itemID)
+}
+
+func (s tracedTodoItem) Update(ctx context.Context, userID, itemID int, update model.UpdateTodoItem) (err error) {
+	ctx, span := tracing.Start(ctx, "TodoItem.Update")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Update(ctx, userID, itemID, update)
+}
+
+func (s tracedTodoItem) Delete(ctx context.Context, userID, itemID int) (err error) {
+	ctx, span := tracing.Start(ctx, "TodoItem.Delete")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Delete(ctx, userID, itemID)
+}
+
+type tracedCalendar struct {
+	next Calendar
+}
+
+func (s tracedCalendar) CreateFeedToken(ctx context.Context, userID int) (token string, err error) {
+	ctx, span := tracing.Start(ctx, "Calendar.CreateFeedToken")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.CreateFeedToken(ctx, userID)
+}
+
+func (s tracedCalendar) RotateFeedToken(ctx context.Context, userID int) (token string, err error) {
+	ctx, span := tracing.Start(ctx, "Calendar.RotateFeedToken")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.RotateFeedToken(ctx, userID)
+}
+
+func (s tracedCalendar) GetFeed(ctx context.Context, token string, events, todos bool) (feed []byte, err error) {
+	ctx, span := tracing.Start(ctx, "Calendar.GetFeed")
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.GetFeed(ctx, token, events, todos)
+}
+
+type tracedCalDAV struct {
+	next CalDAV
+}
+
+func (s tracedCalDAV) GetObjects(ctx context.Context, userID, listID int) (objects []model.CalendarObject, err error) {
+	ctx, span := tracing.Start(ctx, "CalDAV.GetObjects")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.GetObjects(ctx, userID, listID)
+}
+
+func (s tracedCalDAV) GetObjectByName(ctx context.Context, userID, listID int, name string) (object model.CalendarObject, err error) {
+	ctx, span := tracing.Start(ctx, "CalDAV.GetObjectByName")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.GetObjectByName(ctx, userID, listID, name)
+}
+
+func (s tracedCalDAV) PutItem(ctx context.Context, userID, listID int, object model.CalendarObject,
+	item model.TodoItem) (saved model.CalendarObject, err error) {
+	ctx, span := tracing.Start(ctx, "CalDAV.PutItem")
+	span.SetAttributes(userIDKey.Int(userID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.PutItem(ctx, userID, listID, object, item)
+}
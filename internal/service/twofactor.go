@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/totp"
+	"github.com/dgrijalva/jwt-go"
+)
+
+const (
+	totpIssuer = "todo-app"
+	// totpSkew is the steps before and after the current one the codes are accepted for,
+	// so the clocks of the devices may be off by one step.
+	totpSkew = 1
+
+	recoveryCodeCount = 10
+	recoveryCodeBytes = 5
+
+	// challengeKeySuffix tells the key of the challenge tokens from the key of the access
+	// tokens, so the challenge can't be used as an access token.
+	challengeKeySuffix = ":2fa-challenge"
+)
+
+// challengeClaims are the claims of the token returned by the sign-in with the password
+// of an account with the two-factor authentication, exchanged with a code for the access token.
+type challengeClaims struct {
+	jwt.StandardClaims
+	UserID  int `json:"user_id"`
+	Version int `json:"ver"`
+}
+
+func (s AuthService) challengeKey() []byte {
+	return []byte(s.cfg.SigningKey + challengeKeySuffix)
+}
+
+func (s AuthService) newChallenge(user model.User) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, challengeClaims{
+		jwt.StandardClaims{
+			IssuedAt:  time.Now().Unix(),
+			ExpiresAt: time.Now().Add(time.Second * time.Duration(s.cfg.TwoFactorChallengeTTL)).Unix(),
+		},
+		user.ID,
+		user.TokenVersion,
+	}).SignedString(s.challengeKey())
+}
+
+// hashRecoveryCode ignores the case and the dashes, the codes are shown as xxxxx-xxxxx.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func generateRecoveryCodes() (codes, hashes []string, err error) {
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, recoveryCodeBytes)
+		if _, err = rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+
+		code := hex.EncodeToString(b)
+		codes = append(codes, code[:len(code)/2]+"-"+code[len(code)/2:])
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// SetupTwoFactor generates a new secret for the user, it's only checked at the sign-in once
+// a code confirms the user has added it to their app.
+func (s AuthService) SetupTwoFactor(ctx context.Context, userID int) (model.TwoFactorSetup, error) {
+	user, err := s.repos.GetUserByID(ctx, userID)
+	if err != nil {
+		logError(ctx, err, ErrFailedToSetUpTwoFactor)
+		return model.TwoFactorSetup{}, ErrFailedToSetUpTwoFactor
+	}
+
+	if user.TOTPEnabled {
+		return model.TwoFactorSetup{}, ErrTwoFactorEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		logError(ctx, err, ErrFailedToSetUpTwoFactor)
+		return model.TwoFactorSetup{}, ErrFailedToSetUpTwoFactor
+	}
+
+	if err = s.repos.SetTOTPSecret(ctx, userID, secret); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.TwoFactorSetup{}, ErrTwoFactorEnabled
+		}
+
+		logError(ctx, err, ErrFailedToSetUpTwoFactor)
+		return model.TwoFactorSetup{}, ErrFailedToSetUpTwoFactor
+	}
+
+	return model.TwoFactorSetup{Secret: secret, URI: totp.URI(totpIssuer, user.Email, secret)}, nil
+}
+
+// ConfirmTwoFactor enables the two-factor authentication with a code of the secret set up
+// and returns the recovery codes. Only their hashes are stored, they can't be shown again.
+func (s AuthService) ConfirmTwoFactor(ctx context.Context, userID int, code string) ([]string, error) {
+	user, err := s.repos.GetUserByID(ctx, userID)
+	if err != nil {
+		logError(ctx, err, ErrFailedToEnableTwoFactor)
+		return nil, ErrFailedToEnableTwoFactor
+	}
+
+	if user.TOTPEnabled {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	if user.TOTPSecret == "" {
+		return nil, ErrTwoFactorNotSetUp
+	}
+
+	step, ok := totp.Validate(user.TOTPSecret, code, time.Now(), totpSkew)
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		logError(ctx, err, ErrFailedToEnableTwoFactor)
+		return nil, ErrFailedToEnableTwoFactor
+	}
+
+	if err = s.repos.EnableTOTP(ctx, userID, step, hashes); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTwoFactorEnabled
+		}
+
+		logError(ctx, err, ErrFailedToEnableTwoFactor)
+		return nil, ErrFailedToEnableTwoFactor
+	}
+
+	return codes, nil
+}
+
+// CompleteSignIn exchanges the challenge token and a code from the app, or a recovery code,
+// for the access token. The wrong codes count as failed sign-ins.
+func (s AuthService) CompleteSignIn(ctx context.Context, challengeToken, code string) (string, error) {
+	start := time.Now()
+
+	user, err := s.completeSignIn(ctx, challengeToken, code)
+	if err != nil {
+		waitUntil(ctx, start.Add(failedSignInDuration))
+		return "", err
+	}
+
+	return s.newToken(user)
+}
+
+func (s AuthService) completeSignIn(ctx context.Context, challengeToken, code string) (model.User, error) {
+	parsed, err := jwt.ParseWithClaims(challengeToken, &challengeClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidSigningMethod
+		}
+
+		return s.challengeKey(), nil
+	})
+	if err != nil {
+		return model.User{}, ErrInvalidChallengeToken
+	}
+
+	claims, ok := parsed.Claims.(*challengeClaims)
+	if !ok {
+		return model.User{}, ErrInvalidChallengeToken
+	}
+
+	user, err := s.repos.GetUserByID(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.User{}, ErrInvalidChallengeToken
+		}
+
+		logError(ctx, err, ErrFailedToCompleteSignIn)
+		return model.User{}, ErrFailedToCompleteSignIn
+	}
+
+	// The challenges issued before a password change are revoked with the sessions.
+	if !user.TOTPEnabled || user.TokenVersion != claims.Version {
+		return model.User{}, ErrInvalidChallengeToken
+	}
+
+	if s.locked(user) {
+		return model.User{}, ErrAccountLocked
+	}
+
+	if err = s.useSecondFactor(ctx, user, code); err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			s.recordFailedSignIn(ctx, user)
+		}
+
+		return model.User{}, err
+	}
+
+	if user.FailedLogins > 0 || user.Lockouts > 0 {
+		if err = s.repos.ResetLockout(ctx, user.ID); err != nil {
+			logError(ctx, err, ErrFailedToUnlockUser)
+		}
+	}
+
+	return user, nil
+}
+
+// useSecondFactor takes the codes of the app by their length, anything else is tried as
+// a recovery code. Both can only be used once.
+func (s AuthService) useSecondFactor(ctx context.Context, user model.User, code string) error {
+	var err error
+	if len(code) == totp.Digits {
+		step, ok := totp.Validate(user.TOTPSecret, code, time.Now(), totpSkew)
+		if !ok {
+			return ErrInvalidTwoFactorCode
+		}
+
+		err = s.repos.UseTOTPStep(ctx, user.ID, step)
+	} else {
+		err = s.repos.UseRecoveryCode(ctx, user.ID, hashRecoveryCode(code))
+	}
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidTwoFactorCode
+		}
+
+		logError(ctx, err, ErrFailedToCompleteSignIn)
+		return ErrFailedToCompleteSignIn
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Lapp-coder/todo-app/internal/mail"
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/dgrijalva/jwt-go"
+)
+
+const (
+	verifyEmailPath = "/auth/verify"
+	// verificationKeySuffix tells the key of the verification tokens from the key of the
+	// access tokens, so neither can be passed for the other.
+	verificationKeySuffix = ":verify-email"
+)
+
+type verificationClaims struct {
+	jwt.StandardClaims
+	UserID int    `json:"user_id"`
+	Email  string `json:"email"`
+}
+
+func (s AuthService) verificationKey() []byte {
+	return []byte(s.cfg.SigningKey + verificationKeySuffix)
+}
+
+// sendVerification mails a link verifying the email of the user to it, a pending email is
+// passed as the email of the user.
+func (s AuthService) sendVerification(ctx context.Context, user model.User) error {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, verificationClaims{
+		jwt.StandardClaims{
+			IssuedAt:  time.Now().Unix(),
+			ExpiresAt: time.Now().Add(time.Second * time.Duration(s.cfg.VerificationTTL)).Unix(),
+		},
+		user.ID,
+		user.Email,
+	}).SignedString(s.verificationKey())
+	if err != nil {
+		return err
+	}
+
+	link := strings.TrimRight(s.cfg.BaseURL, "/") + verifyEmailPath + "?token=" + url.QueryEscape(token)
+
+	return s.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf("Hi %s,\n\nopen the link to verify your email:\n%s\n\n"+
+			"If you didn't sign up for todo-app, ignore this email.\n", user.Name, link),
+	})
+}
+
+func (s AuthService) VerifyEmail(ctx context.Context, token string) error {
+	parsed, err := jwt.ParseWithClaims(token, &verificationClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidSigningMethod
+		}
+
+		return s.verificationKey(), nil
+	})
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+
+	claims, ok := parsed.Claims.(*verificationClaims)
+	if !ok {
+		return ErrInvalidVerificationToken
+	}
+
+	// The token of a pending email replaces the email of the user, the token of an email the
+	// user has changed since doesn't match any account.
+	err = s.repos.VerifyEmail(ctx, claims.UserID, claims.Email)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = s.confirmEmail(ctx, claims.UserID, claims.Email)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidVerificationToken
+		}
+
+		if errors.Is(err, ErrEmailAlreadyTaken) {
+			return err
+		}
+
+		logError(ctx, err, ErrFailedToVerifyEmail)
+		return ErrFailedToVerifyEmail
+	}
+
+	return nil
+}
+
+// confirmEmail makes the pending email the email of the user, unless another account has taken
+// it in the meantime.
+func (s AuthService) confirmEmail(ctx context.Context, userID int, email string) error {
+	_, err := s.repos.GetUser(ctx, email)
+	if err == nil {
+		return ErrEmailAlreadyTaken
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	return s.repos.ConfirmEmail(ctx, userID, email)
+}
+
+// ResendVerification sends a new link to the unverified accounts. The unknown and the
+// verified emails are skipped without an error, so the response doesn't reveal the accounts.
+func (s AuthService) ResendVerification(ctx context.Context, email string) error {
+	user, err := s.repos.GetUser(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+
+		logError(ctx, err, ErrFailedToSendVerification)
+		return ErrFailedToSendVerification
+	}
+
+	if user.Verified {
+		return nil
+	}
+
+	if err = s.sendVerification(ctx, user); err != nil {
+		logError(ctx, err, ErrFailedToSendVerification)
+		return ErrFailedToSendVerification
+	}
+
+	return nil
+}
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Lapp-coder/todo-app/internal/model"
+	"github.com/Lapp-coder/todo-app/internal/repository"
+)
+
+type workspaceKey struct{}
+
+// selectedWorkspace is the workspace the lists and the items of the user are scoped to.
+type selectedWorkspace struct {
+	userID    int
+	workspace model.Workspace
+}
+
+// WithWorkspace scopes the lists and the items of the user to the workspace, the user has to
+// be checked to be its member. The calls made without a workspace use the personal one.
+func WithWorkspace(ctx context.Context, userID int, workspace model.Workspace) context.Context {
+	return context.WithValue(ctx, workspaceKey{}, selectedWorkspace{userID: userID, workspace: workspace})
+}
+
+// currentWorkspace returns the workspace the user selected for the call, or their personal workspace.
+func currentWorkspace(ctx context.Context, repos repository.Workspace, userID int) (model.Workspace, error) {
+	if selected, ok := ctx.Value(workspaceKey{}).(selectedWorkspace); ok && selected.userID == userID {
+		return selected.workspace, nil
+	}
+
+	workspace, err := repos.GetPersonal(ctx, userID)
+	if err != nil {
+		logError(ctx, err, ErrFailedToGetWorkspace)
+		return model.Workspace{}, ErrFailedToGetWorkspace
+	}
+
+	return workspace, nil
+}
+
+// writableWorkspace is the current workspace for the changes, the viewers can't make them.
+func writableWorkspace(ctx context.Context, repos repository.Workspace, userID int) (model.Workspace, error) {
+	workspace, err := currentWorkspace(ctx, repos, userID)
+	if err != nil {
+		return model.Workspace{}, err
+	}
+
+	if !workspace.CanWrite() {
+		return model.Workspace{}, ErrWorkspaceReadOnly
+	}
+
+	return workspace, nil
+}
+
+type WorkspaceService struct {
+	repos     repository.Workspace
+	reposAuth repository.Authorization
+	tx        repository.TxManager
+}
+
+func NewWorkspaceService(repos *repository.Repository) *WorkspaceService {
+	return &WorkspaceService{repos: repos.Workspace, reposAuth: repos.Authorization, tx: repos.TxManager}
+}
+
+// Create creates the workspace with the user as its owner.
+func (s WorkspaceService) Create(ctx context.Context, userID int, input model.CreateWorkspace) (int, error) {
+	workspaceID, err := s.repos.Create(ctx, userID, input.Name)
+	if err != nil {
+		logError(ctx, err, ErrFailedToCreateWorkspace)
+		return 0, ErrFailedToCreateWorkspace
+	}
+
+	return workspaceID, nil
+}
+
+func (s WorkspaceService) GetAll(ctx context.Context, userID int) ([]model.Workspace, error) {
+	workspaces, err := s.repos.GetAll(ctx, userID)
+	if err != nil {
+		logError(ctx, err, ErrFailedToGetWorkspaces)
+		return nil, ErrFailedToGetWorkspaces
+	}
+
+	return workspaces, nil
+}
+
+// GetByID returns the workspace with the role of the user, the workspaces the user isn't
+// a member of aren't found.
+func (s WorkspaceService) GetByID(ctx context.Context, userID, workspaceID int) (model.Workspace, error) {
+	workspace, err := s.repos.GetByID(ctx, userID, workspaceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Workspace{}, ErrWorkspaceNotFound
+		}
+
+		logError(ctx, err, ErrFailedToGetWorkspace)
+		return model.Workspace{}, ErrFailedToGetWorkspace
+	}
+
+	return workspace, nil
+}
+
+func (s WorkspaceService) GetPersonal(ctx context.Context, userID int) (model.Workspace, error) {
+	workspace, err := s.repos.GetPersonal(ctx, userID)
+	if err != nil {
+		logError(ctx, err, ErrFailedToGetWorkspace)
+		return model.Workspace{}, ErrFailedToGetWorkspace
+	}
+
+	return workspace, nil
+}
+
+func (s WorkspaceService) Update(ctx context.Context, userID, workspaceID int, input model.UpdateWorkspace) error {
+	if _, err := s.managed(ctx, userID, workspaceID); err != nil {
+		return err
+	}
+
+	if err := s.repos.Update(ctx, workspaceID, input.Name); err != nil {
+		logError(ctx, err, ErrFailedToUpdateWorkspace)
+		return ErrFailedToUpdateWorkspace
+	}
+
+	return nil
+}
+
+// Delete deletes the workspace with its lists and items, the personal workspaces are kept.
+func (s WorkspaceService) Delete(ctx context.Context, userID, workspaceID int) error {
+	workspace, err := s.managed(ctx, userID, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	if workspace.Personal {
+		return ErrPersonalWorkspace
+	}
+
+	delete(cacheList, workspaceID)
+	delete(cacheItem, workspaceID)
+
+	if err = s.repos.Delete(ctx, workspaceID); err != nil {
+		logError(ctx, err, ErrFailedToDeleteWorkspace)
+		return ErrFailedToDeleteWorkspace
+	}
+
+	return nil
+}
+
+func (s WorkspaceService) GetMembers(ctx context.Context, userID, workspaceID int) ([]model.WorkspaceMember, error) {
+	if _, err := s.GetByID(ctx, userID, workspaceID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.repos.GetMembers(ctx, workspaceID)
+	if err != nil {
+		logError(ctx, err, ErrFailedToGetMembers)
+		return nil, ErrFailedToGetMembers
+	}
+
+	return members, nil
+}
+
+// AddMember adds the user with the email to the workspace, only the owners may add the members.
+func (s WorkspaceService) AddMember(ctx context.Context, userID, workspaceID int, input model.AddWorkspaceMember) error {
+	workspace, err := s.managed(ctx, userID, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	if workspace.Personal {
+		return ErrPersonalWorkspace
+	}
+
+	user, err := s.reposAuth.GetUser(ctx, input.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+
+		logError(ctx, err, ErrFailedToSetMember)
+		return ErrFailedToSetMember
+	}
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		members, err := s.getMembers(ctx, workspaceID, ErrFailedToSetMember)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := findMember(members, user.ID); ok {
+			return ErrAlreadyMember
+		}
+
+		if err = s.repos.SetMember(ctx, workspaceID, user.ID, input.Role); err != nil {
+			logError(ctx, err, ErrFailedToSetMember)
+			return ErrFailedToSetMember
+		}
+
+		return nil
+	})
+}
+
+// SetMemberRole changes the role of the member, the workspace has to keep an owner.
+func (s WorkspaceService) SetMemberRole(ctx context.Context, userID, workspaceID, memberID int, role string) error {
+	workspace, err := s.managed(ctx, userID, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	if workspace.Personal {
+		return ErrPersonalWorkspace
+	}
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		members, err := s.getMembers(ctx, workspaceID, ErrFailedToSetMember)
+		if err != nil {
+			return err
+		}
+
+		member, ok := findMember(members, memberID)
+		if !ok {
+			return ErrMemberNotFound
+		}
+
+		if member.Role == model.WorkspaceOwner && role != model.WorkspaceOwner && countOwners(members) == 1 {
+			return ErrLastWorkspaceOwner
+		}
+
+		if err = s.repos.SetMember(ctx, workspaceID, memberID, role); err != nil {
+			logError(ctx, err, ErrFailedToSetMember)
+			return ErrFailedToSetMember
+		}
+
+		return nil
+	})
+}
+
+// RemoveMember takes the member out of the workspace. The owners may remove anyone, the rest
+// of the members may only leave, and the workspace has to keep an owner.
+func (s WorkspaceService) RemoveMember(ctx context.Context, userID, workspaceID, memberID int) error {
+	var workspace model.Workspace
+	var err error
+	if memberID == userID {
+		workspace, err = s.GetByID(ctx, userID, workspaceID)
+	} else {
+		workspace, err = s.managed(ctx, userID, workspaceID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if workspace.Personal {
+		return ErrPersonalWorkspace
+	}
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		members, err := s.getMembers(ctx, workspaceID, ErrFailedToRemoveMember)
+		if err != nil {
+			return err
+		}
+
+		member, ok := findMember(members, memberID)
+		if !ok {
+			return ErrMemberNotFound
+		}
+
+		if member.Role == model.WorkspaceOwner && countOwners(members) == 1 {
+			return ErrLastWorkspaceOwner
+		}
+
+		if err = s.repos.DeleteMember(ctx, workspaceID, memberID); err != nil {
+			logError(ctx, err, ErrFailedToRemoveMember)
+			return ErrFailedToRemoveMember
+		}
+
+		return nil
+	})
+}
+
+// managed returns the workspace if the user may manage it.
+func (s WorkspaceService) managed(ctx context.Context, userID, workspaceID int) (model.Workspace, error) {
+	workspace, err := s.GetByID(ctx, userID, workspaceID)
+	if err != nil {
+		return model.Workspace{}, err
+	}
+
+	if !workspace.CanManage() {
+		return model.Workspace{}, ErrWorkspaceReadOnly
+	}
+
+	return workspace, nil
+}
+
+func (s WorkspaceService) getMembers(ctx context.Context, workspaceID int, reason error) ([]model.WorkspaceMember, error) {
+	members, err := s.repos.GetMembers(ctx, workspaceID)
+	if err != nil {
+		logError(ctx, err, reason)
+		return nil, reason
+	}
+
+	return members, nil
+}
+
+func findMember(members []model.WorkspaceMember, userID int) (model.WorkspaceMember, bool) {
+	for _, member := range members {
+		if member.UserID == userID {
+			return member, true
+		}
+	}
+
+	return model.WorkspaceMember{}, false
+}
+
+func countOwners(members []model.WorkspaceMember) int {
+	var owners int
+	for _, member := range members {
+		if member.Role == model.WorkspaceOwner {
+			owners++
+		}
+	}
+
+	return owners
+}
@@ -0,0 +1,83 @@
+package totp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA1 key of the test vectors in RFC 6238, "12345678901234567890" in base32.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	testTable := []struct {
+		name     string
+		time     int64
+		expected string
+	}{
+		{name: "59", time: 59, expected: "287082"},
+		{name: "1111111109", time: 1111111109, expected: "081804"},
+		{name: "1111111111", time: 1111111111, expected: "050471"},
+		{name: "1234567890", time: 1234567890, expected: "005924"},
+		{name: "2000000000", time: 2000000000, expected: "279037"},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			code, err := Code(rfcSecret, Step(time.Unix(tc.time, 0)))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, code)
+		})
+	}
+}
+
+func TestCode_invalidSecret(t *testing.T) {
+	_, err := Code("not base32!", 1)
+	assert.ErrorIs(t, err, ErrInvalidSecret)
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := Step(now)
+
+	previous, err := Code(rfcSecret, step-1)
+	require.NoError(t, err)
+
+	testTable := []struct {
+		name         string
+		code         string
+		skew         int
+		expectedStep int64
+		expectedOK   bool
+	}{
+		{name: "Current", code: "050471", skew: 1, expectedStep: step, expectedOK: true},
+		{name: "Previous", code: previous, skew: 1, expectedStep: step - 1, expectedOK: true},
+		{name: "Previous without skew", code: previous, skew: 0},
+		{name: "Wrong", code: "000000", skew: 1},
+		{name: "Short", code: "05047", skew: 1},
+	}
+
+	for _, tc := range testTable {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := Validate(rfcSecret, tc.code, now, tc.skew)
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedStep, got)
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	_, err = Code(secret, 1)
+	assert.NoError(t, err)
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("todo-app", "user@mail.ru", rfcSecret)
+	assert.Equal(t, "otpauth://totp/todo-app:user@mail.ru?algorithm=SHA1&digits=6&issuer=todo-app&period=30&secret="+rfcSecret, uri)
+}
@@ -0,0 +1,15 @@
+// Package version holds the build information, which is injected with
+//
+//	go build -ldflags "-X github.com/Lapp-coder/todo-app/internal/version.Commit=$(git rev-parse --short HEAD) -X github.com/Lapp-coder/todo-app/internal/version.BuildTime=$(date -u +%FT%TZ)"
+package version
+
+import "runtime"
+
+var (
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
+
+func GoVersion() string {
+	return runtime.Version()
+}
@@ -0,0 +1,6 @@
+CREATE TABLE calendar_feeds
+(
+    id      SERIAL                    NOT NULL UNIQUE,
+    user_id INT REFERENCES users (id) NOT NULL UNIQUE,
+    token   VARCHAR(64)               NOT NULL UNIQUE
+);
@@ -0,0 +1,6 @@
+CREATE TABLE caldav_objects
+(
+    item_id INT REFERENCES todo_items (id) ON DELETE CASCADE NOT NULL UNIQUE,
+    name    VARCHAR(255)                                    NOT NULL,
+    uid     VARCHAR(255)                                    NOT NULL
+);
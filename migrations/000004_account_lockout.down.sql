@@ -0,0 +1,4 @@
+ALTER TABLE users
+    DROP COLUMN failed_logins,
+    DROP COLUMN lockouts,
+    DROP COLUMN locked_until;
@@ -0,0 +1,4 @@
+ALTER TABLE users
+    ADD COLUMN failed_logins INT NOT NULL DEFAULT 0,
+    ADD COLUMN lockouts      INT NOT NULL DEFAULT 0,
+    ADD COLUMN locked_until  TIMESTAMPTZ;
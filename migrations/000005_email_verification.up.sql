@@ -0,0 +1,6 @@
+-- The accounts created before the verification was introduced count as verified.
+ALTER TABLE users
+    ADD COLUMN verified BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE users
+    ALTER COLUMN verified SET DEFAULT FALSE;
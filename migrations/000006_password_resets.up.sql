@@ -0,0 +1,10 @@
+-- The access tokens carry the version they were issued for, bumping it signs out all the sessions.
+ALTER TABLE users
+    ADD COLUMN token_version INT NOT NULL DEFAULT 0;
+
+CREATE TABLE password_resets
+(
+    token_hash VARCHAR(64)                                NOT NULL PRIMARY KEY,
+    user_id    INT REFERENCES users (id) ON DELETE CASCADE NOT NULL,
+    expires_at TIMESTAMPTZ                                NOT NULL
+);
@@ -0,0 +1,13 @@
+-- The secret is set on the setup and only checked once the first code confirms it. The last step
+-- is the time step of the last accepted code, the codes can't be used twice.
+ALTER TABLE users
+    ADD COLUMN totp_secret    VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN totp_enabled   BOOLEAN     NOT NULL DEFAULT FALSE,
+    ADD COLUMN totp_last_step BIGINT      NOT NULL DEFAULT 0;
+
+CREATE TABLE recovery_codes
+(
+    user_id   INT REFERENCES users (id) ON DELETE CASCADE NOT NULL,
+    code_hash VARCHAR(64)                                 NOT NULL,
+    PRIMARY KEY (user_id, code_hash)
+);
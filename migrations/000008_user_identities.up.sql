@@ -0,0 +1,8 @@
+-- The accounts at the identity providers the users sign in with, by the issuer and the subject.
+CREATE TABLE user_identities
+(
+    issuer  VARCHAR(255)                                NOT NULL,
+    subject VARCHAR(255)                                NOT NULL,
+    user_id INT REFERENCES users (id) ON DELETE CASCADE NOT NULL,
+    PRIMARY KEY (issuer, subject)
+);
@@ -0,0 +1,13 @@
+-- The personal access tokens of the users, by the hash of the token. The scopes are
+-- separated by spaces, the tokens without expires_at don't expire.
+CREATE TABLE access_tokens
+(
+    id           SERIAL                                      NOT NULL UNIQUE,
+    user_id      INT REFERENCES users (id) ON DELETE CASCADE NOT NULL,
+    name         VARCHAR(100)                                NOT NULL,
+    token_hash   VARCHAR(64)                                 NOT NULL UNIQUE,
+    scopes       VARCHAR(255)                                NOT NULL,
+    expires_at   TIMESTAMP,
+    last_used_at TIMESTAMP,
+    created_at   TIMESTAMP                                   NOT NULL
+);
@@ -0,0 +1,4 @@
+-- The timezone is an IANA time zone name and the locale a BCP 47 language tag.
+ALTER TABLE users
+    ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    ADD COLUMN locale   VARCHAR(35) NOT NULL DEFAULT 'en';
@@ -0,0 +1,4 @@
+-- The disabled accounts can't sign in, their sessions are revoked with the token version.
+ALTER TABLE users
+    ADD COLUMN role     VARCHAR(20) NOT NULL DEFAULT 'user',
+    ADD COLUMN disabled BOOLEAN     NOT NULL DEFAULT FALSE;
@@ -0,0 +1,38 @@
+-- The workspaces own the lists, every user has a personal workspace only they are a member of.
+CREATE TABLE workspaces
+(
+    id               SERIAL                                      NOT NULL UNIQUE,
+    name             VARCHAR(40)                                 NOT NULL,
+    personal_user_id INT REFERENCES users (id) ON DELETE CASCADE UNIQUE,
+    created_at       TIMESTAMP                                   NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE workspace_members
+(
+    workspace_id INT REFERENCES workspaces (id) ON DELETE CASCADE NOT NULL,
+    user_id      INT REFERENCES users (id) ON DELETE CASCADE      NOT NULL,
+    role         VARCHAR(20)                                      NOT NULL,
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE INDEX workspace_members_user_id_idx ON workspace_members (user_id);
+
+INSERT INTO workspaces (name, personal_user_id)
+SELECT 'Personal', id FROM users;
+
+INSERT INTO workspace_members (workspace_id, user_id, role)
+SELECT id, personal_user_id, 'owner' FROM workspaces;
+
+-- The lists of the users move to their personal workspaces, user_id is kept as the author.
+ALTER TABLE todo_lists
+    ADD COLUMN workspace_id INT REFERENCES workspaces (id);
+
+UPDATE todo_lists tl
+SET workspace_id = w.id
+FROM workspaces w
+WHERE w.personal_user_id = tl.user_id;
+
+ALTER TABLE todo_lists
+    ALTER COLUMN workspace_id SET NOT NULL;
+
+CREATE INDEX todo_lists_workspace_id_idx ON todo_lists (workspace_id);
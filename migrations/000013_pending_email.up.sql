@@ -0,0 +1,3 @@
+-- The changed email waits in pending_email until the link mailed to it is opened.
+ALTER TABLE users
+    ADD COLUMN pending_email VARCHAR(255) NOT NULL DEFAULT '';
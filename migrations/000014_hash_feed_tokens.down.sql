@@ -0,0 +1,5 @@
+-- The hashes can't be turned back into the tokens, the feeds are dropped and have to be created again.
+DELETE FROM calendar_feeds;
+
+ALTER TABLE calendar_feeds
+    RENAME COLUMN token_hash TO token;
@@ -0,0 +1,6 @@
+-- The feed tokens are stored as their SHA-256 hashes, like the access and the reset tokens.
+ALTER TABLE calendar_feeds
+    RENAME COLUMN token TO token_hash;
+
+UPDATE calendar_feeds
+SET token_hash = encode(sha256(convert_to(token_hash, 'UTF8')), 'hex');